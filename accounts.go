@@ -0,0 +1,218 @@
+package bitcoincash
+
+import (
+	"errors"
+
+	db "github.com/BubbaJoe/spvwallet-cash/db"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchutil"
+	hd "github.com/gcash/bchutil/hdkeychain"
+)
+
+// DefaultAccount is the BIP44 account created with the wallet. The wallet
+// methods which don't take an account receive and spend with it.
+const DefaultAccount uint32 = 0
+
+// ErrUnknownAccount is returned for an account which hasn't been created.
+var ErrUnknownAccount = errors.New("Unknown account")
+
+// ErrAccountsNotSupported is returned when creating an account in a datastore
+// which can only hold the default account.
+var ErrAccountsNotSupported = errors.New("Datastore does not support accounts")
+
+// Account is a BIP44 account of the wallet. Each account has its own
+// external and internal chains, balance and transaction history.
+type Account struct {
+	Number uint32
+	Name   string
+}
+
+// accountStore is implemented by datastores which keep the keys, utxos and
+// transactions of each account apart. It is implemented by db.SQLiteDatastore.
+type accountStore interface {
+	Account(account uint32) wallet.Datastore
+	PutAccount(account db.Account) error
+	GetAccounts() ([]db.Account, error)
+}
+
+// loadAccounts starts tracking the accounts created in addition to the default
+// account. The account keys are derived from the master key if the wallet is
+// unlocked and read from the datastore otherwise.
+func (w *SPVWallet) loadAccounts(ds wallet.Datastore) error {
+	store, ok := ds.(accountStore)
+	if !ok || w.watchOnly {
+		return nil
+	}
+	accounts, err := store.GetAccounts()
+	if err != nil {
+		return err
+	}
+	for _, a := range accounts {
+		var accountKey *hd.ExtendedKey
+		if w.masterPrivateKey != nil {
+			accountKey, err = bip44Account(w.masterPrivateKey, a.Number)
+		} else {
+			accountKey, err = hd.NewKeyFromString(a.PublicKey)
+		}
+		if err != nil {
+			return err
+		}
+		km, err := newKeyManager(store.Account(a.Number).Keys(), w.params, a.Number, accountKey)
+		if err != nil {
+			return err
+		}
+		w.txstore.addAccount(km)
+	}
+	return nil
+}
+
+// CreateAccount derives the next BIP44 account and starts watching its
+// addresses. The wallet must be unlocked.
+func (w *SPVWallet) CreateAccount(name string) (Account, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.privateKeyError(); err != nil {
+		return Account{}, err
+	}
+	store, ok := w.txstore.Datastore.(accountStore)
+	if !ok {
+		return Account{}, ErrAccountsNotSupported
+	}
+	var number uint32
+	for _, km := range w.txstore.keyManagers() {
+		if km.account >= number {
+			number = km.account + 1
+		}
+	}
+	accountKey, err := bip44Account(w.masterPrivateKey, number)
+	if err != nil {
+		return Account{}, err
+	}
+	accountPub, err := accountKey.Neuter()
+	if err != nil {
+		return Account{}, err
+	}
+	err = store.PutAccount(db.Account{Number: number, Name: name, PublicKey: accountPub.String()})
+	if err != nil {
+		return Account{}, err
+	}
+	km, err := newKeyManager(store.Account(number).Keys(), w.params, number, accountKey)
+	if err != nil {
+		return Account{}, err
+	}
+	w.txstore.addAccount(km)
+	if w.running {
+		w.wireService.MsgChan() <- updateFiltersMsg{}
+	}
+	return Account{Number: number, Name: name}, nil
+}
+
+// Accounts returns the accounts of the wallet starting with the default account.
+func (w *SPVWallet) Accounts() ([]Account, error) {
+	accounts := []Account{{Number: DefaultAccount, Name: "default"}}
+	store, ok := w.txstore.Datastore.(accountStore)
+	if !ok {
+		return accounts, nil
+	}
+	stored, err := store.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range stored {
+		accounts = append(accounts, Account{Number: a.Number, Name: a.Name})
+	}
+	return accounts, nil
+}
+
+func (w *SPVWallet) AccountCurrentAddress(account uint32, purpose wallet.KeyPurpose) (bchutil.Address, error) {
+	km, ok := w.txstore.accountKeyManager(account)
+	if !ok {
+		return nil, ErrUnknownAccount
+	}
+	key, err := km.GetCurrentKey(purpose)
+	if err != nil {
+		return nil, err
+	}
+	addr, err := key.Address(w.params)
+	if err != nil {
+		return nil, err
+	}
+	return bchutil.NewAddressPubKeyHash(addr.ScriptAddress(), w.params)
+}
+
+func (w *SPVWallet) AccountNewAddress(account uint32, purpose wallet.KeyPurpose) (bchutil.Address, error) {
+	km, ok := w.txstore.accountKeyManager(account)
+	if !ok {
+		return nil, ErrUnknownAccount
+	}
+	unused, err := km.datastore.GetUnused(purpose)
+	if err != nil {
+		return nil, err
+	}
+	if len(unused) < 2 {
+		return nil, errors.New("No unused keys in database")
+	}
+	key, err := km.generateChildKey(purpose, uint32(unused[1]))
+	if err != nil {
+		return nil, err
+	}
+	addr, err := key.Address(w.params)
+	if err != nil {
+		return nil, err
+	}
+	if err := km.MarkKeyAsUsed(addr.ScriptAddress()); err != nil {
+		return nil, err
+	}
+	w.txstore.PopulateAdrs()
+	return bchutil.NewAddressPubKeyHash(addr.ScriptAddress(), w.params)
+}
+
+func (w *SPVWallet) AccountBalance(account uint32) (confirmed, unconfirmed int64, err error) {
+	if _, ok := w.txstore.accountKeyManager(account); !ok {
+		return 0, 0, ErrUnknownAccount
+	}
+	utxos, err := w.txstore.accountDatastore(account).Utxos().GetAll()
+	if err != nil {
+		return 0, 0, err
+	}
+	confirmed, unconfirmed = w.balance(utxos)
+	return confirmed, unconfirmed, nil
+}
+
+// AccountTransactions returns the transaction history of an account with the
+// value of each transaction to the account. A transfer between two accounts
+// is listed under both.
+func (w *SPVWallet) AccountTransactions(account uint32) ([]wallet.Txn, error) {
+	if _, ok := w.txstore.accountKeyManager(account); !ok {
+		return nil, ErrUnknownAccount
+	}
+	txns, err := w.txstore.accountDatastore(account).Txns().GetAll(false)
+	if err != nil {
+		return txns, err
+	}
+	return w.setTxnStatus(txns), nil
+}
+
+// SpendFromAccount is like Spend but only spends the utxos of account and
+// sends the change back to it.
+func (w *SPVWallet) SpendFromAccount(account uint32, amount int64, addr bchutil.Address, feeLevel wallet.FeeLevel) (*chainhash.Hash, error) {
+	return w.SpendMany(payment(addr, amount), feeLevel, SpendOptions{Account: account})
+}
+
+// keyForScript returns the key of a script address from whichever account it
+// belongs to.
+func (w *SPVWallet) keyForScript(scriptAddress []byte) (*hd.ExtendedKey, error) {
+	var firstErr error
+	for _, km := range w.txstore.keyManagers() {
+		key, err := km.GetKeyForScript(scriptAddress)
+		if err == nil {
+			return key, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
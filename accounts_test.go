@@ -0,0 +1,249 @@
+package bitcoincash
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	db "github.com/BubbaJoe/spvwallet-cash/db"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil"
+)
+
+// createAccountsWallet returns a mock wallet backed by a SQLite datastore,
+// which supports accounts.
+func createAccountsWallet(t *testing.T) (*SPVWallet, *db.SQLiteDatastore, func()) {
+	dir, err := ioutil.TempDir("", "spvwallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := db.Create(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := MockWallet()
+	w.keyManager, err = NewKeyManager(ds.Keys(), w.params, w.masterPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.txstore, err = NewTxStore(w.params, ds, w.keyManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w, ds, func() {
+		os.RemoveAll(dir)
+		os.Remove("headers.bin")
+	}
+}
+
+// payTo returns a transaction paying value to addr.
+func payTo(t *testing.T, addr bchutil.Address, value int64) *wire.MsgTx {
+	h, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := wire.NewMsgTx(1)
+	// The signature script pads the transaction to the minimum size
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(h, 0), make([]byte, 20)))
+	tx.AddTxOut(wire.NewTxOut(value, script))
+	return tx
+}
+
+func TestSPVWallet_CreateAccount(t *testing.T) {
+	w, ds, cleanup := createAccountsWallet(t)
+	defer cleanup()
+
+	account, err := w.CreateAccount("savings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Number != 1 || account.Name != "savings" {
+		t.Errorf("Created the wrong account %v", account)
+	}
+	accounts, err := w.Accounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[1] != account {
+		t.Errorf("Returned the wrong accounts %v", accounts)
+	}
+
+	// The account derives its own addresses
+	addr, err := w.AccountCurrentAddress(account.Number, wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() == w.CurrentAddress(wallet.EXTERNAL).String() {
+		t.Error("Account returned the default account's address")
+	}
+	if !w.HasKey(addr) {
+		t.Error("Wallet doesn't have the account's key")
+	}
+	accountKey, err := bip44Account(w.masterPrivateKey, account.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, external, err := accountChains(accountKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := external.Child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstAddr, err := first.Address(w.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != firstAddr.String() {
+		t.Error("Account address was not derived from m/44'/145'/1'/0/0")
+	}
+	if _, err := w.AccountCurrentAddress(5, wallet.EXTERNAL); err != ErrUnknownAccount {
+		t.Errorf("Expected ErrUnknownAccount, got %v", err)
+	}
+
+	// Reloading the wallet restores the account
+	w.txstore, err = NewTxStore(w.params, ds, w.keyManager)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.loadAccounts(ds); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := w.txstore.accountKeyManager(account.Number); !ok {
+		t.Error("Account was not loaded")
+	}
+
+	// Locked wallets can't create accounts
+	w.masterPrivateKey = nil
+	if _, err := w.CreateAccount("locked"); err != ErrWalletLocked {
+		t.Errorf("Expected ErrWalletLocked, got %v", err)
+	}
+}
+
+func TestSPVWallet_AccountBalance(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+
+	account, err := w.CreateAccount("savings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.AccountCurrentAddress(account.Number, wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := payTo(t, addr, 1000000)
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	confirmed, unconfirmed, err := w.AccountBalance(account.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed+unconfirmed != 1000000 {
+		t.Errorf("Account balance is %d, expected 1000000", confirmed+unconfirmed)
+	}
+	confirmed, unconfirmed, err = w.AccountBalance(DefaultAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed+unconfirmed != 0 {
+		t.Errorf("Default account balance is %d, expected 0", confirmed+unconfirmed)
+	}
+	txns, err := w.AccountTransactions(account.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Value != 1000000 {
+		t.Error("Transaction was not recorded in the account")
+	}
+	txns, err = w.AccountTransactions(DefaultAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 0 {
+		t.Error("Transaction was recorded in the default account")
+	}
+
+	// Only the account can spend its coins and the change goes back to it
+	if len(w.gatherCoins(DefaultAccount)) != 0 {
+		t.Error("Default account gathered another account's coins")
+	}
+	if _, err := w.buildTx(SpendOptions{}, payment(addr, 10000), wallet.NORMAL); err == nil {
+		t.Error("Default account spent another account's coins")
+	}
+	spend, err := w.buildTx(SpendOptions{Account: account.Number}, payment(w.CurrentAddress(wallet.EXTERNAL), 10000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spend.TxIn) != 1 || spend.TxIn[0].PreviousOutPoint.Hash != tx.TxHash() {
+		t.Fatal("Spent the wrong coins")
+	}
+	km, _ := w.txstore.accountKeyManager(account.Number)
+	change := false
+	for _, out := range spend.TxOut {
+		changeAddr, err := w.ScriptToAddress(out.PkScript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path, err := km.datastore.GetPathForKey(changeAddr.ScriptAddress()); err == nil && path.Purpose == wallet.INTERNAL {
+			change = true
+		}
+	}
+	if !change {
+		t.Error("Change was not sent to the account")
+	}
+
+	// The transfer is listed under both accounts and the wallet only loses the fee
+	if _, err := w.txstore.Ingest(spend, 2, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	fee := int64(1000000)
+	for _, out := range spend.TxOut {
+		fee -= out.Value
+	}
+	for _, test := range []struct {
+		account uint32
+		value   int64
+	}{
+		{DefaultAccount, 10000},
+		{account.Number, -10000 - fee},
+	} {
+		txns, err := w.AccountTransactions(test.account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, txn := range txns {
+			if txn.Txid == spend.TxHash().String() {
+				found = true
+				if txn.Value != test.value {
+					t.Errorf("Account %d: transfer value is %d, expected %d", test.account, txn.Value, test.value)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Account %d: transfer was not recorded", test.account)
+		}
+	}
+	txns, err = w.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, txn := range txns {
+		if txn.Txid == spend.TxHash().String() && txn.Value != -fee {
+			t.Errorf("Transfer net value is %d, expected %d", txn.Value, -fee)
+		}
+	}
+}
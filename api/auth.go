@@ -0,0 +1,199 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Permission is the access level granted by an API token. Each level also
+// grants the ones below it.
+type Permission int
+
+const (
+	// ReadOnly can query the wallet's balance, addresses and transactions
+	ReadOnly Permission = iota
+
+	// Spend can also send coins, sign transactions and label them
+	Spend
+
+	// Admin can also read private keys, import keys, encrypt, unlock and lock
+	// the wallet, resync and stop it
+	Admin
+)
+
+func (p Permission) String() string {
+	switch p {
+	case ReadOnly:
+		return "readonly"
+	case Spend:
+		return "spend"
+	default:
+		return "admin"
+	}
+}
+
+// AdminCookie is the name of the file holding the admin token, the one the
+// CLI uses.
+const AdminCookie = "admin.cookie"
+
+// The cookie files holding the token of each permission level
+var cookieFiles = map[Permission]string{
+	ReadOnly: "readonly.cookie",
+	Spend:    "spend.cookie",
+	Admin:    AdminCookie,
+}
+
+// The permission each method requires. Methods which aren't listed require
+// Admin.
+var methodPermissions = map[string]Permission{
+	"/pb.API/CurrentAddress":            ReadOnly,
+	"/pb.API/NewAddress":                ReadOnly,
+	"/pb.API/PaymentURI":                ReadOnly,
+	"/pb.API/ChainTip":                  ReadOnly,
+	"/pb.API/Balance":                   ReadOnly,
+	"/pb.API/MasterPublicKey":           ReadOnly,
+	"/pb.API/HasKey":                    ReadOnly,
+	"/pb.API/Params":                    ReadOnly,
+	"/pb.API/Transactions":              ReadOnly,
+	"/pb.API/GetTransaction":            ReadOnly,
+	"/pb.API/GetTransactionDetail":      ReadOnly,
+	"/pb.API/ExportHistory":             ReadOnly,
+	"/pb.API/GetInvoice":                ReadOnly,
+	"/pb.API/ListInvoices":              ReadOnly,
+	"/pb.API/GetFeePerByte":             ReadOnly,
+	"/pb.API/Peers":                     ReadOnly,
+	"/pb.API/GetConfirmations":          ReadOnly,
+	"/pb.API/EstimateFee":               ReadOnly,
+	"/pb.API/ListAddresses":             ReadOnly,
+	"/pb.API/ListUnspent":               ReadOnly,
+	"/pb.API/ListLockedUnspent":         ReadOnly,
+	"/pb.API/CreateUnsignedTransaction": ReadOnly,
+	"/pb.API/WalletNotify":              ReadOnly,
+	"/pb.API/SubscribeEvents":           ReadOnly,
+	"/pb.API/DumpHeaders":               ReadOnly,
+
+	"/pb.API/Spend":                   Spend,
+	"/pb.API/SpendMany":               Spend,
+	"/pb.API/PayURI":                  Spend,
+	"/pb.API/BumpFee":                 Spend,
+	"/pb.API/SweepAddress":            Spend,
+	"/pb.API/CreateMultisigSignature": Spend,
+	"/pb.API/Multisign":               Spend,
+	"/pb.API/SignTransaction":         Spend,
+	"/pb.API/FinalizeAndBroadcast":    Spend,
+	"/pb.API/LockUnspent":             Spend,
+	"/pb.API/UnlockUnspent":           Spend,
+	"/pb.API/SetAddressLabel":         Spend,
+	"/pb.API/SetTxMemo":               Spend,
+	"/pb.API/CreateInvoice":           Spend,
+
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": ReadOnly,
+}
+
+// authenticator checks the bearer token of each call against the token of
+// every permission level.
+type authenticator struct {
+	tokens map[Permission]string
+}
+
+// loadTokens reads the token of each permission level from its cookie file in
+// dir, creating the files which don't exist yet.
+func loadTokens(dir string) (*authenticator, error) {
+	a := &authenticator{tokens: make(map[Permission]string)}
+	for perm, name := range cookieFiles {
+		token, err := ReadCookie(path.Join(dir, name))
+		if os.IsNotExist(err) || (err == nil && token == "") {
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				return nil, err
+			}
+			token = hex.EncodeToString(b)
+			err = ioutil.WriteFile(path.Join(dir, name), []byte(token), 0600)
+		}
+		if err != nil {
+			return nil, err
+		}
+		a.tokens[perm] = token
+	}
+	return a, nil
+}
+
+// ReadCookie returns the token saved in a cookie file.
+func ReadCookie(file string) (string, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// authorize returns an error unless the call's token grants the permission
+// the method requires.
+func (a *authenticator) authorize(ctx context.Context, method string) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["authorization"]) == 0 {
+		return status.Error(codes.Unauthenticated, "Missing API token")
+	}
+	return a.check(strings.TrimPrefix(md["authorization"][0], "Bearer "), method)
+}
+
+// check returns an error unless token grants the permission the method
+// requires.
+func (a *authenticator) check(token, method string) error {
+	if token == "" {
+		return status.Error(codes.Unauthenticated, "Missing API token")
+	}
+	required, ok := methodPermissions[method]
+	if !ok {
+		required = Admin
+	}
+	granted, found := ReadOnly, false
+	for perm, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			granted, found = perm, true
+		}
+	}
+	if !found {
+		return status.Error(codes.Unauthenticated, "Invalid API token")
+	}
+	if granted < required {
+		return status.Error(codes.PermissionDenied, "API token does not permit "+method)
+	}
+	return nil
+}
+
+func (a *authenticator) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := a.authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// tokenCredentials sends a token as the bearer token of each call.
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return true
+}
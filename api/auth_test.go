@@ -0,0 +1,105 @@
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticator_Authorize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	auth, err := loadTokens(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The tokens are kept across restarts
+	reloaded, err := loadTokens(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for perm, token := range auth.tokens {
+		if reloaded.tokens[perm] != token || len(token) != 64 {
+			t.Errorf("Token %d was not saved", perm)
+		}
+	}
+	readOnly, err := ReadCookie(path.Join(dir, "readonly.cookie"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := ReadCookie(path.Join(dir, AdminCookie))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withToken := func(token string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	}
+	tests := []struct {
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{withToken(readOnly), "/pb.API/Balance", codes.OK},
+		{withToken(readOnly), "/pb.API/Spend", codes.PermissionDenied},
+		{withToken(readOnly), "/pb.API/MasterPrivateKey", codes.PermissionDenied},
+		{withToken(admin), "/pb.API/MasterPrivateKey", codes.OK},
+		{withToken(admin), "/pb.API/Spend", codes.OK},
+		{withToken(auth.tokens[Spend]), "/pb.API/Spend", codes.OK},
+		{withToken(auth.tokens[Spend]), "/pb.API/Stop", codes.PermissionDenied},
+		// Methods without a permission are for admins only
+		{withToken(auth.tokens[Spend]), "/pb.API/Unknown", codes.PermissionDenied},
+		{withToken("invalid"), "/pb.API/Balance", codes.Unauthenticated},
+		{withToken(""), "/pb.API/Balance", codes.Unauthenticated},
+		{context.Background(), "/pb.API/Balance", codes.Unauthenticated},
+	}
+	for _, test := range tests {
+		err := auth.authorize(test.ctx, test.method)
+		if status.Code(err) != test.code {
+			t.Errorf("Calling %s returned %v, expected %s", test.method, err, test.code)
+		}
+	}
+}
+
+func TestEnsureCert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certPath, keyPath := path.Join(dir, CertFile), path.Join(dir, KeyFile)
+
+	if err := ensureCert(certPath, keyPath, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !certCovers(certPath, []string{"localhost", "127.0.0.1"}) {
+		t.Error("Certificate is not valid for localhost")
+	}
+	cert, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A valid certificate is kept, another is made for new hosts
+	if err := ensureCert(certPath, keyPath, []string{"127.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	if kept, _ := ioutil.ReadFile(certPath); string(kept) != string(cert) {
+		t.Error("Replaced a valid certificate")
+	}
+	if err := ensureCert(certPath, keyPath, []string{"wallet.example.com", "10.0.0.5"}); err != nil {
+		t.Fatal(err)
+	}
+	if !certCovers(certPath, []string{"localhost", "wallet.example.com", "10.0.0.5"}) {
+		t.Error("Certificate was not made for the new hosts")
+	}
+}
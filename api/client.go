@@ -0,0 +1,25 @@
+package api
+
+import (
+	"github.com/BubbaJoe/spvwallet-cash/api/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Dial connects to the API at addr, trusting the certificate in certFile and
+// authenticating with the token in cookieFile.
+func Dial(addr, certFile, cookieFile string) (pb.APIClient, *grpc.ClientConn, error) {
+	creds, err := credentials.NewClientTLSFromFile(certFile, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	token, err := ReadCookie(cookieFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(creds), grpc.WithPerRPCCredentials(tokenCredentials(token)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return pb.NewAPIClient(conn), conn, nil
+}
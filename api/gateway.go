@@ -0,0 +1,306 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BubbaJoe/spvwallet-cash/api/pb"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GatewayPrefix is the path under which the gateway serves each RPC, for
+// example POST /v1/Balance.
+const GatewayPrefix = "/v1/"
+
+// The largest request body the gateway reads
+const maxGatewayRequest = 10 << 20
+
+var errStreamClosed = errors.New("Stream is closed")
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// gatewayMethod is an RPC of the API served over HTTP.
+type gatewayMethod struct {
+	name string
+
+	// The request message and the response message, or the streamed message
+	// of a server stream
+	in, out reflect.Type
+	stream  bool
+}
+
+// The stream implementations of the server streaming RPCs, which the
+// gateway sends as server-sent events
+var gatewayStreams = map[string]func(*eventStream) interface{}{
+	"WalletNotify":    func(s *eventStream) interface{} { return walletNotifyStream{s} },
+	"DumpHeaders":     func(s *eventStream) interface{} { return dumpHeadersStream{s} },
+	"SubscribeEvents": func(s *eventStream) interface{} { return subscribeEventsStream{s} },
+}
+
+// gatewayMethods returns every method of pb.APIServer, found by reflection so
+// that new RPCs are served without changes here.
+func gatewayMethods() []gatewayMethod {
+	t := reflect.TypeOf((*pb.APIServer)(nil)).Elem()
+	var methods []gatewayMethod
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if m.Type.NumIn() != 2 {
+			continue
+		}
+		switch {
+		case m.Type.In(0) == contextType && m.Type.NumOut() == 2:
+			methods = append(methods, gatewayMethod{name: m.Name, in: m.Type.In(1).Elem(), out: m.Type.Out(0).Elem()})
+		case m.Type.NumOut() == 1 && gatewayStreams[m.Name] != nil:
+			send, ok := m.Type.In(1).MethodByName("Send")
+			if !ok {
+				continue
+			}
+			methods = append(methods, gatewayMethod{name: m.Name, in: m.Type.In(0).Elem(), out: send.Type.In(0).Elem(), stream: true})
+		}
+	}
+	return methods
+}
+
+// gateway serves the API as JSON over HTTP. Each unary RPC is a POST of its
+// request message to GatewayPrefix plus the method name, and each server stream
+// is a GET or POST answered with server-sent events. Messages are encoded with
+// jsonpb and calls need the same tokens as the gRPC server, sent as a bearer
+// token or as the access_token parameter of a GET stream.
+type gateway struct {
+	api     reflect.Value
+	auth    *authenticator
+	methods map[string]gatewayMethod
+	spec    []byte
+}
+
+func newGateway(api pb.APIServer, auth *authenticator) (*gateway, error) {
+	g := &gateway{
+		api:     reflect.ValueOf(api),
+		auth:    auth,
+		methods: make(map[string]gatewayMethod),
+	}
+	methods := gatewayMethods()
+	for _, m := range methods {
+		g.methods[m.name] = m
+	}
+	spec, err := json.MarshalIndent(openAPISpec(methods), "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	g.spec = spec
+	return g, nil
+}
+
+func (g *gateway) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == GatewayPrefix+"openapi.json" {
+		if r.Method != "GET" {
+			writeGatewayError(rw, status.Error(codes.Unimplemented, "The OpenAPI document is read with GET"))
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Write(g.spec)
+		return
+	}
+	m, ok := g.methods[strings.TrimPrefix(r.URL.Path, GatewayPrefix)]
+	if !ok || !strings.HasPrefix(r.URL.Path, GatewayPrefix) {
+		writeGatewayError(rw, status.Error(codes.NotFound, "Unknown method "+r.URL.Path))
+		return
+	}
+	if r.Method != "POST" && !(m.stream && r.Method == "GET") {
+		writeGatewayError(rw, status.Error(codes.Unimplemented, m.name+" does not accept "+r.Method))
+		return
+	}
+
+	// Browsers can't set headers for an EventSource so the token of a GET
+	// stream may be a query parameter. Other calls must use the header so a
+	// token isn't left in logs and browser history.
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" && m.stream && r.Method == "GET" {
+		token = r.URL.Query().Get("access_token")
+	}
+	if err := g.auth.check(token, "/pb.API/"+m.name); err != nil {
+		writeGatewayError(rw, err)
+		return
+	}
+
+	in := reflect.New(m.in)
+	if r.Method == "POST" {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, maxGatewayRequest))
+		if err != nil {
+			writeGatewayError(rw, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := jsonpb.Unmarshal(bytes.NewReader(body), in.Interface().(proto.Message)); err != nil {
+				writeGatewayError(rw, status.Error(codes.InvalidArgument, err.Error()))
+				return
+			}
+		}
+	}
+	if m.stream {
+		g.serveStream(rw, r, m, in)
+		return
+	}
+
+	results := g.api.MethodByName(m.name).Call([]reflect.Value{reflect.ValueOf(r.Context()), in})
+	if err, _ := results[1].Interface().(error); err != nil {
+		writeGatewayError(rw, err)
+		return
+	}
+	out, _ := results[0].Interface().(proto.Message)
+	if results[0].IsNil() {
+		out = reflect.New(m.out).Interface().(proto.Message)
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := gatewayMarshaler.Marshal(rw, out); err != nil {
+		writeGatewayError(rw, err)
+	}
+}
+
+// serveStream calls a server streaming RPC, sending each message as an event
+// until the RPC returns or the client goes away. An error is sent as an event
+// named error.
+func (g *gateway) serveStream(rw http.ResponseWriter, r *http.Request, m gatewayMethod, in reflect.Value) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		writeGatewayError(rw, status.Error(codes.Internal, "Streaming is not supported"))
+		return
+	}
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	stream := &eventStream{ctx: r.Context(), rw: rw, flusher: flusher}
+	defer stream.close()
+	results := g.api.MethodByName(m.name).Call([]reflect.Value{in, reflect.ValueOf(gatewayStreams[m.name](stream))})
+	if err, _ := results[0].Interface().(error); err != nil {
+		b, _ := json.Marshal(gatewayError(err))
+		stream.send("error", b)
+	}
+}
+
+var gatewayMarshaler = jsonpb.Marshaler{OrigName: true, EmitDefaults: true}
+
+// gatewayErrorBody is the body of an error response, the gRPC status code
+// and message of the error.
+type gatewayErrorBody struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+func gatewayError(err error) gatewayErrorBody {
+	st, _ := status.FromError(err)
+	return gatewayErrorBody{Code: st.Code(), Message: st.Message()}
+}
+
+func writeGatewayError(rw http.ResponseWriter, err error) {
+	body := gatewayError(err)
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(httpStatus(body.Code))
+	json.NewEncoder(rw).Encode(body)
+}
+
+// httpStatus returns the HTTP status of a gRPC status code.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// eventStream is a grpc.ServerStream which sends each message as a
+// server-sent event. Messages sent after the request ends are dropped.
+type eventStream struct {
+	ctx     context.Context
+	rw      http.ResponseWriter
+	flusher http.Flusher
+
+	mutex  sync.Mutex
+	closed bool
+}
+
+func (s *eventStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return errors.New("Not a protobuf message")
+	}
+	var buf bytes.Buffer
+	if err := gatewayMarshaler.Marshal(&buf, msg); err != nil {
+		return err
+	}
+	return s.send("", buf.Bytes())
+}
+
+func (s *eventStream) send(event string, data []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed || s.ctx.Err() != nil {
+		return errStreamClosed
+	}
+	var buf bytes.Buffer
+	if event != "" {
+		buf.WriteString("event: " + event + "\n")
+	}
+	buf.WriteString("data: ")
+	buf.Write(data)
+	buf.WriteString("\n\n")
+	if _, err := s.rw.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *eventStream) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+}
+
+func (s *eventStream) Context() context.Context     { return s.ctx }
+func (s *eventStream) SetHeader(metadata.MD) error  { return nil }
+func (s *eventStream) SendHeader(metadata.MD) error { return nil }
+func (s *eventStream) SetTrailer(metadata.MD)       {}
+func (s *eventStream) RecvMsg(m interface{}) error  { return errors.New("Server streams can't receive") }
+
+type walletNotifyStream struct{ *eventStream }
+
+func (s walletNotifyStream) Send(m *pb.Tx) error { return s.SendMsg(m) }
+
+type dumpHeadersStream struct{ *eventStream }
+
+func (s dumpHeadersStream) Send(m *pb.Header) error { return s.SendMsg(m) }
+
+type subscribeEventsStream struct{ *eventStream }
+
+func (s subscribeEventsStream) Send(m *pb.Event) error { return s.SendMsg(m) }
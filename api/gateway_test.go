@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/BubbaJoe/spvwallet-cash/api/pb"
+	"golang.org/x/net/context"
+)
+
+// fakeAPI implements the methods the gateway tests call
+type fakeAPI struct {
+	pb.APIServer
+}
+
+func (f *fakeAPI) ChainTip(ctx context.Context, in *pb.Empty) (*pb.Height, error) {
+	return &pb.Height{Height: 1000}, nil
+}
+
+func (f *fakeAPI) Spend(ctx context.Context, in *pb.SpendInfo) (*pb.Txid, error) {
+	if in.Amount == 0 {
+		return nil, errors.New("Amount is zero")
+	}
+	return &pb.Txid{Hash: in.Address}, nil
+}
+
+func (f *fakeAPI) WalletNotify(in *pb.Empty, stream pb.API_WalletNotifyServer) error {
+	for _, txid := range []string{"a", "b"} {
+		if err := stream.Send(&pb.Tx{Txid: txid, Value: 5}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestGateway(t *testing.T) (*gateway, *authenticator, func()) {
+	dir, err := ioutil.TempDir("", "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := loadTokens(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := newGateway(&fakeAPI{}, auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, auth, func() { os.RemoveAll(dir) }
+}
+
+func gatewayRequest(g *gateway, method, path, token, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestGateway_Unary(t *testing.T) {
+	g, auth, cleanup := newTestGateway(t)
+	defer cleanup()
+
+	rec := gatewayRequest(g, "POST", "/v1/ChainTip", auth.tokens[ReadOnly], "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var height struct{ Height uint32 }
+	if err := json.Unmarshal(rec.Body.Bytes(), &height); err != nil {
+		t.Fatal(err)
+	}
+	if height.Height != 1000 {
+		t.Errorf("Expected height 1000, got %d", height.Height)
+	}
+
+	rec = gatewayRequest(g, "POST", "/v1/Spend", auth.tokens[Spend], `{"address": "addr", "amount": "5000", "feeLevel": "ECONOMIC"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"hash":"addr"`) {
+		t.Errorf("Unexpected spend response %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestGateway_Errors(t *testing.T) {
+	g, auth, cleanup := newTestGateway(t)
+	defer cleanup()
+
+	tests := []struct {
+		method, path, token, body string
+		status                    int
+	}{
+		{"POST", "/v1/Spend", auth.tokens[ReadOnly], `{"address": "addr", "amount": "1"}`, http.StatusForbidden},
+		{"POST", "/v1/Spend", "", `{}`, http.StatusUnauthorized},
+		{"POST", "/v1/Spend", "invalid", `{}`, http.StatusUnauthorized},
+		{"POST", "/v1/Spend", auth.tokens[Spend], `{"address": `, http.StatusBadRequest},
+		{"POST", "/v1/Spend", auth.tokens[Spend], `{"unknown": 1}`, http.StatusBadRequest},
+		{"POST", "/v1/Spend", auth.tokens[Spend], `{"address": "addr"}`, http.StatusInternalServerError},
+		{"GET", "/v1/Spend", auth.tokens[Spend], ``, http.StatusNotImplemented},
+		{"POST", "/v1/Balance?access_token=" + auth.tokens[Admin], "", `{}`, http.StatusUnauthorized},
+		{"POST", "/v1/WalletNotify?access_token=" + auth.tokens[Admin], "", `{}`, http.StatusUnauthorized},
+		{"POST", "/v1/Unknown", auth.tokens[Admin], `{}`, http.StatusNotFound},
+		{"POST", "/Balance", auth.tokens[Admin], `{}`, http.StatusNotFound},
+	}
+	for _, test := range tests {
+		rec := gatewayRequest(g, test.method, test.path, test.token, test.body)
+		if rec.Code != test.status {
+			t.Errorf("%s %s %s: expected %d, got %d", test.method, test.path, test.body, test.status, rec.Code)
+		}
+		var body gatewayErrorBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Message == "" {
+			t.Errorf("%s %s: expected an error body, got %s", test.method, test.path, rec.Body)
+		}
+	}
+}
+
+func TestGateway_Stream(t *testing.T) {
+	g, auth, cleanup := newTestGateway(t)
+	defer cleanup()
+
+	rec := gatewayRequest(g, "GET", "/v1/WalletNotify?access_token="+auth.tokens[ReadOnly], "", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %d: %s", rec.Code, rec.Body)
+	}
+	events := strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n")
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %q", events)
+	}
+	for i, txid := range []string{"a", "b"} {
+		var tx struct{ Txid, Value string }
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(events[i], "data: ")), &tx); err != nil {
+			t.Fatal(err)
+		}
+		if tx.Txid != txid || tx.Value != "5" {
+			t.Errorf("Unexpected event %s", events[i])
+		}
+	}
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	g, _, cleanup := newTestGateway(t)
+	defer cleanup()
+
+	rec := gatewayRequest(g, "GET", "/v1/openapi.json", "", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var spec struct {
+		Paths      map[string]map[string]interface{}
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{}
+			}
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spec.Paths["/v1/Balance"]["post"]; !ok {
+		t.Error("Balance is missing from the document")
+	}
+	if _, ok := spec.Paths["/v1/WalletNotify"]["get"]; !ok {
+		t.Error("WalletNotify is missing from the document")
+	}
+	spendInfo := spec.Components.Schemas["SpendInfo"].Properties
+	if spendInfo["amount"]["type"] != "string" || spendInfo["feeLevel"]["enum"] == nil {
+		t.Errorf("Unexpected SpendInfo schema %v", spendInfo)
+	}
+	if spec.Components.Schemas["Tx"].Properties["timestamp"]["format"] != "date-time" {
+		t.Error("Timestamps are not date-times")
+	}
+}
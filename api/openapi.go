@@ -0,0 +1,156 @@
+package api
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	bitcoincash "github.com/BubbaJoe/spvwallet-cash"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+var timestampType = reflect.TypeOf(timestamp.Timestamp{})
+
+// openAPISpec returns the OpenAPI 3 document of the gateway's methods. The
+// schemas follow the jsonpb encoding of the messages.
+func openAPISpec(methods []gatewayMethod) map[string]interface{} {
+	schemas := map[string]interface{}{
+		"Error": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"code":    map[string]interface{}{"type": "integer", "description": "The gRPC status code"},
+				"message": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+	paths := make(map[string]interface{})
+	for _, m := range methods {
+		perm, ok := methodPermissions["/pb.API/"+m.name]
+		if !ok {
+			perm = Admin
+		}
+		op := map[string]interface{}{
+			"operationId": m.name,
+			"description": "Requires a " + perm.String() + " token.",
+			"responses": map[string]interface{}{
+				"default": map[string]interface{}{
+					"description": "An error",
+					"content":     jsonContent(map[string]interface{}{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		}
+		if m.stream {
+			op["description"] = "Streams a server-sent event for each message. " + op["description"].(string)
+			op["responses"].(map[string]interface{})["200"] = map[string]interface{}{
+				"description": "A stream of events",
+				"content": map[string]interface{}{
+					"text/event-stream": map[string]interface{}{"schema": messageSchema(m.out, schemas)},
+				},
+			}
+			paths[GatewayPrefix+m.name] = map[string]interface{}{"get": op}
+			continue
+		}
+		op["requestBody"] = map[string]interface{}{
+			"required": false,
+			"content":  jsonContent(messageSchema(m.in, schemas)),
+		}
+		op["responses"].(map[string]interface{})["200"] = map[string]interface{}{
+			"description": "The response",
+			"content":     jsonContent(messageSchema(m.out, schemas)),
+		}
+		paths[GatewayPrefix+m.name] = map[string]interface{}{"post": op}
+	}
+	return map[string]interface{}{
+		"openapi": "3.0.0",
+		"info": map[string]interface{}{
+			"title":   "spvwallet-cash API",
+			"version": bitcoincash.WALLET_VERSION,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"token": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"token": []string{}}},
+	}
+}
+
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// messageSchema adds the schema of a message struct and those of the
+// messages it contains to schemas, returning a reference to it.
+func messageSchema(t reflect.Type, schemas map[string]interface{}) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	if _, ok := schemas[t.Name()]; ok {
+		return ref
+	}
+	properties := make(map[string]interface{})
+	schemas[t.Name()] = map[string]interface{}{"type": "object", "properties": properties}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("protobuf")
+		if tag == "" {
+			continue
+		}
+		name, enum := f.Name, ""
+		for _, opt := range strings.Split(tag, ",") {
+			switch {
+			case strings.HasPrefix(opt, "name="):
+				name = strings.TrimPrefix(opt, "name=")
+			case strings.HasPrefix(opt, "enum="):
+				enum = strings.TrimPrefix(opt, "enum=")
+			}
+		}
+		properties[name] = fieldSchema(f.Type, enum, schemas)
+	}
+	return ref
+}
+
+// fieldSchema returns the schema of a field of type t. enum is the name of
+// its enum type, if it has one.
+func fieldSchema(t reflect.Type, enum string, schemas map[string]interface{}) map[string]interface{} {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		return map[string]interface{}{"type": "array", "items": fieldSchema(t.Elem(), enum, schemas)}
+	}
+	if enum != "" {
+		values := proto.EnumValueMap(enum)
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool { return values[names[i]] < values[names[j]] })
+		return map[string]interface{}{"type": "string", "enum": names}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if t.Elem() == timestampType {
+			return map[string]interface{}{"type": "string", "format": "date-time"}
+		}
+		return messageSchema(t.Elem(), schemas)
+	case reflect.Slice:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": fieldSchema(t.Elem(), "", schemas)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int32, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": t.Kind().String()}
+	case reflect.Int64, reflect.Uint64:
+		// jsonpb encodes 64 bit integers as strings
+		return map[string]interface{}{"type": "string", "format": t.Kind().String()}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	}
+	return map[string]interface{}{}
+}
@@ -11,6 +11,7 @@ It has these top-level messages:
 	Empty
 	KeySelection
 	Address
+	AddressLabel
 	Height
 	Balances
 	Key
@@ -21,14 +22,32 @@ It has these top-level messages:
 	TransactionList
 	Tx
 	Txid
+	TxMemo
+	TransactionDetail
+	InputDetail
+	OutputDetail
+	HistoryRequest
+	HistoryExport
+	InvoiceRequest
+	InvoiceID
+	Invoice
+	InvoicePayment
+	InvoiceList
 	FeeLevelSelection
 	FeePerByte
 	Fee
 	SpendInfo
+	Payment
+	SpendManyInfo
+	URIPayment
+	PaymentURIRequest
+	PaymentURI
 	PeerList
 	Peer
 	Confirmations
 	Utxo
+	Unspent
+	UnspentList
 	SweepInfo
 	Input
 	Output
@@ -37,9 +56,15 @@ It has these top-level messages:
 	SignatureList
 	MultisignInfo
 	RawTx
+	PartiallySignedTx
 	EstimateFeeData
 	Header
 	ImportedKey
+	Passphrase
+	PassphraseChange
+	Event
+	Block
+	SyncProgress
 */
 package pb
 
@@ -85,6 +110,39 @@ func (x KeyPurpose) String() string {
 }
 func (KeyPurpose) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+type InvoiceStatus int32
+
+const (
+	InvoiceStatus_UNPAID         InvoiceStatus = 0
+	InvoiceStatus_PARTIALLY_PAID InvoiceStatus = 1
+	InvoiceStatus_PAID           InvoiceStatus = 2
+	InvoiceStatus_OVERPAID       InvoiceStatus = 3
+	InvoiceStatus_EXPIRED        InvoiceStatus = 4
+	InvoiceStatus_CONFIRMED      InvoiceStatus = 5
+)
+
+var InvoiceStatus_name = map[int32]string{
+	0: "UNPAID",
+	1: "PARTIALLY_PAID",
+	2: "PAID",
+	3: "OVERPAID",
+	4: "EXPIRED",
+	5: "CONFIRMED",
+}
+var InvoiceStatus_value = map[string]int32{
+	"UNPAID":         0,
+	"PARTIALLY_PAID": 1,
+	"PAID":           2,
+	"OVERPAID":       3,
+	"EXPIRED":        4,
+	"CONFIRMED":      5,
+}
+
+func (x InvoiceStatus) String() string {
+	return proto.EnumName(InvoiceStatus_name, int32(x))
+}
+func (InvoiceStatus) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 type FeeLevel int32
 
 const (
@@ -107,7 +165,46 @@ var FeeLevel_value = map[string]int32{
 func (x FeeLevel) String() string {
 	return proto.EnumName(FeeLevel_name, int32(x))
 }
-func (FeeLevel) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (FeeLevel) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+type EventType int32
+
+const (
+	EventType_NEW_TRANSACTION   EventType = 0
+	EventType_CONFIRMATION      EventType = 1
+	EventType_REORG             EventType = 2
+	EventType_NEW_TIP           EventType = 3
+	EventType_BALANCE           EventType = 4
+	EventType_PEER_CONNECTED    EventType = 5
+	EventType_PEER_DISCONNECTED EventType = 6
+	EventType_SYNC_PROGRESS     EventType = 7
+)
+
+var EventType_name = map[int32]string{
+	0: "NEW_TRANSACTION",
+	1: "CONFIRMATION",
+	2: "REORG",
+	3: "NEW_TIP",
+	4: "BALANCE",
+	5: "PEER_CONNECTED",
+	6: "PEER_DISCONNECTED",
+	7: "SYNC_PROGRESS",
+}
+var EventType_value = map[string]int32{
+	"NEW_TRANSACTION":   0,
+	"CONFIRMATION":      1,
+	"REORG":             2,
+	"NEW_TIP":           3,
+	"BALANCE":           4,
+	"PEER_CONNECTED":    5,
+	"PEER_DISCONNECTED": 6,
+	"SYNC_PROGRESS":     7,
+}
+
+func (x EventType) String() string {
+	return proto.EnumName(EventType_name, int32(x))
+}
+func (EventType) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 type Empty struct {
 }
@@ -134,7 +231,8 @@ func (m *KeySelection) GetPurpose() KeyPurpose {
 }
 
 type Address struct {
-	Addr string `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
+	Addr  string `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
+	Label string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
 }
 
 func (m *Address) Reset()                    { *m = Address{} }
@@ -149,6 +247,37 @@ func (m *Address) GetAddr() string {
 	return ""
 }
 
+func (m *Address) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+type AddressLabel struct {
+	Addr  string `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
+	Label string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+}
+
+func (m *AddressLabel) Reset()                    { *m = AddressLabel{} }
+func (m *AddressLabel) String() string            { return proto.CompactTextString(m) }
+func (*AddressLabel) ProtoMessage()               {}
+func (*AddressLabel) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *AddressLabel) GetAddr() string {
+	if m != nil {
+		return m.Addr
+	}
+	return ""
+}
+
+func (m *AddressLabel) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
 type Height struct {
 	Height uint32 `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
 }
@@ -156,7 +285,7 @@ type Height struct {
 func (m *Height) Reset()                    { *m = Height{} }
 func (m *Height) String() string            { return proto.CompactTextString(m) }
 func (*Height) ProtoMessage()               {}
-func (*Height) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*Height) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *Height) GetHeight() uint32 {
 	if m != nil {
@@ -168,12 +297,13 @@ func (m *Height) GetHeight() uint32 {
 type Balances struct {
 	Confirmed   uint64 `protobuf:"varint,1,opt,name=confirmed" json:"confirmed,omitempty"`
 	Unconfirmed uint64 `protobuf:"varint,2,opt,name=unconfirmed" json:"unconfirmed,omitempty"`
+	Locked      uint64 `protobuf:"varint,3,opt,name=locked" json:"locked,omitempty"`
 }
 
 func (m *Balances) Reset()                    { *m = Balances{} }
 func (m *Balances) String() string            { return proto.CompactTextString(m) }
 func (*Balances) ProtoMessage()               {}
-func (*Balances) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*Balances) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *Balances) GetConfirmed() uint64 {
 	if m != nil {
@@ -189,6 +319,13 @@ func (m *Balances) GetUnconfirmed() uint64 {
 	return 0
 }
 
+func (m *Balances) GetLocked() uint64 {
+	if m != nil {
+		return m.Locked
+	}
+	return 0
+}
+
 type Key struct {
 	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 }
@@ -196,7 +333,7 @@ type Key struct {
 func (m *Key) Reset()                    { *m = Key{} }
 func (m *Key) String() string            { return proto.CompactTextString(m) }
 func (*Key) ProtoMessage()               {}
-func (*Key) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*Key) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *Key) GetKey() string {
 	if m != nil {
@@ -212,7 +349,7 @@ type Keys struct {
 func (m *Keys) Reset()                    { *m = Keys{} }
 func (m *Keys) String() string            { return proto.CompactTextString(m) }
 func (*Keys) ProtoMessage()               {}
-func (*Keys) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*Keys) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *Keys) GetKeys() []*Key {
 	if m != nil {
@@ -228,7 +365,7 @@ type Addresses struct {
 func (m *Addresses) Reset()                    { *m = Addresses{} }
 func (m *Addresses) String() string            { return proto.CompactTextString(m) }
 func (*Addresses) ProtoMessage()               {}
-func (*Addresses) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*Addresses) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *Addresses) GetAddresses() []*Address {
 	if m != nil {
@@ -244,7 +381,7 @@ type BoolResponse struct {
 func (m *BoolResponse) Reset()                    { *m = BoolResponse{} }
 func (m *BoolResponse) String() string            { return proto.CompactTextString(m) }
 func (*BoolResponse) ProtoMessage()               {}
-func (*BoolResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*BoolResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *BoolResponse) GetBool() bool {
 	if m != nil {
@@ -260,7 +397,7 @@ type NetParams struct {
 func (m *NetParams) Reset()                    { *m = NetParams{} }
 func (m *NetParams) String() string            { return proto.CompactTextString(m) }
 func (*NetParams) ProtoMessage()               {}
-func (*NetParams) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*NetParams) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *NetParams) GetName() string {
 	if m != nil {
@@ -276,7 +413,7 @@ type TransactionList struct {
 func (m *TransactionList) Reset()                    { *m = TransactionList{} }
 func (m *TransactionList) String() string            { return proto.CompactTextString(m) }
 func (*TransactionList) ProtoMessage()               {}
-func (*TransactionList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*TransactionList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *TransactionList) GetTransactions() []*Tx {
 	if m != nil {
@@ -292,12 +429,13 @@ type Tx struct {
 	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=timestamp" json:"timestamp,omitempty"`
 	WatchOnly bool                       `protobuf:"varint,5,opt,name=watchOnly" json:"watchOnly,omitempty"`
 	Raw       []byte                     `protobuf:"bytes,6,opt,name=raw,proto3" json:"raw,omitempty"`
+	Memo      string                     `protobuf:"bytes,7,opt,name=memo" json:"memo,omitempty"`
 }
 
 func (m *Tx) Reset()                    { *m = Tx{} }
 func (m *Tx) String() string            { return proto.CompactTextString(m) }
 func (*Tx) ProtoMessage()               {}
-func (*Tx) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*Tx) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *Tx) GetTxid() string {
 	if m != nil {
@@ -341,6 +479,13 @@ func (m *Tx) GetRaw() []byte {
 	return nil
 }
 
+func (m *Tx) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
 type Txid struct {
 	Hash string `protobuf:"bytes,1,opt,name=hash" json:"hash,omitempty"`
 }
@@ -348,7 +493,7 @@ type Txid struct {
 func (m *Txid) Reset()                    { *m = Txid{} }
 func (m *Txid) String() string            { return proto.CompactTextString(m) }
 func (*Txid) ProtoMessage()               {}
-func (*Txid) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*Txid) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *Txid) GetHash() string {
 	if m != nil {
@@ -357,578 +502,1547 @@ func (m *Txid) GetHash() string {
 	return ""
 }
 
-type FeeLevelSelection struct {
-	FeeLevel FeeLevel `protobuf:"varint,1,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+type TxMemo struct {
+	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Memo string `protobuf:"bytes,2,opt,name=memo" json:"memo,omitempty"`
 }
 
-func (m *FeeLevelSelection) Reset()                    { *m = FeeLevelSelection{} }
-func (m *FeeLevelSelection) String() string            { return proto.CompactTextString(m) }
-func (*FeeLevelSelection) ProtoMessage()               {}
-func (*FeeLevelSelection) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (m *TxMemo) Reset()                    { *m = TxMemo{} }
+func (m *TxMemo) String() string            { return proto.CompactTextString(m) }
+func (*TxMemo) ProtoMessage()               {}
+func (*TxMemo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
-func (m *FeeLevelSelection) GetFeeLevel() FeeLevel {
+func (m *TxMemo) GetTxid() string {
 	if m != nil {
-		return m.FeeLevel
+		return m.Txid
 	}
-	return FeeLevel_ECONOMIC
+	return ""
 }
 
-type FeePerByte struct {
-	Fee uint64 `protobuf:"varint,1,opt,name=fee" json:"fee,omitempty"`
+func (m *TxMemo) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
 }
 
-func (m *FeePerByte) Reset()                    { *m = FeePerByte{} }
-func (m *FeePerByte) String() string            { return proto.CompactTextString(m) }
-func (*FeePerByte) ProtoMessage()               {}
-func (*FeePerByte) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+type TransactionDetail struct {
+	Tx            *Tx             `protobuf:"bytes,1,opt,name=tx" json:"tx,omitempty"`
+	Confirmations int64           `protobuf:"varint,2,opt,name=confirmations" json:"confirmations,omitempty"`
+	Status        string          `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	Inputs        []*InputDetail  `protobuf:"bytes,4,rep,name=inputs" json:"inputs,omitempty"`
+	Outputs       []*OutputDetail `protobuf:"bytes,5,rep,name=outputs" json:"outputs,omitempty"`
+	Fee           int64           `protobuf:"varint,6,opt,name=fee" json:"fee,omitempty"`
+	FeeRate       float64         `protobuf:"fixed64,7,opt,name=feeRate" json:"feeRate,omitempty"`
+	Size          uint32          `protobuf:"varint,8,opt,name=size" json:"size,omitempty"`
+}
 
-func (m *FeePerByte) GetFee() uint64 {
+func (m *TransactionDetail) Reset()                    { *m = TransactionDetail{} }
+func (m *TransactionDetail) String() string            { return proto.CompactTextString(m) }
+func (*TransactionDetail) ProtoMessage()               {}
+func (*TransactionDetail) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *TransactionDetail) GetTx() *Tx {
 	if m != nil {
-		return m.Fee
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *TransactionDetail) GetConfirmations() int64 {
+	if m != nil {
+		return m.Confirmations
 	}
 	return 0
 }
 
-type Fee struct {
-	Fee uint64 `protobuf:"varint,1,opt,name=fee" json:"fee,omitempty"`
+func (m *TransactionDetail) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
 }
 
-func (m *Fee) Reset()                    { *m = Fee{} }
-func (m *Fee) String() string            { return proto.CompactTextString(m) }
-func (*Fee) ProtoMessage()               {}
-func (*Fee) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (m *TransactionDetail) GetInputs() []*InputDetail {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
 
-func (m *Fee) GetFee() uint64 {
+func (m *TransactionDetail) GetOutputs() []*OutputDetail {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *TransactionDetail) GetFee() int64 {
 	if m != nil {
 		return m.Fee
 	}
 	return 0
 }
 
-type SpendInfo struct {
-	Address  string   `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Amount   uint64   `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
-	FeeLevel FeeLevel `protobuf:"varint,3,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+func (m *TransactionDetail) GetFeeRate() float64 {
+	if m != nil {
+		return m.FeeRate
+	}
+	return 0
 }
 
-func (m *SpendInfo) Reset()                    { *m = SpendInfo{} }
-func (m *SpendInfo) String() string            { return proto.CompactTextString(m) }
-func (*SpendInfo) ProtoMessage()               {}
-func (*SpendInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (m *TransactionDetail) GetSize() uint32 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
 
-func (m *SpendInfo) GetAddress() string {
+type InputDetail struct {
+	Txid    string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Index   uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Address string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	Value   int64  `protobuf:"varint,4,opt,name=value" json:"value,omitempty"`
+	Mine    bool   `protobuf:"varint,5,opt,name=mine" json:"mine,omitempty"`
+}
+
+func (m *InputDetail) Reset()                    { *m = InputDetail{} }
+func (m *InputDetail) String() string            { return proto.CompactTextString(m) }
+func (*InputDetail) ProtoMessage()               {}
+func (*InputDetail) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *InputDetail) GetTxid() string {
 	if m != nil {
-		return m.Address
+		return m.Txid
 	}
 	return ""
 }
 
-func (m *SpendInfo) GetAmount() uint64 {
+func (m *InputDetail) GetIndex() uint32 {
 	if m != nil {
-		return m.Amount
+		return m.Index
 	}
 	return 0
 }
 
-func (m *SpendInfo) GetFeeLevel() FeeLevel {
+func (m *InputDetail) GetAddress() string {
 	if m != nil {
-		return m.FeeLevel
+		return m.Address
 	}
-	return FeeLevel_ECONOMIC
+	return ""
 }
 
-type PeerList struct {
-	Peers []*Peer `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+func (m *InputDetail) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
 }
 
-func (m *PeerList) Reset()                    { *m = PeerList{} }
-func (m *PeerList) String() string            { return proto.CompactTextString(m) }
-func (*PeerList) ProtoMessage()               {}
-func (*PeerList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
-
-func (m *PeerList) GetPeers() []*Peer {
+func (m *InputDetail) GetMine() bool {
 	if m != nil {
-		return m.Peers
+		return m.Mine
 	}
-	return nil
+	return false
 }
 
-type Peer struct {
-	Address         string                     `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	BytesSent       uint64                     `protobuf:"varint,2,opt,name=bytesSent" json:"bytesSent,omitempty"`
-	BytesReceived   uint64                     `protobuf:"varint,3,opt,name=bytesReceived" json:"bytesReceived,omitempty"`
-	Connected       bool                       `protobuf:"varint,4,opt,name=connected" json:"connected,omitempty"`
-	ID              int32                      `protobuf:"varint,5,opt,name=ID" json:"ID,omitempty"`
-	LastBlock       int32                      `protobuf:"varint,6,opt,name=lastBlock" json:"lastBlock,omitempty"`
-	ProtocolVersion uint32                     `protobuf:"varint,7,opt,name=protocolVersion" json:"protocolVersion,omitempty"`
-	Services        string                     `protobuf:"bytes,8,opt,name=services" json:"services,omitempty"`
-	UserAgent       string                     `protobuf:"bytes,9,opt,name=userAgent" json:"userAgent,omitempty"`
-	TimeConnected   *google_protobuf.Timestamp `protobuf:"bytes,10,opt,name=timeConnected" json:"timeConnected,omitempty"`
+type OutputDetail struct {
+	Index   uint32   `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Address string   `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Value   int64    `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+	Mine    bool     `protobuf:"varint,4,opt,name=mine" json:"mine,omitempty"`
+	Data    [][]byte `protobuf:"bytes,5,rep,name=data,proto3" json:"data,omitempty"`
 }
 
-func (m *Peer) Reset()                    { *m = Peer{} }
-func (m *Peer) String() string            { return proto.CompactTextString(m) }
-func (*Peer) ProtoMessage()               {}
-func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (m *OutputDetail) Reset()                    { *m = OutputDetail{} }
+func (m *OutputDetail) String() string            { return proto.CompactTextString(m) }
+func (*OutputDetail) ProtoMessage()               {}
+func (*OutputDetail) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
-func (m *Peer) GetAddress() string {
+func (m *OutputDetail) GetIndex() uint32 {
 	if m != nil {
-		return m.Address
+		return m.Index
 	}
-	return ""
+	return 0
 }
 
-func (m *Peer) GetBytesSent() uint64 {
+func (m *OutputDetail) GetAddress() string {
 	if m != nil {
-		return m.BytesSent
+		return m.Address
 	}
-	return 0
+	return ""
 }
 
-func (m *Peer) GetBytesReceived() uint64 {
+func (m *OutputDetail) GetValue() int64 {
 	if m != nil {
-		return m.BytesReceived
+		return m.Value
 	}
 	return 0
 }
 
-func (m *Peer) GetConnected() bool {
+func (m *OutputDetail) GetMine() bool {
 	if m != nil {
-		return m.Connected
+		return m.Mine
 	}
 	return false
 }
 
-func (m *Peer) GetID() int32 {
+func (m *OutputDetail) GetData() [][]byte {
 	if m != nil {
-		return m.ID
+		return m.Data
 	}
-	return 0
+	return nil
 }
 
-func (m *Peer) GetLastBlock() int32 {
+type HistoryRequest struct {
+	Format   string                     `protobuf:"bytes,1,opt,name=format" json:"format,omitempty"`
+	From     *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=from" json:"from,omitempty"`
+	To       *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=to" json:"to,omitempty"`
+	Currency string                     `protobuf:"bytes,4,opt,name=currency" json:"currency,omitempty"`
+}
+
+func (m *HistoryRequest) Reset()                    { *m = HistoryRequest{} }
+func (m *HistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*HistoryRequest) ProtoMessage()               {}
+func (*HistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *HistoryRequest) GetFormat() string {
 	if m != nil {
-		return m.LastBlock
+		return m.Format
 	}
-	return 0
+	return ""
 }
 
-func (m *Peer) GetProtocolVersion() uint32 {
+func (m *HistoryRequest) GetFrom() *google_protobuf.Timestamp {
 	if m != nil {
-		return m.ProtocolVersion
+		return m.From
 	}
-	return 0
+	return nil
 }
 
-func (m *Peer) GetServices() string {
+func (m *HistoryRequest) GetTo() *google_protobuf.Timestamp {
 	if m != nil {
-		return m.Services
+		return m.To
 	}
-	return ""
+	return nil
 }
 
-func (m *Peer) GetUserAgent() string {
+func (m *HistoryRequest) GetCurrency() string {
 	if m != nil {
-		return m.UserAgent
+		return m.Currency
 	}
 	return ""
 }
 
-func (m *Peer) GetTimeConnected() *google_protobuf.Timestamp {
+type HistoryExport struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *HistoryExport) Reset()                    { *m = HistoryExport{} }
+func (m *HistoryExport) String() string            { return proto.CompactTextString(m) }
+func (*HistoryExport) ProtoMessage()               {}
+func (*HistoryExport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *HistoryExport) GetData() []byte {
 	if m != nil {
-		return m.TimeConnected
+		return m.Data
 	}
 	return nil
 }
 
-type Confirmations struct {
-	Confirmations uint32 `protobuf:"varint,1,opt,name=confirmations" json:"confirmations,omitempty"`
+type InvoiceRequest struct {
+	Amount     uint64  `protobuf:"varint,1,opt,name=amount" json:"amount,omitempty"`
+	FiatAmount float64 `protobuf:"fixed64,2,opt,name=fiatAmount" json:"fiatAmount,omitempty"`
+	Currency   string  `protobuf:"bytes,3,opt,name=currency" json:"currency,omitempty"`
+	Expiry     uint32  `protobuf:"varint,4,opt,name=expiry" json:"expiry,omitempty"`
+	Memo       string  `protobuf:"bytes,5,opt,name=memo" json:"memo,omitempty"`
 }
 
-func (m *Confirmations) Reset()                    { *m = Confirmations{} }
-func (m *Confirmations) String() string            { return proto.CompactTextString(m) }
-func (*Confirmations) ProtoMessage()               {}
-func (*Confirmations) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (m *InvoiceRequest) Reset()                    { *m = InvoiceRequest{} }
+func (m *InvoiceRequest) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceRequest) ProtoMessage()               {}
+func (*InvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
-func (m *Confirmations) GetConfirmations() uint32 {
+func (m *InvoiceRequest) GetAmount() uint64 {
 	if m != nil {
-		return m.Confirmations
+		return m.Amount
 	}
 	return 0
 }
 
-type Utxo struct {
-	Txid  string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
-	Index uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
-	Value uint64 `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+func (m *InvoiceRequest) GetFiatAmount() float64 {
+	if m != nil {
+		return m.FiatAmount
+	}
+	return 0
 }
 
-func (m *Utxo) Reset()                    { *m = Utxo{} }
-func (m *Utxo) String() string            { return proto.CompactTextString(m) }
-func (*Utxo) ProtoMessage()               {}
-func (*Utxo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
-
-func (m *Utxo) GetTxid() string {
+func (m *InvoiceRequest) GetCurrency() string {
 	if m != nil {
-		return m.Txid
+		return m.Currency
 	}
 	return ""
 }
 
-func (m *Utxo) GetIndex() uint32 {
+func (m *InvoiceRequest) GetExpiry() uint32 {
 	if m != nil {
-		return m.Index
+		return m.Expiry
 	}
 	return 0
 }
 
-func (m *Utxo) GetValue() uint64 {
+func (m *InvoiceRequest) GetMemo() string {
 	if m != nil {
-		return m.Value
+		return m.Memo
 	}
-	return 0
+	return ""
 }
 
-type SweepInfo struct {
-	Utxos        []*Utxo  `protobuf:"bytes,1,rep,name=utxos" json:"utxos,omitempty"`
-	Address      string   `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
-	Key          string   `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
-	RedeemScript []byte   `protobuf:"bytes,4,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
-	FeeLevel     FeeLevel `protobuf:"varint,5,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+type InvoiceID struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
 }
 
-func (m *SweepInfo) Reset()                    { *m = SweepInfo{} }
-func (m *SweepInfo) String() string            { return proto.CompactTextString(m) }
-func (*SweepInfo) ProtoMessage()               {}
-func (*SweepInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+func (m *InvoiceID) Reset()                    { *m = InvoiceID{} }
+func (m *InvoiceID) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceID) ProtoMessage()               {}
+func (*InvoiceID) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
-func (m *SweepInfo) GetUtxos() []*Utxo {
+func (m *InvoiceID) GetId() string {
 	if m != nil {
-		return m.Utxos
+		return m.Id
 	}
-	return nil
+	return ""
 }
 
-func (m *SweepInfo) GetAddress() string {
+type Invoice struct {
+	Id           string                     `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Address      string                     `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Uri          string                     `protobuf:"bytes,3,opt,name=uri" json:"uri,omitempty"`
+	Amount       uint64                     `protobuf:"varint,4,opt,name=amount" json:"amount,omitempty"`
+	FiatAmount   float64                    `protobuf:"fixed64,5,opt,name=fiatAmount" json:"fiatAmount,omitempty"`
+	Currency     string                     `protobuf:"bytes,6,opt,name=currency" json:"currency,omitempty"`
+	ExchangeRate float64                    `protobuf:"fixed64,7,opt,name=exchangeRate" json:"exchangeRate,omitempty"`
+	Memo         string                     `protobuf:"bytes,8,opt,name=memo" json:"memo,omitempty"`
+	Created      *google_protobuf.Timestamp `protobuf:"bytes,9,opt,name=created" json:"created,omitempty"`
+	Expires      *google_protobuf.Timestamp `protobuf:"bytes,10,opt,name=expires" json:"expires,omitempty"`
+	Payments     []*InvoicePayment          `protobuf:"bytes,11,rep,name=payments" json:"payments,omitempty"`
+	Received     uint64                     `protobuf:"varint,12,opt,name=received" json:"received,omitempty"`
+	Status       InvoiceStatus              `protobuf:"varint,13,opt,name=status,enum=pb.InvoiceStatus" json:"status,omitempty"`
+}
+
+func (m *Invoice) Reset()                    { *m = Invoice{} }
+func (m *Invoice) String() string            { return proto.CompactTextString(m) }
+func (*Invoice) ProtoMessage()               {}
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *Invoice) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Invoice) GetAddress() string {
 	if m != nil {
 		return m.Address
 	}
 	return ""
 }
 
-func (m *SweepInfo) GetKey() string {
+func (m *Invoice) GetUri() string {
 	if m != nil {
-		return m.Key
+		return m.Uri
 	}
 	return ""
 }
 
-func (m *SweepInfo) GetRedeemScript() []byte {
+func (m *Invoice) GetAmount() uint64 {
 	if m != nil {
-		return m.RedeemScript
+		return m.Amount
 	}
-	return nil
+	return 0
 }
 
-func (m *SweepInfo) GetFeeLevel() FeeLevel {
+func (m *Invoice) GetFiatAmount() float64 {
 	if m != nil {
-		return m.FeeLevel
+		return m.FiatAmount
 	}
-	return FeeLevel_ECONOMIC
+	return 0
 }
 
-type Input struct {
-	Txid  string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
-	Index uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+func (m *Invoice) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
 }
 
-func (m *Input) Reset()                    { *m = Input{} }
-func (m *Input) String() string            { return proto.CompactTextString(m) }
-func (*Input) ProtoMessage()               {}
-func (*Input) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+func (m *Invoice) GetExchangeRate() float64 {
+	if m != nil {
+		return m.ExchangeRate
+	}
+	return 0
+}
 
-func (m *Input) GetTxid() string {
+func (m *Invoice) GetMemo() string {
 	if m != nil {
-		return m.Txid
+		return m.Memo
 	}
 	return ""
 }
 
-func (m *Input) GetIndex() uint32 {
+func (m *Invoice) GetCreated() *google_protobuf.Timestamp {
 	if m != nil {
-		return m.Index
+		return m.Created
 	}
-	return 0
+	return nil
 }
 
-type Output struct {
-	ScriptPubKey []byte `protobuf:"bytes,1,opt,name=scriptPubKey,proto3" json:"scriptPubKey,omitempty"`
-	Value        uint64 `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
+func (m *Invoice) GetExpires() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
 }
 
-func (m *Output) Reset()                    { *m = Output{} }
-func (m *Output) String() string            { return proto.CompactTextString(m) }
-func (*Output) ProtoMessage()               {}
-func (*Output) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
-
-func (m *Output) GetScriptPubKey() []byte {
+func (m *Invoice) GetPayments() []*InvoicePayment {
 	if m != nil {
-		return m.ScriptPubKey
+		return m.Payments
 	}
 	return nil
 }
 
-func (m *Output) GetValue() uint64 {
+func (m *Invoice) GetReceived() uint64 {
 	if m != nil {
-		return m.Value
+		return m.Received
 	}
 	return 0
 }
 
-type Signature struct {
-	Index     uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
-	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+func (m *Invoice) GetStatus() InvoiceStatus {
+	if m != nil {
+		return m.Status
+	}
+	return InvoiceStatus_UNPAID
 }
 
-func (m *Signature) Reset()                    { *m = Signature{} }
-func (m *Signature) String() string            { return proto.CompactTextString(m) }
-func (*Signature) ProtoMessage()               {}
-func (*Signature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+type InvoicePayment struct {
+	Txid          string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Index         uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Value         int64  `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+	Height        int32  `protobuf:"varint,4,opt,name=height" json:"height,omitempty"`
+	Confirmations uint32 `protobuf:"varint,5,opt,name=confirmations" json:"confirmations,omitempty"`
+}
 
-func (m *Signature) GetIndex() uint32 {
+func (m *InvoicePayment) Reset()                    { *m = InvoicePayment{} }
+func (m *InvoicePayment) String() string            { return proto.CompactTextString(m) }
+func (*InvoicePayment) ProtoMessage()               {}
+func (*InvoicePayment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *InvoicePayment) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *InvoicePayment) GetIndex() uint32 {
 	if m != nil {
 		return m.Index
 	}
 	return 0
 }
 
-func (m *Signature) GetSignature() []byte {
+func (m *InvoicePayment) GetValue() int64 {
 	if m != nil {
-		return m.Signature
+		return m.Value
 	}
-	return nil
+	return 0
 }
 
-type CreateMultisigInfo struct {
-	Inputs       []*Input  `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
-	Outputs      []*Output `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
-	Key          string    `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
-	RedeemScript []byte    `protobuf:"bytes,4,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
-	FeePerByte   uint64    `protobuf:"varint,5,opt,name=feePerByte" json:"feePerByte,omitempty"`
+func (m *InvoicePayment) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
 }
 
-func (m *CreateMultisigInfo) Reset()                    { *m = CreateMultisigInfo{} }
-func (m *CreateMultisigInfo) String() string            { return proto.CompactTextString(m) }
-func (*CreateMultisigInfo) ProtoMessage()               {}
-func (*CreateMultisigInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
-
-func (m *CreateMultisigInfo) GetInputs() []*Input {
+func (m *InvoicePayment) GetConfirmations() uint32 {
 	if m != nil {
-		return m.Inputs
+		return m.Confirmations
 	}
-	return nil
+	return 0
 }
 
-func (m *CreateMultisigInfo) GetOutputs() []*Output {
+type InvoiceList struct {
+	Invoices []*Invoice `protobuf:"bytes,1,rep,name=invoices" json:"invoices,omitempty"`
+}
+
+func (m *InvoiceList) Reset()                    { *m = InvoiceList{} }
+func (m *InvoiceList) String() string            { return proto.CompactTextString(m) }
+func (*InvoiceList) ProtoMessage()               {}
+func (*InvoiceList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *InvoiceList) GetInvoices() []*Invoice {
 	if m != nil {
-		return m.Outputs
+		return m.Invoices
 	}
 	return nil
 }
 
-func (m *CreateMultisigInfo) GetKey() string {
+type FeeLevelSelection struct {
+	FeeLevel FeeLevel `protobuf:"varint,1,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+}
+
+func (m *FeeLevelSelection) Reset()                    { *m = FeeLevelSelection{} }
+func (m *FeeLevelSelection) String() string            { return proto.CompactTextString(m) }
+func (*FeeLevelSelection) ProtoMessage()               {}
+func (*FeeLevelSelection) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *FeeLevelSelection) GetFeeLevel() FeeLevel {
 	if m != nil {
-		return m.Key
+		return m.FeeLevel
 	}
-	return ""
+	return FeeLevel_ECONOMIC
 }
 
-func (m *CreateMultisigInfo) GetRedeemScript() []byte {
+type FeePerByte struct {
+	Fee uint64 `protobuf:"varint,1,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *FeePerByte) Reset()                    { *m = FeePerByte{} }
+func (m *FeePerByte) String() string            { return proto.CompactTextString(m) }
+func (*FeePerByte) ProtoMessage()               {}
+func (*FeePerByte) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *FeePerByte) GetFee() uint64 {
 	if m != nil {
-		return m.RedeemScript
+		return m.Fee
 	}
-	return nil
+	return 0
 }
 
-func (m *CreateMultisigInfo) GetFeePerByte() uint64 {
+type Fee struct {
+	Fee uint64 `protobuf:"varint,1,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *Fee) Reset()                    { *m = Fee{} }
+func (m *Fee) String() string            { return proto.CompactTextString(m) }
+func (*Fee) ProtoMessage()               {}
+func (*Fee) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *Fee) GetFee() uint64 {
 	if m != nil {
-		return m.FeePerByte
+		return m.Fee
 	}
 	return 0
 }
 
-type SignatureList struct {
-	Sigs []*Signature `protobuf:"bytes,1,rep,name=sigs" json:"sigs,omitempty"`
+type SpendInfo struct {
+	Address       string   `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Amount        uint64   `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	FeeLevel      FeeLevel `protobuf:"varint,3,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+	Inputs        []*Input `protobuf:"bytes,4,rep,name=inputs" json:"inputs,omitempty"`
+	ExcludeInputs []*Input `protobuf:"bytes,5,rep,name=excludeInputs" json:"excludeInputs,omitempty"`
+	Memo          string   `protobuf:"bytes,6,opt,name=memo" json:"memo,omitempty"`
+	FeePerByte    uint64   `protobuf:"varint,7,opt,name=feePerByte" json:"feePerByte,omitempty"`
+	Fee           uint64   `protobuf:"varint,8,opt,name=fee" json:"fee,omitempty"`
 }
 
-func (m *SignatureList) Reset()                    { *m = SignatureList{} }
-func (m *SignatureList) String() string            { return proto.CompactTextString(m) }
-func (*SignatureList) ProtoMessage()               {}
-func (*SignatureList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+func (m *SpendInfo) Reset()                    { *m = SpendInfo{} }
+func (m *SpendInfo) String() string            { return proto.CompactTextString(m) }
+func (*SpendInfo) ProtoMessage()               {}
+func (*SpendInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
 
-func (m *SignatureList) GetSigs() []*Signature {
+func (m *SpendInfo) GetAddress() string {
 	if m != nil {
-		return m.Sigs
+		return m.Address
 	}
-	return nil
+	return ""
 }
 
-type MultisignInfo struct {
-	Inputs       []*Input     `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
-	Outputs      []*Output    `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
-	Sig1         []*Signature `protobuf:"bytes,3,rep,name=sig1" json:"sig1,omitempty"`
-	Sig2         []*Signature `protobuf:"bytes,4,rep,name=sig2" json:"sig2,omitempty"`
-	RedeemScript []byte       `protobuf:"bytes,5,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
-	FeePerByte   uint64       `protobuf:"varint,6,opt,name=feePerByte" json:"feePerByte,omitempty"`
-	Broadcast    bool         `protobuf:"varint,7,opt,name=broadcast" json:"broadcast,omitempty"`
+func (m *SpendInfo) GetAmount() uint64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
 }
 
-func (m *MultisignInfo) Reset()                    { *m = MultisignInfo{} }
-func (m *MultisignInfo) String() string            { return proto.CompactTextString(m) }
-func (*MultisignInfo) ProtoMessage()               {}
-func (*MultisignInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+func (m *SpendInfo) GetFeeLevel() FeeLevel {
+	if m != nil {
+		return m.FeeLevel
+	}
+	return FeeLevel_ECONOMIC
+}
 
-func (m *MultisignInfo) GetInputs() []*Input {
+func (m *SpendInfo) GetInputs() []*Input {
 	if m != nil {
 		return m.Inputs
 	}
 	return nil
 }
 
-func (m *MultisignInfo) GetOutputs() []*Output {
+func (m *SpendInfo) GetExcludeInputs() []*Input {
 	if m != nil {
-		return m.Outputs
+		return m.ExcludeInputs
 	}
 	return nil
 }
 
-func (m *MultisignInfo) GetSig1() []*Signature {
+func (m *SpendInfo) GetMemo() string {
 	if m != nil {
-		return m.Sig1
+		return m.Memo
 	}
-	return nil
+	return ""
 }
 
-func (m *MultisignInfo) GetSig2() []*Signature {
+func (m *SpendInfo) GetFeePerByte() uint64 {
 	if m != nil {
-		return m.Sig2
+		return m.FeePerByte
 	}
-	return nil
+	return 0
 }
 
-func (m *MultisignInfo) GetRedeemScript() []byte {
+func (m *SpendInfo) GetFee() uint64 {
 	if m != nil {
-		return m.RedeemScript
+		return m.Fee
 	}
-	return nil
+	return 0
 }
 
-func (m *MultisignInfo) GetFeePerByte() uint64 {
+type Payment struct {
+	Address string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Amount  uint64 `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *Payment) Reset()                    { *m = Payment{} }
+func (m *Payment) String() string            { return proto.CompactTextString(m) }
+func (*Payment) ProtoMessage()               {}
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *Payment) GetAddress() string {
 	if m != nil {
-		return m.FeePerByte
+		return m.Address
 	}
-	return 0
+	return ""
 }
 
-func (m *MultisignInfo) GetBroadcast() bool {
+func (m *Payment) GetAmount() uint64 {
 	if m != nil {
-		return m.Broadcast
+		return m.Amount
 	}
-	return false
+	return 0
 }
 
-type RawTx struct {
-	Tx []byte `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+type SpendManyInfo struct {
+	Payments      []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
+	FeeLevel      FeeLevel   `protobuf:"varint,2,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+	Inputs        []*Input   `protobuf:"bytes,3,rep,name=inputs" json:"inputs,omitempty"`
+	ExcludeInputs []*Input   `protobuf:"bytes,4,rep,name=excludeInputs" json:"excludeInputs,omitempty"`
 }
 
-func (m *RawTx) Reset()                    { *m = RawTx{} }
-func (m *RawTx) String() string            { return proto.CompactTextString(m) }
-func (*RawTx) ProtoMessage()               {}
-func (*RawTx) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+func (m *SpendManyInfo) Reset()                    { *m = SpendManyInfo{} }
+func (m *SpendManyInfo) String() string            { return proto.CompactTextString(m) }
+func (*SpendManyInfo) ProtoMessage()               {}
+func (*SpendManyInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
 
-func (m *RawTx) GetTx() []byte {
+func (m *SpendManyInfo) GetPayments() []*Payment {
 	if m != nil {
-		return m.Tx
+		return m.Payments
 	}
 	return nil
 }
 
-type EstimateFeeData struct {
-	Inputs     []*Input  `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
-	Outputs    []*Output `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
-	FeePerByte uint64    `protobuf:"varint,3,opt,name=feePerByte" json:"feePerByte,omitempty"`
+func (m *SpendManyInfo) GetFeeLevel() FeeLevel {
+	if m != nil {
+		return m.FeeLevel
+	}
+	return FeeLevel_ECONOMIC
 }
 
-func (m *EstimateFeeData) Reset()                    { *m = EstimateFeeData{} }
-func (m *EstimateFeeData) String() string            { return proto.CompactTextString(m) }
-func (*EstimateFeeData) ProtoMessage()               {}
-func (*EstimateFeeData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
-
-func (m *EstimateFeeData) GetInputs() []*Input {
+func (m *SpendManyInfo) GetInputs() []*Input {
 	if m != nil {
 		return m.Inputs
 	}
 	return nil
 }
 
-func (m *EstimateFeeData) GetOutputs() []*Output {
+func (m *SpendManyInfo) GetExcludeInputs() []*Input {
 	if m != nil {
-		return m.Outputs
+		return m.ExcludeInputs
 	}
 	return nil
 }
 
-func (m *EstimateFeeData) GetFeePerByte() uint64 {
+type URIPayment struct {
+	Uri      string   `protobuf:"bytes,1,opt,name=uri" json:"uri,omitempty"`
+	FeeLevel FeeLevel `protobuf:"varint,2,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+}
+
+func (m *URIPayment) Reset()                    { *m = URIPayment{} }
+func (m *URIPayment) String() string            { return proto.CompactTextString(m) }
+func (*URIPayment) ProtoMessage()               {}
+func (*URIPayment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *URIPayment) GetUri() string {
 	if m != nil {
-		return m.FeePerByte
+		return m.Uri
 	}
-	return 0
+	return ""
 }
 
-type Header struct {
-	Entry string `protobuf:"bytes,1,opt,name=entry" json:"entry,omitempty"`
+func (m *URIPayment) GetFeeLevel() FeeLevel {
+	if m != nil {
+		return m.FeeLevel
+	}
+	return FeeLevel_ECONOMIC
 }
 
-func (m *Header) Reset()                    { *m = Header{} }
-func (m *Header) String() string            { return proto.CompactTextString(m) }
-func (*Header) ProtoMessage()               {}
-func (*Header) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+type PaymentURIRequest struct {
+	Amount  uint64 `protobuf:"varint,1,opt,name=amount" json:"amount,omitempty"`
+	Label   string `protobuf:"bytes,2,opt,name=label" json:"label,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message" json:"message,omitempty"`
+}
 
-func (m *Header) GetEntry() string {
+func (m *PaymentURIRequest) Reset()                    { *m = PaymentURIRequest{} }
+func (m *PaymentURIRequest) String() string            { return proto.CompactTextString(m) }
+func (*PaymentURIRequest) ProtoMessage()               {}
+func (*PaymentURIRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *PaymentURIRequest) GetAmount() uint64 {
 	if m != nil {
-		return m.Entry
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *PaymentURIRequest) GetLabel() string {
+	if m != nil {
+		return m.Label
 	}
 	return ""
 }
 
-type ImportedKey struct {
-	Key          string                     `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
-	CreationDate *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=creationDate" json:"creationDate,omitempty"`
+func (m *PaymentURIRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
 }
 
-func (m *ImportedKey) Reset()                    { *m = ImportedKey{} }
-func (m *ImportedKey) String() string            { return proto.CompactTextString(m) }
-func (*ImportedKey) ProtoMessage()               {}
-func (*ImportedKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+type PaymentURI struct {
+	Uri string `protobuf:"bytes,1,opt,name=uri" json:"uri,omitempty"`
+}
 
-func (m *ImportedKey) GetKey() string {
+func (m *PaymentURI) Reset()                    { *m = PaymentURI{} }
+func (m *PaymentURI) String() string            { return proto.CompactTextString(m) }
+func (*PaymentURI) ProtoMessage()               {}
+func (*PaymentURI) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *PaymentURI) GetUri() string {
 	if m != nil {
-		return m.Key
+		return m.Uri
 	}
 	return ""
 }
 
-func (m *ImportedKey) GetCreationDate() *google_protobuf.Timestamp {
+type PeerList struct {
+	Peers []*Peer `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *PeerList) Reset()                    { *m = PeerList{} }
+func (m *PeerList) String() string            { return proto.CompactTextString(m) }
+func (*PeerList) ProtoMessage()               {}
+func (*PeerList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *PeerList) GetPeers() []*Peer {
 	if m != nil {
-		return m.CreationDate
+		return m.Peers
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Empty)(nil), "pb.Empty")
-	proto.RegisterType((*KeySelection)(nil), "pb.KeySelection")
+type Peer struct {
+	Address         string                     `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	BytesSent       uint64                     `protobuf:"varint,2,opt,name=bytesSent" json:"bytesSent,omitempty"`
+	BytesReceived   uint64                     `protobuf:"varint,3,opt,name=bytesReceived" json:"bytesReceived,omitempty"`
+	Connected       bool                       `protobuf:"varint,4,opt,name=connected" json:"connected,omitempty"`
+	ID              int32                      `protobuf:"varint,5,opt,name=ID" json:"ID,omitempty"`
+	LastBlock       int32                      `protobuf:"varint,6,opt,name=lastBlock" json:"lastBlock,omitempty"`
+	ProtocolVersion uint32                     `protobuf:"varint,7,opt,name=protocolVersion" json:"protocolVersion,omitempty"`
+	Services        string                     `protobuf:"bytes,8,opt,name=services" json:"services,omitempty"`
+	UserAgent       string                     `protobuf:"bytes,9,opt,name=userAgent" json:"userAgent,omitempty"`
+	TimeConnected   *google_protobuf.Timestamp `protobuf:"bytes,10,opt,name=timeConnected" json:"timeConnected,omitempty"`
+}
+
+func (m *Peer) Reset()                    { *m = Peer{} }
+func (m *Peer) String() string            { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()               {}
+func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *Peer) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Peer) GetBytesSent() uint64 {
+	if m != nil {
+		return m.BytesSent
+	}
+	return 0
+}
+
+func (m *Peer) GetBytesReceived() uint64 {
+	if m != nil {
+		return m.BytesReceived
+	}
+	return 0
+}
+
+func (m *Peer) GetConnected() bool {
+	if m != nil {
+		return m.Connected
+	}
+	return false
+}
+
+func (m *Peer) GetID() int32 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+func (m *Peer) GetLastBlock() int32 {
+	if m != nil {
+		return m.LastBlock
+	}
+	return 0
+}
+
+func (m *Peer) GetProtocolVersion() uint32 {
+	if m != nil {
+		return m.ProtocolVersion
+	}
+	return 0
+}
+
+func (m *Peer) GetServices() string {
+	if m != nil {
+		return m.Services
+	}
+	return ""
+}
+
+func (m *Peer) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *Peer) GetTimeConnected() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.TimeConnected
+	}
+	return nil
+}
+
+type Confirmations struct {
+	Confirmations uint32 `protobuf:"varint,1,opt,name=confirmations" json:"confirmations,omitempty"`
+}
+
+func (m *Confirmations) Reset()                    { *m = Confirmations{} }
+func (m *Confirmations) String() string            { return proto.CompactTextString(m) }
+func (*Confirmations) ProtoMessage()               {}
+func (*Confirmations) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *Confirmations) GetConfirmations() uint32 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+type Utxo struct {
+	Txid  string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Index uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Value uint64 `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Utxo) Reset()                    { *m = Utxo{} }
+func (m *Utxo) String() string            { return proto.CompactTextString(m) }
+func (*Utxo) ProtoMessage()               {}
+func (*Utxo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *Utxo) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *Utxo) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Utxo) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type Unspent struct {
+	Txid          string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Index         uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+	Value         uint64 `protobuf:"varint,3,opt,name=value" json:"value,omitempty"`
+	Address       string `protobuf:"bytes,4,opt,name=address" json:"address,omitempty"`
+	Confirmations uint32 `protobuf:"varint,5,opt,name=confirmations" json:"confirmations,omitempty"`
+	Account       uint32 `protobuf:"varint,6,opt,name=account" json:"account,omitempty"`
+	WatchOnly     bool   `protobuf:"varint,7,opt,name=watchOnly" json:"watchOnly,omitempty"`
+	Label         string `protobuf:"bytes,8,opt,name=label" json:"label,omitempty"`
+	Locked        bool   `protobuf:"varint,9,opt,name=locked" json:"locked,omitempty"`
+}
+
+func (m *Unspent) Reset()                    { *m = Unspent{} }
+func (m *Unspent) String() string            { return proto.CompactTextString(m) }
+func (*Unspent) ProtoMessage()               {}
+func (*Unspent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *Unspent) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *Unspent) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Unspent) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Unspent) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Unspent) GetConfirmations() uint32 {
+	if m != nil {
+		return m.Confirmations
+	}
+	return 0
+}
+
+func (m *Unspent) GetAccount() uint32 {
+	if m != nil {
+		return m.Account
+	}
+	return 0
+}
+
+func (m *Unspent) GetWatchOnly() bool {
+	if m != nil {
+		return m.WatchOnly
+	}
+	return false
+}
+
+func (m *Unspent) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *Unspent) GetLocked() bool {
+	if m != nil {
+		return m.Locked
+	}
+	return false
+}
+
+type UnspentList struct {
+	Utxos []*Unspent `protobuf:"bytes,1,rep,name=utxos" json:"utxos,omitempty"`
+}
+
+func (m *UnspentList) Reset()                    { *m = UnspentList{} }
+func (m *UnspentList) String() string            { return proto.CompactTextString(m) }
+func (*UnspentList) ProtoMessage()               {}
+func (*UnspentList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *UnspentList) GetUtxos() []*Unspent {
+	if m != nil {
+		return m.Utxos
+	}
+	return nil
+}
+
+type SweepInfo struct {
+	Utxos        []*Utxo  `protobuf:"bytes,1,rep,name=utxos" json:"utxos,omitempty"`
+	Address      string   `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Key          string   `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
+	RedeemScript []byte   `protobuf:"bytes,4,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
+	FeeLevel     FeeLevel `protobuf:"varint,5,opt,name=feeLevel,enum=pb.FeeLevel" json:"feeLevel,omitempty"`
+}
+
+func (m *SweepInfo) Reset()                    { *m = SweepInfo{} }
+func (m *SweepInfo) String() string            { return proto.CompactTextString(m) }
+func (*SweepInfo) ProtoMessage()               {}
+func (*SweepInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *SweepInfo) GetUtxos() []*Utxo {
+	if m != nil {
+		return m.Utxos
+	}
+	return nil
+}
+
+func (m *SweepInfo) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *SweepInfo) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SweepInfo) GetRedeemScript() []byte {
+	if m != nil {
+		return m.RedeemScript
+	}
+	return nil
+}
+
+func (m *SweepInfo) GetFeeLevel() FeeLevel {
+	if m != nil {
+		return m.FeeLevel
+	}
+	return FeeLevel_ECONOMIC
+}
+
+type Input struct {
+	Txid  string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
+	Index uint32 `protobuf:"varint,2,opt,name=index" json:"index,omitempty"`
+}
+
+func (m *Input) Reset()                    { *m = Input{} }
+func (m *Input) String() string            { return proto.CompactTextString(m) }
+func (*Input) ProtoMessage()               {}
+func (*Input) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *Input) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *Input) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type Output struct {
+	ScriptPubKey []byte `protobuf:"bytes,1,opt,name=scriptPubKey,proto3" json:"scriptPubKey,omitempty"`
+	Value        uint64 `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Output) Reset()                    { *m = Output{} }
+func (m *Output) String() string            { return proto.CompactTextString(m) }
+func (*Output) ProtoMessage()               {}
+func (*Output) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *Output) GetScriptPubKey() []byte {
+	if m != nil {
+		return m.ScriptPubKey
+	}
+	return nil
+}
+
+func (m *Output) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+type Signature struct {
+	Index     uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *Signature) Reset()                    { *m = Signature{} }
+func (m *Signature) String() string            { return proto.CompactTextString(m) }
+func (*Signature) ProtoMessage()               {}
+func (*Signature) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *Signature) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Signature) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type CreateMultisigInfo struct {
+	Inputs       []*Input  `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
+	Outputs      []*Output `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
+	Key          string    `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
+	RedeemScript []byte    `protobuf:"bytes,4,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
+	FeePerByte   uint64    `protobuf:"varint,5,opt,name=feePerByte" json:"feePerByte,omitempty"`
+}
+
+func (m *CreateMultisigInfo) Reset()                    { *m = CreateMultisigInfo{} }
+func (m *CreateMultisigInfo) String() string            { return proto.CompactTextString(m) }
+func (*CreateMultisigInfo) ProtoMessage()               {}
+func (*CreateMultisigInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *CreateMultisigInfo) GetInputs() []*Input {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *CreateMultisigInfo) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *CreateMultisigInfo) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *CreateMultisigInfo) GetRedeemScript() []byte {
+	if m != nil {
+		return m.RedeemScript
+	}
+	return nil
+}
+
+func (m *CreateMultisigInfo) GetFeePerByte() uint64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+type SignatureList struct {
+	Sigs []*Signature `protobuf:"bytes,1,rep,name=sigs" json:"sigs,omitempty"`
+}
+
+func (m *SignatureList) Reset()                    { *m = SignatureList{} }
+func (m *SignatureList) String() string            { return proto.CompactTextString(m) }
+func (*SignatureList) ProtoMessage()               {}
+func (*SignatureList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *SignatureList) GetSigs() []*Signature {
+	if m != nil {
+		return m.Sigs
+	}
+	return nil
+}
+
+type MultisignInfo struct {
+	Inputs       []*Input     `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
+	Outputs      []*Output    `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
+	Sig1         []*Signature `protobuf:"bytes,3,rep,name=sig1" json:"sig1,omitempty"`
+	Sig2         []*Signature `protobuf:"bytes,4,rep,name=sig2" json:"sig2,omitempty"`
+	RedeemScript []byte       `protobuf:"bytes,5,opt,name=redeemScript,proto3" json:"redeemScript,omitempty"`
+	FeePerByte   uint64       `protobuf:"varint,6,opt,name=feePerByte" json:"feePerByte,omitempty"`
+	Broadcast    bool         `protobuf:"varint,7,opt,name=broadcast" json:"broadcast,omitempty"`
+}
+
+func (m *MultisignInfo) Reset()                    { *m = MultisignInfo{} }
+func (m *MultisignInfo) String() string            { return proto.CompactTextString(m) }
+func (*MultisignInfo) ProtoMessage()               {}
+func (*MultisignInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *MultisignInfo) GetInputs() []*Input {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *MultisignInfo) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *MultisignInfo) GetSig1() []*Signature {
+	if m != nil {
+		return m.Sig1
+	}
+	return nil
+}
+
+func (m *MultisignInfo) GetSig2() []*Signature {
+	if m != nil {
+		return m.Sig2
+	}
+	return nil
+}
+
+func (m *MultisignInfo) GetRedeemScript() []byte {
+	if m != nil {
+		return m.RedeemScript
+	}
+	return nil
+}
+
+func (m *MultisignInfo) GetFeePerByte() uint64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+func (m *MultisignInfo) GetBroadcast() bool {
+	if m != nil {
+		return m.Broadcast
+	}
+	return false
+}
+
+type RawTx struct {
+	Tx []byte `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+}
+
+func (m *RawTx) Reset()                    { *m = RawTx{} }
+func (m *RawTx) String() string            { return proto.CompactTextString(m) }
+func (*RawTx) ProtoMessage()               {}
+func (*RawTx) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *RawTx) GetTx() []byte {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+type PartiallySignedTx struct {
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *PartiallySignedTx) Reset()                    { *m = PartiallySignedTx{} }
+func (m *PartiallySignedTx) String() string            { return proto.CompactTextString(m) }
+func (*PartiallySignedTx) ProtoMessage()               {}
+func (*PartiallySignedTx) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *PartiallySignedTx) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type EstimateFeeData struct {
+	Inputs     []*Input  `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty"`
+	Outputs    []*Output `protobuf:"bytes,2,rep,name=outputs" json:"outputs,omitempty"`
+	FeePerByte uint64    `protobuf:"varint,3,opt,name=feePerByte" json:"feePerByte,omitempty"`
+}
+
+func (m *EstimateFeeData) Reset()                    { *m = EstimateFeeData{} }
+func (m *EstimateFeeData) String() string            { return proto.CompactTextString(m) }
+func (*EstimateFeeData) ProtoMessage()               {}
+func (*EstimateFeeData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *EstimateFeeData) GetInputs() []*Input {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *EstimateFeeData) GetOutputs() []*Output {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *EstimateFeeData) GetFeePerByte() uint64 {
+	if m != nil {
+		return m.FeePerByte
+	}
+	return 0
+}
+
+type Header struct {
+	Entry string `protobuf:"bytes,1,opt,name=entry" json:"entry,omitempty"`
+}
+
+func (m *Header) Reset()                    { *m = Header{} }
+func (m *Header) String() string            { return proto.CompactTextString(m) }
+func (*Header) ProtoMessage()               {}
+func (*Header) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *Header) GetEntry() string {
+	if m != nil {
+		return m.Entry
+	}
+	return ""
+}
+
+type ImportedKey struct {
+	Key          string                     `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	CreationDate *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=creationDate" json:"creationDate,omitempty"`
+}
+
+func (m *ImportedKey) Reset()                    { *m = ImportedKey{} }
+func (m *ImportedKey) String() string            { return proto.CompactTextString(m) }
+func (*ImportedKey) ProtoMessage()               {}
+func (*ImportedKey) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *ImportedKey) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *ImportedKey) GetCreationDate() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.CreationDate
+	}
+	return nil
+}
+
+type Passphrase struct {
+	Passphrase string `protobuf:"bytes,1,opt,name=passphrase" json:"passphrase,omitempty"`
+}
+
+func (m *Passphrase) Reset()                    { *m = Passphrase{} }
+func (m *Passphrase) String() string            { return proto.CompactTextString(m) }
+func (*Passphrase) ProtoMessage()               {}
+func (*Passphrase) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *Passphrase) GetPassphrase() string {
+	if m != nil {
+		return m.Passphrase
+	}
+	return ""
+}
+
+type PassphraseChange struct {
+	OldPassphrase string `protobuf:"bytes,1,opt,name=oldPassphrase" json:"oldPassphrase,omitempty"`
+	NewPassphrase string `protobuf:"bytes,2,opt,name=newPassphrase" json:"newPassphrase,omitempty"`
+}
+
+func (m *PassphraseChange) Reset()                    { *m = PassphraseChange{} }
+func (m *PassphraseChange) String() string            { return proto.CompactTextString(m) }
+func (*PassphraseChange) ProtoMessage()               {}
+func (*PassphraseChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *PassphraseChange) GetOldPassphrase() string {
+	if m != nil {
+		return m.OldPassphrase
+	}
+	return ""
+}
+
+func (m *PassphraseChange) GetNewPassphrase() string {
+	if m != nil {
+		return m.NewPassphrase
+	}
+	return ""
+}
+
+type Event struct {
+	Type        EventType                  `protobuf:"varint,1,opt,name=type,enum=pb.EventType" json:"type,omitempty"`
+	Timestamp   *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	Tx          *Tx                        `protobuf:"bytes,3,opt,name=tx" json:"tx,omitempty"`
+	Block       *Block                     `protobuf:"bytes,4,opt,name=block" json:"block,omitempty"`
+	ReorgHeight uint32                     `protobuf:"varint,5,opt,name=reorgHeight" json:"reorgHeight,omitempty"`
+	Balances    *Balances                  `protobuf:"bytes,6,opt,name=balances" json:"balances,omitempty"`
+	Peer        *Peer                      `protobuf:"bytes,7,opt,name=peer" json:"peer,omitempty"`
+	PeerCount   uint32                     `protobuf:"varint,8,opt,name=peerCount" json:"peerCount,omitempty"`
+	Sync        *SyncProgress              `protobuf:"bytes,9,opt,name=sync" json:"sync,omitempty"`
+}
+
+func (m *Event) Reset()                    { *m = Event{} }
+func (m *Event) String() string            { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()               {}
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *Event) GetType() EventType {
+	if m != nil {
+		return m.Type
+	}
+	return EventType_NEW_TRANSACTION
+}
+
+func (m *Event) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *Event) GetTx() *Tx {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *Event) GetBlock() *Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *Event) GetReorgHeight() uint32 {
+	if m != nil {
+		return m.ReorgHeight
+	}
+	return 0
+}
+
+func (m *Event) GetBalances() *Balances {
+	if m != nil {
+		return m.Balances
+	}
+	return nil
+}
+
+func (m *Event) GetPeer() *Peer {
+	if m != nil {
+		return m.Peer
+	}
+	return nil
+}
+
+func (m *Event) GetPeerCount() uint32 {
+	if m != nil {
+		return m.PeerCount
+	}
+	return 0
+}
+
+func (m *Event) GetSync() *SyncProgress {
+	if m != nil {
+		return m.Sync
+	}
+	return nil
+}
+
+type Block struct {
+	Hash      string                     `protobuf:"bytes,1,opt,name=hash" json:"hash,omitempty"`
+	Height    uint32                     `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=timestamp" json:"timestamp,omitempty"`
+	PrevBlock string                     `protobuf:"bytes,4,opt,name=prevBlock" json:"prevBlock,omitempty"`
+}
+
+func (m *Block) Reset()                    { *m = Block{} }
+func (m *Block) String() string            { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()               {}
+func (*Block) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *Block) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Block) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Block) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *Block) GetPrevBlock() string {
+	if m != nil {
+		return m.PrevBlock
+	}
+	return ""
+}
+
+type SyncProgress struct {
+	Height       uint32 `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	TargetHeight uint32 `protobuf:"varint,2,opt,name=targetHeight" json:"targetHeight,omitempty"`
+	Synced       bool   `protobuf:"varint,3,opt,name=synced" json:"synced,omitempty"`
+}
+
+func (m *SyncProgress) Reset()                    { *m = SyncProgress{} }
+func (m *SyncProgress) String() string            { return proto.CompactTextString(m) }
+func (*SyncProgress) ProtoMessage()               {}
+func (*SyncProgress) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *SyncProgress) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetTargetHeight() uint32 {
+	if m != nil {
+		return m.TargetHeight
+	}
+	return 0
+}
+
+func (m *SyncProgress) GetSynced() bool {
+	if m != nil {
+		return m.Synced
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "pb.Empty")
+	proto.RegisterType((*KeySelection)(nil), "pb.KeySelection")
 	proto.RegisterType((*Address)(nil), "pb.Address")
+	proto.RegisterType((*AddressLabel)(nil), "pb.AddressLabel")
 	proto.RegisterType((*Height)(nil), "pb.Height")
 	proto.RegisterType((*Balances)(nil), "pb.Balances")
 	proto.RegisterType((*Key)(nil), "pb.Key")
@@ -939,14 +2053,32 @@ func init() {
 	proto.RegisterType((*TransactionList)(nil), "pb.TransactionList")
 	proto.RegisterType((*Tx)(nil), "pb.Tx")
 	proto.RegisterType((*Txid)(nil), "pb.Txid")
+	proto.RegisterType((*TxMemo)(nil), "pb.TxMemo")
+	proto.RegisterType((*TransactionDetail)(nil), "pb.TransactionDetail")
+	proto.RegisterType((*InputDetail)(nil), "pb.InputDetail")
+	proto.RegisterType((*OutputDetail)(nil), "pb.OutputDetail")
+	proto.RegisterType((*HistoryRequest)(nil), "pb.HistoryRequest")
+	proto.RegisterType((*HistoryExport)(nil), "pb.HistoryExport")
+	proto.RegisterType((*InvoiceRequest)(nil), "pb.InvoiceRequest")
+	proto.RegisterType((*InvoiceID)(nil), "pb.InvoiceID")
+	proto.RegisterType((*Invoice)(nil), "pb.Invoice")
+	proto.RegisterType((*InvoicePayment)(nil), "pb.InvoicePayment")
+	proto.RegisterType((*InvoiceList)(nil), "pb.InvoiceList")
 	proto.RegisterType((*FeeLevelSelection)(nil), "pb.FeeLevelSelection")
 	proto.RegisterType((*FeePerByte)(nil), "pb.FeePerByte")
 	proto.RegisterType((*Fee)(nil), "pb.Fee")
 	proto.RegisterType((*SpendInfo)(nil), "pb.SpendInfo")
+	proto.RegisterType((*Payment)(nil), "pb.Payment")
+	proto.RegisterType((*SpendManyInfo)(nil), "pb.SpendManyInfo")
+	proto.RegisterType((*URIPayment)(nil), "pb.URIPayment")
+	proto.RegisterType((*PaymentURIRequest)(nil), "pb.PaymentURIRequest")
+	proto.RegisterType((*PaymentURI)(nil), "pb.PaymentURI")
 	proto.RegisterType((*PeerList)(nil), "pb.PeerList")
 	proto.RegisterType((*Peer)(nil), "pb.Peer")
 	proto.RegisterType((*Confirmations)(nil), "pb.Confirmations")
 	proto.RegisterType((*Utxo)(nil), "pb.Utxo")
+	proto.RegisterType((*Unspent)(nil), "pb.Unspent")
+	proto.RegisterType((*UnspentList)(nil), "pb.UnspentList")
 	proto.RegisterType((*SweepInfo)(nil), "pb.SweepInfo")
 	proto.RegisterType((*Input)(nil), "pb.Input")
 	proto.RegisterType((*Output)(nil), "pb.Output")
@@ -955,861 +2087,1512 @@ func init() {
 	proto.RegisterType((*SignatureList)(nil), "pb.SignatureList")
 	proto.RegisterType((*MultisignInfo)(nil), "pb.MultisignInfo")
 	proto.RegisterType((*RawTx)(nil), "pb.RawTx")
+	proto.RegisterType((*PartiallySignedTx)(nil), "pb.PartiallySignedTx")
 	proto.RegisterType((*EstimateFeeData)(nil), "pb.EstimateFeeData")
 	proto.RegisterType((*Header)(nil), "pb.Header")
 	proto.RegisterType((*ImportedKey)(nil), "pb.ImportedKey")
+	proto.RegisterType((*Passphrase)(nil), "pb.Passphrase")
+	proto.RegisterType((*PassphraseChange)(nil), "pb.PassphraseChange")
+	proto.RegisterType((*Event)(nil), "pb.Event")
+	proto.RegisterType((*Block)(nil), "pb.Block")
+	proto.RegisterType((*SyncProgress)(nil), "pb.SyncProgress")
 	proto.RegisterEnum("pb.KeyPurpose", KeyPurpose_name, KeyPurpose_value)
+	proto.RegisterEnum("pb.InvoiceStatus", InvoiceStatus_name, InvoiceStatus_value)
 	proto.RegisterEnum("pb.FeeLevel", FeeLevel_name, FeeLevel_value)
+	proto.RegisterEnum("pb.EventType", EventType_name, EventType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for API service
+
+type APIClient interface {
+	Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	CurrentAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error)
+	NewAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error)
+	PaymentURI(ctx context.Context, in *PaymentURIRequest, opts ...grpc.CallOption) (*PaymentURI, error)
+	ChainTip(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Height, error)
+	Balance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Balances, error)
+	MasterPrivateKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error)
+	MasterPublicKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error)
+	HasKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*BoolResponse, error)
+	Params(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NetParams, error)
+	Transactions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TransactionList, error)
+	GetTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Tx, error)
+	GetTransactionDetail(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*TransactionDetail, error)
+	ExportHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryExport, error)
+	CreateInvoice(ctx context.Context, in *InvoiceRequest, opts ...grpc.CallOption) (*Invoice, error)
+	GetInvoice(ctx context.Context, in *InvoiceID, opts ...grpc.CallOption) (*Invoice, error)
+	ListInvoices(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InvoiceList, error)
+	GetFeePerByte(ctx context.Context, in *FeeLevelSelection, opts ...grpc.CallOption) (*FeePerByte, error)
+	Spend(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*Txid, error)
+	SpendMany(ctx context.Context, in *SpendManyInfo, opts ...grpc.CallOption) (*Txid, error)
+	PayURI(ctx context.Context, in *URIPayment, opts ...grpc.CallOption) (*Txid, error)
+	BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Txid, error)
+	Peers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerList, error)
+	AddWatchedAddress(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Empty, error)
+	GetConfirmations(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Confirmations, error)
+	SweepAddress(ctx context.Context, in *SweepInfo, opts ...grpc.CallOption) (*Txid, error)
+	ReSyncBlockchain(ctx context.Context, in *Height, opts ...grpc.CallOption) (*Empty, error)
+	CreateMultisigSignature(ctx context.Context, in *CreateMultisigInfo, opts ...grpc.CallOption) (*SignatureList, error)
+	Multisign(ctx context.Context, in *MultisignInfo, opts ...grpc.CallOption) (*RawTx, error)
+	EstimateFee(ctx context.Context, in *EstimateFeeData, opts ...grpc.CallOption) (*Fee, error)
+	GetKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Key, error)
+	ListKeys(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Keys, error)
+	ListAddresses(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Addresses, error)
+	SetAddressLabel(ctx context.Context, in *AddressLabel, opts ...grpc.CallOption) (*Empty, error)
+	SetTxMemo(ctx context.Context, in *TxMemo, opts ...grpc.CallOption) (*Empty, error)
+	ListUnspent(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UnspentList, error)
+	LockUnspent(ctx context.Context, in *Input, opts ...grpc.CallOption) (*Empty, error)
+	UnlockUnspent(ctx context.Context, in *Input, opts ...grpc.CallOption) (*Empty, error)
+	ListLockedUnspent(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UnspentList, error)
+	ImportKey(ctx context.Context, in *ImportedKey, opts ...grpc.CallOption) (*Empty, error)
+	EncryptWallet(ctx context.Context, in *Passphrase, opts ...grpc.CallOption) (*Empty, error)
+	Unlock(ctx context.Context, in *Passphrase, opts ...grpc.CallOption) (*Empty, error)
+	Lock(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	ChangePassphrase(ctx context.Context, in *PassphraseChange, opts ...grpc.CallOption) (*Empty, error)
+	CreateUnsignedTransaction(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*PartiallySignedTx, error)
+	SignTransaction(ctx context.Context, in *PartiallySignedTx, opts ...grpc.CallOption) (*PartiallySignedTx, error)
+	FinalizeAndBroadcast(ctx context.Context, in *PartiallySignedTx, opts ...grpc.CallOption) (*Txid, error)
+	WalletNotify(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_WalletNotifyClient, error)
+	DumpHeaders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_DumpHeadersClient, error)
+	SubscribeEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_SubscribeEventsClient, error)
+}
+
+type aPIClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAPIClient(cc *grpc.ClientConn) APIClient {
+	return &aPIClient{cc}
+}
+
+func (c *aPIClient) Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/Stop", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CurrentAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error) {
+	out := new(Address)
+	err := grpc.Invoke(ctx, "/pb.API/CurrentAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) NewAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error) {
+	out := new(Address)
+	err := grpc.Invoke(ctx, "/pb.API/NewAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) PaymentURI(ctx context.Context, in *PaymentURIRequest, opts ...grpc.CallOption) (*PaymentURI, error) {
+	out := new(PaymentURI)
+	err := grpc.Invoke(ctx, "/pb.API/PaymentURI", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ChainTip(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Height, error) {
+	out := new(Height)
+	err := grpc.Invoke(ctx, "/pb.API/ChainTip", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Balance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Balances, error) {
+	out := new(Balances)
+	err := grpc.Invoke(ctx, "/pb.API/Balance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) MasterPrivateKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error) {
+	out := new(Key)
+	err := grpc.Invoke(ctx, "/pb.API/MasterPrivateKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) MasterPublicKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error) {
+	out := new(Key)
+	err := grpc.Invoke(ctx, "/pb.API/MasterPublicKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) HasKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*BoolResponse, error) {
+	out := new(BoolResponse)
+	err := grpc.Invoke(ctx, "/pb.API/HasKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Params(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NetParams, error) {
+	out := new(NetParams)
+	err := grpc.Invoke(ctx, "/pb.API/Params", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Transactions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TransactionList, error) {
+	out := new(TransactionList)
+	err := grpc.Invoke(ctx, "/pb.API/Transactions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Tx, error) {
+	out := new(Tx)
+	err := grpc.Invoke(ctx, "/pb.API/GetTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetTransactionDetail(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*TransactionDetail, error) {
+	out := new(TransactionDetail)
+	err := grpc.Invoke(ctx, "/pb.API/GetTransactionDetail", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ExportHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryExport, error) {
+	out := new(HistoryExport)
+	err := grpc.Invoke(ctx, "/pb.API/ExportHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CreateInvoice(ctx context.Context, in *InvoiceRequest, opts ...grpc.CallOption) (*Invoice, error) {
+	out := new(Invoice)
+	err := grpc.Invoke(ctx, "/pb.API/CreateInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetInvoice(ctx context.Context, in *InvoiceID, opts ...grpc.CallOption) (*Invoice, error) {
+	out := new(Invoice)
+	err := grpc.Invoke(ctx, "/pb.API/GetInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListInvoices(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InvoiceList, error) {
+	out := new(InvoiceList)
+	err := grpc.Invoke(ctx, "/pb.API/ListInvoices", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetFeePerByte(ctx context.Context, in *FeeLevelSelection, opts ...grpc.CallOption) (*FeePerByte, error) {
+	out := new(FeePerByte)
+	err := grpc.Invoke(ctx, "/pb.API/GetFeePerByte", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Spend(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := grpc.Invoke(ctx, "/pb.API/Spend", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SpendMany(ctx context.Context, in *SpendManyInfo, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := grpc.Invoke(ctx, "/pb.API/SpendMany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) PayURI(ctx context.Context, in *URIPayment, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := grpc.Invoke(ctx, "/pb.API/PayURI", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := grpc.Invoke(ctx, "/pb.API/BumpFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Peers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerList, error) {
+	out := new(PeerList)
+	err := grpc.Invoke(ctx, "/pb.API/Peers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) AddWatchedAddress(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/AddWatchedAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (c *aPIClient) GetConfirmations(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Confirmations, error) {
+	out := new(Confirmations)
+	err := grpc.Invoke(ctx, "/pb.API/GetConfirmations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (c *aPIClient) SweepAddress(ctx context.Context, in *SweepInfo, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := grpc.Invoke(ctx, "/pb.API/SweepAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-// Client API for API service
+func (c *aPIClient) ReSyncBlockchain(ctx context.Context, in *Height, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/ReSyncBlockchain", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
-type APIClient interface {
-	Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
-	CurrentAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error)
-	NewAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error)
-	ChainTip(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Height, error)
-	Balance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Balances, error)
-	MasterPrivateKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error)
-	MasterPublicKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error)
-	HasKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*BoolResponse, error)
-	Params(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NetParams, error)
-	Transactions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TransactionList, error)
-	GetTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Tx, error)
-	GetFeePerByte(ctx context.Context, in *FeeLevelSelection, opts ...grpc.CallOption) (*FeePerByte, error)
-	Spend(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*Txid, error)
-	BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Txid, error)
-	Peers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerList, error)
-	AddWatchedAddress(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Empty, error)
-	GetConfirmations(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Confirmations, error)
-	SweepAddress(ctx context.Context, in *SweepInfo, opts ...grpc.CallOption) (*Txid, error)
-	ReSyncBlockchain(ctx context.Context, in *Height, opts ...grpc.CallOption) (*Empty, error)
-	CreateMultisigSignature(ctx context.Context, in *CreateMultisigInfo, opts ...grpc.CallOption) (*SignatureList, error)
-	Multisign(ctx context.Context, in *MultisignInfo, opts ...grpc.CallOption) (*RawTx, error)
-	EstimateFee(ctx context.Context, in *EstimateFeeData, opts ...grpc.CallOption) (*Fee, error)
-	GetKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Key, error)
-	ListKeys(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Keys, error)
-	ListAddresses(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Addresses, error)
-	ImportKey(ctx context.Context, in *ImportedKey, opts ...grpc.CallOption) (*Empty, error)
-	WalletNotify(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_WalletNotifyClient, error)
-	DumpHeaders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_DumpHeadersClient, error)
+func (c *aPIClient) CreateMultisigSignature(ctx context.Context, in *CreateMultisigInfo, opts ...grpc.CallOption) (*SignatureList, error) {
+	out := new(SignatureList)
+	err := grpc.Invoke(ctx, "/pb.API/CreateMultisigSignature", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-type aPIClient struct {
-	cc *grpc.ClientConn
+func (c *aPIClient) Multisign(ctx context.Context, in *MultisignInfo, opts ...grpc.CallOption) (*RawTx, error) {
+	out := new(RawTx)
+	err := grpc.Invoke(ctx, "/pb.API/Multisign", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func NewAPIClient(cc *grpc.ClientConn) APIClient {
-	return &aPIClient{cc}
+func (c *aPIClient) EstimateFee(ctx context.Context, in *EstimateFeeData, opts ...grpc.CallOption) (*Fee, error) {
+	out := new(Fee)
+	err := grpc.Invoke(ctx, "/pb.API/EstimateFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (c *aPIClient) Stop(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+func (c *aPIClient) GetKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Key, error) {
+	out := new(Key)
+	err := grpc.Invoke(ctx, "/pb.API/GetKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListKeys(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Keys, error) {
+	out := new(Keys)
+	err := grpc.Invoke(ctx, "/pb.API/ListKeys", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListAddresses(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Addresses, error) {
+	out := new(Addresses)
+	err := grpc.Invoke(ctx, "/pb.API/ListAddresses", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SetAddressLabel(ctx context.Context, in *AddressLabel, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.API/Stop", in, out, c.cc, opts...)
+	err := grpc.Invoke(ctx, "/pb.API/SetAddressLabel", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) CurrentAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error) {
-	out := new(Address)
-	err := grpc.Invoke(ctx, "/pb.API/CurrentAddress", in, out, c.cc, opts...)
+func (c *aPIClient) SetTxMemo(ctx context.Context, in *TxMemo, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/SetTxMemo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListUnspent(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UnspentList, error) {
+	out := new(UnspentList)
+	err := grpc.Invoke(ctx, "/pb.API/ListUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) LockUnspent(ctx context.Context, in *Input, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/LockUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) UnlockUnspent(ctx context.Context, in *Input, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/UnlockUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListLockedUnspent(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UnspentList, error) {
+	out := new(UnspentList)
+	err := grpc.Invoke(ctx, "/pb.API/ListLockedUnspent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ImportKey(ctx context.Context, in *ImportedKey, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/ImportKey", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) EncryptWallet(ctx context.Context, in *Passphrase, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/EncryptWallet", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Unlock(ctx context.Context, in *Passphrase, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/Unlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Lock(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/Lock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ChangePassphrase(ctx context.Context, in *PassphraseChange, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/pb.API/ChangePassphrase", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CreateUnsignedTransaction(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*PartiallySignedTx, error) {
+	out := new(PartiallySignedTx)
+	err := grpc.Invoke(ctx, "/pb.API/CreateUnsignedTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SignTransaction(ctx context.Context, in *PartiallySignedTx, opts ...grpc.CallOption) (*PartiallySignedTx, error) {
+	out := new(PartiallySignedTx)
+	err := grpc.Invoke(ctx, "/pb.API/SignTransaction", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) FinalizeAndBroadcast(ctx context.Context, in *PartiallySignedTx, opts ...grpc.CallOption) (*Txid, error) {
+	out := new(Txid)
+	err := grpc.Invoke(ctx, "/pb.API/FinalizeAndBroadcast", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) NewAddress(ctx context.Context, in *KeySelection, opts ...grpc.CallOption) (*Address, error) {
-	out := new(Address)
-	err := grpc.Invoke(ctx, "/pb.API/NewAddress", in, out, c.cc, opts...)
-	if err != nil {
+func (c *aPIClient) WalletNotify(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_WalletNotifyClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/pb.API/WalletNotify", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIWalletNotifyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_WalletNotifyClient interface {
+	Recv() (*Tx, error)
+	grpc.ClientStream
+}
+
+type aPIWalletNotifyClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIWalletNotifyClient) Recv() (*Tx, error) {
+	m := new(Tx)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) DumpHeaders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_DumpHeadersClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[1], c.cc, "/pb.API/DumpHeaders", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPIDumpHeadersClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_DumpHeadersClient interface {
+	Recv() (*Header, error)
+	grpc.ClientStream
+}
+
+type aPIDumpHeadersClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIDumpHeadersClient) Recv() (*Header, error) {
+	m := new(Header)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *aPIClient) ChainTip(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Height, error) {
-	out := new(Height)
-	err := grpc.Invoke(ctx, "/pb.API/ChainTip", in, out, c.cc, opts...)
+func (c *aPIClient) SubscribeEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_SubscribeEventsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[2], c.cc, "/pb.API/SubscribeEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *aPIClient) Balance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Balances, error) {
-	out := new(Balances)
-	err := grpc.Invoke(ctx, "/pb.API/Balance", in, out, c.cc, opts...)
-	if err != nil {
+	x := &aPISubscribeEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *aPIClient) MasterPrivateKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error) {
-	out := new(Key)
-	err := grpc.Invoke(ctx, "/pb.API/MasterPrivateKey", in, out, c.cc, opts...)
-	if err != nil {
+	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return x, nil
 }
 
-func (c *aPIClient) MasterPublicKey(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Key, error) {
-	out := new(Key)
-	err := grpc.Invoke(ctx, "/pb.API/MasterPublicKey", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type API_SubscribeEventsClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
 }
 
-func (c *aPIClient) HasKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*BoolResponse, error) {
-	out := new(BoolResponse)
-	err := grpc.Invoke(ctx, "/pb.API/HasKey", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type aPISubscribeEventsClient struct {
+	grpc.ClientStream
 }
 
-func (c *aPIClient) Params(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NetParams, error) {
-	out := new(NetParams)
-	err := grpc.Invoke(ctx, "/pb.API/Params", in, out, c.cc, opts...)
-	if err != nil {
+func (x *aPISubscribeEventsClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *aPIClient) Transactions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TransactionList, error) {
-	out := new(TransactionList)
-	err := grpc.Invoke(ctx, "/pb.API/Transactions", in, out, c.cc, opts...)
-	if err != nil {
+// Server API for API service
+
+type APIServer interface {
+	Stop(context.Context, *Empty) (*Empty, error)
+	CurrentAddress(context.Context, *KeySelection) (*Address, error)
+	NewAddress(context.Context, *KeySelection) (*Address, error)
+	PaymentURI(context.Context, *PaymentURIRequest) (*PaymentURI, error)
+	ChainTip(context.Context, *Empty) (*Height, error)
+	Balance(context.Context, *Empty) (*Balances, error)
+	MasterPrivateKey(context.Context, *Empty) (*Key, error)
+	MasterPublicKey(context.Context, *Empty) (*Key, error)
+	HasKey(context.Context, *Address) (*BoolResponse, error)
+	Params(context.Context, *Empty) (*NetParams, error)
+	Transactions(context.Context, *Empty) (*TransactionList, error)
+	GetTransaction(context.Context, *Txid) (*Tx, error)
+	GetTransactionDetail(context.Context, *Txid) (*TransactionDetail, error)
+	ExportHistory(context.Context, *HistoryRequest) (*HistoryExport, error)
+	CreateInvoice(context.Context, *InvoiceRequest) (*Invoice, error)
+	GetInvoice(context.Context, *InvoiceID) (*Invoice, error)
+	ListInvoices(context.Context, *Empty) (*InvoiceList, error)
+	GetFeePerByte(context.Context, *FeeLevelSelection) (*FeePerByte, error)
+	Spend(context.Context, *SpendInfo) (*Txid, error)
+	SpendMany(context.Context, *SpendManyInfo) (*Txid, error)
+	PayURI(context.Context, *URIPayment) (*Txid, error)
+	BumpFee(context.Context, *Txid) (*Txid, error)
+	Peers(context.Context, *Empty) (*PeerList, error)
+	AddWatchedAddress(context.Context, *Address) (*Empty, error)
+	GetConfirmations(context.Context, *Txid) (*Confirmations, error)
+	SweepAddress(context.Context, *SweepInfo) (*Txid, error)
+	ReSyncBlockchain(context.Context, *Height) (*Empty, error)
+	CreateMultisigSignature(context.Context, *CreateMultisigInfo) (*SignatureList, error)
+	Multisign(context.Context, *MultisignInfo) (*RawTx, error)
+	EstimateFee(context.Context, *EstimateFeeData) (*Fee, error)
+	GetKey(context.Context, *Address) (*Key, error)
+	ListKeys(context.Context, *Empty) (*Keys, error)
+	ListAddresses(context.Context, *Empty) (*Addresses, error)
+	SetAddressLabel(context.Context, *AddressLabel) (*Empty, error)
+	SetTxMemo(context.Context, *TxMemo) (*Empty, error)
+	ListUnspent(context.Context, *Empty) (*UnspentList, error)
+	LockUnspent(context.Context, *Input) (*Empty, error)
+	UnlockUnspent(context.Context, *Input) (*Empty, error)
+	ListLockedUnspent(context.Context, *Empty) (*UnspentList, error)
+	ImportKey(context.Context, *ImportedKey) (*Empty, error)
+	EncryptWallet(context.Context, *Passphrase) (*Empty, error)
+	Unlock(context.Context, *Passphrase) (*Empty, error)
+	Lock(context.Context, *Empty) (*Empty, error)
+	ChangePassphrase(context.Context, *PassphraseChange) (*Empty, error)
+	CreateUnsignedTransaction(context.Context, *SpendInfo) (*PartiallySignedTx, error)
+	SignTransaction(context.Context, *PartiallySignedTx) (*PartiallySignedTx, error)
+	FinalizeAndBroadcast(context.Context, *PartiallySignedTx) (*Txid, error)
+	WalletNotify(*Empty, API_WalletNotifyServer) error
+	DumpHeaders(*Empty, API_DumpHeadersServer) error
+	SubscribeEvents(*Empty, API_SubscribeEventsServer) error
+}
+
+func RegisterAPIServer(s *grpc.Server, srv APIServer) {
+	s.RegisterService(&_API_serviceDesc, srv)
+}
+
+func _API_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).Stop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/Stop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Stop(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) GetTransaction(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Tx, error) {
-	out := new(Tx)
-	err := grpc.Invoke(ctx, "/pb.API/GetTransaction", in, out, c.cc, opts...)
-	if err != nil {
+func _API_CurrentAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeySelection)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).CurrentAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/CurrentAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).CurrentAddress(ctx, req.(*KeySelection))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) GetFeePerByte(ctx context.Context, in *FeeLevelSelection, opts ...grpc.CallOption) (*FeePerByte, error) {
-	out := new(FeePerByte)
-	err := grpc.Invoke(ctx, "/pb.API/GetFeePerByte", in, out, c.cc, opts...)
-	if err != nil {
+func _API_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeySelection)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).NewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/NewAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).NewAddress(ctx, req.(*KeySelection))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) Spend(ctx context.Context, in *SpendInfo, opts ...grpc.CallOption) (*Txid, error) {
-	out := new(Txid)
-	err := grpc.Invoke(ctx, "/pb.API/Spend", in, out, c.cc, opts...)
-	if err != nil {
+func _API_PaymentURI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentURIRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).PaymentURI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/PaymentURI",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).PaymentURI(ctx, req.(*PaymentURIRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) BumpFee(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Txid, error) {
-	out := new(Txid)
-	err := grpc.Invoke(ctx, "/pb.API/BumpFee", in, out, c.cc, opts...)
-	if err != nil {
+func _API_ChainTip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).ChainTip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/ChainTip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ChainTip(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) Peers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PeerList, error) {
-	out := new(PeerList)
-	err := grpc.Invoke(ctx, "/pb.API/Peers", in, out, c.cc, opts...)
-	if err != nil {
+func _API_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).Balance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/Balance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Balance(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) AddWatchedAddress(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.API/AddWatchedAddress", in, out, c.cc, opts...)
-	if err != nil {
+func _API_MasterPrivateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).MasterPrivateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/MasterPrivateKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).MasterPrivateKey(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) GetConfirmations(ctx context.Context, in *Txid, opts ...grpc.CallOption) (*Confirmations, error) {
-	out := new(Confirmations)
-	err := grpc.Invoke(ctx, "/pb.API/GetConfirmations", in, out, c.cc, opts...)
-	if err != nil {
+func _API_MasterPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).MasterPublicKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/MasterPublicKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).MasterPublicKey(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) SweepAddress(ctx context.Context, in *SweepInfo, opts ...grpc.CallOption) (*Txid, error) {
-	out := new(Txid)
-	err := grpc.Invoke(ctx, "/pb.API/SweepAddress", in, out, c.cc, opts...)
-	if err != nil {
+func _API_HasKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Address)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).HasKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/HasKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).HasKey(ctx, req.(*Address))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) ReSyncBlockchain(ctx context.Context, in *Height, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.API/ReSyncBlockchain", in, out, c.cc, opts...)
-	if err != nil {
+func _API_Params_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).Params(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/Params",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Params(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) CreateMultisigSignature(ctx context.Context, in *CreateMultisigInfo, opts ...grpc.CallOption) (*SignatureList, error) {
-	out := new(SignatureList)
-	err := grpc.Invoke(ctx, "/pb.API/CreateMultisigSignature", in, out, c.cc, opts...)
-	if err != nil {
+func _API_Transactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).Transactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/Transactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Transactions(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) Multisign(ctx context.Context, in *MultisignInfo, opts ...grpc.CallOption) (*RawTx, error) {
-	out := new(RawTx)
-	err := grpc.Invoke(ctx, "/pb.API/Multisign", in, out, c.cc, opts...)
-	if err != nil {
+func _API_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Txid)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetTransaction(ctx, req.(*Txid))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) EstimateFee(ctx context.Context, in *EstimateFeeData, opts ...grpc.CallOption) (*Fee, error) {
-	out := new(Fee)
-	err := grpc.Invoke(ctx, "/pb.API/EstimateFee", in, out, c.cc, opts...)
-	if err != nil {
+func _API_GetTransactionDetail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Txid)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).GetTransactionDetail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/GetTransactionDetail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetTransactionDetail(ctx, req.(*Txid))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) GetKey(ctx context.Context, in *Address, opts ...grpc.CallOption) (*Key, error) {
-	out := new(Key)
-	err := grpc.Invoke(ctx, "/pb.API/GetKey", in, out, c.cc, opts...)
-	if err != nil {
+func _API_ExportHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).ExportHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/ExportHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ExportHistory(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) ListKeys(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Keys, error) {
-	out := new(Keys)
-	err := grpc.Invoke(ctx, "/pb.API/ListKeys", in, out, c.cc, opts...)
-	if err != nil {
+func _API_CreateInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvoiceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).CreateInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/CreateInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).CreateInvoice(ctx, req.(*InvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) ListAddresses(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Addresses, error) {
-	out := new(Addresses)
-	err := grpc.Invoke(ctx, "/pb.API/ListAddresses", in, out, c.cc, opts...)
-	if err != nil {
+func _API_GetInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvoiceID)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).GetInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/GetInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetInvoice(ctx, req.(*InvoiceID))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) ImportKey(ctx context.Context, in *ImportedKey, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := grpc.Invoke(ctx, "/pb.API/ImportKey", in, out, c.cc, opts...)
-	if err != nil {
+func _API_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(APIServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/ListInvoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListInvoices(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) WalletNotify(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_WalletNotifyClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/pb.API/WalletNotify", opts...)
-	if err != nil {
+func _API_GetFeePerByte_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeLevelSelection)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &aPIWalletNotifyClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(APIServer).GetFeePerByte(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/GetFeePerByte",
 	}
-	return x, nil
-}
-
-type API_WalletNotifyClient interface {
-	Recv() (*Tx, error)
-	grpc.ClientStream
-}
-
-type aPIWalletNotifyClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetFeePerByte(ctx, req.(*FeeLevelSelection))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *aPIWalletNotifyClient) Recv() (*Tx, error) {
-	m := new(Tx)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _API_Spend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendInfo)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(APIServer).Spend(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/Spend",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Spend(ctx, req.(*SpendInfo))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *aPIClient) DumpHeaders(ctx context.Context, in *Empty, opts ...grpc.CallOption) (API_DumpHeadersClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[1], c.cc, "/pb.API/DumpHeaders", opts...)
-	if err != nil {
+func _API_SpendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendManyInfo)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &aPIDumpHeadersClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(APIServer).SpendMany(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/SpendMany",
 	}
-	return x, nil
-}
-
-type API_DumpHeadersClient interface {
-	Recv() (*Header, error)
-	grpc.ClientStream
-}
-
-type aPIDumpHeadersClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).SpendMany(ctx, req.(*SpendManyInfo))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *aPIDumpHeadersClient) Recv() (*Header, error) {
-	m := new(Header)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _API_PayURI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(URIPayment)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
-}
-
-// Server API for API service
-
-type APIServer interface {
-	Stop(context.Context, *Empty) (*Empty, error)
-	CurrentAddress(context.Context, *KeySelection) (*Address, error)
-	NewAddress(context.Context, *KeySelection) (*Address, error)
-	ChainTip(context.Context, *Empty) (*Height, error)
-	Balance(context.Context, *Empty) (*Balances, error)
-	MasterPrivateKey(context.Context, *Empty) (*Key, error)
-	MasterPublicKey(context.Context, *Empty) (*Key, error)
-	HasKey(context.Context, *Address) (*BoolResponse, error)
-	Params(context.Context, *Empty) (*NetParams, error)
-	Transactions(context.Context, *Empty) (*TransactionList, error)
-	GetTransaction(context.Context, *Txid) (*Tx, error)
-	GetFeePerByte(context.Context, *FeeLevelSelection) (*FeePerByte, error)
-	Spend(context.Context, *SpendInfo) (*Txid, error)
-	BumpFee(context.Context, *Txid) (*Txid, error)
-	Peers(context.Context, *Empty) (*PeerList, error)
-	AddWatchedAddress(context.Context, *Address) (*Empty, error)
-	GetConfirmations(context.Context, *Txid) (*Confirmations, error)
-	SweepAddress(context.Context, *SweepInfo) (*Txid, error)
-	ReSyncBlockchain(context.Context, *Height) (*Empty, error)
-	CreateMultisigSignature(context.Context, *CreateMultisigInfo) (*SignatureList, error)
-	Multisign(context.Context, *MultisignInfo) (*RawTx, error)
-	EstimateFee(context.Context, *EstimateFeeData) (*Fee, error)
-	GetKey(context.Context, *Address) (*Key, error)
-	ListKeys(context.Context, *Empty) (*Keys, error)
-	ListAddresses(context.Context, *Empty) (*Addresses, error)
-	ImportKey(context.Context, *ImportedKey) (*Empty, error)
-	WalletNotify(*Empty, API_WalletNotifyServer) error
-	DumpHeaders(*Empty, API_DumpHeadersServer) error
-}
-
-func RegisterAPIServer(s *grpc.Server, srv APIServer) {
-	s.RegisterService(&_API_serviceDesc, srv)
+	if interceptor == nil {
+		return srv.(APIServer).PayURI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.API/PayURI",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).PayURI(ctx, req.(*URIPayment))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-
-func _API_Stop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+
+func _API_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Txid)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Stop(ctx, in)
+		return srv.(APIServer).BumpFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Stop",
+		FullMethod: "/pb.API/BumpFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Stop(ctx, req.(*Empty))
+		return srv.(APIServer).BumpFee(ctx, req.(*Txid))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_CurrentAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeySelection)
+func _API_Peers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).CurrentAddress(ctx, in)
+		return srv.(APIServer).Peers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/CurrentAddress",
+		FullMethod: "/pb.API/Peers",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).CurrentAddress(ctx, req.(*KeySelection))
+		return srv.(APIServer).Peers(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(KeySelection)
+func _API_AddWatchedAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Address)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).NewAddress(ctx, in)
+		return srv.(APIServer).AddWatchedAddress(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/NewAddress",
+		FullMethod: "/pb.API/AddWatchedAddress",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).NewAddress(ctx, req.(*KeySelection))
+		return srv.(APIServer).AddWatchedAddress(ctx, req.(*Address))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_ChainTip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_GetConfirmations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Txid)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).ChainTip(ctx, in)
+		return srv.(APIServer).GetConfirmations(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/ChainTip",
+		FullMethod: "/pb.API/GetConfirmations",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).ChainTip(ctx, req.(*Empty))
+		return srv.(APIServer).GetConfirmations(ctx, req.(*Txid))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_Balance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_SweepAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepInfo)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Balance(ctx, in)
+		return srv.(APIServer).SweepAddress(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Balance",
+		FullMethod: "/pb.API/SweepAddress",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Balance(ctx, req.(*Empty))
+		return srv.(APIServer).SweepAddress(ctx, req.(*SweepInfo))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_MasterPrivateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_ReSyncBlockchain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Height)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).MasterPrivateKey(ctx, in)
+		return srv.(APIServer).ReSyncBlockchain(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/MasterPrivateKey",
+		FullMethod: "/pb.API/ReSyncBlockchain",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).MasterPrivateKey(ctx, req.(*Empty))
+		return srv.(APIServer).ReSyncBlockchain(ctx, req.(*Height))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_MasterPublicKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_CreateMultisigSignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMultisigInfo)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).MasterPublicKey(ctx, in)
+		return srv.(APIServer).CreateMultisigSignature(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/MasterPublicKey",
+		FullMethod: "/pb.API/CreateMultisigSignature",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).MasterPublicKey(ctx, req.(*Empty))
+		return srv.(APIServer).CreateMultisigSignature(ctx, req.(*CreateMultisigInfo))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_HasKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Address)
+func _API_Multisign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MultisignInfo)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).HasKey(ctx, in)
+		return srv.(APIServer).Multisign(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/HasKey",
+		FullMethod: "/pb.API/Multisign",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).HasKey(ctx, req.(*Address))
+		return srv.(APIServer).Multisign(ctx, req.(*MultisignInfo))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_Params_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeData)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Params(ctx, in)
+		return srv.(APIServer).EstimateFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Params",
+		FullMethod: "/pb.API/EstimateFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Params(ctx, req.(*Empty))
+		return srv.(APIServer).EstimateFee(ctx, req.(*EstimateFeeData))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_Transactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_GetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Address)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Transactions(ctx, in)
+		return srv.(APIServer).GetKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Transactions",
+		FullMethod: "/pb.API/GetKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Transactions(ctx, req.(*Empty))
+		return srv.(APIServer).GetKey(ctx, req.(*Address))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Txid)
+func _API_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).GetTransaction(ctx, in)
+		return srv.(APIServer).ListKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/GetTransaction",
+		FullMethod: "/pb.API/ListKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).GetTransaction(ctx, req.(*Txid))
+		return srv.(APIServer).ListKeys(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_GetFeePerByte_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FeeLevelSelection)
+func _API_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).GetFeePerByte(ctx, in)
+		return srv.(APIServer).ListAddresses(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/GetFeePerByte",
+		FullMethod: "/pb.API/ListAddresses",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).GetFeePerByte(ctx, req.(*FeeLevelSelection))
+		return srv.(APIServer).ListAddresses(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_Spend_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SpendInfo)
+func _API_SetAddressLabel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressLabel)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Spend(ctx, in)
+		return srv.(APIServer).SetAddressLabel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Spend",
+		FullMethod: "/pb.API/SetAddressLabel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Spend(ctx, req.(*SpendInfo))
+		return srv.(APIServer).SetAddressLabel(ctx, req.(*AddressLabel))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_BumpFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Txid)
+func _API_SetTxMemo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxMemo)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).BumpFee(ctx, in)
+		return srv.(APIServer).SetTxMemo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/BumpFee",
+		FullMethod: "/pb.API/SetTxMemo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).BumpFee(ctx, req.(*Txid))
+		return srv.(APIServer).SetTxMemo(ctx, req.(*TxMemo))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_Peers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+func _API_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Peers(ctx, in)
+		return srv.(APIServer).ListUnspent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Peers",
+		FullMethod: "/pb.API/ListUnspent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Peers(ctx, req.(*Empty))
+		return srv.(APIServer).ListUnspent(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_AddWatchedAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Address)
+func _API_LockUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Input)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).AddWatchedAddress(ctx, in)
+		return srv.(APIServer).LockUnspent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/AddWatchedAddress",
+		FullMethod: "/pb.API/LockUnspent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).AddWatchedAddress(ctx, req.(*Address))
+		return srv.(APIServer).LockUnspent(ctx, req.(*Input))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_GetConfirmations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Txid)
+func _API_UnlockUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Input)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).GetConfirmations(ctx, in)
+		return srv.(APIServer).UnlockUnspent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/GetConfirmations",
+		FullMethod: "/pb.API/UnlockUnspent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).GetConfirmations(ctx, req.(*Txid))
+		return srv.(APIServer).UnlockUnspent(ctx, req.(*Input))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_SweepAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SweepInfo)
+func _API_ListLockedUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).SweepAddress(ctx, in)
+		return srv.(APIServer).ListLockedUnspent(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/SweepAddress",
+		FullMethod: "/pb.API/ListLockedUnspent",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).SweepAddress(ctx, req.(*SweepInfo))
+		return srv.(APIServer).ListLockedUnspent(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_ReSyncBlockchain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Height)
+func _API_ImportKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportedKey)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).ReSyncBlockchain(ctx, in)
+		return srv.(APIServer).ImportKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/ReSyncBlockchain",
+		FullMethod: "/pb.API/ImportKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).ReSyncBlockchain(ctx, req.(*Height))
+		return srv.(APIServer).ImportKey(ctx, req.(*ImportedKey))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_CreateMultisigSignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateMultisigInfo)
+func _API_EncryptWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Passphrase)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).CreateMultisigSignature(ctx, in)
+		return srv.(APIServer).EncryptWallet(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/CreateMultisigSignature",
+		FullMethod: "/pb.API/EncryptWallet",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).CreateMultisigSignature(ctx, req.(*CreateMultisigInfo))
+		return srv.(APIServer).EncryptWallet(ctx, req.(*Passphrase))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_Multisign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MultisignInfo)
+func _API_Unlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Passphrase)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).Multisign(ctx, in)
+		return srv.(APIServer).Unlock(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/Multisign",
+		FullMethod: "/pb.API/Unlock",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).Multisign(ctx, req.(*MultisignInfo))
+		return srv.(APIServer).Unlock(ctx, req.(*Passphrase))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EstimateFeeData)
+func _API_Lock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).EstimateFee(ctx, in)
+		return srv.(APIServer).Lock(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/EstimateFee",
+		FullMethod: "/pb.API/Lock",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).EstimateFee(ctx, req.(*EstimateFeeData))
+		return srv.(APIServer).Lock(ctx, req.(*Empty))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_GetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Address)
+func _API_ChangePassphrase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PassphraseChange)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).GetKey(ctx, in)
+		return srv.(APIServer).ChangePassphrase(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/GetKey",
+		FullMethod: "/pb.API/ChangePassphrase",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).GetKey(ctx, req.(*Address))
+		return srv.(APIServer).ChangePassphrase(ctx, req.(*PassphraseChange))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_CreateUnsignedTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendInfo)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).ListKeys(ctx, in)
+		return srv.(APIServer).CreateUnsignedTransaction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/ListKeys",
+		FullMethod: "/pb.API/CreateUnsignedTransaction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).ListKeys(ctx, req.(*Empty))
+		return srv.(APIServer).CreateUnsignedTransaction(ctx, req.(*SpendInfo))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_ListAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+func _API_SignTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PartiallySignedTx)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).ListAddresses(ctx, in)
+		return srv.(APIServer).SignTransaction(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/ListAddresses",
+		FullMethod: "/pb.API/SignTransaction",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).ListAddresses(ctx, req.(*Empty))
+		return srv.(APIServer).SignTransaction(ctx, req.(*PartiallySignedTx))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _API_ImportKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ImportedKey)
+func _API_FinalizeAndBroadcast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PartiallySignedTx)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(APIServer).ImportKey(ctx, in)
+		return srv.(APIServer).FinalizeAndBroadcast(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/pb.API/ImportKey",
+		FullMethod: "/pb.API/FinalizeAndBroadcast",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(APIServer).ImportKey(ctx, req.(*ImportedKey))
+		return srv.(APIServer).FinalizeAndBroadcast(ctx, req.(*PartiallySignedTx))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1856,6 +3639,27 @@ func (x *aPIDumpHeadersServer) Send(m *Header) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _API_SubscribeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).SubscribeEvents(m, &aPISubscribeEventsServer{stream})
+}
+
+type API_SubscribeEventsServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type aPISubscribeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPISubscribeEventsServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _API_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.API",
 	HandlerType: (*APIServer)(nil),
@@ -1872,6 +3676,10 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "NewAddress",
 			Handler:    _API_NewAddress_Handler,
 		},
+		{
+			MethodName: "PaymentURI",
+			Handler:    _API_PaymentURI_Handler,
+		},
 		{
 			MethodName: "ChainTip",
 			Handler:    _API_ChainTip_Handler,
@@ -1904,6 +3712,26 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetTransaction",
 			Handler:    _API_GetTransaction_Handler,
 		},
+		{
+			MethodName: "GetTransactionDetail",
+			Handler:    _API_GetTransactionDetail_Handler,
+		},
+		{
+			MethodName: "ExportHistory",
+			Handler:    _API_ExportHistory_Handler,
+		},
+		{
+			MethodName: "CreateInvoice",
+			Handler:    _API_CreateInvoice_Handler,
+		},
+		{
+			MethodName: "GetInvoice",
+			Handler:    _API_GetInvoice_Handler,
+		},
+		{
+			MethodName: "ListInvoices",
+			Handler:    _API_ListInvoices_Handler,
+		},
 		{
 			MethodName: "GetFeePerByte",
 			Handler:    _API_GetFeePerByte_Handler,
@@ -1912,6 +3740,14 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Spend",
 			Handler:    _API_Spend_Handler,
 		},
+		{
+			MethodName: "SpendMany",
+			Handler:    _API_SpendMany_Handler,
+		},
+		{
+			MethodName: "PayURI",
+			Handler:    _API_PayURI_Handler,
+		},
 		{
 			MethodName: "BumpFee",
 			Handler:    _API_BumpFee_Handler,
@@ -1960,10 +3796,62 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListAddresses",
 			Handler:    _API_ListAddresses_Handler,
 		},
+		{
+			MethodName: "SetAddressLabel",
+			Handler:    _API_SetAddressLabel_Handler,
+		},
+		{
+			MethodName: "SetTxMemo",
+			Handler:    _API_SetTxMemo_Handler,
+		},
+		{
+			MethodName: "ListUnspent",
+			Handler:    _API_ListUnspent_Handler,
+		},
+		{
+			MethodName: "LockUnspent",
+			Handler:    _API_LockUnspent_Handler,
+		},
+		{
+			MethodName: "UnlockUnspent",
+			Handler:    _API_UnlockUnspent_Handler,
+		},
+		{
+			MethodName: "ListLockedUnspent",
+			Handler:    _API_ListLockedUnspent_Handler,
+		},
 		{
 			MethodName: "ImportKey",
 			Handler:    _API_ImportKey_Handler,
 		},
+		{
+			MethodName: "EncryptWallet",
+			Handler:    _API_EncryptWallet_Handler,
+		},
+		{
+			MethodName: "Unlock",
+			Handler:    _API_Unlock_Handler,
+		},
+		{
+			MethodName: "Lock",
+			Handler:    _API_Lock_Handler,
+		},
+		{
+			MethodName: "ChangePassphrase",
+			Handler:    _API_ChangePassphrase_Handler,
+		},
+		{
+			MethodName: "CreateUnsignedTransaction",
+			Handler:    _API_CreateUnsignedTransaction_Handler,
+		},
+		{
+			MethodName: "SignTransaction",
+			Handler:    _API_SignTransaction_Handler,
+		},
+		{
+			MethodName: "FinalizeAndBroadcast",
+			Handler:    _API_FinalizeAndBroadcast_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1976,6 +3864,11 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			Handler:       _API_DumpHeaders_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeEvents",
+			Handler:       _API_SubscribeEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api.proto",
 }
@@ -1983,102 +3876,192 @@ var _API_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("api.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1546 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x5b, 0x6f, 0x23, 0x49,
-	0x15, 0xf6, 0xdd, 0xee, 0x13, 0x3b, 0xf1, 0x14, 0xb0, 0x63, 0x99, 0x21, 0xe3, 0xa9, 0x5d, 0x84,
-	0x37, 0x88, 0xec, 0xc6, 0x08, 0x34, 0x2f, 0x0b, 0xe4, 0xbe, 0x26, 0x93, 0xc4, 0x2a, 0x1b, 0x16,
-	0x9e, 0x50, 0xb9, 0xfb, 0xc4, 0x69, 0x8d, 0xdd, 0xdd, 0xea, 0xae, 0x4e, 0x6c, 0x9e, 0xf8, 0x33,
-	0xbc, 0x23, 0xf1, 0xcc, 0xdf, 0xe0, 0xf7, 0xa0, 0x3a, 0x7d, 0x4f, 0x26, 0x99, 0x11, 0x9a, 0xb7,
-	0xaa, 0x73, 0x3e, 0x77, 0x9d, 0xcb, 0x57, 0xa7, 0x3e, 0x83, 0x21, 0x3d, 0x7b, 0xdf, 0xf3, 0x5d,
-	0xe5, 0xb2, 0x8a, 0x37, 0xef, 0xbf, 0x5e, 0xb8, 0xee, 0x62, 0x89, 0xdf, 0x90, 0x65, 0x1e, 0xde,
-	0x7c, 0xa3, 0xec, 0x15, 0x06, 0x4a, 0xae, 0xbc, 0x08, 0xc4, 0x9b, 0x50, 0x3f, 0x5d, 0x79, 0x6a,
-	0xc3, 0xdf, 0x42, 0xfb, 0x02, 0x37, 0x53, 0x5c, 0xa2, 0xa9, 0x6c, 0xd7, 0x61, 0x43, 0x68, 0x7a,
-	0xa1, 0xef, 0xb9, 0x01, 0xf6, 0xca, 0x83, 0xf2, 0x70, 0x7b, 0xb4, 0xbd, 0xef, 0xcd, 0xf7, 0x2f,
-	0x70, 0x33, 0x89, 0xac, 0x22, 0x71, 0xf3, 0x9f, 0x41, 0xf3, 0xd0, 0xb2, 0x7c, 0x0c, 0x02, 0xc6,
-	0xa0, 0x26, 0x2d, 0xcb, 0xa7, 0x5f, 0x18, 0x82, 0xd6, 0x7c, 0x00, 0x8d, 0xef, 0xd1, 0x5e, 0xdc,
-	0x2a, 0xf6, 0x05, 0x34, 0x6e, 0x69, 0x45, 0xfe, 0x8e, 0x88, 0x77, 0xfc, 0x8f, 0xd0, 0x3a, 0x92,
-	0x4b, 0xe9, 0x98, 0x18, 0xb0, 0x57, 0x60, 0x98, 0xae, 0x73, 0x63, 0xfb, 0x2b, 0xb4, 0x08, 0x56,
-	0x13, 0x99, 0x81, 0x0d, 0x60, 0x2b, 0x74, 0x32, 0x7f, 0x85, 0xfc, 0x79, 0x13, 0x7f, 0x09, 0xd5,
-	0x0b, 0xdc, 0xb0, 0x2e, 0x54, 0xdf, 0xe3, 0x26, 0x8e, 0x43, 0x2f, 0xf9, 0x97, 0x50, 0xbb, 0xc0,
-	0x4d, 0xc0, 0x7e, 0x0a, 0xb5, 0xf7, 0xb8, 0x09, 0x7a, 0xe5, 0x41, 0x75, 0xb8, 0x35, 0x6a, 0xc6,
-	0x49, 0x09, 0x32, 0xf2, 0xdf, 0x82, 0x11, 0xa7, 0x82, 0x01, 0xfb, 0x1a, 0x0c, 0x99, 0x6c, 0x62,
-	0xf8, 0x96, 0x86, 0xc7, 0x08, 0x91, 0x79, 0x39, 0x87, 0xf6, 0x91, 0xeb, 0x2e, 0x05, 0x06, 0x9e,
-	0xeb, 0x04, 0xa8, 0xeb, 0x30, 0x77, 0xdd, 0x25, 0x9d, 0xdf, 0x12, 0xb4, 0xe6, 0xaf, 0xc1, 0xb8,
-	0x42, 0x35, 0x91, 0xbe, 0x5c, 0x51, 0xa1, 0x1c, 0xb9, 0xc2, 0xa4, 0x50, 0x7a, 0xcd, 0xbf, 0x83,
-	0x9d, 0x99, 0x2f, 0x9d, 0x40, 0x52, 0x03, 0xde, 0xd9, 0x81, 0x62, 0x7b, 0xd0, 0x56, 0x99, 0x29,
-	0x89, 0xa2, 0xa1, 0xa3, 0x98, 0xad, 0x45, 0xc1, 0xc7, 0xff, 0x55, 0x86, 0xca, 0x6c, 0xad, 0xbf,
-	0xac, 0xd6, 0xb6, 0x95, 0x7c, 0x59, 0xaf, 0xd9, 0x8f, 0xa1, 0x7e, 0x27, 0x97, 0x21, 0x52, 0xc1,
-	0xaa, 0x22, 0xda, 0xe4, 0xda, 0x51, 0x1d, 0x94, 0x87, 0xf5, 0xa4, 0x1d, 0xec, 0x2d, 0x18, 0x29,
-	0x4b, 0x7a, 0xb5, 0x41, 0x79, 0xb8, 0x35, 0xea, 0xef, 0x47, 0x3c, 0xda, 0x4f, 0x78, 0xb4, 0x3f,
-	0x4b, 0x10, 0x22, 0x03, 0xeb, 0xe6, 0xdd, 0x4b, 0x65, 0xde, 0x5e, 0x3b, 0xcb, 0x4d, 0xaf, 0x4e,
-	0xb9, 0x67, 0x06, 0xdd, 0x13, 0x5f, 0xde, 0xf7, 0x1a, 0x83, 0xf2, 0xb0, 0x2d, 0xf4, 0x92, 0xf7,
-	0xa1, 0x36, 0xd3, 0xf1, 0x31, 0xa8, 0xdd, 0xca, 0xe0, 0x36, 0x89, 0x59, 0xaf, 0xf9, 0x77, 0xf0,
-	0xe2, 0x0c, 0xf1, 0x1d, 0xde, 0xe1, 0x32, 0x4f, 0xca, 0xd6, 0x4d, 0x6c, 0x8c, 0x59, 0xd9, 0xd6,
-	0xb5, 0x48, 0x80, 0x22, 0xf5, 0xf2, 0x5d, 0x80, 0x33, 0xc4, 0x09, 0xfa, 0x47, 0x1b, 0x85, 0xfa,
-	0xe8, 0x1b, 0xc4, 0x98, 0x4f, 0x7a, 0xa9, 0x79, 0x72, 0x86, 0x1f, 0x72, 0x2c, 0xc0, 0x98, 0x7a,
-	0xe8, 0x58, 0x63, 0xe7, 0xc6, 0x65, 0x3d, 0x68, 0xc6, 0x4d, 0x8e, 0x63, 0x4b, 0xb6, 0xba, 0x78,
-	0x72, 0xe5, 0x86, 0x8e, 0x8a, 0x49, 0x18, 0xef, 0x0a, 0x11, 0x56, 0x9f, 0x8d, 0x70, 0x0f, 0x5a,
-	0x13, 0x44, 0x9f, 0xfa, 0xbc, 0x0b, 0x75, 0x0f, 0xd1, 0x4f, 0x1a, 0xdc, 0xd2, 0x3f, 0xd1, 0x4e,
-	0x11, 0x99, 0xf9, 0x7f, 0x2b, 0x50, 0xd3, 0xfb, 0x67, 0x02, 0x7a, 0x05, 0xc6, 0x7c, 0xa3, 0x30,
-	0x98, 0x62, 0x1a, 0x53, 0x66, 0x60, 0x5f, 0x41, 0x87, 0x36, 0x02, 0x4d, 0xb4, 0xef, 0xd0, 0xa2,
-	0xd8, 0x6a, 0xa2, 0x68, 0x8c, 0x2f, 0x9f, 0x83, 0xa6, 0x42, 0x8b, 0x3a, 0xdf, 0x12, 0x99, 0x81,
-	0x6d, 0x43, 0x65, 0x7c, 0x42, 0x6d, 0xad, 0x8b, 0xca, 0xf8, 0x44, 0xa3, 0x97, 0x32, 0x50, 0x47,
-	0x4b, 0xd7, 0x7c, 0x4f, 0x5d, 0xad, 0x8b, 0xcc, 0xc0, 0x86, 0xb0, 0x43, 0x64, 0x31, 0xdd, 0xe5,
-	0x9f, 0xd1, 0x0f, 0x6c, 0xd7, 0xe9, 0x35, 0xe9, 0xd6, 0x3f, 0x34, 0xb3, 0x3e, 0xb4, 0x02, 0xf4,
-	0xef, 0x6c, 0x13, 0x83, 0x5e, 0x8b, 0x92, 0x4a, 0xf7, 0xfa, 0x8c, 0x30, 0x40, 0xff, 0x70, 0xa1,
-	0xb3, 0x32, 0xc8, 0x99, 0x19, 0xd8, 0x1f, 0xa0, 0xa3, 0xc9, 0x77, 0x9c, 0xc6, 0x0c, 0x1f, 0x65,
-	0x6b, 0xf1, 0x07, 0xfc, 0x37, 0xd0, 0x39, 0x8e, 0x66, 0x87, 0xa4, 0x5b, 0xa4, 0x0b, 0x65, 0xe6,
-	0x0d, 0xf1, 0xa8, 0x2a, 0x1a, 0xf9, 0x19, 0xd4, 0xfe, 0xa4, 0xd6, 0xee, 0x53, 0x97, 0xcd, 0x76,
-	0x2c, 0x5c, 0x53, 0x13, 0x3a, 0x22, 0xda, 0x64, 0x57, 0x30, 0x2a, 0x7c, 0xb4, 0xe1, 0xff, 0x2c,
-	0x83, 0x31, 0xbd, 0x47, 0xf4, 0x88, 0x6d, 0xbb, 0x50, 0x0f, 0xd5, 0xda, 0x2d, 0xb0, 0x40, 0x1f,
-	0x23, 0x22, 0x73, 0xbe, 0xf9, 0x95, 0x62, 0xf3, 0xe3, 0x71, 0x57, 0x4d, 0xc7, 0x1d, 0xe3, 0xd0,
-	0xf6, 0xd1, 0x42, 0x5c, 0x4d, 0x4d, 0xdf, 0xf6, 0x14, 0x75, 0xb3, 0x2d, 0x0a, 0xb6, 0x02, 0x57,
-	0xeb, 0xcf, 0x72, 0xf5, 0x00, 0xea, 0x63, 0xc7, 0x0b, 0xd5, 0xa7, 0x27, 0xcc, 0x8f, 0xa0, 0x71,
-	0x1d, 0x2a, 0xfd, 0x1b, 0x0e, 0xed, 0x80, 0x0e, 0x9c, 0x84, 0xf3, 0x8b, 0x78, 0x28, 0xb7, 0x45,
-	0xc1, 0x56, 0x9c, 0x50, 0x69, 0x79, 0x7e, 0x0f, 0xc6, 0xd4, 0x5e, 0x38, 0x52, 0x85, 0x3e, 0x66,
-	0xc7, 0x94, 0xf3, 0x75, 0x7d, 0x05, 0x46, 0x90, 0x40, 0xe8, 0xc7, 0x6d, 0x91, 0x19, 0xf8, 0xbf,
-	0xcb, 0xc0, 0x8e, 0x7d, 0x94, 0x0a, 0x2f, 0xc3, 0xa5, 0xb2, 0x03, 0x7b, 0x41, 0x85, 0x7e, 0x03,
-	0x0d, 0x5b, 0xa7, 0x93, 0x54, 0xda, 0xd0, 0x69, 0x53, 0x82, 0x22, 0x76, 0xb0, 0xaf, 0xa0, 0xe9,
-	0x52, 0xf8, 0xba, 0xd6, 0x1a, 0x03, 0x1a, 0x13, 0x65, 0x24, 0x12, 0xd7, 0xff, 0x59, 0xf7, 0x5d,
-	0x80, 0x9b, 0x74, 0x36, 0x51, 0xe5, 0x6b, 0x22, 0x67, 0xe1, 0x23, 0xe8, 0xa4, 0x69, 0xd3, 0x78,
-	0x78, 0x03, 0xb5, 0xc0, 0x5e, 0x24, 0xd1, 0x76, 0x74, 0x24, 0x29, 0x40, 0x90, 0x8b, 0xff, 0xa3,
-	0x02, 0x9d, 0x24, 0x47, 0xe7, 0xf3, 0x26, 0x19, 0x9d, 0x7e, 0xd0, 0xab, 0x3e, 0x75, 0xfa, 0x41,
-	0x0c, 0x19, 0xf5, 0x6a, 0x4f, 0x41, 0x46, 0x8f, 0x0a, 0x53, 0xff, 0x68, 0x61, 0x1a, 0x0f, 0x0b,
-	0x43, 0x33, 0xce, 0x77, 0xa5, 0x65, 0xca, 0x40, 0xd1, 0x34, 0x69, 0x89, 0xcc, 0xc0, 0x5f, 0x42,
-	0x5d, 0xc8, 0xfb, 0xd9, 0x5a, 0x0f, 0x2a, 0xb5, 0x8e, 0x69, 0x56, 0x51, 0x6b, 0xfe, 0x77, 0xd8,
-	0x39, 0x0d, 0x94, 0xbd, 0x92, 0x0a, 0xcf, 0x10, 0x4f, 0xa4, 0x92, 0x9f, 0xaf, 0x38, 0xc5, 0x90,
-	0xab, 0x8f, 0x7a, 0xb9, 0xab, 0xd5, 0x8f, 0xb4, 0xd0, 0xd7, 0xfc, 0x45, 0x47, 0xf9, 0x89, 0x28,
-	0x89, 0x36, 0xfc, 0x6f, 0xb0, 0x35, 0x5e, 0x79, 0xae, 0xaf, 0xd0, 0xfa, 0xa0, 0x6e, 0x61, 0xbf,
-	0x83, 0xb6, 0xa9, 0x19, 0x6c, 0xbb, 0xce, 0x89, 0x54, 0x11, 0xc7, 0x9f, 0x1f, 0x71, 0x05, 0xfc,
-	0xde, 0x10, 0x20, 0x13, 0x6d, 0xac, 0x0d, 0xad, 0xf1, 0xd5, 0xec, 0x54, 0x5c, 0x1d, 0xbe, 0xeb,
-	0x96, 0xf4, 0xee, 0xf4, 0x2f, 0xf1, 0xae, 0xbc, 0x37, 0x82, 0x56, 0x72, 0xf5, 0xc9, 0x73, 0x7c,
-	0x7d, 0x75, 0x7d, 0x39, 0x3e, 0xee, 0x96, 0x18, 0x40, 0xe3, 0xea, 0x5a, 0x5c, 0x6a, 0x94, 0xf6,
-	0x4c, 0xc4, 0xf8, 0x5a, 0x8c, 0x67, 0x7f, 0xed, 0x56, 0x46, 0xff, 0x31, 0xa0, 0x7a, 0x38, 0x19,
-	0xb3, 0x5d, 0xa8, 0x4d, 0x95, 0xeb, 0x31, 0xaa, 0x23, 0x09, 0xca, 0x7e, 0xb6, 0xe4, 0x25, 0x76,
-	0x00, 0xdb, 0xc7, 0xa1, 0xef, 0xa3, 0xa3, 0x12, 0xa9, 0xd8, 0x8d, 0x95, 0x57, 0xfa, 0xb8, 0xf7,
-	0xf3, 0xe2, 0x8a, 0x97, 0xd8, 0xaf, 0x00, 0xae, 0xf0, 0xfe, 0x93, 0xe1, 0x5f, 0x42, 0xeb, 0xf8,
-	0x56, 0xda, 0xce, 0xcc, 0x2e, 0x44, 0x41, 0x4d, 0x8b, 0xf4, 0x27, 0x2f, 0xe9, 0x9e, 0xc6, 0x4a,
-	0x33, 0x8f, 0xa1, 0xa9, 0x97, 0x28, 0x50, 0x5e, 0x62, 0x43, 0xe8, 0x5e, 0xca, 0x40, 0xa1, 0x3f,
-	0xf1, 0xed, 0x3b, 0xa9, 0x50, 0x37, 0x26, 0x07, 0x4f, 0x34, 0x23, 0x2f, 0xb1, 0x5f, 0xc0, 0x4e,
-	0x8c, 0x0c, 0xe7, 0x4b, 0xdb, 0x7c, 0x1a, 0xf8, 0x35, 0x34, 0xbe, 0x97, 0x81, 0xf6, 0xe7, 0xc3,
-	0xee, 0x53, 0x56, 0x79, 0xe5, 0x48, 0x31, 0x36, 0x62, 0x91, 0x98, 0xfb, 0x14, 0x5d, 0xa9, 0x54,
-	0x3e, 0xf2, 0x12, 0xfb, 0x16, 0xda, 0x39, 0xb1, 0x58, 0xc0, 0xfe, 0x88, 0xe4, 0x61, 0x51, 0x49,
-	0xd2, 0x77, 0xb7, 0xcf, 0x51, 0xe5, 0xec, 0xac, 0x15, 0xe9, 0x48, 0xdb, 0xea, 0xc7, 0x8a, 0x92,
-	0x97, 0xd8, 0x5b, 0xe8, 0x9c, 0xa3, 0xca, 0x49, 0xa7, 0x9f, 0xe4, 0x9f, 0x84, 0xac, 0xfa, 0xdb,
-	0xb1, 0x39, 0xe1, 0x79, 0x89, 0x71, 0xa8, 0x93, 0x70, 0x62, 0xd1, 0xf5, 0x4f, 0x34, 0x54, 0x3f,
-	0x3d, 0x85, 0x97, 0xd8, 0x6b, 0x68, 0x1e, 0x85, 0x2b, 0x4f, 0x2b, 0xaf, 0xec, 0xf0, 0x3c, 0x80,
-	0x43, 0x5d, 0xeb, 0x9c, 0xe0, 0x51, 0x7b, 0x12, 0xa9, 0x44, 0xc4, 0x78, 0x71, 0x68, 0x59, 0x3f,
-	0x68, 0x5d, 0x89, 0x56, 0xc2, 0x8f, 0x42, 0x59, 0x1f, 0x50, 0xaf, 0x7b, 0x8e, 0xaa, 0xf8, 0xca,
-	0x67, 0x87, 0xbf, 0xd0, 0xab, 0x82, 0x93, 0xba, 0xd5, 0xa6, 0x57, 0x39, 0xf9, 0x78, 0x94, 0x51,
-	0xf2, 0x4e, 0x17, 0x02, 0xfe, 0x25, 0x74, 0x05, 0x4e, 0x37, 0x8e, 0x49, 0xaa, 0xc7, 0xd4, 0x0c,
-	0x64, 0x39, 0xce, 0x15, 0x43, 0x39, 0x83, 0x97, 0xc5, 0xd7, 0x28, 0x7b, 0xdd, 0xbe, 0xa0, 0x38,
-	0x1e, 0x3d, 0x55, 0x51, 0x7c, 0x85, 0xd7, 0x80, 0x0e, 0x35, 0xd2, 0x59, 0xcf, 0x08, 0x51, 0x18,
-	0xfd, 0xd1, 0xa1, 0x34, 0x0b, 0xa9, 0x5c, 0x5b, 0xb9, 0xe9, 0xc7, 0x88, 0x1d, 0x0f, 0xc6, 0x61,
-	0xc4, 0xd4, 0x33, 0xd4, 0x6d, 0x1c, 0x40, 0xe3, 0x1c, 0xd5, 0x23, 0xa6, 0xe6, 0xb8, 0xfc, 0x06,
-	0x5a, 0x3a, 0x0e, 0xfa, 0x37, 0x95, 0x6b, 0x53, 0x2b, 0x46, 0x04, 0x14, 0x60, 0x47, 0x43, 0xb2,
-	0xff, 0x52, 0x0f, 0xa9, 0x9c, 0x7a, 0xa8, 0xda, 0x46, 0x34, 0x02, 0xf5, 0xa1, 0x3b, 0x34, 0x88,
-	0xb3, 0x89, 0x58, 0x2c, 0xe0, 0xcf, 0xa1, 0xfd, 0x83, 0x5c, 0x2e, 0x51, 0x5d, 0xb9, 0xca, 0xbe,
-	0x29, 0x5c, 0xb6, 0x94, 0xc2, 0xdf, 0x96, 0xd9, 0x10, 0xb6, 0x4e, 0xc2, 0x95, 0x17, 0x0d, 0xde,
-	0xe0, 0x03, 0xe3, 0x40, 0xdb, 0x35, 0x72, 0xde, 0xa0, 0xf9, 0xf9, 0xeb, 0xff, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0xf6, 0xbc, 0x7d, 0x54, 0x37, 0x0f, 0x00, 0x00,
+	// 2983 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x39, 0x5b, 0x6f, 0x1b, 0xc7,
+	0xd5, 0xbc, 0x5f, 0x0e, 0x49, 0x89, 0x9a, 0xd8, 0x09, 0x3f, 0xc5, 0xf0, 0x65, 0xe2, 0xc0, 0xb2,
+	0x92, 0x4f, 0xb1, 0x95, 0x26, 0x70, 0x50, 0xa4, 0x2d, 0x45, 0x51, 0x36, 0x61, 0x99, 0x22, 0x86,
+	0x74, 0x2e, 0x28, 0x50, 0x63, 0x49, 0x8e, 0xa4, 0x85, 0xc9, 0x5d, 0x76, 0x77, 0x28, 0x8b, 0x69,
+	0x1f, 0xda, 0xe7, 0x02, 0x45, 0x5f, 0xfa, 0xde, 0x97, 0xfe, 0x82, 0x16, 0xfd, 0x05, 0x7d, 0xeb,
+	0x7b, 0xff, 0x45, 0xd1, 0x7f, 0x50, 0x14, 0x73, 0x66, 0x76, 0x77, 0x86, 0xba, 0x3a, 0xcd, 0xd3,
+	0xce, 0x9c, 0x73, 0x66, 0xe6, 0xcc, 0x99, 0x73, 0x5f, 0x28, 0x3b, 0x33, 0x77, 0x6b, 0x16, 0xf8,
+	0xc2, 0x27, 0x99, 0xd9, 0x70, 0xfd, 0xce, 0x91, 0xef, 0x1f, 0x4d, 0xf8, 0x27, 0x08, 0x19, 0xce,
+	0x0f, 0x3f, 0x11, 0xee, 0x94, 0x87, 0xc2, 0x99, 0xce, 0x14, 0x11, 0x2d, 0x42, 0xbe, 0x3d, 0x9d,
+	0x89, 0x05, 0x7d, 0x02, 0xd5, 0xe7, 0x7c, 0xd1, 0xe7, 0x13, 0x3e, 0x12, 0xae, 0xef, 0x91, 0x0d,
+	0x28, 0xce, 0xe6, 0xc1, 0xcc, 0x0f, 0x79, 0x23, 0x7d, 0x37, 0xbd, 0xb1, 0xb2, 0xbd, 0xb2, 0x35,
+	0x1b, 0x6e, 0x3d, 0xe7, 0x8b, 0x9e, 0x82, 0xb2, 0x08, 0x4d, 0x3f, 0x85, 0x62, 0x73, 0x3c, 0x0e,
+	0x78, 0x18, 0x12, 0x02, 0x39, 0x67, 0x3c, 0x0e, 0x70, 0x45, 0x99, 0xe1, 0x98, 0xdc, 0x80, 0xfc,
+	0xc4, 0x19, 0xf2, 0x49, 0x23, 0x83, 0x40, 0x35, 0x91, 0xc7, 0xe9, 0x45, 0xfb, 0x72, 0xfe, 0x16,
+	0x2b, 0xef, 0x42, 0xe1, 0x19, 0x77, 0x8f, 0x8e, 0x05, 0x79, 0x17, 0x0a, 0xc7, 0x38, 0xc2, 0x55,
+	0x35, 0xa6, 0x67, 0x74, 0x08, 0xa5, 0x1d, 0x67, 0xe2, 0x78, 0x23, 0x1e, 0x92, 0x5b, 0x50, 0x1e,
+	0xf9, 0xde, 0xa1, 0x1b, 0x4c, 0xf9, 0x18, 0xc9, 0x72, 0x2c, 0x01, 0x90, 0xbb, 0x50, 0x99, 0x7b,
+	0x09, 0x3e, 0x83, 0x78, 0x13, 0x24, 0xcf, 0x98, 0xf8, 0xa3, 0xd7, 0x7c, 0xdc, 0xc8, 0x22, 0x52,
+	0xcf, 0xe8, 0x7b, 0x90, 0x7d, 0xce, 0x17, 0xa4, 0x0e, 0xd9, 0xd7, 0x7c, 0xa1, 0xb9, 0x96, 0x43,
+	0xfa, 0x01, 0xe4, 0x9e, 0xf3, 0x45, 0x48, 0xde, 0x87, 0xdc, 0x6b, 0xbe, 0x08, 0x1b, 0xe9, 0xbb,
+	0xd9, 0x8d, 0xca, 0x76, 0x51, 0x0b, 0x8f, 0x21, 0x90, 0x7e, 0x0e, 0x65, 0x7d, 0x7b, 0x1e, 0x92,
+	0x87, 0x50, 0x76, 0xa2, 0x89, 0x26, 0xaf, 0x48, 0x72, 0x4d, 0xc1, 0x12, 0x2c, 0xa5, 0x50, 0xdd,
+	0xf1, 0xfd, 0x09, 0xe3, 0xe1, 0xcc, 0xf7, 0x42, 0x2e, 0xa5, 0x36, 0xf4, 0xfd, 0x09, 0x9e, 0x5f,
+	0x62, 0x38, 0xa6, 0x77, 0xa0, 0xdc, 0xe5, 0xa2, 0xe7, 0x04, 0xce, 0x14, 0x1f, 0xc4, 0x73, 0xa6,
+	0x3c, 0x12, 0xab, 0x1c, 0xd3, 0x2f, 0x61, 0x75, 0x10, 0x38, 0x5e, 0xe8, 0xe0, 0x43, 0xef, 0xbb,
+	0xa1, 0x20, 0x9b, 0x50, 0x15, 0x09, 0x28, 0xe2, 0xa2, 0x20, 0xb9, 0x18, 0x9c, 0x32, 0x0b, 0x47,
+	0xff, 0x9e, 0x86, 0xcc, 0xe0, 0x54, 0xee, 0x2c, 0x4e, 0xdd, 0x71, 0xb4, 0xb3, 0x1c, 0xcb, 0x07,
+	0x3b, 0x71, 0x26, 0x73, 0x8e, 0x82, 0xcc, 0x32, 0x35, 0x31, 0x9e, 0x49, 0x8a, 0x30, 0x1f, 0x3d,
+	0x13, 0x79, 0x02, 0xe5, 0x58, 0x1b, 0x1b, 0xb9, 0xbb, 0xe9, 0x8d, 0xca, 0xf6, 0xfa, 0x96, 0xd2,
+	0xd7, 0xad, 0x48, 0x5f, 0xb7, 0x06, 0x11, 0x05, 0x4b, 0x88, 0xe5, 0xa3, 0xbe, 0x71, 0xc4, 0xe8,
+	0xf8, 0xc0, 0x9b, 0x2c, 0x1a, 0x79, 0xbc, 0x7b, 0x02, 0x90, 0x6f, 0x12, 0x38, 0x6f, 0x1a, 0x85,
+	0xbb, 0xe9, 0x8d, 0x2a, 0x93, 0x43, 0xc9, 0xeb, 0x94, 0x4f, 0xfd, 0x46, 0x51, 0xf1, 0x2a, 0xc7,
+	0x74, 0x1d, 0x72, 0x03, 0xc9, 0x33, 0x81, 0xdc, 0xb1, 0x13, 0x1e, 0x47, 0xf7, 0x90, 0x63, 0xfa,
+	0x08, 0x0a, 0x83, 0xd3, 0x17, 0x7c, 0xea, 0x9f, 0x7b, 0xcb, 0x68, 0xb7, 0x8c, 0xb1, 0xdb, 0x7f,
+	0xd2, 0xb0, 0x66, 0x08, 0x75, 0x97, 0x0b, 0xc7, 0x9d, 0x90, 0x77, 0x21, 0x23, 0x4e, 0x71, 0x6d,
+	0x22, 0xcc, 0x8c, 0x38, 0x25, 0xf7, 0xa1, 0xa6, 0x35, 0xcc, 0x51, 0xf2, 0x56, 0xf2, 0xb2, 0x81,
+	0x52, 0x6e, 0xa1, 0x70, 0xc4, 0x3c, 0x44, 0xb9, 0x95, 0x99, 0x9e, 0x91, 0x07, 0x50, 0x70, 0xbd,
+	0xd9, 0x5c, 0x84, 0x8d, 0x1c, 0x3e, 0xd3, 0xaa, 0xdc, 0xb9, 0x23, 0x21, 0xea, 0x58, 0xa6, 0xd1,
+	0x64, 0x13, 0x8a, 0xfe, 0x5c, 0x20, 0x65, 0x1e, 0x29, 0xeb, 0x92, 0xf2, 0x60, 0x2e, 0x12, 0xd2,
+	0x88, 0x40, 0x0a, 0xed, 0x90, 0x73, 0x14, 0x5a, 0x96, 0xc9, 0x21, 0x69, 0x40, 0xf1, 0x90, 0x73,
+	0xe6, 0x08, 0x8e, 0x72, 0x4b, 0xb3, 0x68, 0x2a, 0x05, 0x10, 0xba, 0xdf, 0xf1, 0x46, 0x09, 0xad,
+	0x0e, 0xc7, 0xf4, 0x57, 0x50, 0x31, 0x58, 0xb8, 0x48, 0x3b, 0x5c, 0x6f, 0xcc, 0x4f, 0xf1, 0xb6,
+	0x35, 0xa6, 0x26, 0xf2, 0x18, 0xad, 0xdf, 0xfa, 0x9a, 0xd1, 0x34, 0xd1, 0xa6, 0x9c, 0xa9, 0x4d,
+	0x52, 0xfa, 0xae, 0xc7, 0xf5, 0xb3, 0xe3, 0x98, 0xfe, 0x1a, 0xaa, 0xe6, 0xad, 0x92, 0x93, 0xd2,
+	0x17, 0x9c, 0x94, 0xb9, 0xe0, 0xa4, 0xec, 0x79, 0x27, 0xe5, 0x92, 0x93, 0x24, 0x6c, 0xec, 0x08,
+	0x07, 0xe5, 0x59, 0x65, 0x38, 0xa6, 0x7f, 0x4a, 0xc3, 0xca, 0x33, 0x37, 0x14, 0x7e, 0xb0, 0x60,
+	0xfc, 0x97, 0x73, 0x1e, 0xa2, 0x67, 0x3a, 0xf4, 0xe5, 0x43, 0x6a, 0x01, 0xe8, 0x19, 0xd9, 0x82,
+	0xdc, 0x61, 0xe0, 0x4f, 0x1b, 0x99, 0x2b, 0xb5, 0x1d, 0xe9, 0xc8, 0x26, 0x64, 0x84, 0xdf, 0xc8,
+	0x5e, 0x49, 0x9d, 0x11, 0x3e, 0x59, 0x87, 0xd2, 0x68, 0x1e, 0x04, 0xdc, 0x1b, 0x2d, 0x90, 0xe5,
+	0x32, 0x8b, 0xe7, 0xf4, 0x03, 0xa8, 0x69, 0x0e, 0xdb, 0xa7, 0x33, 0x3f, 0x10, 0xf1, 0x3d, 0xd2,
+	0x68, 0x24, 0xea, 0x1e, 0x7f, 0x48, 0xc3, 0x4a, 0xc7, 0x3b, 0xf1, 0xdd, 0x11, 0x37, 0xee, 0xe1,
+	0x4c, 0xfd, 0xb9, 0x27, 0xb4, 0xeb, 0xd4, 0x33, 0x72, 0x1b, 0xe0, 0xd0, 0x75, 0x44, 0x53, 0xe1,
+	0x32, 0xa8, 0x1e, 0x06, 0xc4, 0xe2, 0x25, 0x6b, 0xf3, 0x22, 0xf7, 0xe4, 0xa7, 0x33, 0x37, 0x50,
+	0x5c, 0xd6, 0x98, 0x9e, 0xc5, 0x66, 0x95, 0x37, 0xcc, 0xea, 0x7d, 0x28, 0x6b, 0x8e, 0x3a, 0xbb,
+	0x64, 0x05, 0x32, 0xb1, 0x46, 0x65, 0xdc, 0x31, 0xfd, 0x6b, 0x16, 0x8a, 0x1a, 0xbb, 0x8c, 0xbb,
+	0xe4, 0xad, 0xeb, 0x90, 0x9d, 0x07, 0xae, 0xe6, 0x4a, 0x0e, 0x8d, 0x4b, 0xe6, 0x2e, 0xb9, 0x64,
+	0xfe, 0xd2, 0x4b, 0x16, 0x96, 0x2e, 0x49, 0xa1, 0xca, 0x4f, 0x47, 0xc7, 0x8e, 0x77, 0x64, 0x5a,
+	0x90, 0x05, 0x8b, 0x2f, 0x5c, 0x4a, 0x2e, 0x4c, 0x7e, 0x04, 0xc5, 0x51, 0xc0, 0x1d, 0xc1, 0xc7,
+	0x8d, 0xf2, 0x95, 0xaf, 0x1e, 0x91, 0xca, 0x55, 0x28, 0x44, 0x1e, 0x36, 0xe0, 0xea, 0x55, 0x9a,
+	0x94, 0x6c, 0x41, 0x69, 0xe6, 0x2c, 0xa6, 0xdc, 0x13, 0x61, 0xa3, 0x82, 0xfe, 0x81, 0x28, 0x4f,
+	0x82, 0x22, 0xed, 0x29, 0x14, 0x8b, 0x69, 0xe4, 0x7d, 0x03, 0x3e, 0xe2, 0xee, 0x09, 0x1f, 0x37,
+	0xaa, 0x28, 0xa9, 0x78, 0x4e, 0x1e, 0xc6, 0xbe, 0xaa, 0x86, 0xc9, 0xc2, 0x9a, 0xb1, 0x53, 0x1f,
+	0x11, 0x91, 0xfb, 0xa2, 0xbf, 0x4f, 0xd4, 0x4c, 0x9f, 0xf1, 0x16, 0xde, 0xe2, 0x7c, 0x4b, 0x4d,
+	0x22, 0x4c, 0xce, 0x8a, 0x30, 0x67, 0xfc, 0x6c, 0x1e, 0xf7, 0xb2, 0x81, 0xf4, 0x73, 0xa8, 0x68,
+	0x7e, 0x30, 0x16, 0x3e, 0x80, 0x92, 0xab, 0xa6, 0x56, 0x34, 0x8e, 0x2c, 0x23, 0x46, 0xd2, 0x2f,
+	0x61, 0x6d, 0x8f, 0xf3, 0x7d, 0x7e, 0xc2, 0x27, 0x66, 0xda, 0x54, 0x3a, 0xd4, 0x40, 0x9d, 0x37,
+	0x55, 0xe5, 0xea, 0x88, 0x90, 0xc5, 0x58, 0x7a, 0x1b, 0x60, 0x8f, 0xf3, 0x1e, 0x0f, 0x76, 0x16,
+	0x82, 0x47, 0xfe, 0x57, 0x99, 0x99, 0x1c, 0xca, 0x0c, 0x63, 0x8f, 0x9f, 0x87, 0xf8, 0x6d, 0x06,
+	0xca, 0xfd, 0x19, 0xf7, 0xc6, 0x1d, 0xef, 0xd0, 0x37, 0x35, 0x3d, 0x6d, 0x6b, 0x7a, 0xa2, 0xd7,
+	0x19, 0x4b, 0xaf, 0x4d, 0x16, 0xb3, 0x97, 0xb1, 0x48, 0xee, 0x2d, 0x45, 0x9a, 0x72, 0x1c, 0x69,
+	0xe2, 0x18, 0xf3, 0x09, 0xd4, 0xf8, 0xe9, 0x68, 0x32, 0x1f, 0xf3, 0x8e, 0x67, 0x44, 0x1a, 0x83,
+	0xd2, 0xc6, 0xc7, 0x5a, 0x5f, 0x30, 0xb4, 0x5e, 0x5a, 0x5a, 0x2c, 0x0a, 0xb4, 0x95, 0x1c, 0x33,
+	0x20, 0x91, 0x0c, 0x4a, 0x89, 0x0c, 0x7e, 0x0c, 0xc5, 0x48, 0x79, 0xde, 0x5a, 0x00, 0xf4, 0x6f,
+	0x69, 0xa8, 0xa1, 0x00, 0x5f, 0x38, 0xde, 0x02, 0x85, 0xf8, 0xc0, 0x30, 0x05, 0xe3, 0xcd, 0xcf,
+	0xda, 0x80, 0x29, 0xbb, 0xcc, 0x35, 0x65, 0x97, 0xbd, 0xb6, 0xec, 0x72, 0x97, 0xcb, 0x8e, 0x3e,
+	0x03, 0x78, 0xc9, 0x3a, 0xd1, 0xc5, 0xb5, 0x27, 0x4b, 0x27, 0x9e, 0xec, 0xda, 0xdc, 0xd1, 0x9f,
+	0xc3, 0x9a, 0xde, 0xe6, 0x25, 0xeb, 0x5c, 0xe5, 0xed, 0xcf, 0xcd, 0xc3, 0xa5, 0xdc, 0xa7, 0x3c,
+	0x0c, 0x9d, 0x23, 0x1e, 0x05, 0x6e, 0x3d, 0x95, 0x9a, 0x9d, 0x6c, 0x7e, 0x96, 0x4d, 0xba, 0x09,
+	0xa5, 0x1e, 0xe7, 0x01, 0x5a, 0xdb, 0x6d, 0xc8, 0xcf, 0x38, 0x0f, 0x22, 0xb1, 0x97, 0x50, 0xec,
+	0x9c, 0x07, 0x4c, 0x81, 0xe9, 0x3f, 0x33, 0x90, 0x93, 0xf3, 0x4b, 0x9e, 0xf9, 0x16, 0x94, 0x87,
+	0x0b, 0xc1, 0xc3, 0x3e, 0x8f, 0x5f, 0x3a, 0x01, 0x48, 0x1f, 0x80, 0x13, 0x16, 0xb9, 0x2e, 0x95,
+	0xc7, 0xdb, 0x40, 0x5d, 0x26, 0x78, 0x7c, 0x24, 0x3d, 0xaf, 0x0a, 0xf8, 0x09, 0x40, 0x46, 0x97,
+	0xce, 0x2e, 0x3a, 0x8f, 0x3c, 0xcb, 0x74, 0x76, 0x25, 0xf5, 0xc4, 0x09, 0xc5, 0x8e, 0xac, 0x05,
+	0x50, 0x91, 0xf3, 0x2c, 0x01, 0x90, 0x0d, 0x58, 0x45, 0xb7, 0x3b, 0xf2, 0x27, 0x5f, 0xf1, 0x20,
+	0x74, 0x7d, 0x0f, 0x55, 0xba, 0xc6, 0x96, 0xc1, 0xd2, 0xa3, 0x86, 0x3c, 0x38, 0x41, 0x57, 0xa3,
+	0xa2, 0x40, 0x3c, 0x97, 0x67, 0xcc, 0x43, 0x1e, 0x34, 0x8f, 0xe4, 0xad, 0xca, 0x88, 0x4c, 0x00,
+	0xe4, 0x67, 0x50, 0x93, 0xe9, 0x70, 0x2b, 0xe6, 0xf9, 0x6a, 0xbf, 0x6f, 0x2f, 0xa0, 0x9f, 0x41,
+	0xad, 0x65, 0xa5, 0x9b, 0x67, 0x9c, 0x65, 0xfa, 0x3c, 0x67, 0xb9, 0x07, 0xb9, 0x97, 0xe2, 0xd4,
+	0xff, 0xbe, 0x2e, 0x3b, 0xa7, 0x5d, 0x36, 0xfd, 0x77, 0x1a, 0x8a, 0x2f, 0xbd, 0x70, 0xf6, 0x3f,
+	0xb8, 0xff, 0x68, 0x2f, 0x53, 0x35, 0x72, 0xb6, 0x6a, 0x5c, 0x2b, 0x00, 0xe0, 0xfa, 0xd1, 0x08,
+	0x15, 0xbf, 0x80, 0xf8, 0x68, 0x6a, 0x17, 0x1a, 0xc5, 0xe5, 0x42, 0x23, 0xb6, 0x8b, 0x92, 0x69,
+	0x17, 0x49, 0xc5, 0x58, 0xc6, 0x05, 0x7a, 0x46, 0x1f, 0x41, 0x45, 0x5f, 0x18, 0x15, 0xff, 0x1e,
+	0xe4, 0xe7, 0xe2, 0xd4, 0xb7, 0xfc, 0x8d, 0xc6, 0x33, 0x85, 0xa1, 0x7f, 0x4e, 0x43, 0xb9, 0xff,
+	0x86, 0xf3, 0x19, 0xfa, 0xa8, 0xdb, 0xf6, 0x02, 0xb4, 0x14, 0xf9, 0x14, 0x9a, 0xfa, 0xf2, 0x94,
+	0x47, 0x16, 0xa9, 0xd9, 0xb8, 0x48, 0x95, 0xe9, 0x49, 0xc0, 0xc7, 0x9c, 0x4f, 0xfb, 0xa3, 0xc0,
+	0x9d, 0xa9, 0xb0, 0x59, 0x65, 0x16, 0xcc, 0x72, 0x26, 0xf9, 0x4b, 0x9d, 0xc9, 0x63, 0xc8, 0xa3,
+	0x83, 0xba, 0xfe, 0x43, 0xd2, 0x1d, 0x28, 0xa8, 0x8c, 0x5d, 0xb2, 0x12, 0xe2, 0x81, 0xbd, 0xf9,
+	0xf0, 0xb9, 0x2e, 0xa5, 0xab, 0xcc, 0x82, 0xd9, 0x75, 0x65, 0xac, 0x42, 0x3f, 0x85, 0x72, 0xdf,
+	0x3d, 0xf2, 0x1c, 0x31, 0x0f, 0xf8, 0x05, 0x29, 0xff, 0x2d, 0x28, 0x87, 0x11, 0x09, 0x2e, 0xae,
+	0xb2, 0x04, 0x40, 0xff, 0x92, 0x06, 0xd2, 0xc2, 0x14, 0xea, 0xc5, 0x7c, 0x22, 0xdc, 0xd0, 0x3d,
+	0x42, 0x41, 0x27, 0x9e, 0x3b, 0x7d, 0x91, 0xe7, 0xbe, 0x9f, 0x54, 0x56, 0x19, 0xa4, 0x81, 0xa4,
+	0xb2, 0xb2, 0x6a, 0xaa, 0xef, 0x21, 0x77, 0x3b, 0x18, 0xe6, 0x97, 0x83, 0x21, 0xdd, 0x86, 0x5a,
+	0x7c, 0x6d, 0xad, 0x49, 0xb9, 0xd0, 0x3d, 0x8a, 0xb8, 0xad, 0x49, 0x4e, 0x62, 0x02, 0x86, 0x28,
+	0xfa, 0x9b, 0x0c, 0xd4, 0xa2, 0x3b, 0x7a, 0x3f, 0xec, 0x25, 0xd5, 0xe9, 0x8f, 0x1b, 0xd9, 0x8b,
+	0x4e, 0x7f, 0xac, 0x49, 0xb6, 0x1b, 0xb9, 0x8b, 0x48, 0xb6, 0xcf, 0x08, 0x26, 0x7f, 0xa5, 0x60,
+	0x0a, 0x67, 0xb2, 0x04, 0x19, 0x07, 0x02, 0xdf, 0x19, 0x8f, 0x9c, 0x50, 0x44, 0xc6, 0x1a, 0x03,
+	0xe8, 0x7b, 0x90, 0x67, 0xce, 0x9b, 0xc1, 0x29, 0x59, 0x89, 0x8b, 0xf2, 0xaa, 0x2c, 0xc6, 0xe9,
+	0x03, 0x19, 0x0a, 0x03, 0xe1, 0x3a, 0x93, 0xc9, 0x42, 0xb2, 0xc5, 0xc7, 0xaa, 0xbb, 0x71, 0xa6,
+	0x3e, 0xfa, 0x0e, 0x56, 0xdb, 0xa1, 0x70, 0xa7, 0x8e, 0xe0, 0x7b, 0x9c, 0xef, 0x3a, 0xc2, 0xf9,
+	0xe1, 0xa4, 0x68, 0xdf, 0x2d, 0x7b, 0xe6, 0xd1, 0x6f, 0xcb, 0xa6, 0x97, 0x33, 0xe6, 0xd8, 0x14,
+	0xe3, 0x9e, 0x08, 0xa2, 0x9e, 0x93, 0x9a, 0xd0, 0x57, 0x50, 0xe9, 0x4c, 0x65, 0x65, 0xc7, 0xc7,
+	0xe7, 0xb6, 0xa5, 0xc8, 0x4f, 0xa0, 0x8a, 0xd5, 0x82, 0x6c, 0x4e, 0x38, 0x42, 0x19, 0xc3, 0xe5,
+	0xf1, 0xc2, 0xa2, 0xa7, 0x1f, 0xcb, 0x98, 0x1e, 0x86, 0xb3, 0xe3, 0xc0, 0x09, 0xb9, 0x64, 0x77,
+	0x16, 0xcf, 0xf4, 0x31, 0x06, 0x84, 0xfe, 0x02, 0xea, 0x09, 0x75, 0x0b, 0x4b, 0x1e, 0xe9, 0x8b,
+	0xfd, 0xc9, 0xb8, 0xb7, 0xbc, 0xcc, 0x06, 0x4a, 0x2a, 0x8f, 0xbf, 0x31, 0xa8, 0x94, 0x2f, 0xb3,
+	0x81, 0xf4, 0x1f, 0x19, 0xc8, 0xb7, 0x4f, 0x64, 0xec, 0xb8, 0x07, 0x39, 0xb1, 0x98, 0x45, 0x3d,
+	0x4a, 0xd4, 0x2d, 0x44, 0x0c, 0x16, 0x33, 0xce, 0x10, 0x65, 0xf7, 0x99, 0x32, 0x6f, 0xd3, 0x67,
+	0x52, 0xfd, 0x9b, 0xec, 0x99, 0xfe, 0xcd, 0x1d, 0xc8, 0x0f, 0x31, 0xf6, 0xab, 0xae, 0x15, 0xbe,
+	0x3a, 0xc6, 0x7e, 0xa6, 0xe0, 0xb2, 0xaf, 0x18, 0x70, 0x3f, 0x38, 0x52, 0x8d, 0x4a, 0x1d, 0x75,
+	0x4c, 0x90, 0xf4, 0xae, 0x43, 0xdd, 0xa3, 0x44, 0x55, 0xae, 0x28, 0xef, 0x1a, 0xf5, 0x2d, 0x59,
+	0x8c, 0x25, 0xb7, 0x20, 0x27, 0x53, 0x21, 0xd4, 0x68, 0x33, 0x41, 0x42, 0xa8, 0x54, 0x7a, 0xf9,
+	0x6d, 0x61, 0xf4, 0x52, 0x0d, 0x99, 0x04, 0x40, 0xee, 0x43, 0x2e, 0x5c, 0x78, 0x23, 0x5d, 0x4b,
+	0x62, 0xfb, 0xa7, 0xbf, 0xf0, 0x46, 0xbd, 0xc0, 0x3f, 0xc2, 0xd6, 0x22, 0x62, 0xe9, 0xef, 0xd2,
+	0x90, 0x57, 0xa9, 0xcb, 0x39, 0xcd, 0x30, 0xa3, 0xb8, 0xca, 0x98, 0x5d, 0x56, 0x5b, 0xac, 0xd9,
+	0xb7, 0x6c, 0xdf, 0xcd, 0x02, 0x7e, 0xb2, 0x13, 0x8b, 0xb0, 0xcc, 0x12, 0x00, 0x1d, 0x42, 0xd5,
+	0xe4, 0xf1, 0xa2, 0x2e, 0xaf, 0x74, 0x19, 0xc2, 0x09, 0x8e, 0xb8, 0x78, 0x66, 0x72, 0x67, 0xc1,
+	0xe4, 0x5a, 0x79, 0x43, 0x9d, 0xf5, 0x95, 0x98, 0x9e, 0x6d, 0x6e, 0x00, 0x24, 0x9d, 0x6c, 0x52,
+	0x85, 0x52, 0xa7, 0x3b, 0x68, 0xb3, 0x6e, 0x73, 0xbf, 0x9e, 0x92, 0xb3, 0xf6, 0x37, 0x7a, 0x96,
+	0xde, 0x1c, 0x41, 0xcd, 0x2a, 0x63, 0x09, 0x40, 0xe1, 0x65, 0xb7, 0xd7, 0xec, 0xec, 0xd6, 0x53,
+	0x84, 0xc0, 0x4a, 0xaf, 0xc9, 0x06, 0x9d, 0xe6, 0xfe, 0xfe, 0xb7, 0xaf, 0x10, 0x96, 0x26, 0x25,
+	0xc8, 0xe1, 0x28, 0x23, 0x37, 0x3a, 0xf8, 0xaa, 0xcd, 0x70, 0x96, 0x25, 0x15, 0x28, 0xb6, 0xbf,
+	0xe9, 0x75, 0x58, 0x7b, 0xb7, 0x9e, 0x23, 0x35, 0x28, 0xb7, 0x0e, 0xba, 0x7b, 0x1d, 0xf6, 0xa2,
+	0xbd, 0x5b, 0xcf, 0x6f, 0x6e, 0x43, 0x29, 0x0a, 0xab, 0x78, 0x7c, 0xeb, 0xa0, 0x7b, 0xf0, 0xa2,
+	0xd3, 0xaa, 0xa7, 0xe4, 0x69, 0xdd, 0x03, 0xf6, 0x42, 0xb2, 0x22, 0x31, 0x3d, 0xd6, 0x39, 0x60,
+	0x9d, 0xc1, 0xb7, 0xf5, 0xcc, 0xe6, 0x1f, 0xd3, 0x50, 0x8e, 0x35, 0x9d, 0xbc, 0x03, 0xab, 0xdd,
+	0xf6, 0xd7, 0xaf, 0x06, 0xac, 0xd9, 0xed, 0x37, 0x5b, 0x83, 0xce, 0x41, 0xb7, 0x9e, 0x22, 0x75,
+	0xa8, 0xea, 0x53, 0x9a, 0x08, 0x49, 0x93, 0x32, 0xe4, 0x59, 0xfb, 0x80, 0x3d, 0xad, 0x67, 0x24,
+	0x3f, 0xb8, 0xa2, 0xd3, 0x53, 0xcc, 0xed, 0x34, 0xf7, 0x9b, 0xdd, 0x56, 0xbb, 0x9e, 0xc3, 0x5b,
+	0xb5, 0xdb, 0xec, 0x55, 0xeb, 0xa0, 0xdb, 0x6d, 0xb7, 0x06, 0x92, 0x43, 0x72, 0x13, 0xd6, 0x10,
+	0xb6, 0xdb, 0xe9, 0x27, 0xe0, 0x02, 0x59, 0x83, 0x5a, 0xff, 0xdb, 0x6e, 0xeb, 0x55, 0x8f, 0x1d,
+	0x3c, 0x65, 0xed, 0x7e, 0xbf, 0x5e, 0xdc, 0xfe, 0xd7, 0x1a, 0x64, 0x9b, 0xbd, 0x0e, 0xb9, 0x0d,
+	0xb9, 0xbe, 0xf0, 0x67, 0x04, 0x8d, 0x03, 0x7f, 0x31, 0xac, 0x27, 0x43, 0x9a, 0x22, 0x8f, 0x61,
+	0xa5, 0x85, 0xdd, 0x12, 0x11, 0xfd, 0x3c, 0xa8, 0xeb, 0x1e, 0x79, 0x5c, 0x4c, 0xaf, 0x9b, 0x6d,
+	0x70, 0x9a, 0x22, 0xff, 0x0f, 0xd0, 0xe5, 0x6f, 0xae, 0x4d, 0xfe, 0x99, 0x55, 0x86, 0xdc, 0x34,
+	0x0a, 0xba, 0xa4, 0xe6, 0x59, 0x5f, 0xb1, 0xc1, 0x34, 0x45, 0x3e, 0x80, 0x52, 0xeb, 0xd8, 0x71,
+	0xbd, 0x81, 0x6b, 0x31, 0x8f, 0x6e, 0x5b, 0xa9, 0x15, 0x4d, 0x49, 0xaf, 0xae, 0x4d, 0xd5, 0xa4,
+	0xb1, 0x4c, 0x98, 0xa6, 0xc8, 0x06, 0xd4, 0x5f, 0x38, 0xa1, 0xe0, 0x41, 0x2f, 0x70, 0x4f, 0x1c,
+	0xc1, 0xa5, 0x6b, 0x36, 0xc8, 0xa3, 0x9f, 0x02, 0x34, 0x45, 0x1e, 0xc0, 0xaa, 0xa6, 0x9c, 0x0f,
+	0x27, 0xee, 0xe8, 0x62, 0xc2, 0x87, 0x50, 0x78, 0xe6, 0x84, 0x12, 0x6f, 0xde, 0x76, 0x1d, 0x85,
+	0x61, 0xfe, 0x1a, 0x40, 0x1e, 0x0b, 0xfa, 0x2f, 0x80, 0xb1, 0x15, 0x7a, 0xc8, 0xf8, 0xff, 0x00,
+	0x4d, 0x91, 0x47, 0x50, 0x35, 0x1a, 0xd7, 0x16, 0xed, 0x3b, 0x72, 0xb8, 0xf4, 0xab, 0x00, 0xf7,
+	0x5d, 0x79, 0xca, 0x85, 0x01, 0x27, 0x25, 0xe5, 0x1b, 0xdd, 0xf1, 0xba, 0xf6, 0x92, 0x34, 0x45,
+	0xbe, 0x80, 0x1b, 0x36, 0x95, 0xee, 0xcd, 0x26, 0xb4, 0x37, 0x97, 0xb6, 0x57, 0x04, 0x34, 0x45,
+	0x9e, 0x40, 0x4d, 0xb5, 0x29, 0x75, 0xcf, 0x92, 0x60, 0x5f, 0xca, 0x6e, 0xb1, 0xae, 0xaf, 0x19,
+	0x30, 0x45, 0x4d, 0x53, 0x64, 0x1b, 0x6a, 0x2a, 0xa1, 0x8b, 0xfa, 0x82, 0x66, 0x47, 0x2b, 0x5a,
+	0x69, 0xb6, 0x73, 0x68, 0x8a, 0x6c, 0x02, 0x3c, 0xe5, 0x22, 0x5a, 0x50, 0x33, 0x90, 0x9d, 0xdd,
+	0x65, 0xda, 0x8f, 0xa1, 0x2a, 0x85, 0xa0, 0x01, 0x96, 0xb0, 0x56, 0x0d, 0x4a, 0x2d, 0xa8, 0x27,
+	0x50, 0x7b, 0xca, 0x85, 0xd1, 0xe4, 0xb9, 0x69, 0x26, 0xd0, 0x89, 0xde, 0xae, 0x68, 0x70, 0x14,
+	0xec, 0x53, 0x84, 0x42, 0x1e, 0x1b, 0x14, 0x8a, 0x9d, 0xb8, 0xd9, 0xb3, 0x1e, 0x0b, 0x0f, 0xf9,
+	0x2e, 0xc7, 0x4d, 0x0c, 0xb2, 0x16, 0xd3, 0x45, 0x3d, 0x0d, 0x8b, 0x16, 0x55, 0x61, 0x21, 0xcd,
+	0x00, 0xcf, 0x4a, 0x9a, 0x08, 0x16, 0xd5, 0x1d, 0x28, 0xee, 0xcc, 0xa7, 0x33, 0xd9, 0x75, 0x4a,
+	0x5e, 0xc9, 0x24, 0xa0, 0x90, 0x97, 0xa1, 0x27, 0x3c, 0xa3, 0xf3, 0x51, 0x39, 0x8f, 0x46, 0xba,
+	0xd6, 0x1c, 0x8f, 0xbf, 0x96, 0x45, 0x12, 0x1f, 0x47, 0xb6, 0x6a, 0xe9, 0xea, 0x92, 0x1b, 0xa8,
+	0x3f, 0xe5, 0xc2, 0xae, 0x44, 0x93, 0xc3, 0xf1, 0x5a, 0x16, 0x12, 0x4d, 0xa0, 0x8a, 0x55, 0x51,
+	0xb4, 0xb9, 0x92, 0x51, 0x54, 0x27, 0x59, 0x0c, 0x7f, 0x04, 0x75, 0xc6, 0x65, 0x34, 0xc1, 0xd0,
+	0x32, 0x92, 0x66, 0x4d, 0x0c, 0x43, 0xb6, 0x59, 0xd9, 0x83, 0xf7, 0xec, 0x6a, 0x20, 0xa9, 0x2e,
+	0xde, 0x45, 0x3e, 0xce, 0x94, 0x0a, 0x8a, 0x3f, 0x2b, 0x1b, 0xc7, 0x43, 0xcb, 0x11, 0x91, 0xa7,
+	0x1e, 0xc6, 0x4a, 0xbd, 0xd5, 0xa1, 0x98, 0x8b, 0xa2, 0xb8, 0x2a, 0x46, 0x52, 0x49, 0xd0, 0xe4,
+	0x96, 0xb2, 0x4c, 0x65, 0xfe, 0x7b, 0x5c, 0x2a, 0xc6, 0x5d, 0x28, 0x3c, 0xe5, 0xe2, 0x8c, 0xf9,
+	0x1b, 0x0e, 0xe2, 0x1e, 0x94, 0x24, 0x1f, 0xf8, 0x0f, 0xd2, 0x78, 0xa6, 0x92, 0xa6, 0x08, 0x91,
+	0xc1, 0x9a, 0x24, 0x49, 0xfe, 0x40, 0x2e, 0xfb, 0x87, 0x18, 0x83, 0xfe, 0x61, 0xb5, 0xcf, 0x85,
+	0xf5, 0xaf, 0xb6, 0x6e, 0xd0, 0x20, 0xc4, 0x96, 0xe3, 0x7d, 0x28, 0xf7, 0xb9, 0xd0, 0x3f, 0xd0,
+	0x40, 0xbd, 0x86, 0x1c, 0xdb, 0x54, 0x1f, 0x41, 0x45, 0x32, 0x11, 0xf5, 0x00, 0x96, 0x2d, 0xc9,
+	0x28, 0x95, 0x69, 0x8a, 0x7c, 0x08, 0x95, 0x7d, 0x7f, 0xf4, 0xda, 0x22, 0xc6, 0x34, 0xdb, 0xde,
+	0xf3, 0x01, 0xd4, 0x5e, 0x7a, 0x93, 0x6b, 0x10, 0x3e, 0x86, 0x35, 0xb9, 0xf3, 0x3e, 0x56, 0xe6,
+	0xd7, 0x63, 0xe1, 0x21, 0x94, 0x55, 0x86, 0x2d, 0x85, 0xaf, 0x8c, 0x3d, 0x49, 0xb8, 0xed, 0xdd,
+	0x3f, 0x86, 0x5a, 0xdb, 0x1b, 0x05, 0x8b, 0x99, 0xf8, 0xda, 0x99, 0x4c, 0xb8, 0x20, 0x3a, 0xc8,
+	0x44, 0xe9, 0xab, 0x4d, 0xfd, 0x21, 0x14, 0x14, 0xd3, 0x97, 0x93, 0xdd, 0x86, 0x9c, 0x64, 0xf7,
+	0xc2, 0x78, 0xfa, 0x19, 0xd4, 0x55, 0xa2, 0x9d, 0x6c, 0x40, 0x6e, 0xd8, 0x1b, 0x2a, 0xbc, 0xbd,
+	0xac, 0x05, 0xff, 0xa7, 0xf4, 0xfa, 0xa5, 0x17, 0xaa, 0xe2, 0xc7, 0xf0, 0xeb, 0x4b, 0xde, 0x47,
+	0x87, 0xd0, 0xa5, 0x5a, 0x89, 0xa6, 0x48, 0x13, 0x56, 0xe5, 0xcc, 0x5c, 0x7a, 0x3e, 0xed, 0xc5,
+	0x5b, 0x7c, 0x01, 0x37, 0xf6, 0x5c, 0xcf, 0x99, 0xb8, 0xdf, 0xf1, 0xa6, 0x37, 0xde, 0x89, 0xca,
+	0xb6, 0x8b, 0xf6, 0x31, 0x8d, 0xfc, 0x43, 0xa8, 0x2a, 0x39, 0x77, 0x7d, 0xe1, 0x1e, 0x5a, 0x81,
+	0x33, 0x0e, 0x47, 0x8f, 0xd2, 0x64, 0x03, 0x2a, 0xbb, 0xf3, 0xe9, 0x4c, 0x95, 0x51, 0xe1, 0x39,
+	0xa1, 0x5d, 0xc2, 0x91, 0xf2, 0x23, 0x58, 0xed, 0xcf, 0x87, 0xb2, 0x03, 0x31, 0xe4, 0x98, 0x62,
+	0x85, 0x67, 0xa5, 0x2e, 0xc1, 0x92, 0x78, 0x58, 0xc0, 0x5c, 0xf7, 0xd3, 0xff, 0x0e, 0x00, 0x97,
+	0x33, 0xd7, 0x1b, 0x79, 0x21, 0x00, 0x00,
 }
@@ -2,11 +2,12 @@ package api
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"errors"
-	"math/rand"
 	"net"
-	"strconv"
-	"sync"
+	"net/http"
+	"path"
+	"time"
 
 	bitcoincash "github.com/BubbaJoe/spvwallet-cash"
 	"github.com/BubbaJoe/spvwallet-cash/api/pb"
@@ -14,32 +15,101 @@ import (
 	"github.com/gcash/bchd/bchec"
 	"github.com/gcash/bchd/chaincfg"
 	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/peer"
+	"github.com/gcash/bchd/wire"
 	"github.com/gcash/bchutil"
 	"github.com/gcash/bchutil/hdkeychain"
 	"github.com/golang/protobuf/ptypes"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
 const Addr = "127.0.0.1:8234"
 
+// Config configures the API server.
+type Config struct {
+	// The address to listen on, Addr if empty
+	ListenAddr string
+
+	// The directory holding the TLS certificate and the cookie files with
+	// the token of each permission level. They are created if they don't
+	// exist.
+	Dir string
+
+	// Additional hostnames or IP addresses to make the certificate for so
+	// the API can be reached from another host
+	TLSHosts []string
+
+	// The address the HTTP/JSON gateway listens on, see GatewayPrefix. It
+	// isn't served if empty.
+	GatewayListenAddr string
+}
+
 type server struct {
 	w *bitcoincash.SPVWallet
 }
 
-func ServeAPI(w *bitcoincash.SPVWallet) error {
-	lis, err := net.Listen("tcp", Addr)
+// ServeAPI serves the API over TLS, and the HTTP/JSON gateway to it if it has
+// a listen address. Every call must carry the token of a permission level
+// allowing the method, see Permission.
+func ServeAPI(w *bitcoincash.SPVWallet, config Config) error {
+	listenAddr := config.ListenAddr
+	if listenAddr == "" {
+		listenAddr = Addr
+	}
+	hosts := config.TLSHosts
+	for _, addr := range []string{listenAddr, config.GatewayListenAddr} {
+		if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+			if ip := net.ParseIP(host); ip == nil || !ip.IsUnspecified() {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	certPath, keyPath := path.Join(config.Dir, CertFile), path.Join(config.Dir, KeyFile)
+	if err := ensureCert(certPath, keyPath, hosts); err != nil {
+		return err
+	}
+	creds, err := credentials.NewServerTLSFromFile(certPath, keyPath)
 	if err != nil {
 		return err
 	}
-	s := grpc.NewServer()
-	pb.RegisterAPIServer(s, &server{w})
-	reflection.Register(s)
-	if err := s.Serve(lis); err != nil {
+	auth, err := loadTokens(config.Dir)
+	if err != nil {
 		return err
 	}
-	return nil
+	lis, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return err
+	}
+	s := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.UnaryInterceptor(auth.unaryInterceptor),
+		grpc.StreamInterceptor(auth.streamInterceptor),
+	)
+	api := &server{w}
+	pb.RegisterAPIServer(s, api)
+	reflection.Register(s)
+
+	errs := make(chan error, 2)
+	if config.GatewayListenAddr != "" {
+		g, err := newGateway(api, auth)
+		if err != nil {
+			return err
+		}
+		gatewayLis, err := net.Listen("tcp", config.GatewayListenAddr)
+		if err != nil {
+			return err
+		}
+		go func() {
+			errs <- (&http.Server{Handler: g}).ServeTLS(gatewayLis, certPath, keyPath)
+		}()
+	}
+	go func() {
+		errs <- s.Serve(lis)
+	}()
+	return <-errs
 }
 
 func (s *server) Stop(ctx context.Context, in *pb.Empty) (*pb.Empty, error) {
@@ -57,7 +127,7 @@ func (s *server) CurrentAddress(ctx context.Context, in *pb.KeySelection) (*pb.A
 		return nil, errors.New("Unknown key purpose")
 	}
 	addr := s.w.CurrentAddress(purpose)
-	return &pb.Address{addr.String()}, nil
+	return &pb.Address{Addr: addr.String()}, nil
 }
 
 func (s *server) NewAddress(ctx context.Context, in *pb.KeySelection) (*pb.Address, error) {
@@ -70,7 +140,11 @@ func (s *server) NewAddress(ctx context.Context, in *pb.KeySelection) (*pb.Addre
 		return nil, errors.New("Unknown key purpose")
 	}
 	addr := s.w.NewAddress(purpose)
-	return &pb.Address{addr.String()}, nil
+	return &pb.Address{Addr: addr.String()}, nil
+}
+
+func (s *server) PaymentURI(ctx context.Context, in *pb.PaymentURIRequest) (*pb.PaymentURI, error) {
+	return &pb.PaymentURI{s.w.CurrentAddressURI(int64(in.Amount), in.Label, in.Message)}, nil
 }
 
 func (s *server) ChainTip(ctx context.Context, in *pb.Empty) (*pb.Height, error) {
@@ -80,11 +154,19 @@ func (s *server) ChainTip(ctx context.Context, in *pb.Empty) (*pb.Height, error)
 
 func (s *server) Balance(ctx context.Context, in *pb.Empty) (*pb.Balances, error) {
 	confirmed, unconfirmed := s.w.Balance()
-	return &pb.Balances{uint64(confirmed), uint64(unconfirmed)}, nil
+	locked, err := s.w.LockedBalance()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Balances{uint64(confirmed), uint64(unconfirmed), uint64(locked)}, nil
 }
 
 func (s *server) MasterPrivateKey(ctx context.Context, in *pb.Empty) (*pb.Key, error) {
-	return &pb.Key{s.w.MasterPrivateKey().String()}, nil
+	key, err := s.w.MasterPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Key{key.String()}, nil
 }
 
 func (s *server) MasterPublicKey(ctx context.Context, in *pb.Empty) (*pb.Key, error) {
@@ -136,6 +218,7 @@ func (s *server) Transactions(ctx context.Context, in *pb.Empty) (*pb.Transactio
 			WatchOnly: tx.WatchOnly,
 			Timestamp: ts,
 			Raw:       tx.Bytes,
+			Memo:      tx.Memo,
 		}
 		list = append(list, respTx)
 	}
@@ -162,10 +245,159 @@ func (s *server) GetTransaction(ctx context.Context, in *pb.Txid) (*pb.Tx, error
 		WatchOnly: tx.WatchOnly,
 		Timestamp: ts,
 		Raw:       tx.Bytes,
+		Memo:      tx.Memo,
 	}
 	return respTx, nil
 }
 
+func (s *server) GetTransactionDetail(ctx context.Context, in *pb.Txid) (*pb.TransactionDetail, error) {
+	ch, err := chainhash.NewHashFromStr(in.Hash)
+	if err != nil {
+		return nil, err
+	}
+	detail, err := s.w.GetTransactionDetail(*ch)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := ptypes.TimestampProto(detail.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.TransactionDetail{
+		Tx: &pb.Tx{
+			Txid:      detail.Txid,
+			Value:     detail.Value,
+			Height:    detail.Height,
+			Timestamp: ts,
+			Raw:       detail.Raw,
+			Memo:      detail.Memo,
+		},
+		Confirmations: detail.Confirmations,
+		Status:        string(detail.Status),
+		Fee:           detail.Fee,
+		FeeRate:       detail.FeeRate,
+		Size:          uint32(detail.Size),
+	}
+	for _, input := range detail.Inputs {
+		resp.Inputs = append(resp.Inputs, &pb.InputDetail{
+			Txid:    input.Outpoint.Hash.String(),
+			Index:   input.Outpoint.Index,
+			Address: input.Address,
+			Value:   input.Value,
+			Mine:    input.Mine,
+		})
+	}
+	for _, output := range detail.Outputs {
+		resp.Outputs = append(resp.Outputs, &pb.OutputDetail{
+			Index:   output.Index,
+			Address: output.Address,
+			Value:   output.Value,
+			Mine:    output.Mine,
+			Data:    output.Data,
+		})
+	}
+	return resp, nil
+}
+
+func (s *server) ExportHistory(ctx context.Context, in *pb.HistoryRequest) (*pb.HistoryExport, error) {
+	var from, to time.Time
+	var err error
+	if in.From != nil {
+		from, err = ptypes.Timestamp(in.From)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if in.To != nil {
+		to, err = ptypes.Timestamp(in.To)
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := s.w.ExportHistory(bitcoincash.HistoryFormat(in.Format), from, to, in.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.HistoryExport{Data: data}, nil
+}
+
+func (s *server) CreateInvoice(ctx context.Context, in *pb.InvoiceRequest) (*pb.Invoice, error) {
+	invoice, err := s.w.CreateInvoice(int64(in.Amount), in.FiatAmount, in.Currency, time.Duration(in.Expiry)*time.Second, in.Memo)
+	if err != nil {
+		return nil, err
+	}
+	return invoiceProto(invoice)
+}
+
+func (s *server) GetInvoice(ctx context.Context, in *pb.InvoiceID) (*pb.Invoice, error) {
+	invoice, err := s.w.GetInvoice(in.Id)
+	if err != nil {
+		return nil, err
+	}
+	return invoiceProto(invoice)
+}
+
+func (s *server) ListInvoices(ctx context.Context, in *pb.Empty) (*pb.InvoiceList, error) {
+	invoices, err := s.w.Invoices()
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.InvoiceList{}
+	for _, invoice := range invoices {
+		i, err := invoiceProto(invoice)
+		if err != nil {
+			return nil, err
+		}
+		resp.Invoices = append(resp.Invoices, i)
+	}
+	return resp, nil
+}
+
+var invoiceStatuses = map[bitcoincash.InvoiceStatus]pb.InvoiceStatus{
+	bitcoincash.InvoiceUnpaid:        pb.InvoiceStatus_UNPAID,
+	bitcoincash.InvoicePartiallyPaid: pb.InvoiceStatus_PARTIALLY_PAID,
+	bitcoincash.InvoicePaid:          pb.InvoiceStatus_PAID,
+	bitcoincash.InvoiceOverpaid:      pb.InvoiceStatus_OVERPAID,
+	bitcoincash.InvoiceExpired:       pb.InvoiceStatus_EXPIRED,
+	bitcoincash.InvoiceConfirmed:     pb.InvoiceStatus_CONFIRMED,
+}
+
+func invoiceProto(invoice bitcoincash.Invoice) (*pb.Invoice, error) {
+	created, err := ptypes.TimestampProto(invoice.Created)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.Invoice{
+		Id:           invoice.ID,
+		Address:      invoice.Address,
+		Uri:          invoice.URI,
+		Amount:       uint64(invoice.Amount),
+		FiatAmount:   invoice.FiatAmount,
+		Currency:     invoice.FiatCurrency,
+		ExchangeRate: invoice.ExchangeRate,
+		Memo:         invoice.Memo,
+		Created:      created,
+		Received:     uint64(invoice.Received),
+		Status:       invoiceStatuses[invoice.Status],
+	}
+	if !invoice.Expires.IsZero() {
+		resp.Expires, err = ptypes.TimestampProto(invoice.Expires)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range invoice.Payments {
+		resp.Payments = append(resp.Payments, &pb.InvoicePayment{
+			Txid:          p.Txid,
+			Index:         p.Index,
+			Value:         p.Value,
+			Height:        p.Height,
+			Confirmations: p.Confirmations,
+		})
+	}
+	return resp, nil
+}
+
 func (s *server) GetFeePerByte(ctx context.Context, in *pb.FeeLevelSelection) (*pb.FeePerByte, error) {
 	var feeLevel wallet.FeeLevel
 	switch in.FeeLevel {
@@ -182,10 +414,48 @@ func (s *server) GetFeePerByte(ctx context.Context, in *pb.FeeLevelSelection) (*
 }
 
 func (s *server) Spend(ctx context.Context, in *pb.SpendInfo) (*pb.Txid, error) {
-	params, err := s.Params(ctx, &pb.Empty{})
+	addr, feeLevel, err := s.parseSpendInfo(ctx, in)
 	if err != nil {
 		return nil, err
 	}
+	var txid *chainhash.Hash
+	if len(in.Inputs) > 0 || len(in.ExcludeInputs) > 0 || in.FeePerByte > 0 || in.Fee > 0 {
+		opts := bitcoincash.SpendOptions{FeePerByte: in.FeePerByte, Fee: int64(in.Fee), Memo: in.Memo}
+		opts.Inputs, err = parseOutpoints(in.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		opts.ExcludeInputs, err = parseOutpoints(in.ExcludeInputs)
+		if err != nil {
+			return nil, err
+		}
+		txid, err = s.w.SpendWithOptions(int64(in.Amount), addr, feeLevel, opts)
+	} else {
+		txid, err = s.w.Spend(int64(in.Amount), addr, feeLevel, in.Memo)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Txid{txid.String()}, nil
+}
+
+func parseOutpoints(inputs []*pb.Input) ([]wire.OutPoint, error) {
+	var ops []wire.OutPoint
+	for _, input := range inputs {
+		h, err := chainhash.NewHashFromStr(input.Txid)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, *wire.NewOutPoint(h, input.Index))
+	}
+	return ops, nil
+}
+
+func (s *server) parseSpendInfo(ctx context.Context, in *pb.SpendInfo) (bchutil.Address, wallet.FeeLevel, error) {
+	params, err := s.Params(ctx, &pb.Empty{})
+	if err != nil {
+		return nil, 0, err
+	}
 	var p chaincfg.Params
 	switch params.Name {
 	case chaincfg.TestNet3Params.Name:
@@ -195,24 +465,110 @@ func (s *server) Spend(ctx context.Context, in *pb.SpendInfo) (*pb.Txid, error)
 	case chaincfg.RegressionNetParams.Name:
 		p = chaincfg.RegressionNetParams
 	default:
-		return nil, errors.New("Unknown network parameters")
+		return nil, 0, errors.New("Unknown network parameters")
 	}
-	var feeLevel wallet.FeeLevel
-	switch in.FeeLevel {
+	feeLevel, err := parseFeeLevel(in.FeeLevel)
+	if err != nil {
+		return nil, 0, err
+	}
+	addr, err := bchutil.DecodeAddress(in.Address, &p)
+	if err != nil {
+		return nil, 0, err
+	}
+	return addr, feeLevel, nil
+}
+
+func parseFeeLevel(level pb.FeeLevel) (wallet.FeeLevel, error) {
+	switch level {
 	case pb.FeeLevel_ECONOMIC:
-		feeLevel = wallet.ECONOMIC
+		return wallet.ECONOMIC, nil
 	case pb.FeeLevel_NORMAL:
-		feeLevel = wallet.NORMAL
+		return wallet.NORMAL, nil
 	case pb.FeeLevel_PRIORITY:
-		feeLevel = wallet.PRIOIRTY
+		return wallet.PRIOIRTY, nil
 	default:
-		return nil, errors.New("Unknown fee level")
+		return 0, errors.New("Unknown fee level")
 	}
-	addr, err := bchutil.DecodeAddress(in.Address, &p)
+}
+
+func (s *server) SpendMany(ctx context.Context, in *pb.SpendManyInfo) (*pb.Txid, error) {
+	feeLevel, err := parseFeeLevel(in.FeeLevel)
+	if err != nil {
+		return nil, err
+	}
+	var outputs []wallet.TransactionOutput
+	for _, payment := range in.Payments {
+		addr, err := s.w.DecodeAddress(payment.Address)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: int64(payment.Amount)})
+	}
+	var opts bitcoincash.SpendOptions
+	opts.Inputs, err = parseOutpoints(in.Inputs)
+	if err != nil {
+		return nil, err
+	}
+	opts.ExcludeInputs, err = parseOutpoints(in.ExcludeInputs)
+	if err != nil {
+		return nil, err
+	}
+	txid, err := s.w.SpendMany(outputs, feeLevel, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Txid{txid.String()}, nil
+}
+
+func (s *server) PayURI(ctx context.Context, in *pb.URIPayment) (*pb.Txid, error) {
+	feeLevel, err := parseFeeLevel(in.FeeLevel)
+	if err != nil {
+		return nil, err
+	}
+	txid, err := s.w.PayURI(in.Uri, feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Txid{txid.String()}, nil
+}
+
+func (s *server) CreateUnsignedTransaction(ctx context.Context, in *pb.SpendInfo) (*pb.PartiallySignedTx, error) {
+	addr, feeLevel, err := s.parseSpendInfo(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	pst, err := s.w.CreateUnsignedTransaction(int64(in.Amount), addr, feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(pst, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PartiallySignedTx{data}, nil
+}
+
+func (s *server) SignTransaction(ctx context.Context, in *pb.PartiallySignedTx) (*pb.PartiallySignedTx, error) {
+	pst := new(bitcoincash.PartiallySignedTransaction)
+	if err := json.Unmarshal(in.Data, pst); err != nil {
+		return nil, err
+	}
+	if err := s.w.SignTransaction(pst); err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(pst, "", "    ")
 	if err != nil {
 		return nil, err
 	}
-	txid, err := s.w.Spend(int64(in.Amount), addr, feeLevel, strconv.Itoa(rand.Int()))
+	return &pb.PartiallySignedTx{data}, nil
+}
+
+func (s *server) FinalizeAndBroadcast(ctx context.Context, in *pb.PartiallySignedTx) (*pb.Txid, error) {
+	pst := new(bitcoincash.PartiallySignedTransaction)
+	if err := json.Unmarshal(in.Data, pst); err != nil {
+		return nil, err
+	}
+	txid, err := s.w.FinalizeAndBroadcast(pst)
 	if err != nil {
 		return nil, err
 	}
@@ -234,27 +590,34 @@ func (s *server) BumpFee(ctx context.Context, in *pb.Txid) (*pb.Txid, error) {
 func (s *server) Peers(ctx context.Context, in *pb.Empty) (*pb.PeerList, error) {
 	var peers []*pb.Peer
 	for _, peer := range s.w.ConnectedPeers() {
-		ts, err := ptypes.TimestampProto(peer.TimeConnected())
+		p, err := peerProto(peer)
 		if err != nil {
 			return nil, err
 		}
-		p := &pb.Peer{
-			Address:         peer.Addr(),
-			BytesSent:       peer.BytesSent(),
-			BytesReceived:   peer.BytesReceived(),
-			Connected:       peer.Connected(),
-			ID:              peer.ID(),
-			LastBlock:       peer.LastBlock(),
-			ProtocolVersion: peer.ProtocolVersion(),
-			Services:        peer.Services().String(),
-			UserAgent:       peer.UserAgent(),
-			TimeConnected:   ts,
-		}
 		peers = append(peers, p)
 	}
 	return &pb.PeerList{peers}, nil
 }
 
+func peerProto(p *peer.Peer) (*pb.Peer, error) {
+	ts, err := ptypes.TimestampProto(p.TimeConnected())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Peer{
+		Address:         p.Addr(),
+		BytesSent:       p.BytesSent(),
+		BytesReceived:   p.BytesReceived(),
+		Connected:       p.Connected(),
+		ID:              p.ID(),
+		LastBlock:       p.LastBlock(),
+		ProtocolVersion: p.ProtocolVersion(),
+		Services:        p.Services().String(),
+		UserAgent:       p.UserAgent(),
+		TimeConnected:   ts,
+	}, nil
+}
+
 func (s *server) AddWatchedAddress(ctx context.Context, in *pb.Address) (*pb.Empty, error) {
 	params, err := s.Params(ctx, &pb.Empty{})
 	if err != nil {
@@ -547,28 +910,106 @@ func (s *server) EstimateFee(ctx context.Context, in *pb.EstimateFeeData) (*pb.F
 }
 
 func (s *server) WalletNotify(in *pb.Empty, stream pb.API_WalletNotifyServer) error {
-	cb := func(tx wallet.TransactionCallback) {
-		ts, err := ptypes.TimestampProto(tx.Timestamp)
+	events, unsubscribe := s.w.SubscribeEvents()
+	defer unsubscribe()
+	for {
+		select {
+		case e := <-events:
+			if e.Type != bitcoincash.EventNewTransaction && e.Type != bitcoincash.EventConfirmation {
+				continue
+			}
+			tx, err := txCallbackProto(*e.Transaction)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(tx); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// SubscribeEvents streams the wallet's events until the client goes away.
+func (s *server) SubscribeEvents(in *pb.Empty, stream pb.API_SubscribeEventsServer) error {
+	events, unsubscribe := s.w.SubscribeEvents()
+	defer unsubscribe()
+	for {
+		select {
+		case e := <-events:
+			event, err := eventProto(e)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+var eventTypes = map[bitcoincash.EventType]pb.EventType{
+	bitcoincash.EventNewTransaction:   pb.EventType_NEW_TRANSACTION,
+	bitcoincash.EventConfirmation:     pb.EventType_CONFIRMATION,
+	bitcoincash.EventReorg:            pb.EventType_REORG,
+	bitcoincash.EventNewTip:           pb.EventType_NEW_TIP,
+	bitcoincash.EventBalance:          pb.EventType_BALANCE,
+	bitcoincash.EventPeerConnected:    pb.EventType_PEER_CONNECTED,
+	bitcoincash.EventPeerDisconnected: pb.EventType_PEER_DISCONNECTED,
+	bitcoincash.EventSyncProgress:     pb.EventType_SYNC_PROGRESS,
+}
+
+func eventProto(e bitcoincash.Event) (*pb.Event, error) {
+	ts, err := ptypes.TimestampProto(e.Time)
+	if err != nil {
+		return nil, err
+	}
+	event := &pb.Event{Type: eventTypes[e.Type], Timestamp: ts}
+	switch e.Type {
+	case bitcoincash.EventNewTransaction, bitcoincash.EventConfirmation:
+		if event.Tx, err = txCallbackProto(*e.Transaction); err != nil {
+			return nil, err
+		}
+	case bitcoincash.EventNewTip, bitcoincash.EventReorg:
+		blockTime, err := ptypes.TimestampProto(e.Block.Timestamp)
 		if err != nil {
-			return
+			return nil, err
 		}
-		resp := &pb.Tx{
-			Txid:      tx.Txid,
-			Value:     tx.Value,
-			Height:    tx.Height,
-			Timestamp: ts,
-			WatchOnly: tx.WatchOnly,
+		event.Block = &pb.Block{
+			Hash:      e.Block.Hash,
+			Height:    e.Block.Height,
+			Timestamp: blockTime,
+			PrevBlock: e.Block.PrevBlock,
 		}
-		if err := stream.Send(resp); err != nil {
-			return
+		event.ReorgHeight = e.ReorgHeight
+	case bitcoincash.EventBalance:
+		event.Balances = &pb.Balances{Confirmed: uint64(e.Confirmed), Unconfirmed: uint64(e.Unconfirmed)}
+	case bitcoincash.EventPeerConnected, bitcoincash.EventPeerDisconnected:
+		if event.Peer, err = peerProto(e.Peer); err != nil {
+			return nil, err
 		}
+		event.PeerCount = uint32(e.PeerCount)
+	case bitcoincash.EventSyncProgress:
+		event.Sync = &pb.SyncProgress{Height: e.Height, TargetHeight: e.TargetHeight, Synced: e.Synced}
 	}
-	s.w.AddTransactionListener(cb)
-	// Keep the connection open to continue streaming
-	var wg sync.WaitGroup
-	wg.Add(1)
-	wg.Wait()
-	return nil
+	return event, nil
+}
+
+func txCallbackProto(tx wallet.TransactionCallback) (*pb.Tx, error) {
+	ts, err := ptypes.TimestampProto(tx.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Tx{
+		Txid:      tx.Txid,
+		Value:     tx.Value,
+		Height:    tx.Height,
+		Timestamp: ts,
+		WatchOnly: tx.WatchOnly,
+	}, nil
 }
 
 type HeaderWriter struct {
@@ -622,17 +1063,103 @@ func (s *server) GetKey(ctx context.Context, in *pb.Address) (*pb.Key, error) {
 
 func (s *server) ListAddresses(ctx context.Context, in *pb.Empty) (*pb.Addresses, error) {
 	addrs := s.w.ListAddresses()
+	labels := s.w.AddressLabels()
 	var list []*pb.Address
 	for _, addr := range addrs {
 		ret := new(pb.Address)
 		ret.Addr = addr.String()
+		ret.Label = labels[addr.String()]
 		list = append(list, ret)
 	}
 	return &pb.Addresses{list}, nil
 }
 
+func (s *server) SetAddressLabel(ctx context.Context, in *pb.AddressLabel) (*pb.Empty, error) {
+	addr, err := s.w.DecodeAddress(in.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.w.SetAddressLabel(addr, in.Label); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) SetTxMemo(ctx context.Context, in *pb.TxMemo) (*pb.Empty, error) {
+	txid, err := chainhash.NewHashFromStr(in.Txid)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.w.SetTxMemo(*txid, in.Memo); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) ListUnspent(ctx context.Context, in *pb.Empty) (*pb.UnspentList, error) {
+	utxos, err := s.w.ListUnspent()
+	if err != nil {
+		return nil, err
+	}
+	return unspentList(utxos), nil
+}
+
+func (s *server) LockUnspent(ctx context.Context, in *pb.Input) (*pb.Empty, error) {
+	ops, err := parseOutpoints([]*pb.Input{in})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.w.LockUnspent(ops[0]); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) UnlockUnspent(ctx context.Context, in *pb.Input) (*pb.Empty, error) {
+	ops, err := parseOutpoints([]*pb.Input{in})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.w.UnlockUnspent(ops[0]); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) ListLockedUnspent(ctx context.Context, in *pb.Empty) (*pb.UnspentList, error) {
+	utxos, err := s.w.ListLockedUnspent()
+	if err != nil {
+		return nil, err
+	}
+	return unspentList(utxos), nil
+}
+
+func unspentList(utxos []bitcoincash.UnspentOutput) *pb.UnspentList {
+	var list []*pb.Unspent
+	for _, u := range utxos {
+		ret := &pb.Unspent{
+			Txid:          u.Op.Hash.String(),
+			Index:         u.Op.Index,
+			Value:         uint64(u.Value),
+			Confirmations: u.Confirmations,
+			Account:       u.Account,
+			WatchOnly:     u.WatchOnly,
+			Locked:        u.Locked,
+			Label:         u.Label,
+		}
+		if u.Address != nil {
+			ret.Address = u.Address.String()
+		}
+		list = append(list, ret)
+	}
+	return &pb.UnspentList{list}
+}
+
 func (s *server) ListKeys(ctx context.Context, in *pb.Empty) (*pb.Keys, error) {
-	keys := s.w.ListKeys()
+	keys, err := s.w.ListKeys()
+	if err != nil {
+		return nil, err
+	}
 	var list []*pb.Key
 	for _, key := range keys {
 		ret := new(pb.Key)
@@ -674,3 +1201,31 @@ func (s *server) ImportKey(ctx context.Context, in *pb.ImportedKey) (*pb.Empty,
 	s.w.ReSyncBlockchain(t)
 	return &pb.Empty{}, nil
 }
+
+func (s *server) EncryptWallet(ctx context.Context, in *pb.Passphrase) (*pb.Empty, error) {
+	if err := s.w.EncryptWallet(in.Passphrase); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) Unlock(ctx context.Context, in *pb.Passphrase) (*pb.Empty, error) {
+	if err := s.w.Unlock(in.Passphrase); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) Lock(ctx context.Context, in *pb.Empty) (*pb.Empty, error) {
+	if err := s.w.Lock(); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
+
+func (s *server) ChangePassphrase(ctx context.Context, in *pb.PassphraseChange) (*pb.Empty, error) {
+	if err := s.w.ChangePassphrase(in.OldPassphrase, in.NewPassphrase); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
+}
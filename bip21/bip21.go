@@ -0,0 +1,177 @@
+// Package bip21 parses and builds bitcoincash: payment URIs as described in
+// BIP21, with the r= parameter of BIP72 and the op_return_raw extension used by
+// Bitcoin Cash wallets.
+package bip21
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchutil"
+)
+
+// ErrInvalidScheme is returned for a URI which isn't for the network's cash
+// address prefix, such as bitcoincash: on mainnet.
+var ErrInvalidScheme = errors.New("URI scheme is not for this network")
+
+// URI is a request for payment.
+type URI struct {
+	// Address is nil if the URI only has a PaymentURL.
+	Address bchutil.Address
+
+	// Amount in satoshi, zero if the payer chooses it.
+	Amount int64
+
+	// Label of the recipient and message describing the payment.
+	Label   string
+	Message string
+
+	// OpReturnRaw is the script following OP_RETURN of a data output the
+	// payment should include.
+	OpReturnRaw []byte
+
+	// PaymentURL is the r= parameter, the URL of a payment protocol request
+	// which replaces the other parameters.
+	PaymentURL string
+}
+
+// Parse decodes a payment URI for the network of params. Unknown parameters
+// are ignored except those starting with req- which the payer is required to
+// understand. The address may only be left out if there is an r= parameter.
+func Parse(uri string, params *chaincfg.Params) (*URI, error) {
+	parts := strings.SplitN(uri, ":", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], params.CashAddressPrefix) {
+		return nil, ErrInvalidScheme
+	}
+	rest := parts[1]
+	query := ""
+	if i := strings.Index(rest, "?"); i >= 0 {
+		rest, query = rest[:i], rest[i+1:]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := new(URI)
+	if rest != "" || values.Get("r") == "" {
+		ret.Address, err = bchutil.DecodeAddress(rest, params)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address %s: %s", rest, err)
+		}
+		if !ret.Address.IsForNet(params) {
+			return nil, fmt.Errorf("Address %s is not for %s", rest, params.Name)
+		}
+	}
+	for key, value := range values {
+		if len(value) != 1 {
+			return nil, fmt.Errorf("Parameter %s is given more than once", key)
+		}
+		switch key {
+		case "amount":
+			ret.Amount, err = parseAmount(value[0])
+			if err != nil {
+				return nil, err
+			}
+		case "label":
+			ret.Label = value[0]
+		case "message":
+			ret.Message = value[0]
+		case "op_return_raw":
+			ret.OpReturnRaw, err = hex.DecodeString(value[0])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid op_return_raw: %s", err)
+			}
+		case "r":
+			ret.PaymentURL = value[0]
+		default:
+			if strings.HasPrefix(key, "req-") {
+				return nil, fmt.Errorf("Unsupported required parameter %s", key)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// String encodes the URI. The scheme is the cash address prefix of the
+// network of the address. A URI with only a payment URL has no address to
+// tell the network from and uses the mainnet prefix.
+func (u *URI) String() string {
+	prefix := chaincfg.MainNetParams.CashAddressPrefix
+	ret := prefix + ":"
+	if u.Address != nil {
+		for _, p := range []*chaincfg.Params{&chaincfg.MainNetParams, &chaincfg.TestNet3Params, &chaincfg.RegressionNetParams} {
+			if u.Address.IsForNet(p) {
+				prefix = p.CashAddressPrefix
+				break
+			}
+		}
+		ret = prefix + ":" + strings.TrimPrefix(u.Address.EncodeAddress(), prefix+":")
+	}
+
+	var query []string
+	if u.Amount > 0 {
+		query = append(query, "amount="+formatAmount(u.Amount))
+	}
+	if u.Label != "" {
+		query = append(query, "label="+escape(u.Label))
+	}
+	if u.Message != "" {
+		query = append(query, "message="+escape(u.Message))
+	}
+	if len(u.OpReturnRaw) > 0 {
+		query = append(query, "op_return_raw="+hex.EncodeToString(u.OpReturnRaw))
+	}
+	if u.PaymentURL != "" {
+		query = append(query, "r="+escape(u.PaymentURL))
+	}
+	if len(query) > 0 {
+		ret += "?" + strings.Join(query, "&")
+	}
+	return ret
+}
+
+// parseAmount converts a decimal BCH amount to satoshi without rounding.
+func parseAmount(s string) (int64, error) {
+	whole, frac := s, ""
+	if i := strings.Index(s, "."); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if whole == "" && frac == "" || len(frac) > 8 || strings.ContainsAny(whole+frac, "+-") {
+		return 0, fmt.Errorf("Invalid amount %s", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	frac += strings.Repeat("0", 8-len(frac))
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid amount %s", s)
+	}
+	f, err := strconv.ParseInt(frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid amount %s", s)
+	}
+	amount := w*bchutil.SatoshiPerBitcoin + f
+	if w > bchutil.MaxSatoshi/bchutil.SatoshiPerBitcoin || amount > bchutil.MaxSatoshi {
+		return 0, fmt.Errorf("Amount %s is too large", s)
+	}
+	return amount, nil
+}
+
+// formatAmount formats satoshi as a decimal BCH amount.
+func formatAmount(amount int64) string {
+	s := fmt.Sprintf("%d.%08d", amount/bchutil.SatoshiPerBitcoin, amount%bchutil.SatoshiPerBitcoin)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// escape percent-encodes a parameter value. Spaces are encoded as %20 as
+// BIP21 doesn't treat + as a space.
+func escape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
@@ -0,0 +1,147 @@
+package bip21
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gcash/bchd/chaincfg"
+)
+
+const testAddress = "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"
+
+func TestParse(t *testing.T) {
+	uri, err := Parse("bitcoincash:"+testAddress+"?amount=1.5&label=Shop&message=Order%2042&op_return_raw=6a04&foo=bar", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Address.String() != testAddress {
+		t.Errorf("Parsed address %s, expected %s", uri.Address, testAddress)
+	}
+	if uri.Amount != 150000000 {
+		t.Errorf("Parsed amount %d, expected 150000000", uri.Amount)
+	}
+	if uri.Label != "Shop" || uri.Message != "Order 42" {
+		t.Error("Parsed the wrong label or message")
+	}
+	if !bytes.Equal(uri.OpReturnRaw, []byte{0x6a, 0x04}) {
+		t.Error("Parsed the wrong op_return_raw")
+	}
+
+	// The scheme is case insensitive and parameters are optional
+	uri, err = Parse("BITCOINCASH:"+testAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Amount != 0 {
+		t.Error("Parsed an amount which wasn't given")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		testAddress,
+		"bchtest:" + testAddress,
+		"bitcoin:" + testAddress,
+		"bitcoincash:notanaddress",
+		"bitcoincash:" + testAddress + "?amount=0.000000001",
+		"bitcoincash:" + testAddress + "?amount=-1",
+		"bitcoincash:" + testAddress + "?amount=1e3",
+		"bitcoincash:" + testAddress + "?amount=.",
+		"bitcoincash:" + testAddress + "?amount=22000000",
+		"bitcoincash:" + testAddress + "?amount=1&amount=2",
+		"bitcoincash:" + testAddress + "?op_return_raw=xyz",
+		"bitcoincash:" + testAddress + "?req-somethingnew=1",
+		"bitcoincash:?label=nothing",
+	}
+	for _, s := range tests {
+		if _, err := Parse(s, &chaincfg.MainNetParams); err == nil {
+			t.Errorf("Parsed invalid URI %s", s)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := map[string]int64{
+		"1":          100000000,
+		"0.1":        10000000,
+		".5":         50000000,
+		"20.":        2000000000,
+		"0.00000001": 1,
+		"21000000":   2100000000000000,
+	}
+	for s, expected := range tests {
+		amount, err := parseAmount(s)
+		if err != nil {
+			t.Errorf("Failed to parse %s: %s", s, err)
+			continue
+		}
+		if amount != expected {
+			t.Errorf("Parsed %s as %d, expected %d", s, amount, expected)
+		}
+	}
+}
+
+func TestURI_String(t *testing.T) {
+	uri, err := Parse("bitcoincash:"+testAddress, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.String() != "bitcoincash:"+testAddress {
+		t.Errorf("Encoded %s", uri)
+	}
+
+	uri.Amount = 123450000
+	uri.Label = "Coffee & cake"
+	uri.Message = "Table 4"
+	uri.OpReturnRaw = []byte{0x01, 0x02}
+	expected := "bitcoincash:" + testAddress + "?amount=1.2345&label=Coffee%20%26%20cake&message=Table%204&op_return_raw=0102"
+	if uri.String() != expected {
+		t.Errorf("Encoded %s, expected %s", uri, expected)
+	}
+
+	// The encoding round trips
+	parsed, err := Parse(uri.String(), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Amount != uri.Amount || parsed.Label != uri.Label || parsed.Message != uri.Message || !bytes.Equal(parsed.OpReturnRaw, uri.OpReturnRaw) {
+		t.Error("Encoding didn't round trip")
+	}
+}
+
+func TestParsePaymentURL(t *testing.T) {
+	uri, err := Parse("bitcoincash:?r=https%3A%2F%2Fmerchant.example%2Fi%2F1", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Address != nil || uri.PaymentURL != "https://merchant.example/i/1" {
+		t.Error("Parsed the wrong payment URL")
+	}
+	uri, err = Parse("bitcoincash:"+testAddress+"?amount=1&r=https://merchant.example/i/1", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Address == nil || uri.PaymentURL != "https://merchant.example/i/1" {
+		t.Error("Parsed the wrong address or payment URL")
+	}
+	if _, err := Parse("bitcoincash:?amount=1", &chaincfg.MainNetParams); err == nil {
+		t.Error("Parsed a URI without an address or payment URL")
+	}
+}
+
+func TestURI_StringPaymentURL(t *testing.T) {
+	uri := &URI{PaymentURL: "https://merchant.example/i/1"}
+	expected := "bitcoincash:?r=https%3A%2F%2Fmerchant.example%2Fi%2F1"
+	if uri.String() != expected {
+		t.Errorf("Encoded %s, expected %s", uri, expected)
+	}
+
+	// The encoding round trips
+	parsed, err := Parse(uri.String(), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Address != nil || parsed.PaymentURL != uri.PaymentURL {
+		t.Error("Encoding didn't round trip")
+	}
+}
@@ -179,14 +179,24 @@ func (b *Blockchain) CheckHeader(header wire.BlockHeader, prevHeader StoredHeade
 	return true // it must have worked if there's no errors and got to the end.
 }
 
-// Get the PoW target this block should meet. We may need to handle a difficulty adjustment
-// or testnet difficulty rules.
+// Get the PoW target this block should meet. The algorithm is selected by height: aserti3-2d
+// after the November 2020 anchor, cw-144 after the November 2017 fork and the emergency
+// difficulty adjustment (or the original retarget) before that. We also handle testnet
+// difficulty rules.
 func (b *Blockchain) calcRequiredWork(header wire.BlockHeader, height int32, prevHeader StoredHeader) (uint32, error) {
 	// Special difficulty rule for testnet
-	if b.params.ReduceMinDifficulty && header.Timestamp.After(prevHeader.header.Timestamp.Add(targetSpacing*2)) {
+	if b.params.ReduceMinDifficulty && header.Timestamp.After(prevHeader.header.Timestamp.Add(targetSpacing*2*time.Second)) {
 		return b.params.PowLimitBits, nil
 	}
 
+	rules := getDifficultyRules(b.params)
+	if rules.Asert != nil && uint32(height) > rules.Asert.Height {
+		return calcASERT(prevHeader, *rules.Asert, b.params), nil
+	}
+	if rules.DaaHeight != 0 && uint32(height) <= rules.DaaHeight {
+		return b.calcEDA(height, prevHeader)
+	}
+
 	suitableHeader, err := b.GetSuitableBlock(prevHeader)
 	if err != nil {
 		log.Error(err)
@@ -436,6 +446,35 @@ func (b *Blockchain) RollbackToHeight(rollbackHeight uint32) error {
 	return b.db.Put(sh, true)
 }
 
+// GetHeadersAfter returns up to max headers on the best chain following the given
+// height in ascending order.
+func (b *Blockchain) GetHeadersAfter(height uint32, max int) ([]StoredHeader, error) {
+	sh, err := b.db.GetBestHeader()
+	if err != nil {
+		return nil, err
+	}
+	if sh.height <= height {
+		return nil, nil
+	}
+	for sh.height > height+uint32(max) {
+		sh, err = b.db.GetPreviousHeader(sh.header)
+		if err != nil {
+			return nil, err
+		}
+	}
+	headers := make([]StoredHeader, sh.height-height)
+	for i := len(headers) - 1; i >= 0; i-- {
+		headers[i] = sh
+		if i > 0 {
+			sh, err = b.db.GetPreviousHeader(sh.header)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return headers, nil
+}
+
 func (b *Blockchain) BestBlock() (StoredHeader, error) {
 	sh, err := b.db.GetBestHeader()
 	if err != nil {
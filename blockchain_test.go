@@ -591,14 +591,14 @@ func TestBlockchain_calcRequiredWork(t *testing.T) {
 	// Test during difficulty adjust period
 	newHdr := wire.BlockHeader{}
 	newHdr.PrevBlock = best.header.BlockHash()
-	work, err := bc.calcRequiredWork(newHdr, 2016, best)
+	work, err := bc.calcRequiredWork(newHdr, int32(best.height+1), best)
 	if err != nil {
 		t.Error(err)
 	}
 	newHdr.Bits = work
 	sh := StoredHeader{
 		header:    newHdr,
-		height:    2016,
+		height:    best.height + 1,
 		totalWork: blockchain.CompactToBig(work),
 	}
 	bc.db.Put(sh, true)
@@ -607,7 +607,7 @@ func TestBlockchain_calcRequiredWork(t *testing.T) {
 	params.ReduceMinDifficulty = false
 	newHdr1 := wire.BlockHeader{}
 	newHdr1.PrevBlock = newHdr.BlockHash()
-	work1, err := bc.calcRequiredWork(newHdr1, 2017, sh)
+	work1, err := bc.calcRequiredWork(newHdr1, int32(sh.height+1), sh)
 	if err != nil {
 		t.Error(err)
 	}
@@ -617,7 +617,7 @@ func TestBlockchain_calcRequiredWork(t *testing.T) {
 	newHdr1.Bits = work1
 	sh = StoredHeader{
 		header:    newHdr1,
-		height:    2017,
+		height:    best.height + 2,
 		totalWork: blockchain.CompactToBig(work1),
 	}
 	bc.db.Put(sh, true)
@@ -626,8 +626,8 @@ func TestBlockchain_calcRequiredWork(t *testing.T) {
 	params.ReduceMinDifficulty = true
 	newHdr2 := wire.BlockHeader{}
 	newHdr2.PrevBlock = newHdr1.BlockHash()
-	newHdr2.Timestamp = sh.header.Timestamp.Add(targetSpacing * 3)
-	work2, err := bc.calcRequiredWork(newHdr2, 2018, sh)
+	newHdr2.Timestamp = sh.header.Timestamp.Add(targetSpacing * 3 * time.Second)
+	work2, err := bc.calcRequiredWork(newHdr2, int32(sh.height+1), sh)
 	if err != nil {
 		t.Error(err)
 	}
@@ -637,7 +637,7 @@ func TestBlockchain_calcRequiredWork(t *testing.T) {
 	newHdr2.Bits = work2
 	sh = StoredHeader{
 		header:    newHdr2,
-		height:    2018,
+		height:    best.height + 3,
 		totalWork: blockchain.CompactToBig(work2),
 	}
 	bc.db.Put(sh, true)
@@ -683,3 +683,50 @@ func TestBlockchain_Rollback(t *testing.T) {
 	}
 	os.RemoveAll("headers.bin")
 }
+
+func TestBlockchain_GetHeadersAfter(t *testing.T) {
+	bc, err := NewBlockchain("", MockCreationTime, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("headers.bin")
+	parent := StoredHeader{
+		header:    wire.BlockHeader{Timestamp: time.Unix(1500000000, 0)},
+		height:    100,
+		totalWork: big.NewInt(0),
+	}
+	_, err = putHeaderChain(bc, parent, 10, 0x207fffff, func(i int) time.Time {
+		return time.Unix(1500000000+int64(i)*600, 0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		height   uint32
+		max      int
+		expected []uint32
+	}{
+		{105, 3, []uint32{106, 107, 108}},
+		{107, 10, []uint32{108, 109, 110}},
+		{110, 10, nil},
+	}
+	for _, test := range tests {
+		headers, err := bc.GetHeadersAfter(test.height, test.max)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(headers) != len(test.expected) {
+			t.Errorf("After height %d: expected %d headers, got %d", test.height, len(test.expected), len(headers))
+			continue
+		}
+		for i, sh := range headers {
+			if sh.height != test.expected[i] {
+				t.Errorf("After height %d: expected height %d, got %d", test.height, test.expected[i], sh.height)
+			}
+			if i > 0 && sh.header.PrevBlock != headers[i-1].header.BlockHash() {
+				t.Errorf("After height %d: headers do not connect", test.height)
+			}
+		}
+	}
+}
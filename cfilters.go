@@ -0,0 +1,782 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	peerpkg "github.com/gcash/bchd/peer"
+	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil/gcs"
+	"github.com/gcash/bchutil/gcs/builder"
+)
+
+// SyncMode selects how the wallet learns of transactions in new blocks.
+type SyncMode int
+
+const (
+	// BloomFilterSync loads a bloom filter into each peer and downloads merkle
+	// blocks. This is the default.
+	BloomFilterSync SyncMode = iota
+
+	// CompactFilterSync downloads BIP157/158 compact block filters, matches them
+	// locally and only downloads the full blocks which match. Peers learn nothing
+	// about our addresses but unconfirmed transactions are not detected.
+	CompactFilterSync
+)
+
+// How long peers have to answer a request for filter headers before the
+// headers are checked without them and the peers which didn't answer are banned.
+var cfHeadersTimeout = time.Second * 30
+
+// cfheadersMsg packages a cfheaders message and the peer it came from together
+// so the handler has access to that information.
+type cfheadersMsg struct {
+	cfheaders *wire.MsgCFHeaders
+	peer      *peerpkg.Peer
+}
+
+// cfheadersTimeoutMsg signifies the deadline for a request for filter headers
+// has passed.
+type cfheadersTimeoutMsg struct {
+	request uint64
+}
+
+// cfilterMsg packages a cfilter message and the peer it came from together
+// so the handler has access to that information.
+type cfilterMsg struct {
+	cfilter *wire.MsgCFilter
+	peer    *peerpkg.Peer
+}
+
+// blockMsg packages a block message and the peer it came from together
+// so the handler has access to that information.
+type blockMsg struct {
+	block *wire.MsgBlock
+	peer  *peerpkg.Peer
+}
+
+// cfSyncState tracks the compact filter requests in flight. Only one batch of
+// filter headers, one batch of filters or one block is requested at a time.
+type cfSyncState struct {
+	// The blocks whose filter headers were requested, the peers they were
+	// requested from and the responses received so far. The request number
+	// identifies the request a timeout is for.
+	headerRequest   uint64
+	headerBatch     []StoredHeader
+	headerPeers     map[*peerpkg.Peer]struct{}
+	headerResponses map[*peerpkg.Peer]*wire.MsgCFHeaders
+
+	// The blocks whose filters were requested but not yet matched, the peer
+	// serving them and the filters received so far.
+	filterBatch []StoredHeader
+	filterPeer  *peerpkg.Peer
+	filters     map[chainhash.Hash]*gcs.Filter
+
+	// The last block whose filter was matched but not yet saved as the filter tip.
+	scanned *chainhash.Hash
+
+	// A matched block we are waiting for.
+	pendingBlock *StoredHeader
+
+	// The scripts and outpoints we are looking for. Nil when it needs rebuilding.
+	watchList *cfWatchList
+}
+
+func newCFSyncState() *cfSyncState {
+	return &cfSyncState{}
+}
+
+// cfWatchList holds the data we look for in compact filters and in the blocks
+// they match.
+type cfWatchList struct {
+	// Entries matched against compact filters
+	entries [][]byte
+
+	// Data which marks an output as ours if it appears in its script
+	patterns [][]byte
+
+	// Outpoints which mark an input as ours
+	outpoints map[wire.OutPoint]struct{}
+}
+
+func newCFWatchList() *cfWatchList {
+	return &cfWatchList{outpoints: make(map[wire.OutPoint]struct{})}
+}
+
+func (wl *cfWatchList) addPattern(data []byte) {
+	wl.entries = append(wl.entries, data)
+	wl.patterns = append(wl.patterns, data)
+}
+
+func (wl *cfWatchList) addOutPoint(op wire.OutPoint) {
+	if _, ok := wl.outpoints[op]; ok {
+		return
+	}
+	wl.outpoints[op] = struct{}{}
+	var b [chainhash.HashSize + 4]byte
+	copy(b[:], op.Hash[:])
+	binary.LittleEndian.PutUint32(b[chainhash.HashSize:], op.Index)
+	wl.entries = append(wl.entries, b[:])
+}
+
+// relevant reports whether the transaction spends or pays to anything in the
+// watch list. The outputs of relevant transactions are added to the watch list
+// so that transactions spending them later in the same block are found too.
+func (wl *cfWatchList) relevant(tx *wire.MsgTx) bool {
+	found := false
+	for _, in := range tx.TxIn {
+		if _, ok := wl.outpoints[in.PreviousOutPoint]; ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+	outputs:
+		for _, out := range tx.TxOut {
+			for _, pattern := range wl.patterns {
+				if bytes.Contains(out.PkScript, pattern) {
+					found = true
+					break outputs
+				}
+			}
+		}
+	}
+	if found {
+		txid := tx.TxHash()
+		for i := range tx.TxOut {
+			wl.addOutPoint(*wire.NewOutPoint(&txid, uint32(i)))
+		}
+	}
+	return found
+}
+
+// matchFilter reports whether the compact filter for the block matches any of
+// the entries.
+func matchFilter(filter *gcs.Filter, blockHash chainhash.Hash, entries [][]byte) (bool, error) {
+	if len(entries) == 0 {
+		return false, nil
+	}
+	return filter.MatchAny(builder.DeriveKey(&blockHash), entries)
+}
+
+// makeFilterHeader returns the filter header committing to a filter with the
+// given hash on top of the previous filter header.
+func makeFilterHeader(filterHash, prevHeader chainhash.Hash) chainhash.Hash {
+	var b [chainhash.HashSize * 2]byte
+	copy(b[:chainhash.HashSize], filterHash[:])
+	copy(b[chainhash.HashSize:], prevHeader[:])
+	return chainhash.DoubleHashH(b[:])
+}
+
+// filterHeaderChain returns the filter headers committed to by a cfheaders
+// message which is expected to cover count blocks.
+func filterHeaderChain(msg *wire.MsgCFHeaders, count int) ([]chainhash.Hash, error) {
+	if msg.FilterType != wire.GCSFilterRegular {
+		return nil, fmt.Errorf("unexpected filter type %d", msg.FilterType)
+	}
+	if len(msg.FilterHashes) != count {
+		return nil, fmt.Errorf("expected %d filter hashes, got %d", count, len(msg.FilterHashes))
+	}
+	headers := make([]chainhash.Hash, count)
+	prev := msg.PrevFilterHeader
+	for i, filterHash := range msg.FilterHashes {
+		headers[i] = makeFilterHeader(*filterHash, prev)
+		prev = headers[i]
+	}
+	return headers, nil
+}
+
+// selectFilterHeaderChain picks the filter header chain returned by a strict
+// majority of peers. The peers which returned a different chain are returned
+// so they can be disconnected. If there is no majority every peer is returned.
+func selectFilterHeaderChain(chains map[*peerpkg.Peer][]chainhash.Hash) ([]chainhash.Hash, []*peerpkg.Peer, error) {
+	// The last header commits to every header before it so it identifies the chain.
+	votes := make(map[chainhash.Hash][]*peerpkg.Peer)
+	var best chainhash.Hash
+	for peer, headers := range chains {
+		if len(headers) == 0 {
+			return nil, nil, errors.New("empty filter header chain")
+		}
+		last := headers[len(headers)-1]
+		votes[last] = append(votes[last], peer)
+		if len(votes[last]) > len(votes[best]) {
+			best = last
+		}
+	}
+	var dissenters []*peerpkg.Peer
+	if len(votes[best])*2 <= len(chains) {
+		for peer := range chains {
+			dissenters = append(dissenters, peer)
+		}
+		return nil, dissenters, errors.New("peers disagree on the filter header chain")
+	}
+	for last, peers := range votes {
+		if last != best {
+			dissenters = append(dissenters, peers...)
+		}
+	}
+	return chains[votes[best][0]], dissenters, nil
+}
+
+// checkBlockMerkleRoot returns an error if the transactions in the block do not
+// hash to the merkle root in its header.
+func checkBlockMerkleRoot(block *wire.MsgBlock) error {
+	if len(block.Transactions) == 0 {
+		return errors.New("block has no transactions")
+	}
+	level := make([]*chainhash.Hash, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txid := tx.TxHash()
+		level[i] = &txid
+	}
+	for len(level) > 1 {
+		var next []*chainhash.Hash
+		for i := 0; i < len(level); i += 2 {
+			var right *chainhash.Hash
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			parent, err := MakeMerkleParent(level[i], right)
+			if err != nil {
+				return err
+			}
+			next = append(next, parent)
+		}
+		level = next
+	}
+	if !level[0].IsEqual(&block.Header.MerkleRoot) {
+		return errors.New("merkle root does not match block header")
+	}
+	return nil
+}
+
+// sortTransactionsForIngest orders the block's transactions so that each one
+// comes after any transaction in the block it spends from. Blocks are sorted by
+// txid so a child can come before its parent.
+func sortTransactionsForIngest(txs []*wire.MsgTx) []*wire.MsgTx {
+	index := make(map[chainhash.Hash]int, len(txs))
+	for i, tx := range txs {
+		index[tx.TxHash()] = i
+	}
+	visited := make([]bool, len(txs))
+	sorted := make([]*wire.MsgTx, 0, len(txs))
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		for _, in := range txs[i].TxIn {
+			if parent, ok := index[in.PreviousOutPoint.Hash]; ok {
+				visit(parent)
+			}
+		}
+		sorted = append(sorted, txs[i])
+	}
+	for i := range txs {
+		visit(i)
+	}
+	return sorted
+}
+
+func supportsCompactFilters(peer *peerpkg.Peer) bool {
+	return peer.Services()&wire.SFNodeCF == wire.SFNodeCF
+}
+
+// handleHeadersMsgCF handles headers messages in compact filter mode. Every
+// header is committed and once we are caught up with the sync peer the filter
+// headers and filters for the new blocks are fetched.
+func (ws *WireService) handleHeadersMsgCF(hmsg *headersMsg) {
+	peer := hmsg.peer
+	if _, exists := ws.peerStates[peer]; !exists {
+		log.Warningf("Received headers message from unknown peer %s", peer)
+		peer.Disconnect()
+		return
+	}
+	// While syncing only the sync peer is followed. Once current we accept
+	// headers from anyone announcing a new block.
+	if peer != ws.syncPeer && !ws.Current() {
+		log.Debugf("Ignoring headers from %s while syncing", peer)
+		return
+	}
+
+	msg := hmsg.headers
+	cutoff := ws.walletCreationDate.Add(-time.Hour * 24 * 7)
+	chainTip := len(msg.Headers) < wire.MaxBlockHeadersPerMsg
+	for _, blockHeader := range msg.Headers {
+		newTip, reorg, height, err := ws.chain.CommitHeader(*blockHeader)
+		if err != nil {
+			log.Warningf("Disconnecting from peer %s: %s", peer, err)
+			peer.Disconnect()
+			return
+		}
+		if reorg != nil {
+			ws.handleReorgCF(reorg)
+			tip := blockCallback(*blockHeader, height, true)
+			ws.events.publish(Event{Type: EventReorg, Block: &tip, ReorgHeight: reorg.height})
+		}
+		if newTip && !blockHeader.Timestamp.Before(cutoff) {
+			ws.notifyBlockListeners(*blockHeader, height, chainTip)
+		}
+	}
+	ws.publishSyncProgress()
+
+	if !chainTip {
+		locator := ws.chain.GetBlockLocator()
+		if err := peer.PushGetHeadersMsg(locator, &ws.zeroHash); err != nil {
+			log.Warningf("Failed to send getheaders message to peer %s: %v", peer.Addr(), err)
+		}
+		return
+	}
+	if best, err := ws.chain.BestBlock(); err == nil && ws.Current() {
+		peer.UpdateLastBlockHeight(int32(best.height))
+	}
+	ws.cfSyncNext()
+}
+
+// handleReorgCF rolls our transactions and filter progress back to the common
+// ancestor of the old and new chain. The blocks of the new chain are then
+// matched as usual.
+func (ws *WireService) handleReorgCF(ancestor *StoredHeader) {
+	if err := ws.txStore.processReorg(ancestor.height); err != nil {
+		log.Error(err)
+	}
+	ws.flushFilterTip()
+	ancestorHash := ancestor.header.BlockHash()
+	tips := []struct {
+		get func() (chainhash.Hash, error)
+		put func(chainhash.Hash) error
+	}{
+		{ws.chain.db.GetFilterHeaderTip, ws.chain.db.PutFilterHeaderTip},
+		{ws.chain.db.GetFilterTip, ws.chain.db.PutFilterTip},
+	}
+	for _, tip := range tips {
+		hash, err := tip.get()
+		if err != nil {
+			continue
+		}
+		sh, err := ws.chain.GetHeader(&hash)
+		if err != nil || sh.height <= ancestor.height {
+			continue
+		}
+		if err := tip.put(ancestorHash); err != nil {
+			log.Error(err)
+		}
+	}
+	// Any requests in flight are for blocks which may no longer be in the best
+	// chain. Their responses will be ignored.
+	watchList := ws.cf.watchList
+	ws.cf = newCFSyncState()
+	ws.cf.watchList = watchList
+}
+
+// cfSyncNext requests the next batch of filter headers or filters if nothing is
+// in flight. Filter headers are synced to the chain tip before any filters.
+func (ws *WireService) cfSyncNext() {
+	if ws.cf.headerBatch != nil || ws.cf.filterBatch != nil || ws.cf.pendingBlock != nil {
+		return
+	}
+	headerTip, err := ws.filterTipHeight(ws.chain.db.GetFilterHeaderTip)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	batch, err := ws.chain.GetHeadersAfter(headerTip, wire.MaxCFHeadersPerMsg)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if len(batch) > 0 {
+		ws.requestFilterHeaders(batch)
+		return
+	}
+
+	filterTip, err := ws.filterTipHeight(ws.chain.db.GetFilterTip)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	batch, err = ws.chain.GetHeadersAfter(filterTip, wire.MaxGetCFiltersReqRange)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if len(batch) > 0 {
+		ws.requestFilters(batch)
+	}
+}
+
+// filterTipHeight returns the height of the block stored under a filter tip. If
+// the tip is not set, or its header was rolled back, both filter tips are reset
+// to the last block before the wallet creation date (minus a one week buffer).
+func (ws *WireService) filterTipHeight(getTip func() (chainhash.Hash, error)) (uint32, error) {
+	if hash, err := getTip(); err == nil {
+		if sh, err := ws.chain.GetHeader(&hash); err == nil {
+			return sh.height, nil
+		}
+	}
+	start, err := ws.chain.BestBlock()
+	if err != nil {
+		return 0, err
+	}
+	cutoff := ws.walletCreationDate.Add(-time.Hour * 24 * 7)
+	for !start.header.Timestamp.Before(cutoff) {
+		prev, err := ws.chain.db.GetPreviousHeader(start.header)
+		if err != nil {
+			// We've reached the checkpoint
+			break
+		}
+		start = prev
+	}
+	startHash := start.header.BlockHash()
+	if err := ws.chain.db.PutFilterHeaderTip(startHash); err != nil {
+		return 0, err
+	}
+	if err := ws.chain.db.PutFilterTip(startHash); err != nil {
+		return 0, err
+	}
+	log.Infof("Starting compact filter sync at height %d", start.height)
+	return start.height, nil
+}
+
+// requestFilterHeaders requests the filter headers for the batch from every
+// peer serving compact filters so they can be checked against each other.
+func (ws *WireService) requestFilterHeaders(batch []StoredHeader) {
+	stopHash := batch[len(batch)-1].header.BlockHash()
+	msg := wire.NewMsgGetCFHeaders(wire.GCSFilterRegular, batch[0].height, &stopHash)
+	peers := make(map[*peerpkg.Peer]struct{})
+	for peer := range ws.peerStates {
+		if !supportsCompactFilters(peer) {
+			continue
+		}
+		peer.QueueMessage(msg, nil)
+		peers[peer] = struct{}{}
+	}
+	if len(peers) == 0 {
+		log.Warning("No peers available serving compact filters")
+		return
+	}
+	log.Debugf("Requesting filter headers for heights %d to %d from %d peers", batch[0].height, batch[len(batch)-1].height, len(peers))
+	ws.cf.headerRequest++
+	ws.cf.headerBatch = batch
+	ws.cf.headerPeers = peers
+	ws.cf.headerResponses = make(map[*peerpkg.Peer]*wire.MsgCFHeaders)
+
+	request := ws.cf.headerRequest
+	quit := ws.quit
+	time.AfterFunc(cfHeadersTimeout, func() {
+		select {
+		case ws.msgChan <- cfheadersTimeoutMsg{request}:
+		case <-quit:
+		}
+	})
+}
+
+// handleCFHeadersTimeout bans the peers which haven't answered a request for
+// filter headers in time and checks the responses we did get.
+func (ws *WireService) handleCFHeadersTimeout(tmsg *cfheadersTimeoutMsg) {
+	if ws.cf.headerBatch == nil || tmsg.request != ws.cf.headerRequest {
+		return
+	}
+	for peer := range ws.cf.headerPeers {
+		if _, ok := ws.cf.headerResponses[peer]; ok {
+			continue
+		}
+		log.Warningf("Peer %s failed to send cfheaders in time", peer)
+		delete(ws.cf.headerPeers, peer)
+		ws.banPeer(peer)
+	}
+	ws.checkFilterHeaderResponses()
+}
+
+func (ws *WireService) handleCFHeadersMsg(cmsg *cfheadersMsg) {
+	peer := cmsg.peer
+	msg := cmsg.cfheaders
+	if _, ok := ws.cf.headerPeers[peer]; !ok {
+		log.Debugf("Received unrequested cfheaders from %s", peer)
+		return
+	}
+	stopHash := ws.cf.headerBatch[len(ws.cf.headerBatch)-1].header.BlockHash()
+	if !msg.StopHash.IsEqual(&stopHash) {
+		log.Debugf("Received cfheaders for an old request from %s", peer)
+		return
+	}
+	ws.cf.headerResponses[peer] = msg
+	ws.checkFilterHeaderResponses()
+}
+
+// checkFilterHeaderResponses validates the batch of filter headers once every
+// peer we asked has responded, disconnected or timed out. Peers which serve
+// invalid headers or disagree with the majority are banned.
+func (ws *WireService) checkFilterHeaderResponses() {
+	if ws.cf.headerBatch == nil || len(ws.cf.headerResponses) < len(ws.cf.headerPeers) {
+		return
+	}
+	batch := ws.cf.headerBatch
+	responses := ws.cf.headerResponses
+	ws.cf.headerBatch = nil
+	ws.cf.headerPeers = nil
+	ws.cf.headerResponses = nil
+
+	// If every peer went away we'll try again when the next one connects.
+	if len(responses) == 0 {
+		return
+	}
+
+	// The filter header before the batch is unknown only for the first batch.
+	prevBlock := batch[0].header.PrevBlock
+	prevHeader, err := ws.chain.db.GetFilterHeader(prevBlock)
+	knownPrev := err == nil
+
+	chains := make(map[*peerpkg.Peer][]chainhash.Hash)
+	for peer, msg := range responses {
+		headers, err := filterHeaderChain(msg, len(batch))
+		if err == nil && knownPrev && !msg.PrevFilterHeader.IsEqual(&prevHeader) {
+			err = errors.New("previous filter header does not match")
+		}
+		if err != nil {
+			log.Warningf("Banning peer %s because of invalid cfheaders: %s", peer, err)
+			ws.banPeer(peer)
+			continue
+		}
+		chains[peer] = headers
+	}
+	if len(chains) == 0 {
+		return
+	}
+	headers, dissenters, err := selectFilterHeaderChain(chains)
+	for _, peer := range dissenters {
+		log.Warningf("Banning peer %s because it served conflicting filter headers", peer)
+		ws.banPeer(peer)
+	}
+	if err != nil {
+		log.Warning(err)
+		return
+	}
+
+	hashes := make([]chainhash.Hash, 0, len(batch)+1)
+	if !knownPrev {
+		// The last header commits to the previous one so any peer in the majority will do.
+		for peer, chain := range chains {
+			if chain[len(chain)-1] == headers[len(headers)-1] {
+				prevHeader = responses[peer].PrevFilterHeader
+				break
+			}
+		}
+		hashes = append(hashes, prevBlock)
+		headers = append([]chainhash.Hash{prevHeader}, headers...)
+	}
+	for _, sh := range batch {
+		hashes = append(hashes, sh.header.BlockHash())
+	}
+	if err := ws.chain.db.PutFilterHeaders(hashes, headers); err != nil {
+		log.Error(err)
+		return
+	}
+	log.Infof("Validated filter headers to height %d", batch[len(batch)-1].height)
+	ws.cfSyncNext()
+}
+
+// requestFilters requests the filters for the batch from a single peer. Each
+// filter is checked against its validated filter header when it arrives.
+func (ws *WireService) requestFilters(batch []StoredHeader) {
+	peer := ws.syncPeer
+	if _, ok := ws.peerStates[peer]; !ok || !supportsCompactFilters(peer) {
+		peer = nil
+		for p := range ws.peerStates {
+			if supportsCompactFilters(p) {
+				peer = p
+				break
+			}
+		}
+	}
+	if peer == nil {
+		log.Warning("No peers available serving compact filters")
+		return
+	}
+	stopHash := batch[len(batch)-1].header.BlockHash()
+	peer.QueueMessage(wire.NewMsgGetCFilters(wire.GCSFilterRegular, batch[0].height, &stopHash), nil)
+	log.Debugf("Requesting filters for heights %d to %d from %s", batch[0].height, batch[len(batch)-1].height, peer)
+	ws.cf.filterBatch = batch
+	ws.cf.filterPeer = peer
+	ws.cf.filters = make(map[chainhash.Hash]*gcs.Filter)
+}
+
+func (ws *WireService) handleCFilterMsg(cmsg *cfilterMsg) {
+	peer := cmsg.peer
+	msg := cmsg.cfilter
+	if peer != ws.cf.filterPeer || ws.cf.filterBatch == nil || msg.FilterType != wire.GCSFilterRegular {
+		log.Debugf("Received unrequested cfilter from %s", peer)
+		return
+	}
+	var sh *StoredHeader
+	for i := range ws.cf.filterBatch {
+		if ws.cf.filterBatch[i].header.BlockHash() == msg.BlockHash {
+			sh = &ws.cf.filterBatch[i]
+			break
+		}
+	}
+	if sh == nil {
+		log.Debugf("Received cfilter for an old request from %s", peer)
+		return
+	}
+
+	filterHeader, err := ws.chain.db.GetFilterHeader(msg.BlockHash)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	prevFilterHeader, err := ws.chain.db.GetFilterHeader(sh.header.PrevBlock)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	if makeFilterHeader(chainhash.DoubleHashH(msg.Data), prevFilterHeader) != filterHeader {
+		log.Warningf("Disconnecting from peer %s because it sent a filter not matching its header", peer)
+		peer.Disconnect()
+		return
+	}
+	filter, err := gcs.FromNBytes(builder.DefaultP, builder.DefaultM, msg.Data)
+	if err != nil {
+		log.Warningf("Disconnecting from peer %s because it sent an invalid filter: %s", peer, err)
+		peer.Disconnect()
+		return
+	}
+	ws.cf.filters[msg.BlockHash] = filter
+	ws.processFilters()
+}
+
+// processFilters matches the received filters in block order. When a filter
+// matches the block is downloaded and matching pauses until it is ingested so
+// that the outputs it pays us are known when matching the following filters.
+func (ws *WireService) processFilters() {
+	if ws.cf.watchList == nil {
+		watchList, err := ws.txStore.watchList()
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		ws.cf.watchList = watchList
+	}
+	for ws.cf.pendingBlock == nil && len(ws.cf.filterBatch) > 0 {
+		sh := ws.cf.filterBatch[0]
+		hash := sh.header.BlockHash()
+		filter, ok := ws.cf.filters[hash]
+		if !ok {
+			return
+		}
+		matched, err := matchFilter(filter, hash, ws.cf.watchList.entries)
+		if err != nil {
+			// Better to download a block we don't need than miss a transaction.
+			log.Warningf("Error matching filter for block %s: %s", hash, err)
+			matched = true
+		}
+		if matched {
+			ws.requestBlock(sh)
+			return
+		}
+		ws.advanceFilterTip(hash)
+	}
+	if ws.cf.pendingBlock == nil {
+		ws.flushFilterTip()
+		ws.cf.filterBatch = nil
+		ws.cf.filterPeer = nil
+		ws.cf.filters = nil
+		ws.cfSyncNext()
+	}
+}
+
+func (ws *WireService) requestBlock(sh StoredHeader) {
+	hash := sh.header.BlockHash()
+	ws.flushFilterTip()
+	gdmsg := wire.NewMsgGetData()
+	gdmsg.AddInvVect(wire.NewInvVect(wire.InvTypeBlock, &hash))
+	ws.cf.filterPeer.QueueMessage(gdmsg, nil)
+	ws.cf.pendingBlock = &sh
+	log.Debugf("Filter matched, requesting block %s", hash)
+}
+
+func (ws *WireService) handleBlockMsg(bmsg *blockMsg) {
+	peer := bmsg.peer
+	block := bmsg.block
+	hash := block.BlockHash()
+	if ws.cf.pendingBlock == nil || peer != ws.cf.filterPeer || ws.cf.pendingBlock.header.BlockHash() != hash {
+		log.Debugf("Received unrequested block %s from %s", hash, peer)
+		return
+	}
+	if err := checkBlockMerkleRoot(block); err != nil {
+		log.Warningf("Disconnecting from peer %s because it sent an invalid block: %s", peer, err)
+		peer.Disconnect()
+		return
+	}
+
+	sh := *ws.cf.pendingBlock
+	// relevant() adds the outputs of our transactions to the watch list so
+	// scan with a fresh copy.
+	watchList, err := ws.txStore.watchList()
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, tx := range sortTransactionsForIngest(block.Transactions) {
+		if !watchList.relevant(tx) {
+			continue
+		}
+		hits, err := ws.txStore.Ingest(tx, int32(sh.height), sh.header.Timestamp)
+		if err != nil {
+			log.Errorf("Error ingesting tx: %s\n", err.Error())
+			continue
+		}
+		if hits > 0 {
+			log.Noticef("Ingested new tx %s at height %d", tx.TxHash().String(), sh.height)
+		}
+	}
+	log.Infof("Received block %s at height %d", hash.String(), sh.height)
+
+	// Ingesting may have added keys and utxos so rebuild the watch list.
+	ws.cf.watchList = nil
+	ws.cf.pendingBlock = nil
+	ws.advanceFilterTip(hash)
+	ws.flushFilterTip()
+	ws.processFilters()
+}
+
+// advanceFilterTip marks the first block of the filter batch as matched. The
+// filter tip is saved in batches by flushFilterTip.
+func (ws *WireService) advanceFilterTip(hash chainhash.Hash) {
+	delete(ws.cf.filters, hash)
+	ws.cf.filterBatch = ws.cf.filterBatch[1:]
+	ws.cf.scanned = &hash
+}
+
+func (ws *WireService) flushFilterTip() {
+	if ws.cf.scanned == nil {
+		return
+	}
+	if err := ws.chain.db.PutFilterTip(*ws.cf.scanned); err != nil {
+		log.Error(err)
+	}
+	ws.cf.scanned = nil
+}
+
+// handleDonePeerCF drops a disconnected peer from any compact filter requests
+// and re-requests whatever it was serving from another peer.
+func (ws *WireService) handleDonePeerCF(peer *peerpkg.Peer) {
+	if _, ok := ws.cf.headerPeers[peer]; ok {
+		delete(ws.cf.headerPeers, peer)
+		delete(ws.cf.headerResponses, peer)
+		ws.checkFilterHeaderResponses()
+	}
+	if peer == ws.cf.filterPeer {
+		ws.flushFilterTip()
+		ws.cf.filterBatch = nil
+		ws.cf.filterPeer = nil
+		ws.cf.filters = nil
+		ws.cf.pendingBlock = nil
+		ws.cfSyncNext()
+	}
+}
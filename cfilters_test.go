@@ -0,0 +1,379 @@
+package bitcoincash
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gcash/bchd/blockchain"
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	peerpkg "github.com/gcash/bchd/peer"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil"
+	"github.com/gcash/bchutil/gcs"
+	"github.com/gcash/bchutil/gcs/builder"
+)
+
+func cfTestTx(prev wire.OutPoint, pkScript []byte) *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	tx.AddTxIn(wire.NewTxIn(&prev, nil))
+	tx.AddTxOut(wire.NewTxOut(10000, pkScript))
+	return tx
+}
+
+func cfTestScript(t *testing.T, b byte) []byte {
+	addr, err := bchutil.NewAddressPubKeyHash(append(make([]byte, 19), b), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestCFWatchList_relevant(t *testing.T) {
+	ours := cfTestScript(t, 1)
+	theirs := cfTestScript(t, 2)
+	wl := newCFWatchList()
+	wl.addPattern(ours[3:23])
+	utxo := wire.OutPoint{Hash: chainhash.DoubleHashH([]byte("utxo")), Index: 1}
+	wl.addOutPoint(utxo)
+
+	payment := cfTestTx(wire.OutPoint{Hash: chainhash.DoubleHashH([]byte("a"))}, ours)
+	child := cfTestTx(wire.OutPoint{Hash: payment.TxHash()}, theirs)
+	spend := cfTestTx(utxo, theirs)
+	other := cfTestTx(wire.OutPoint{Hash: chainhash.DoubleHashH([]byte("b"))}, theirs)
+
+	tests := []struct {
+		name     string
+		tx       *wire.MsgTx
+		expected bool
+	}{
+		{"unrelated", other, false},
+		{"spends child before parent", child, false},
+		{"pays us", payment, true},
+		{"spends child after parent", child, true},
+		{"spends utxo", spend, true},
+	}
+	for _, test := range tests {
+		if relevant := wl.relevant(test.tx); relevant != test.expected {
+			t.Errorf("%s: expected relevant %t, got %t", test.name, test.expected, relevant)
+		}
+	}
+}
+
+func TestMatchFilter(t *testing.T) {
+	blockHash := chainhash.DoubleHashH([]byte("block"))
+	key := builder.DeriveKey(&blockHash)
+	ours := cfTestScript(t, 1)
+	theirs := cfTestScript(t, 2)
+	filter, err := gcs.BuildGCSFilter(builder.DefaultP, builder.DefaultM, key, [][]byte{theirs, {0x6a}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Round trip through the wire encoding
+	data, err := filter.NBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err = gcs.FromNBytes(builder.DefaultP, builder.DefaultM, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		entries  [][]byte
+		expected bool
+	}{
+		{nil, false},
+		{[][]byte{ours}, false},
+		{[][]byte{ours, theirs}, true},
+	}
+	for i, test := range tests {
+		matched, err := matchFilter(filter, blockHash, test.entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched != test.expected {
+			t.Errorf("Test %d: expected match %t, got %t", i, test.expected, matched)
+		}
+	}
+
+	otherHash := chainhash.DoubleHashH([]byte("other block"))
+	if matched, _ := matchFilter(filter, otherHash, [][]byte{theirs}); matched {
+		t.Error("Filter matched with the key of another block")
+	}
+}
+
+func TestFilterHeaderChain(t *testing.T) {
+	blockHash := chainhash.DoubleHashH([]byte("block"))
+	filter, err := gcs.BuildGCSFilter(builder.DefaultP, builder.DefaultM, builder.DeriveKey(&blockHash), [][]byte{{1}, {2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := filter.NBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevHeader := chainhash.DoubleHashH([]byte("prev"))
+	filterHash := chainhash.DoubleHashH(data)
+
+	msg := wire.NewMsgCFHeaders()
+	msg.FilterType = wire.GCSFilterRegular
+	msg.PrevFilterHeader = prevHeader
+	msg.AddCFHash(&filterHash)
+	msg.AddCFHash(&filterHash)
+
+	headers, err := filterHeaderChain(msg, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := builder.MakeHeaderForFilter(filter, prevHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers[0] != expected {
+		t.Error("Incorrect first filter header")
+	}
+	expected, err = builder.MakeHeaderForFilter(filter, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers[1] != expected {
+		t.Error("Incorrect second filter header")
+	}
+
+	if _, err := filterHeaderChain(msg, 3); err == nil {
+		t.Error("Failed to reject cfheaders with missing filter hashes")
+	}
+}
+
+func TestSelectFilterHeaderChain(t *testing.T) {
+	var peers []*peerpkg.Peer
+	for i := 0; i < 4; i++ {
+		peer, err := peerpkg.NewOutboundPeer(&peerpkg.Config{}, "127.0.0.1:8333")
+		if err != nil {
+			t.Fatal(err)
+		}
+		peers = append(peers, peer)
+	}
+	good := []chainhash.Hash{chainhash.DoubleHashH([]byte{1}), chainhash.DoubleHashH([]byte{2})}
+	bad := []chainhash.Hash{chainhash.DoubleHashH([]byte{1}), chainhash.DoubleHashH([]byte{3})}
+
+	tests := []struct {
+		name       string
+		chains     [][]chainhash.Hash
+		expectErr  bool
+		dissenters int
+	}{
+		{"single peer", [][]chainhash.Hash{good}, false, 0},
+		{"all agree", [][]chainhash.Hash{good, good, good}, false, 0},
+		{"one dissenter", [][]chainhash.Hash{good, bad, good}, false, 1},
+		{"tie", [][]chainhash.Hash{good, bad, good, bad}, true, 4},
+		{"no majority", [][]chainhash.Hash{good, bad}, true, 2},
+	}
+	for _, test := range tests {
+		chains := make(map[*peerpkg.Peer][]chainhash.Hash)
+		for i, chain := range test.chains {
+			chains[peers[i]] = chain
+		}
+		headers, dissenters, err := selectFilterHeaderChain(chains)
+		if (err != nil) != test.expectErr {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+		if len(dissenters) != test.dissenters {
+			t.Errorf("%s: expected %d dissenters, got %d", test.name, test.dissenters, len(dissenters))
+		}
+		for _, peer := range dissenters {
+			if !test.expectErr && chains[peer][1] == good[1] {
+				t.Errorf("%s: peer in the majority reported as a dissenter", test.name)
+			}
+		}
+		if !test.expectErr && headers[1] != good[1] {
+			t.Errorf("%s: selected the wrong chain", test.name)
+		}
+	}
+}
+
+func TestCheckBlockMerkleRoot(t *testing.T) {
+	block := wire.NewMsgBlock(&wire.BlockHeader{})
+	for i := 0; i < 3; i++ {
+		block.AddTransaction(cfTestTx(wire.OutPoint{Index: uint32(i)}, []byte{txscript.OP_TRUE}))
+	}
+	var txs []*bchutil.Tx
+	for _, tx := range block.Transactions {
+		txs = append(txs, bchutil.NewTx(tx))
+	}
+	merkles := blockchain.BuildMerkleTreeStore(txs)
+	block.Header.MerkleRoot = *merkles[len(merkles)-1]
+	if err := checkBlockMerkleRoot(block); err != nil {
+		t.Error(err)
+	}
+
+	block.Transactions[2].TxOut[0].Value++
+	if err := checkBlockMerkleRoot(block); err == nil {
+		t.Error("Failed to reject block with a modified transaction")
+	}
+
+	if err := checkBlockMerkleRoot(chaincfg.MainNetParams.GenesisBlock); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSortTransactionsForIngest(t *testing.T) {
+	script := []byte{txscript.OP_TRUE}
+	coinbase := cfTestTx(wire.OutPoint{Index: wire.MaxPrevOutIndex}, script)
+	parent := cfTestTx(wire.OutPoint{Hash: chainhash.DoubleHashH([]byte("a"))}, script)
+	child := cfTestTx(wire.OutPoint{Hash: parent.TxHash()}, script)
+	grandchild := cfTestTx(wire.OutPoint{Hash: child.TxHash()}, script)
+
+	sorted := sortTransactionsForIngest([]*wire.MsgTx{coinbase, grandchild, child, parent})
+	position := make(map[chainhash.Hash]int)
+	for i, tx := range sorted {
+		position[tx.TxHash()] = i
+	}
+	if len(sorted) != 4 || position[coinbase.TxHash()] != 0 {
+		t.Fatal("Coinbase is not first")
+	}
+	if position[parent.TxHash()] > position[child.TxHash()] || position[child.TxHash()] > position[grandchild.TxHash()] {
+		t.Error("Transactions are not in dependency order")
+	}
+}
+
+// cfTestPeer connects to an in-process peer serving compact filters and
+// returns our side of the connection once the handshake is done.
+func cfTestPeer(t *testing.T, ws *WireService, onGetCFHeaders func(*peerpkg.Peer, *wire.MsgGetCFHeaders)) *peerpkg.Peer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		remote := peerpkg.NewInboundPeer(&peerpkg.Config{
+			ChainParams:            &chaincfg.RegressionNetParams,
+			Services:               wire.SFNodeNetwork | wire.SFNodeCF,
+			Listeners:              peerpkg.MessageListeners{OnGetCFHeaders: onGetCFHeaders},
+			TstAllowSelfConnection: true,
+		})
+		remote.AssociateConnection(conn)
+	}()
+
+	verack := make(chan struct{})
+	peer, err := peerpkg.NewOutboundPeer(&peerpkg.Config{
+		ChainParams:            &chaincfg.RegressionNetParams,
+		TstAllowSelfConnection: true,
+		Listeners: peerpkg.MessageListeners{
+			OnVerAck: func(p *peerpkg.Peer, msg *wire.MsgVerAck) {
+				close(verack)
+			},
+			OnCFHeaders: func(p *peerpkg.Peer, msg *wire.MsgCFHeaders) {
+				ws.msgChan <- cfheadersMsg{msg, p}
+			},
+		},
+	}, listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer.AssociateConnection(conn)
+	select {
+	case <-verack:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out connecting to peer")
+	}
+	return peer
+}
+
+func TestFilterHeaderSyncTimeout(t *testing.T) {
+	bc, err := NewBlockchain("", MockCreationTime, &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("headers.bin")
+	best, err := bc.BestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(timeout time.Duration) { cfHeadersTimeout = timeout }(cfHeadersTimeout)
+	cfHeadersTimeout = time.Millisecond * 500
+
+	var banned []*peerpkg.Peer
+	ws := NewWireService(&WireServiceConfig{
+		params:   &chaincfg.RegressionNetParams,
+		chain:    bc,
+		syncMode: CompactFilterSync,
+		banPeer: func(p *peerpkg.Peer) {
+			banned = append(banned, p)
+			p.Disconnect()
+		},
+	})
+	ws.quit = make(chan struct{})
+	defer close(ws.quit)
+
+	filterHash := chainhash.DoubleHashH([]byte("filter"))
+	honest := func(p *peerpkg.Peer, msg *wire.MsgGetCFHeaders) {
+		reply := wire.NewMsgCFHeaders()
+		reply.FilterType = msg.FilterType
+		reply.StopHash = msg.StopHash
+		reply.AddCFHash(&filterHash)
+		p.QueueMessage(reply, nil)
+	}
+	silent := func(p *peerpkg.Peer, msg *wire.MsgGetCFHeaders) {}
+
+	var peers []*peerpkg.Peer
+	for _, serve := range []func(*peerpkg.Peer, *wire.MsgGetCFHeaders){honest, honest, silent} {
+		peer := cfTestPeer(t, ws, serve)
+		defer peer.Disconnect()
+		ws.peerStates[peer] = &peerSyncState{}
+		peers = append(peers, peer)
+	}
+
+	ws.requestFilterHeaders([]StoredHeader{best})
+	if len(ws.cf.headerPeers) != 3 {
+		t.Fatalf("Expected filter headers to be requested from 3 peers, got %d", len(ws.cf.headerPeers))
+	}
+	deadline := time.After(time.Second * 5)
+	for ws.cf.headerBatch != nil {
+		select {
+		case m := <-ws.msgChan:
+			switch msg := m.(type) {
+			case cfheadersMsg:
+				ws.handleCFHeadersMsg(&msg)
+			case cfheadersTimeoutMsg:
+				ws.handleCFHeadersTimeout(&msg)
+			}
+		case <-deadline:
+			t.Fatal("Filter headers were never checked")
+		}
+	}
+
+	if len(banned) != 1 || banned[0] != peers[2] {
+		t.Errorf("Expected only the silent peer to be banned, got %v", banned)
+	}
+	reply := wire.NewMsgCFHeaders()
+	reply.AddCFHash(&filterHash)
+	expected, err := filterHeaderChain(reply, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := bc.db.GetFilterHeader(best.header.BlockHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header != expected[0] {
+		t.Error("Stored the wrong filter header")
+	}
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -15,13 +16,17 @@ import (
 	"github.com/jessevdk/go-flags"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"io"
+	"io/ioutil"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 )
 
 func SetupCli(parser *flags.Parser) {
+	parser.AddGroup("API Options", "", &options)
 	// Add commands to parser
 	parser.AddCommand("stop",
 		"stop the wallet",
@@ -49,6 +54,17 @@ func SetupCli(parser *flags.Parser) {
 			"> spvwallet newaddress internal\n"+
 			"18zAxgfKx4NuTUGUEuB8p7FKgCYPM15DfS\n",
 		&newAddress)
+	parser.AddCommand("paymenturi",
+		"get a payment URI for the current address",
+		"Returns a bitcoincash: URI requesting payment to the current receiving address\n\n"+
+			"Args:\n"+
+			"1. amount        (integer default=0) The amount to request in satoshi, 0 to let the payer choose\n"+
+			"2. label         (string optional) A label for the recipient\n"+
+			"3. message       (string optional) A message describing the payment\n\n"+
+			"Examples:\n"+
+			"> spvwallet paymenturi 1000000 \"Coffee shop\" \"Order 42\"\n"+
+			"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a?amount=0.01&label=Coffee%20shop&message=Order%2042\n",
+		&paymentURI)
 	parser.AddCommand("chaintip",
 		"return the height of the chain",
 		"Returns the height of the best chain of headers",
@@ -59,6 +75,15 @@ func SetupCli(parser *flags.Parser) {
 			"Args:\n"+
 			"1. Path (string) Optional path to the header file\n",
 		&dumpheaders)
+	parser.AddCommand("watch",
+		"print the wallet's events as they happen",
+		"Streams the wallet's events until interrupted: new and confirmed transactions,\n"+
+			"new blocks, reorgs, balance changes, peers connecting and disconnecting and\n"+
+			"sync progress. Each event is printed on one line.\n\n"+
+			"Examples:\n"+
+			"> spvwallet watch\n"+
+			"> spvwallet watch --json\n",
+		&watch)
 	parser.AddCommand("balance",
 		"get the wallet balance",
 		"Returns both the confirmed and unconfirmed balances",
@@ -89,14 +114,86 @@ func SetupCli(parser *flags.Parser) {
 			"Examples:\n"+
 			"> spvwallet importkey KyhgBE8RtdR7kq9D8T4bNGS4CDzBq6UY8nSb5X9zsKTN7zrfiu2F 2012-02-23\n",
 		&importKey)
+	parser.AddCommand("encryptwallet",
+		"encrypt the wallet",
+		"Encrypts the mnemonic and imported keys with a passphrase. The wallet stays unlocked until lock is called or it is restarted.\n\n"+
+			"Args:\n"+
+			"1. passphrase    (string) The passphrase to encrypt the wallet with\n\n"+
+			"Examples:\n"+
+			"> spvwallet encryptwallet \"correct horse battery staple\"\n",
+		&encryptWallet)
+	parser.AddCommand("unlock",
+		"unlock the wallet",
+		"Decrypts the private keys of an encrypted wallet so it can spend\n\n"+
+			"Args:\n"+
+			"1. passphrase    (string) The wallet passphrase\n\n"+
+			"Examples:\n"+
+			"> spvwallet unlock \"correct horse battery staple\"\n",
+		&unlock)
+	parser.AddCommand("lock",
+		"lock the wallet",
+		"Discards the private keys of an encrypted wallet until it is unlocked",
+		&lock)
+	parser.AddCommand("changepassphrase",
+		"change the wallet passphrase",
+		"Re-encrypts the wallet with a new passphrase\n\n"+
+			"Args:\n"+
+			"1. oldpassphrase (string) The current wallet passphrase\n"+
+			"2. newpassphrase (string) The new wallet passphrase\n\n"+
+			"Examples:\n"+
+			"> spvwallet changepassphrase \"correct horse battery staple\" \"new passphrase\"\n",
+		&changePassphrase)
 	parser.AddCommand("getkey",
 		"get a private key",
 		"Return the private key for the given address",
 		&getKey)
 	parser.AddCommand("listaddresses",
 		"list all addresses",
-		"Returns all addresses currently watched by the wallet",
+		"Returns all addresses currently watched by the wallet followed by their labels",
 		&listAddresses)
+	parser.AddCommand("setlabel",
+		"label an address",
+		"Save a label for an address. The label is shown by listaddresses and listunspent.\n\n"+
+			"Args:\n"+
+			"1. address       (string) The address to label\n"+
+			"2. label         (string) The label, leave it out to remove the label\n\n"+
+			"Examples:\n"+
+			"> spvwallet setlabel qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a \"Savings\"\n",
+		&setLabel)
+	parser.AddCommand("setmemo",
+		"add a memo to a transaction",
+		"Save a memo for a transaction. The memo is shown by transactions and gettransaction.\n\n"+
+			"Args:\n"+
+			"1. txid          (string) The transaction ID\n"+
+			"2. memo          (string) The memo, leave it out to remove the memo\n\n"+
+			"Examples:\n"+
+			"> spvwallet setmemo 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5 \"Rent for May\"\n",
+		&setMemo)
+	parser.AddCommand("listunspent",
+		"list unspent outputs",
+		"Returns a json list of the wallet's unspent outputs with their address, confirmations and label. "+
+			"The outpoints can be given to spend --inputs to choose the coins of a spend.",
+		&listUnspent)
+	parser.AddCommand("lockunspent",
+		"lock unspent outputs",
+		"Lock unspent outputs so they are never spent until unlocked. Their value is shown as locked in the balance.\n\n"+
+			"Args:\n"+
+			"1. outpoints     (string) A comma separated list of txid:index outpoints\n\n"+
+			"Examples:\n"+
+			"> spvwallet lockunspent 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5:1\n",
+		&lockUnspent)
+	parser.AddCommand("unlockunspent",
+		"unlock unspent outputs",
+		"Unlock outputs locked with lockunspent\n\n"+
+			"Args:\n"+
+			"1. outpoints     (string) A comma separated list of txid:index outpoints\n\n"+
+			"Examples:\n"+
+			"> spvwallet unlockunspent 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5:1\n",
+		&unlockUnspent)
+	parser.AddCommand("listlockedunspent",
+		"list locked unspent outputs",
+		"Returns a json list of the unspent outputs locked with lockunspent",
+		&listLockedUnspent)
 	parser.AddCommand("listkeys",
 		"list all private keys",
 		"Returns all private keys currently watched by the wallet",
@@ -105,6 +202,40 @@ func SetupCli(parser *flags.Parser) {
 		"get a list of transactions",
 		"Returns a json list of the wallet's transactions",
 		&transactions)
+	parser.AddCommand("exporthistory",
+		"export the transaction history",
+		"Prints the wallet's transactions as CSV or JSON for bookkeeping. Each row has the\n"+
+			"txid, date, height, net value and fee in satoshi, the counterparty addresses, the\n"+
+			"memo and the value in a fiat currency at the exchange rate of the day. Rates are\n"+
+			"cached so exporting again doesn't need to fetch them.\n\n"+
+			"Examples:\n"+
+			"> spvwallet exporthistory > history.csv\n"+
+			"> spvwallet exporthistory --format json --from 2019-01-01 --to 2020-01-01 --currency EUR\n",
+		&exportHistory)
+	parser.AddCommand("createinvoice",
+		"create an invoice",
+		"Creates an invoice paid to a new address and prints it as json. The amount is given in\n"+
+			"satoshi or with --fiat in a fiat currency, converted at the current exchange rate which\n"+
+			"is then kept with the invoice. Its status is unpaid, partially paid, paid, overpaid,\n"+
+			"expired or confirmed as payments to its address arrive.\n\n"+
+			"Args:\n"+
+			"1. amount        (integer) The amount due in satoshi, left out with --fiat\n\n"+
+			"Examples:\n"+
+			"> spvwallet createinvoice 1000000 --memo \"Order 17\"\n"+
+			"> spvwallet createinvoice --fiat 25.50 --currency EUR --expiry 15m\n",
+		&createInvoice)
+	parser.AddCommand("getinvoice",
+		"get an invoice",
+		"Returns json data of an invoice with its payments and status\n\n"+
+			"Args:\n"+
+			"1. id       (string) The ID of the invoice\n\n"+
+			"Examples:\n"+
+			"> spvwallet getinvoice 5b0e5bd2ab8b0d1f1ce36d2ff7e5f6a0\n",
+		&getInvoice)
+	parser.AddCommand("listinvoices",
+		"list the invoices",
+		"Returns a json list of the invoices, newest first",
+		&listInvoices)
 	parser.AddCommand("gettransaction",
 		"get a specific transaction",
 		"Returns json data of a specific transaction\n\n"+
@@ -113,6 +244,16 @@ func SetupCli(parser *flags.Parser) {
 			"Examples:\n"+
 			"> spvwallet gettransaction 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5\n",
 		&getTransaction)
+	parser.AddCommand("gettransactiondetail",
+		"get the inputs, outputs and fee of a transaction",
+		"Returns json data of a transaction with its decoded inputs and outputs, fee, fee rate\n"+
+			"in satoshi per byte, size and confirmations. An input spending an output unknown\n"+
+			"to the wallet has the value -1 and the fee is then 0.\n\n"+
+			"Args:\n"+
+			"1. txid       (string) A transaction ID to search for.\n\n"+
+			"Examples:\n"+
+			"> spvwallet gettransactiondetail 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5\n",
+		&getTransactionDetail)
 	parser.AddCommand("getfeeperbyte",
 		"get the current bitcoin fee",
 		"Returns the current network fee per byte for the given fee level.\n\n"+
@@ -135,8 +276,28 @@ func SetupCli(parser *flags.Parser) {
 			"> spvwallet spend 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 1000000\n"+
 			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n"+
 			"> spvwallet spend 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 3000000000 priority\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n"+
+			"> spvwallet spend --inputs 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5:1 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 1000000\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n"+
+			"> spvwallet spend --feerate 1 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 1000000\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c\n"+
+			"> spvwallet spend --uri \"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a?amount=0.01\" priority\n"+
 			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c",
 		&spend)
+	parser.AddCommand("spendmany",
+		"send bitcoins to many addresses",
+		"Send one transaction paying every address in a CSV file of address,amount lines\n\n"+
+			"Args:\n"+
+			"1. file          (string) The CSV file of payments with amounts in satoshi\n"+
+			"2. feelevel      (string default=normal) The fee level: economic, normal, priority\n\n"+
+			"Examples:\n"+
+			"> cat payroll.csv\n"+
+			"address,amount\n"+
+			"1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS,1000000\n"+
+			"1AmAmdQ1kwjTSr3a6fgA7ijQVvnHbvTkpS,2500000\n"+
+			"> spvwallet spendmany payroll.csv\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c",
+		&spendMany)
 	parser.AddCommand("bumpfee",
 		"bump the tx fee",
 		"Bumps the fee on an unconfirmed transaction\n\n"+
@@ -146,6 +307,35 @@ func SetupCli(parser *flags.Parser) {
 			"> spvwallet bumpfee 190bd83935740b88ebdfe724485f36ca4aa40125a21b93c410e0e191d4e9e0b5\n"+
 			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c",
 		&bumpFee)
+	parser.AddCommand("createunsignedtx",
+		"create an unsigned transaction",
+		"Create a transaction paying the given address and write it to a file to be signed offline\n\n"+
+			"Args:\n"+
+			"1. address       (string) The recipient's bitcoin address\n"+
+			"2. amount        (integer) The amount to send in satoshi\n"+
+			"3. feelevel      (string default=normal) The fee level: economic, normal, priority\n"+
+			"4. file          (string default=unsigned.json) The file to write the transaction to\n\n"+
+			"Examples:\n"+
+			"> spvwallet createunsignedtx 1DxGWC22a46VPEjq8YKoeVXSLzB7BA8sJS 1000000 normal /media/usb/unsigned.json\n",
+		&createUnsignedTx)
+	parser.AddCommand("signtx",
+		"sign a transaction file",
+		"Sign the inputs of a transaction file created with createunsignedtx. The wallet doesn't need to be online.\n\n"+
+			"Args:\n"+
+			"1. file          (string) The file containing the unsigned transaction\n"+
+			"2. output        (string default=file) The file to write the signed transaction to\n\n"+
+			"Examples:\n"+
+			"> spvwallet signtx /media/usb/unsigned.json /media/usb/signed.json\n",
+		&signTx)
+	parser.AddCommand("finalizetx",
+		"broadcast a signed transaction file",
+		"Check every input of a transaction file is signed and broadcast it\n\n"+
+			"Args:\n"+
+			"1. file          (string) The file containing the signed transaction\n\n"+
+			"Examples:\n"+
+			"> spvwallet finalizetx /media/usb/signed.json\n"+
+			"82bfd45f3564e0b5166ab9ca072200a237f78499576e9658b20b0ccd10ff325c",
+		&finalizeTx)
 	parser.AddCommand("peers",
 		"get info about peers",
 		"Returns a list of json data on each connected peer",
@@ -301,14 +491,27 @@ func SetupCli(parser *flags.Parser) {
 		&estimateFee)
 }
 
+// Options locate the wallet's API. By default the local daemon is reached with
+// the certificate and admin token it saves in the default data directory.
+type Options struct {
+	RPCServer string `long:"rpcserver" default:"127.0.0.1:8234" description:"the address of the wallet's API"`
+	RPCDir    string `long:"rpcdir" description:"the directory holding the API's certificate and cookie files, the default data directory if omitted"`
+	RPCCookie string `long:"rpccookie" description:"the cookie file holding the API token, admin.cookie in the rpc directory if omitted"`
+}
+
+var options Options
+
 func newGRPCClient() (pb.APIClient, *grpc.ClientConn, error) {
-	// Set up a connection to the server.
-	conn, err := grpc.Dial(api.Addr, grpc.WithInsecure())
-	if err != nil {
-		return nil, nil, err
+	dir := options.RPCDir
+	if dir == "" {
+		dir = bc.NewDefaultConfig().RepoPath
 	}
-	client := pb.NewAPIClient(conn)
-	return client, conn, nil
+	cookie := options.RPCCookie
+	if cookie == "" {
+		cookie = path.Join(dir, api.AdminCookie)
+	}
+	// Set up a connection to the server.
+	return api.Dial(options.RPCServer, path.Join(dir, api.CertFile), cookie)
 }
 
 type Stop struct{}
@@ -387,6 +590,38 @@ func (x *NewAddress) Execute(args []string) error {
 	return nil
 }
 
+type PaymentURI struct{}
+
+var paymentURI PaymentURI
+
+func (x *PaymentURI) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	req := new(pb.PaymentURIRequest)
+	if len(args) > 0 {
+		amt, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		req.Amount = uint64(amt)
+	}
+	if len(args) > 1 {
+		req.Label = args[1]
+	}
+	if len(args) > 2 {
+		req.Message = args[2]
+	}
+	resp, err := client.PaymentURI(context.Background(), req)
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Uri)
+	return nil
+}
+
 type ChainTip struct{}
 
 var chainTip ChainTip
@@ -422,8 +657,9 @@ func (x *Balance) Execute(args []string) error {
 	type ret struct {
 		Confirmed   uint64 `json:"confirmed"`
 		Unconfirmed uint64 `json:"unconfirmed"`
+		Locked      uint64 `json:"locked"`
 	}
-	out, err := json.MarshalIndent(&ret{resp.Confirmed, resp.Unconfirmed}, "", "    ")
+	out, err := json.MarshalIndent(&ret{resp.Confirmed, resp.Unconfirmed, resp.Locked}, "", "    ")
 	if err != nil {
 		return err
 	}
@@ -480,7 +716,7 @@ func (x *HasKey) Execute(args []string) error {
 	if len(args) <= 0 {
 		return errors.New("Bitcoin address is required")
 	}
-	resp, err := client.HasKey(context.Background(), &pb.Address{args[0]})
+	resp, err := client.HasKey(context.Background(), &pb.Address{Addr: args[0]})
 	if err != nil {
 		return err
 	}
@@ -514,6 +750,7 @@ func (x *Transactions) Execute(args []string) error {
 		Confirmations int32     `json:"confirmations"`
 		Height        int32     `json:"height"`
 		WatchOnly     bool      `json:"watchOnly"`
+		Memo          string    `json:"memo"`
 	}
 	var txns []Tx
 	for _, tx := range resp.Transactions {
@@ -546,6 +783,7 @@ func (x *Transactions) Execute(args []string) error {
 			Timestamp:     ts,
 			Status:        status,
 			Confirmations: confirmations,
+			Memo:          tx.Memo,
 		}
 		txns = append(txns, t)
 	}
@@ -586,6 +824,7 @@ func (x *GetTransaction) Execute(args []string) error {
 		Confirmations int32     `json:"confirmations"`
 		Height        int32     `json:"height"`
 		WatchOnly     bool      `json:"watchOnly"`
+		Memo          string    `json:"memo"`
 	}
 	var confirmations int32
 	var status string
@@ -616,6 +855,7 @@ func (x *GetTransaction) Execute(args []string) error {
 		Timestamp:     ts,
 		Status:        status,
 		Confirmations: confirmations,
+		Memo:          resp.Memo,
 	}
 	formatted, err := json.MarshalIndent(t, "", "    ")
 	if err != nil {
@@ -625,211 +865,740 @@ func (x *GetTransaction) Execute(args []string) error {
 	return nil
 }
 
-type GetFeePerByte struct{}
+type GetTransactionDetail struct{}
 
-var getFeePerByte GetFeePerByte
+var getTransactionDetail GetTransactionDetail
 
-func (x *GetFeePerByte) Execute(args []string) error {
+func (x *GetTransactionDetail) Execute(args []string) error {
 	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	var feeLevel pb.FeeLevel
-	userSelection := ""
-	if len(args) > 0 {
-		userSelection = args[0]
+	if len(args) <= 0 {
+		return errors.New("Txid is required")
 	}
-	switch strings.ToLower(userSelection) {
-	case "economic":
-		feeLevel = pb.FeeLevel_ECONOMIC
-	case "normal":
-		feeLevel = pb.FeeLevel_NORMAL
-	case "priority":
-		feeLevel = pb.FeeLevel_PRIORITY
-	default:
-		feeLevel = pb.FeeLevel_NORMAL
+	resp, err := client.GetTransactionDetail(context.Background(), &pb.Txid{Hash: args[0]})
+	if err != nil {
+		return err
 	}
-	resp, err := client.GetFeePerByte(context.Background(), &pb.FeeLevelSelection{feeLevel})
+	type Input struct {
+		Txid    string `json:"txid"`
+		Index   uint32 `json:"index"`
+		Address string `json:"address"`
+		Value   int64  `json:"value"`
+		Mine    bool   `json:"mine"`
+	}
+	type Output struct {
+		Index   uint32   `json:"index"`
+		Address string   `json:"address,omitempty"`
+		Value   int64    `json:"value"`
+		Mine    bool     `json:"mine"`
+		Data    []string `json:"data,omitempty"`
+	}
+	type Tx struct {
+		Txid          string    `json:"txid"`
+		Value         int64     `json:"value"`
+		Status        string    `json:"status"`
+		Timestamp     time.Time `json:"timestamp"`
+		Confirmations int64     `json:"confirmations"`
+		Height        int32     `json:"height"`
+		Memo          string    `json:"memo"`
+		Fee           int64     `json:"fee"`
+		FeeRate       float64   `json:"feeRate"`
+		Size          uint32    `json:"size"`
+		Inputs        []Input   `json:"inputs"`
+		Outputs       []Output  `json:"outputs"`
+	}
+	t := Tx{
+		Txid:          resp.Tx.Txid,
+		Value:         resp.Tx.Value,
+		Status:        resp.Status,
+		Timestamp:     time.Unix(int64(resp.Tx.Timestamp.Seconds), int64(resp.Tx.Timestamp.Nanos)),
+		Confirmations: resp.Confirmations,
+		Height:        resp.Tx.Height,
+		Memo:          resp.Tx.Memo,
+		Fee:           resp.Fee,
+		FeeRate:       resp.FeeRate,
+		Size:          resp.Size,
+	}
+	for _, in := range resp.Inputs {
+		t.Inputs = append(t.Inputs, Input{in.Txid, in.Index, in.Address, in.Value, in.Mine})
+	}
+	for _, out := range resp.Outputs {
+		o := Output{Index: out.Index, Address: out.Address, Value: out.Value, Mine: out.Mine}
+		for _, d := range out.Data {
+			o.Data = append(o.Data, hex.EncodeToString(d))
+		}
+		t.Outputs = append(t.Outputs, o)
+	}
+	formatted, err := json.MarshalIndent(t, "", "    ")
 	if err != nil {
 		return err
 	}
-	fmt.Println(resp.Fee)
+	fmt.Println(string(formatted))
 	return nil
 }
 
-type Spend struct{}
+type ExportHistory struct {
+	Format   string `long:"format" default:"csv" description:"the file format: csv or json"`
+	From     string `long:"from" description:"the first day to export as YYYY-MM-DD"`
+	To       string `long:"to" description:"the day to stop before as YYYY-MM-DD"`
+	Currency string `long:"currency" default:"USD" description:"the fiat currency of the values, empty to leave them out"`
+}
 
-var spend Spend
+var exportHistory ExportHistory
 
-func (x *Spend) Execute(args []string) error {
+func (x *ExportHistory) Execute(args []string) error {
 	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	var feeLevel pb.FeeLevel
-	userSelection := ""
-	if len(args) > 2 {
-		userSelection = args[2]
-	}
-	if len(args) < 2 {
-		return errors.New("Address and amount are required")
-	}
-	switch strings.ToLower(userSelection) {
-	case "economic":
-		feeLevel = pb.FeeLevel_ECONOMIC
-	case "normal":
-		feeLevel = pb.FeeLevel_NORMAL
-	case "priority":
-		feeLevel = pb.FeeLevel_PRIORITY
-	default:
-		feeLevel = pb.FeeLevel_NORMAL
+	req := &pb.HistoryRequest{Format: x.Format, Currency: x.Currency}
+	if x.From != "" {
+		from, err := time.Parse("2006-01-02", x.From)
+		if err != nil {
+			return err
+		}
+		req.From, err = ptypes.TimestampProto(from)
+		if err != nil {
+			return err
+		}
 	}
-	amt, err := strconv.Atoi(args[1])
-	if err != nil {
-		return err
+	if x.To != "" {
+		to, err := time.Parse("2006-01-02", x.To)
+		if err != nil {
+			return err
+		}
+		req.To, err = ptypes.TimestampProto(to)
+		if err != nil {
+			return err
+		}
 	}
-	resp, err := client.Spend(context.Background(), &pb.SpendInfo{args[0], uint64(amt), feeLevel})
+	resp, err := client.ExportHistory(context.Background(), req)
 	if err != nil {
 		return err
 	}
-	fmt.Println(resp.Hash)
-	return nil
+	_, err = os.Stdout.Write(resp.Data)
+	return err
 }
 
-type BumpFee struct{}
+type CreateInvoice struct {
+	Fiat     float64       `long:"fiat" description:"the amount due in a fiat currency instead of satoshi"`
+	Currency string        `long:"currency" default:"USD" description:"the currency of the fiat amount"`
+	Expiry   time.Duration `long:"expiry" default:"1h" description:"how long the invoice accepts payments, 0 for no expiry"`
+	Memo     string        `long:"memo" description:"a memo saved with the invoice"`
+}
 
-var bumpFee BumpFee
+var createInvoice CreateInvoice
 
-func (x *BumpFee) Execute(args []string) error {
+func (x *CreateInvoice) Execute(args []string) error {
 	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	if len(args) <= 0 {
-		return errors.New("Txid is required")
+	req := &pb.InvoiceRequest{Memo: x.Memo, Expiry: uint32(x.Expiry / time.Second)}
+	if x.Fiat > 0 {
+		if len(args) > 0 {
+			return errors.New("Give either an amount or a fiat amount")
+		}
+		req.FiatAmount = x.Fiat
+		req.Currency = x.Currency
+	} else {
+		if len(args) <= 0 {
+			return errors.New("Amount is required")
+		}
+		req.Amount, err = strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return err
+		}
 	}
-	resp, err := client.BumpFee(context.Background(), &pb.Txid{args[0]})
+	resp, err := client.CreateInvoice(context.Background(), req)
 	if err != nil {
 		return err
 	}
-	fmt.Println(resp.Hash)
+	formatted, err := json.MarshalIndent(newInvoice(resp), "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(formatted))
 	return nil
 }
 
-type Peers struct{}
+type GetInvoice struct{}
 
-var peers Peers
+var getInvoice GetInvoice
 
-func (x *Peers) Execute(args []string) error {
+func (x *GetInvoice) Execute(args []string) error {
 	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	resp, err := client.Peers(context.Background(), &pb.Empty{})
+	if len(args) <= 0 {
+		return errors.New("Invoice ID is required")
+	}
+	resp, err := client.GetInvoice(context.Background(), &pb.InvoiceID{Id: args[0]})
 	if err != nil {
 		return err
 	}
-	type peer struct {
-		Address         string    `json:"address"`
-		BytesSent       uint64    `json:"bytesSent"`
-		BytesReceived   uint64    `json:"bytesReceived"`
-		Connected       bool      `json:"connected"`
-		ID              int32     `json:"id"`
-		LastBlock       int32     `json:"lastBlock"`
-		ProtocolVersion uint32    `json:"protocolVersion"`
-		Services        string    `json:"services"`
-		UserAgent       string    `json:"userAgent"`
-		TimeConnected   time.Time `json:"timeConnected"`
-	}
-	var peers []peer
-	for _, p := range resp.Peers {
-		pi := peer{
-			Address:         p.Address,
-			BytesSent:       p.BytesSent,
-			BytesReceived:   p.BytesReceived,
-			Connected:       p.Connected,
-			ID:              p.ID,
-			LastBlock:       p.LastBlock,
-			ProtocolVersion: p.ProtocolVersion,
-			Services:        p.Services,
-			UserAgent:       p.UserAgent,
-			TimeConnected:   time.Unix(int64(p.TimeConnected.Seconds), int64(p.TimeConnected.Nanos)),
-		}
-		peers = append(peers, pi)
+	formatted, err := json.MarshalIndent(newInvoice(resp), "", "    ")
+	if err != nil {
+		return err
 	}
-	out, err := json.MarshalIndent(peers, "", "    ")
-	fmt.Println(string(out))
+	fmt.Println(string(formatted))
 	return nil
 }
 
-type AddWatchedScript struct{}
+type ListInvoices struct{}
 
-var addWatchedScript AddWatchedScript
+var listInvoices ListInvoices
 
-func (x *AddWatchedScript) Execute(args []string) error {
+func (x *ListInvoices) Execute(args []string) error {
 	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	if len(args) <= 0 {
-		return errors.New("Address or script required")
-	}
-	_, err = client.AddWatchedAddress(context.Background(), &pb.Address{args[0]})
-	return err
-}
-
-type GetConfirmations struct{}
-
-var getConfirmations GetConfirmations
-
-func (x *GetConfirmations) Execute(args []string) error {
-	client, conn, err := newGRPCClient()
+	resp, err := client.ListInvoices(context.Background(), &pb.Empty{})
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	if len(args) <= 0 {
-		return errors.New("Txid is required")
+	invoices := []invoice{}
+	for _, i := range resp.Invoices {
+		invoices = append(invoices, newInvoice(i))
 	}
-	resp, err := client.GetConfirmations(context.Background(), &pb.Txid{args[0]})
+	formatted, err := json.MarshalIndent(invoices, "", "    ")
 	if err != nil {
 		return err
 	}
-	fmt.Println(resp.Confirmations)
+	fmt.Println(string(formatted))
 	return nil
 }
 
-type SweepAddress struct{}
+var invoiceStatusNames = map[pb.InvoiceStatus]string{
+	pb.InvoiceStatus_UNPAID:         "UNPAID",
+	pb.InvoiceStatus_PARTIALLY_PAID: "PARTIALLY PAID",
+	pb.InvoiceStatus_PAID:           "PAID",
+	pb.InvoiceStatus_OVERPAID:       "OVERPAID",
+	pb.InvoiceStatus_EXPIRED:        "EXPIRED",
+	pb.InvoiceStatus_CONFIRMED:      "CONFIRMED",
+}
 
-var sweepAddress SweepAddress
+type invoicePayment struct {
+	Txid          string `json:"txid"`
+	Index         uint32 `json:"index"`
+	Value         int64  `json:"value"`
+	Height        int32  `json:"height"`
+	Confirmations uint32 `json:"confirmations"`
+}
 
-func (x *SweepAddress) Execute(args []string) error {
+type invoice struct {
+	ID           string           `json:"id"`
+	Status       string           `json:"status"`
+	Address      string           `json:"address"`
+	URI          string           `json:"uri"`
+	Amount       uint64           `json:"amount"`
+	Received     uint64           `json:"received"`
+	FiatAmount   float64          `json:"fiatAmount,omitempty"`
+	Currency     string           `json:"currency,omitempty"`
+	ExchangeRate float64          `json:"exchangeRate,omitempty"`
+	Memo         string           `json:"memo"`
+	Created      time.Time        `json:"created"`
+	Expires      *time.Time       `json:"expires,omitempty"`
+	Payments     []invoicePayment `json:"payments"`
+}
+
+func newInvoice(in *pb.Invoice) invoice {
+	i := invoice{
+		ID:           in.Id,
+		Status:       invoiceStatusNames[in.Status],
+		Address:      in.Address,
+		URI:          in.Uri,
+		Amount:       in.Amount,
+		Received:     in.Received,
+		FiatAmount:   in.FiatAmount,
+		Currency:     in.Currency,
+		ExchangeRate: in.ExchangeRate,
+		Memo:         in.Memo,
+		Payments:     []invoicePayment{},
+	}
+	i.Created, _ = ptypes.Timestamp(in.Created)
+	if in.Expires != nil {
+		expires, _ := ptypes.Timestamp(in.Expires)
+		i.Expires = &expires
+	}
+	for _, p := range in.Payments {
+		i.Payments = append(i.Payments, invoicePayment{p.Txid, p.Index, p.Value, p.Height, p.Confirmations})
+	}
+	return i
+}
+
+type GetFeePerByte struct{}
+
+var getFeePerByte GetFeePerByte
+
+func (x *GetFeePerByte) Execute(args []string) error {
 	client, conn, err := newGRPCClient()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	if len(args) <= 0 {
-		return errors.New("Sweep data is required")
+	var feeLevel pb.FeeLevel
+	userSelection := ""
+	if len(args) > 0 {
+		userSelection = args[0]
 	}
-	sweepInfo := new(pb.SweepInfo)
-	if err := jsonpb.UnmarshalString(args[0], sweepInfo); err != nil {
-		return err
+	switch strings.ToLower(userSelection) {
+	case "economic":
+		feeLevel = pb.FeeLevel_ECONOMIC
+	case "normal":
+		feeLevel = pb.FeeLevel_NORMAL
+	case "priority":
+		feeLevel = pb.FeeLevel_PRIORITY
+	default:
+		feeLevel = pb.FeeLevel_NORMAL
 	}
-	resp, err := client.SweepAddress(context.Background(), sweepInfo)
+	resp, err := client.GetFeePerByte(context.Background(), &pb.FeeLevelSelection{feeLevel})
 	if err != nil {
 		return err
 	}
-	fmt.Println(resp.Hash)
+	fmt.Println(resp.Fee)
 	return nil
 }
 
-type ReSyncBlockchain struct{}
+type Spend struct {
+	Inputs  string `long:"inputs" description:"a comma separated list of txid:index outpoints to spend instead of letting the wallet choose"`
+	Exclude string `long:"exclude" description:"a comma separated list of txid:index outpoints which must not be spent"`
+	URI     string `long:"uri" description:"a bitcoincash: payment URI to pay instead of an address and amount"`
+	Memo    string `long:"memo" description:"a memo saved with the transaction"`
+	FeeRate uint64 `long:"feerate" description:"the fee in satoshi per byte to pay instead of the fee level's"`
+	Fee     uint64 `long:"fee" description:"the total fee in satoshi to pay instead of the fee level's"`
+}
+
+var spend Spend
+
+func (x *Spend) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var feeLevel pb.FeeLevel
+	userSelection := ""
+	if x.URI != "" {
+		if x.Inputs != "" || x.Exclude != "" {
+			return errors.New("Inputs can't be chosen when paying a URI")
+		}
+		if x.FeeRate > 0 || x.Fee > 0 {
+			return errors.New("A fee can't be chosen when paying a URI")
+		}
+		if len(args) > 0 {
+			userSelection = args[0]
+		}
+	} else if len(args) > 2 {
+		userSelection = args[2]
+	} else if len(args) < 2 {
+		return errors.New("Address and amount are required")
+	}
+	switch strings.ToLower(userSelection) {
+	case "economic":
+		feeLevel = pb.FeeLevel_ECONOMIC
+	case "normal":
+		feeLevel = pb.FeeLevel_NORMAL
+	case "priority":
+		feeLevel = pb.FeeLevel_PRIORITY
+	default:
+		feeLevel = pb.FeeLevel_NORMAL
+	}
+	if x.URI != "" {
+		resp, err := client.PayURI(context.Background(), &pb.URIPayment{Uri: x.URI, FeeLevel: feeLevel})
+		if err != nil {
+			return err
+		}
+		if x.Memo != "" {
+			if _, err := client.SetTxMemo(context.Background(), &pb.TxMemo{Txid: resp.Hash, Memo: x.Memo}); err != nil {
+				return err
+			}
+		}
+		fmt.Println(resp.Hash)
+		return nil
+	}
+	amt, err := strconv.Atoi(args[1])
+	if err != nil {
+		return err
+	}
+	inputs, err := parseInputs(x.Inputs)
+	if err != nil {
+		return err
+	}
+	excluded, err := parseInputs(x.Exclude)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Spend(context.Background(), &pb.SpendInfo{
+		Address:       args[0],
+		Amount:        uint64(amt),
+		FeeLevel:      feeLevel,
+		Inputs:        inputs,
+		ExcludeInputs: excluded,
+		Memo:          x.Memo,
+		FeePerByte:    x.FeeRate,
+		Fee:           x.Fee,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+type SpendMany struct {
+	Inputs  string `long:"inputs" description:"a comma separated list of txid:index outpoints to spend instead of letting the wallet choose"`
+	Exclude string `long:"exclude" description:"a comma separated list of txid:index outpoints which must not be spent"`
+}
+
+var spendMany SpendMany
+
+func (x *SpendMany) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 1 {
+		return errors.New("A CSV file of payments is required")
+	}
+	var feeLevel pb.FeeLevel
+	userSelection := ""
+	if len(args) > 1 {
+		userSelection = args[1]
+	}
+	switch strings.ToLower(userSelection) {
+	case "economic":
+		feeLevel = pb.FeeLevel_ECONOMIC
+	case "normal":
+		feeLevel = pb.FeeLevel_NORMAL
+	case "priority":
+		feeLevel = pb.FeeLevel_PRIORITY
+	default:
+		feeLevel = pb.FeeLevel_NORMAL
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	payments, err := parsePayments(f)
+	if err != nil {
+		return err
+	}
+	inputs, err := parseInputs(x.Inputs)
+	if err != nil {
+		return err
+	}
+	excluded, err := parseInputs(x.Exclude)
+	if err != nil {
+		return err
+	}
+	resp, err := client.SpendMany(context.Background(), &pb.SpendManyInfo{
+		Payments:      payments,
+		FeeLevel:      feeLevel,
+		Inputs:        inputs,
+		ExcludeInputs: excluded,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+// parsePayments reads address,amount records. A first line of column names is
+// skipped.
+func parsePayments(r io.Reader) ([]*pb.Payment, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var payments []*pb.Payment
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "address") {
+			continue
+		}
+		amt, err := strconv.ParseUint(record[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid amount on line %d: %s", i+1, record[1])
+		}
+		payments = append(payments, &pb.Payment{Address: record[0], Amount: amt})
+	}
+	if len(payments) == 0 {
+		return nil, errors.New("No payments in file")
+	}
+	return payments, nil
+}
+
+// parseInputs parses a comma separated list of txid:index outpoints.
+func parseInputs(list string) ([]*pb.Input, error) {
+	var inputs []*pb.Input
+	if list == "" {
+		return inputs, nil
+	}
+	for _, s := range strings.Split(list, ",") {
+		parts := strings.Split(strings.TrimSpace(s), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("Invalid outpoint %s, expected txid:index", s)
+		}
+		index, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, &pb.Input{Txid: parts[0], Index: uint32(index)})
+	}
+	return inputs, nil
+}
+
+type CreateUnsignedTx struct{}
+
+var createUnsignedTx CreateUnsignedTx
+
+func (x *CreateUnsignedTx) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 2 {
+		return errors.New("Address and amount are required")
+	}
+	var feeLevel pb.FeeLevel
+	userSelection := ""
+	if len(args) > 2 {
+		userSelection = args[2]
+	}
+	switch strings.ToLower(userSelection) {
+	case "economic":
+		feeLevel = pb.FeeLevel_ECONOMIC
+	case "normal":
+		feeLevel = pb.FeeLevel_NORMAL
+	case "priority":
+		feeLevel = pb.FeeLevel_PRIORITY
+	default:
+		feeLevel = pb.FeeLevel_NORMAL
+	}
+	file := "unsigned.json"
+	if len(args) > 3 {
+		file = args[3]
+	}
+	amt, err := strconv.Atoi(args[1])
+	if err != nil {
+		return err
+	}
+	resp, err := client.CreateUnsignedTransaction(context.Background(), &pb.SpendInfo{Address: args[0], Amount: uint64(amt), FeeLevel: feeLevel})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(file, resp.Data, 0600); err != nil {
+		return err
+	}
+	fmt.Println(file)
+	return nil
+}
+
+type SignTx struct{}
+
+var signTx SignTx
+
+func (x *SignTx) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("File is required")
+	}
+	out := args[0]
+	if len(args) > 1 {
+		out = args[1]
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	resp, err := client.SignTransaction(context.Background(), &pb.PartiallySignedTx{data})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(out, resp.Data, 0600); err != nil {
+		return err
+	}
+	fmt.Println(out)
+	return nil
+}
+
+type FinalizeTx struct{}
+
+var finalizeTx FinalizeTx
+
+func (x *FinalizeTx) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("File is required")
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	resp, err := client.FinalizeAndBroadcast(context.Background(), &pb.PartiallySignedTx{data})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+type BumpFee struct{}
+
+var bumpFee BumpFee
+
+func (x *BumpFee) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("Txid is required")
+	}
+	resp, err := client.BumpFee(context.Background(), &pb.Txid{args[0]})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+type Peers struct{}
+
+var peers Peers
+
+func (x *Peers) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := client.Peers(context.Background(), &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	type peer struct {
+		Address         string    `json:"address"`
+		BytesSent       uint64    `json:"bytesSent"`
+		BytesReceived   uint64    `json:"bytesReceived"`
+		Connected       bool      `json:"connected"`
+		ID              int32     `json:"id"`
+		LastBlock       int32     `json:"lastBlock"`
+		ProtocolVersion uint32    `json:"protocolVersion"`
+		Services        string    `json:"services"`
+		UserAgent       string    `json:"userAgent"`
+		TimeConnected   time.Time `json:"timeConnected"`
+	}
+	var peers []peer
+	for _, p := range resp.Peers {
+		pi := peer{
+			Address:         p.Address,
+			BytesSent:       p.BytesSent,
+			BytesReceived:   p.BytesReceived,
+			Connected:       p.Connected,
+			ID:              p.ID,
+			LastBlock:       p.LastBlock,
+			ProtocolVersion: p.ProtocolVersion,
+			Services:        p.Services,
+			UserAgent:       p.UserAgent,
+			TimeConnected:   time.Unix(int64(p.TimeConnected.Seconds), int64(p.TimeConnected.Nanos)),
+		}
+		peers = append(peers, pi)
+	}
+	out, err := json.MarshalIndent(peers, "", "    ")
+	fmt.Println(string(out))
+	return nil
+}
+
+type AddWatchedScript struct{}
+
+var addWatchedScript AddWatchedScript
+
+func (x *AddWatchedScript) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("Address or script required")
+	}
+	_, err = client.AddWatchedAddress(context.Background(), &pb.Address{Addr: args[0]})
+	return err
+}
+
+type GetConfirmations struct{}
+
+var getConfirmations GetConfirmations
+
+func (x *GetConfirmations) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("Txid is required")
+	}
+	resp, err := client.GetConfirmations(context.Background(), &pb.Txid{args[0]})
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Confirmations)
+	return nil
+}
+
+type SweepAddress struct{}
+
+var sweepAddress SweepAddress
+
+func (x *SweepAddress) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("Sweep data is required")
+	}
+	sweepInfo := new(pb.SweepInfo)
+	if err := jsonpb.UnmarshalString(args[0], sweepInfo); err != nil {
+		return err
+	}
+	resp, err := client.SweepAddress(context.Background(), sweepInfo)
+	if err != nil {
+		return err
+	}
+	fmt.Println(resp.Hash)
+	return nil
+}
+
+type ReSyncBlockchain struct{}
 
 var reSyncBlockchain ReSyncBlockchain
 
@@ -993,7 +1762,7 @@ func (x *GetKey) Execute(args []string) error {
 	if len(args) <= 0 {
 		return errors.New("Address is required")
 	}
-	resp, err := client.GetKey(context.Background(), &pb.Address{args[0]})
+	resp, err := client.GetKey(context.Background(), &pb.Address{Addr: args[0]})
 	if err != nil {
 		return err
 	}
@@ -1016,11 +1785,173 @@ func (x *ListAddresses) Execute(args []string) error {
 		return err
 	}
 	for _, addr := range resp.Addresses {
-		fmt.Println(addr.Addr)
+		if addr.Label != "" {
+			fmt.Println(addr.Addr + "\t" + addr.Label)
+		} else {
+			fmt.Println(addr.Addr)
+		}
+	}
+	return nil
+}
+
+type SetLabel struct{}
+
+var setLabel SetLabel
+
+func (x *SetLabel) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 1 {
+		return errors.New("Address is required")
+	}
+	var label string
+	if len(args) > 1 {
+		label = args[1]
+	}
+	_, err = client.SetAddressLabel(context.Background(), &pb.AddressLabel{Addr: args[0], Label: label})
+	return err
+}
+
+type SetMemo struct{}
+
+var setMemo SetMemo
+
+func (x *SetMemo) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) < 1 {
+		return errors.New("Txid is required")
+	}
+	var memo string
+	if len(args) > 1 {
+		memo = args[1]
+	}
+	_, err = client.SetTxMemo(context.Background(), &pb.TxMemo{Txid: args[0], Memo: memo})
+	return err
+}
+
+type ListUnspent struct{}
+
+var listUnspent ListUnspent
+
+func (x *ListUnspent) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := client.ListUnspent(context.Background(), &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	return printUnspent(resp)
+}
+
+type LockUnspent struct{}
+
+var lockUnspent LockUnspent
+
+func (x *LockUnspent) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("Outpoint is required")
+	}
+	inputs, err := parseInputs(args[0])
+	if err != nil {
+		return err
+	}
+	for _, in := range inputs {
+		if _, err := client.LockUnspent(context.Background(), in); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+type UnlockUnspent struct{}
+
+var unlockUnspent UnlockUnspent
+
+func (x *UnlockUnspent) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if len(args) <= 0 {
+		return errors.New("Outpoint is required")
+	}
+	inputs, err := parseInputs(args[0])
+	if err != nil {
+		return err
+	}
+	for _, in := range inputs {
+		if _, err := client.UnlockUnspent(context.Background(), in); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ListLockedUnspent struct{}
+
+var listLockedUnspent ListLockedUnspent
+
+func (x *ListLockedUnspent) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := client.ListLockedUnspent(context.Background(), &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	return printUnspent(resp)
+}
+
+func printUnspent(resp *pb.UnspentList) error {
+	type Utxo struct {
+		Outpoint      string `json:"outpoint"`
+		Value         uint64 `json:"value"`
+		Address       string `json:"address"`
+		Confirmations uint32 `json:"confirmations"`
+		Account       uint32 `json:"account"`
+		Label         string `json:"label"`
+		WatchOnly     bool   `json:"watchOnly"`
+		Locked        bool   `json:"locked"`
+	}
+	utxos := []Utxo{}
+	for _, u := range resp.Utxos {
+		utxos = append(utxos, Utxo{
+			Outpoint:      u.Txid + ":" + strconv.Itoa(int(u.Index)),
+			Value:         u.Value,
+			Address:       u.Address,
+			Confirmations: u.Confirmations,
+			Account:       u.Account,
+			Label:         u.Label,
+			WatchOnly:     u.WatchOnly,
+			Locked:        u.Locked,
+		})
+	}
+	formatted, err := json.MarshalIndent(utxos, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(formatted))
+	return nil
+}
+
 type ListKeys struct{}
 
 var listKeys ListKeys
@@ -1068,3 +1999,138 @@ func (x *ImportKey) Execute(args []string) error {
 	_, err = client.ImportKey(context.Background(), &pb.ImportedKey{args[0], ts})
 	return err
 }
+
+type EncryptWallet struct{}
+
+var encryptWallet EncryptWallet
+
+func (x *EncryptWallet) Execute(args []string) error {
+	if len(args) <= 0 {
+		return errors.New("A passphrase is required")
+	}
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = client.EncryptWallet(context.Background(), &pb.Passphrase{args[0]})
+	return err
+}
+
+type Unlock struct{}
+
+var unlock Unlock
+
+func (x *Unlock) Execute(args []string) error {
+	if len(args) <= 0 {
+		return errors.New("A passphrase is required")
+	}
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = client.Unlock(context.Background(), &pb.Passphrase{args[0]})
+	return err
+}
+
+type Lock struct{}
+
+var lock Lock
+
+func (x *Lock) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = client.Lock(context.Background(), &pb.Empty{})
+	return err
+}
+
+type ChangePassphrase struct{}
+
+var changePassphrase ChangePassphrase
+
+func (x *ChangePassphrase) Execute(args []string) error {
+	if len(args) < 2 {
+		return errors.New("The old and new passphrases are required")
+	}
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = client.ChangePassphrase(context.Background(), &pb.PassphraseChange{args[0], args[1]})
+	return err
+}
+
+type Watch struct {
+	JSON bool `long:"json" description:"print each event as json"`
+}
+
+var watch Watch
+
+func (x *Watch) Execute(args []string) error {
+	client, conn, err := newGRPCClient()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := client.SubscribeEvents(context.Background(), &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	marshaler := jsonpb.Marshaler{}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if x.JSON {
+			out, err := marshaler.MarshalToString(event)
+			if err != nil {
+				return err
+			}
+			fmt.Println(out)
+			continue
+		}
+		fmt.Println(formatEvent(event))
+	}
+}
+
+// formatEvent returns a line describing an event.
+func formatEvent(event *pb.Event) string {
+	t, _ := ptypes.Timestamp(event.Timestamp)
+	line := t.Local().Format("2006-01-02 15:04:05") + " "
+	switch event.Type {
+	case pb.EventType_NEW_TRANSACTION, pb.EventType_CONFIRMATION:
+		verb := "new transaction"
+		if event.Type == pb.EventType_CONFIRMATION {
+			verb = "confirmed"
+		}
+		line += fmt.Sprintf("%s %s value %d height %d", verb, event.Tx.Txid, event.Tx.Value, event.Tx.Height)
+		if event.Tx.WatchOnly {
+			line += " (watch-only)"
+		}
+	case pb.EventType_NEW_TIP:
+		line += fmt.Sprintf("new block %s at height %d", event.Block.Hash, event.Block.Height)
+	case pb.EventType_REORG:
+		line += fmt.Sprintf("reorg from height %d to block %s at height %d", event.ReorgHeight, event.Block.Hash, event.Block.Height)
+	case pb.EventType_BALANCE:
+		line += fmt.Sprintf("balance %d confirmed, %d unconfirmed", event.Balances.Confirmed, event.Balances.Unconfirmed)
+	case pb.EventType_PEER_CONNECTED:
+		line += fmt.Sprintf("connected to %s %s, %d peers", event.Peer.Address, event.Peer.UserAgent, event.PeerCount)
+	case pb.EventType_PEER_DISCONNECTED:
+		line += fmt.Sprintf("disconnected from %s, %d peers", event.Peer.Address, event.PeerCount)
+	case pb.EventType_SYNC_PROGRESS:
+		if event.Sync.Synced {
+			line += fmt.Sprintf("synced at height %d", event.Sync.Height)
+		} else {
+			line += fmt.Sprintf("syncing, height %d of %d", event.Sync.Height, event.Sync.TargetHeight)
+		}
+	default:
+		line += event.Type.String()
+	}
+	return line
+}
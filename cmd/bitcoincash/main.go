@@ -6,23 +6,25 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"math/rand"
+	"math"
 	"net"
 	"net/url"
 	"os"
 	"os/signal"
 	"path"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	bc "github.com/BubbaJoe/spvwallet-cash"
 	"github.com/BubbaJoe/spvwallet-cash/api"
+	"github.com/BubbaJoe/spvwallet-cash/bip21"
 	"github.com/BubbaJoe/spvwallet-cash/cli"
 	"github.com/BubbaJoe/spvwallet-cash/db"
 	"github.com/BubbaJoe/spvwallet-cash/gui"
 	"github.com/BubbaJoe/spvwallet-cash/gui/bootstrap"
+	"github.com/BubbaJoe/spvwallet-cash/jsonrpc"
+	"github.com/BubbaJoe/spvwallet-cash/paymentprotocol"
 	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
 	"github.com/asticode/go-astilectron"
 	"github.com/asticode/go-astilog"
@@ -43,20 +45,34 @@ import (
 var parser = flags.NewParser(nil, flags.Default)
 
 type Start struct {
-	DataDir            string `short:"d" long:"datadir" description:"specify the data directory to be used"`
-	Testnet            bool   `short:"t" long:"testnet" description:"use the test network"`
-	Regtest            bool   `short:"r" long:"regtest" description:"run in regression test mode"`
-	Mnemonic           string `short:"m" long:"mnemonic" description:"specify a mnemonic seed to use to derive the keychain"`
-	WalletCreationDate string `short:"w" long:"walletcreationdate" description:"specify the date the seed was created. if omitted the wallet will sync from the oldest checkpoint."`
-	TrustedPeer        string `short:"i" long:"trustedpeer" description:"specify a single trusted peer to connect to"`
-	Tor                bool   `long:"tor" description:"connect via a running Tor daemon"`
-	FeeAPI             string `short:"f" long:"feeapi" description:"fee API to use to fetch current fee rates. set as empty string to disable API lookups." default:""`
-	MaxFee             uint64 `short:"x" long:"maxfee" description:"the fee-per-byte ceiling beyond which fees cannot go" default:"2000"`
-	LowDefaultFee      uint64 `short:"e" long:"economicfee" description:"the default low fee-per-byte" default:"20"`
-	MediumDefaultFee   uint64 `short:"n" long:"normalfee" description:"the default medium fee-per-byte" default:"90"`
-	HighDefaultFee     uint64 `short:"p" long:"priorityfee" description:"the default high fee-per-byte" default:"180"`
-	Gui                bool   `long:"gui" description:"launch an experimental GUI"`
-	Verbose            bool   `short:"v" long:"verbose" description:"print to standard out"`
+	DataDir            string   `short:"d" long:"datadir" description:"specify the data directory to be used"`
+	Testnet            bool     `short:"t" long:"testnet" description:"use the test network"`
+	Regtest            bool     `short:"r" long:"regtest" description:"run in regression test mode"`
+	Mnemonic           string   `short:"m" long:"mnemonic" description:"specify a mnemonic seed to use to derive the keychain"`
+	Xpub               string   `long:"xpub" description:"start a watch-only wallet from an account extended public key"`
+	Passphrase         string   `long:"passphrase" description:"specify the BIP39 passphrase used with the mnemonic seed. it is never saved so it must be given every time the wallet is started"`
+	WalletPassphrase   string   `long:"walletpassphrase" description:"unlock the wallet at startup with this passphrase. a wallet without a passphrase is encrypted with it"`
+	WalletCreationDate string   `short:"w" long:"walletcreationdate" description:"specify the date the seed was created. if omitted the wallet will sync from the oldest checkpoint."`
+	TrustedPeer        string   `short:"i" long:"trustedpeer" description:"specify a single trusted peer to connect to"`
+	Tor                bool     `long:"tor" description:"connect via a running Tor daemon"`
+	CompactFilters     bool     `long:"compactfilters" description:"sync using BIP157 compact block filters instead of bloom filters"`
+	FeeAPI             string   `short:"f" long:"feeapi" description:"fee API to use to fetch current fee rates. set as empty string to disable API lookups." default:""`
+	StaticFees         bool     `long:"staticfees" description:"always use the default fees instead of working them out from the exchange rate"`
+	MaxFee             uint64   `short:"x" long:"maxfee" description:"the fee-per-byte ceiling beyond which fees cannot go" default:"5"`
+	LowDefaultFee      uint64   `short:"e" long:"economicfee" description:"the default low fee-per-byte" default:"1"`
+	MediumDefaultFee   uint64   `short:"n" long:"normalfee" description:"the default medium fee-per-byte" default:"1"`
+	HighDefaultFee     uint64   `short:"p" long:"priorityfee" description:"the default high fee-per-byte" default:"2"`
+	RPCListen          string   `long:"rpclisten" description:"the address the API listens on. its certificate and cookie files are saved in the data directory" default:"127.0.0.1:8234"`
+	RPCTLSHosts        []string `long:"rpctlshost" description:"an additional hostname or IP address to make the API's TLS certificate for"`
+	RESTListen         string   `long:"restlisten" description:"the address the HTTP/JSON gateway to the API listens on. set it empty to turn the gateway off" default:"127.0.0.1:8235"`
+	JSONRPCListen      string   `long:"jsonrpclisten" description:"the address to serve the bitcoind-compatible JSON-RPC interface on. it is off unless set"`
+	JSONRPCUser        string   `long:"jsonrpcuser" description:"the username of the JSON-RPC interface"`
+	JSONRPCPass        string   `long:"jsonrpcpass" description:"the password of the JSON-RPC interface"`
+	WebhookURL         string   `long:"webhookurl" description:"the URL to POST a webhook to for each payment received. webhooks are off unless set"`
+	WebhookSecret      string   `long:"webhooksecret" description:"the key each webhook is signed with using HMAC-SHA256"`
+	WebhookConfirms    []uint32 `long:"webhookconfirmations" description:"a number of confirmations at which to send a payment.confirmed webhook. may be given more than once" default:"1" default:"6"`
+	Gui                bool     `long:"gui" description:"launch an experimental GUI"`
+	Verbose            bool     `short:"v" long:"verbose" description:"print to standard out"`
 }
 type Version struct{}
 
@@ -125,6 +141,8 @@ func (x *Start) Execute(args []string) error {
 	if x.Mnemonic != "" {
 		config.Mnemonic = x.Mnemonic
 	}
+	config.MnemonicPassphrase = x.Passphrase
+	config.ExtendedPublicKey = x.Xpub
 	if x.TrustedPeer != "" {
 		addr, err := net.ResolveTCPAddr("tcp", x.TrustedPeer)
 		if err != nil {
@@ -147,6 +165,9 @@ func (x *Start) Execute(args []string) error {
 		}
 		config.Proxy = dialer
 	}
+	if x.CompactFilters {
+		config.SyncMode = bc.CompactFilterSync
+	}
 	if x.FeeAPI != "" {
 		u, err := url.Parse(x.FeeAPI)
 		if err != nil {
@@ -161,6 +182,13 @@ func (x *Start) Execute(args []string) error {
 		config.MediumFee = x.MediumDefaultFee
 		config.HighFee = x.HighDefaultFee
 	}
+	if x.StaticFees && x.FeeAPI == "" {
+		config.FeeSource = &bc.StaticFeeSource{Fees: bc.Fees{
+			FastestFee:  config.HighFee,
+			HalfHourFee: config.MediumFee,
+			HourFee:     config.LowFee,
+		}}
+	}
 
 	// Make the logging a little prettier
 	var fileLogFormat = logging.MustStringFormatter(`%{time:15:04:05.000} [%{shortfunc}] [%{level}] %{message}`)
@@ -184,9 +212,11 @@ func (x *Start) Execute(args []string) error {
 	sqliteDatastore, _ := db.Create(config.RepoPath)
 	config.DB = sqliteDatastore
 
-	mn, _ := sqliteDatastore.GetMnemonic()
-	if mn != "" {
-		config.Mnemonic = mn
+	if config.ExtendedPublicKey == "" {
+		mn, _ := sqliteDatastore.GetMnemonic()
+		if mn != "" {
+			config.Mnemonic = mn
+		}
 	}
 
 	// Write version file
@@ -298,16 +328,60 @@ func (x *Start) Execute(args []string) error {
 		return err
 	}
 
-	if err := sqliteDatastore.SetMnemonic(config.Mnemonic); err != nil {
-		log.Error(err)
-		return err
+	if x.WalletPassphrase != "" {
+		if err := cashWallet.Unlock(x.WalletPassphrase); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	if !sqliteDatastore.IsEncrypted() && config.ExtendedPublicKey == "" {
+		if err := sqliteDatastore.SetMnemonic(config.Mnemonic); err != nil {
+			log.Error(err)
+			return err
+		}
 	}
 	if err := sqliteDatastore.SetCreationDate(config.CreationDate); err != nil {
 		log.Error(err)
 		return err
 	}
 
-	go api.ServeAPI(cashWallet)
+	go func() {
+		apiConfig := api.Config{
+			ListenAddr: x.RPCListen,
+			Dir:        basepath,
+			TLSHosts:   x.RPCTLSHosts,
+
+			GatewayListenAddr: x.RESTListen,
+		}
+		if err := api.ServeAPI(cashWallet, apiConfig); err != nil {
+			log.Error(err)
+		}
+	}()
+	if x.JSONRPCListen != "" {
+		go func() {
+			jsonrpcConfig := jsonrpc.Config{
+				ListenAddr: x.JSONRPCListen,
+				User:       x.JSONRPCUser,
+				Password:   x.JSONRPCPass,
+			}
+			if err := jsonrpc.ListenAndServe(cashWallet, jsonrpcConfig); err != nil {
+				log.Error(err)
+			}
+		}()
+	}
+
+	if x.WebhookURL != "" {
+		webhookConfig := bc.WebhookConfig{
+			URL:           x.WebhookURL,
+			Secret:        x.WebhookSecret,
+			Confirmations: x.WebhookConfirms,
+		}
+		if _, err := cashWallet.StartWebhooks(webhookConfig); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
 
 	// Start it!
 	printSplashScreen()
@@ -402,6 +476,8 @@ func (x *Start) Execute(args []string) error {
 						Amount   float64 `json:"amount"`
 						Note     string  `json:"note"`
 						FeeLevel string  `json:"feeLevel"`
+						FeeRate  uint64  `json:"feeRate"`
+						URI      string  `json:"uri"`
 					}
 					var p P
 					if err := json.Unmarshal(m.Payload, &p); err != nil {
@@ -419,16 +495,48 @@ func (x *Start) Execute(args []string) error {
 					default:
 						feeLevel = wallet.NORMAL
 					}
+					// A fee rate typed into the form is paid instead of the fee level's
+					opts := bc.SpendOptions{FeePerByte: p.FeeRate, Memo: p.Note}
+					// A payment protocol URI is shown in place of the address and is
+					// paid to the merchant
+					if p.URI != "" && p.Address == p.URI {
+						payURI(w, p.URI, int64(math.Round(p.Amount)), feeLevel, opts)
+						return
+					}
 					addr, err := bchutil.DecodeAddress(p.Address, cashWallet.Params())
 					if err != nil {
 						w.SendMessage(bootstrap.MessageOut{Name: "spendError", Payload: "Invalid address"})
 						return
 					}
-					_, err = cashWallet.Spend(int64(p.Amount), addr, feeLevel, strconv.Itoa(rand.Int()))
+					// Pay the URI which filled the form, unless the address or amount
+					// were edited, so its op_return_raw is included
+					if u, err := bip21.Parse(p.URI, cashWallet.Params()); err == nil && u.Address != nil &&
+						u.Address.String() == addr.String() && u.Amount == int64(math.Round(p.Amount)) {
+						payURI(w, p.URI, u.Amount, feeLevel, opts)
+						return
+					}
+					_, err = cashWallet.SpendWithOptions(int64(p.Amount), addr, feeLevel, opts)
 
 					if err != nil {
 						w.SendMessage(bootstrap.MessageOut{Name: "spendError", Payload: err.Error()})
 					}
+				case "parseURI":
+					type P struct {
+						URI string `json:"uri"`
+					}
+					var p P
+					if err := json.Unmarshal(m.Payload, &p); err != nil {
+						astilog.Errorf("Unmarshaling %s failed", m.Payload)
+						return
+					}
+					sendPaymentURI(w, p.URI)
+				case "pasteURI":
+					s, err := clipboard.ReadAll()
+					if err != nil {
+						w.SendMessage(bootstrap.MessageOut{Name: "spendError", Payload: err.Error()})
+						return
+					}
+					sendPaymentURI(w, strings.TrimSpace(s))
 				case "clipboard":
 					type P struct {
 						Data string `json:"data"`
@@ -531,8 +639,9 @@ func (x *Start) Execute(args []string) error {
 					cashWallet.ReSyncBlockchain(t)
 				case "restore":
 					type P struct {
-						Mnemonic string `json:"mnemonic"`
-						Date     string `json:"date"`
+						Mnemonic   string `json:"mnemonic"`
+						Passphrase string `json:"passphrase"`
+						Date       string `json:"date"`
 					}
 					var p P
 					if err := json.Unmarshal(m.Payload, &p); err != nil {
@@ -550,6 +659,7 @@ func (x *Start) Execute(args []string) error {
 					sqliteDatastore, _ := db.Create(config.RepoPath)
 					config.DB = sqliteDatastore
 					config.Mnemonic = p.Mnemonic
+					config.MnemonicPassphrase = p.Passphrase
 					config.CreationDate = t
 					cashWallet, err = bc.NewSPVWallet(config)
 					if err != nil {
@@ -588,7 +698,12 @@ func (x *Start) Execute(args []string) error {
 						rc <- 649
 					}()
 				case "getMnemonic":
-					w.SendMessage(bootstrap.MessageOut{Name: "mnemonic", Payload: cashWallet.Mnemonic()})
+					mnemonic, err := cashWallet.Mnemonic()
+					if err != nil {
+						w.SendMessage(bootstrap.MessageOut{Name: "mnemonicError", Payload: err.Error()})
+						return
+					}
+					w.SendMessage(bootstrap.MessageOut{Name: "mnemonic", Payload: mnemonic})
 				}
 			},
 			RestoreAssets: gui.RestoreAssets,
@@ -621,6 +736,85 @@ func (x *Start) Execute(args []string) error {
 	return nil
 }
 
+// shownRequest is the payment protocol request last shown in the send form. It
+// is the one paid so the merchant can't change the payment once the user has
+// seen it.
+var shownRequest struct {
+	sync.Mutex
+	uri string
+	pr  *paymentprotocol.PaymentRequest
+}
+
+// payURI pays a payment URI from the send form with opts. A payment protocol
+// URI pays the request shown for it, which must still be for amount.
+func payURI(w *astilectron.Window, uri string, amount int64, feeLevel wallet.FeeLevel, opts bc.SpendOptions) {
+	u, err := bip21.Parse(uri, cashWallet.Params())
+	if err == nil && u.PaymentURL != "" {
+		shownRequest.Lock()
+		pr := shownRequest.pr
+		if shownRequest.uri != uri {
+			pr = nil
+		}
+		shownRequest.Unlock()
+		switch {
+		case pr == nil:
+			err = errors.New("Payment request wasn't shown, enter the payment URI again")
+		case pr.Amount() != amount:
+			err = errors.New("Payment request changed, enter the payment URI again")
+		default:
+			_, _, err = cashWallet.PayPaymentRequestWithOptions(pr, feeLevel, opts)
+		}
+	} else {
+		_, err = cashWallet.PayURIWithOptions(uri, feeLevel, opts)
+	}
+	if err != nil {
+		w.SendMessage(bootstrap.MessageOut{Name: "spendError", Payload: err.Error()})
+	}
+}
+
+// sendPaymentURI sends the fields of a payment URI to the GUI to fill the send
+// form, or an error if it can't be parsed. For a payment protocol URI the
+// request is fetched and the URI is shown as the address.
+func sendPaymentURI(w *astilectron.Window, uri string) {
+	u, err := bip21.Parse(uri, cashWallet.Params())
+	if err != nil {
+		w.SendMessage(bootstrap.MessageOut{Name: "spendError", Payload: "Invalid payment URI: " + err.Error()})
+		return
+	}
+	type P struct {
+		URI     string `json:"uri"`
+		Address string `json:"address"`
+		Amount  int64  `json:"amount"`
+		Label   string `json:"label"`
+		Message string `json:"message"`
+	}
+	if u.PaymentURL != "" {
+		pr, err := cashWallet.FetchPaymentRequest(u.PaymentURL)
+		if err != nil {
+			w.SendMessage(bootstrap.MessageOut{Name: "spendError", Payload: "Invalid payment request: " + err.Error()})
+			return
+		}
+		shownRequest.Lock()
+		shownRequest.uri, shownRequest.pr = uri, pr
+		shownRequest.Unlock()
+		w.SendMessage(bootstrap.MessageOut{Name: "paymentURI", Payload: P{
+			URI:     uri,
+			Address: uri,
+			Amount:  pr.Amount(),
+			Label:   u.Label,
+			Message: pr.Memo,
+		}})
+		return
+	}
+	w.SendMessage(bootstrap.MessageOut{Name: "paymentURI", Payload: P{
+		URI:     uri,
+		Address: u.Address.String(),
+		Amount:  u.Amount,
+		Label:   u.Label,
+		Message: u.Message,
+	}})
+}
+
 func printSplashScreen() {
 	blue := color.New(color.FgBlue)
 	white := color.New(color.FgWhite)
@@ -0,0 +1,232 @@
+package bitcoincash
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+	bch "github.com/gcash/bchutil"
+	"github.com/gcash/bchutil/coinset"
+	hd "github.com/gcash/bchutil/hdkeychain"
+)
+
+// SpendOptions control how a spend picks its coins and what else it pays. The
+// zero value spends from the default account and lets the coin selector choose
+// among all its coins.
+type SpendOptions struct {
+	// The account to spend from and send the change to.
+	Account uint32
+
+	// If set, exactly these outpoints are spent instead of letting the coin
+	// selector choose. They must all belong to the account.
+	Inputs []wire.OutPoint
+
+	// Outpoints the coin selector must never spend.
+	ExcludeInputs []wire.OutPoint
+
+	// If set, an OP_RETURN output with these data pushes is added to the
+	// transaction.
+	Data [][]byte
+
+	// If set, the fee rate in satoshis per byte paid instead of the rate of
+	// the fee level. It must be between the minimum relay fee and MaxFee.
+	FeePerByte uint64
+
+	// If set, the total fee in satoshis paid instead of a fee worked out from
+	// the size of the transaction. Change which would be dust is added to it.
+	// FeePerByte must not also be set.
+	Fee int64
+
+	// If set, the signature type used instead of the one from the config.
+	SignatureType SignatureType
+
+	// If set, saved as the transaction's memo once it is broadcast. Failing to
+	// save it is only logged as the payment has already been sent.
+	Memo string
+}
+
+// UnspentOutput is a coin of the wallet as returned by ListUnspent.
+type UnspentOutput struct {
+	Op    wire.OutPoint
+	Value int64
+
+	// The output script, Address is nil if it isn't a standard script.
+	ScriptPubkey []byte
+	Address      bch.Address
+
+	Confirmations uint32
+	Account       uint32
+	WatchOnly     bool
+
+	// Locked outputs are never spent, see LockUnspent.
+	Locked bool
+
+	// The label given to Address, empty if it has none.
+	Label string
+}
+
+// ListUnspent returns the unspent outputs of every account including those
+// of watched scripts.
+func (w *SPVWallet) ListUnspent() ([]UnspentOutput, error) {
+	utxos, err := w.txstore.Utxos().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	height, _ := w.blockchain.db.Height()
+	locked := w.lockedUtxos()
+	labels := w.AddressLabels()
+	ret := make([]UnspentOutput, 0, len(utxos))
+	for _, u := range utxos {
+		// A utxo above our tip was seen in a block we haven't synced yet
+		var confirmations uint32
+		if u.AtHeight > 0 && uint32(u.AtHeight) <= height {
+			confirmations = height - uint32(u.AtHeight) + 1
+		}
+		// Non standard watched scripts have no address
+		var label string
+		addr, err := w.ScriptToAddress(u.ScriptPubkey)
+		if err == nil {
+			label = labels[addr.String()]
+		}
+		ret = append(ret, UnspentOutput{
+			Op:            u.Op,
+			Value:         u.Value,
+			ScriptPubkey:  u.ScriptPubkey,
+			Address:       addr,
+			Confirmations: confirmations,
+			Account:       w.txstore.accountForScript(u.ScriptPubkey),
+			WatchOnly:     u.WatchOnly,
+			Locked:        locked[u.Op],
+			Label:         label,
+		})
+	}
+	return ret, nil
+}
+
+// ErrLocksNotSupported is returned when locking a utxo in a datastore which
+// can't save locks.
+var ErrLocksNotSupported = errors.New("Datastore does not support locking utxos")
+
+// utxoLockStore is implemented by datastores which can save locked utxos. It is
+// implemented by db.SQLiteDatastore.
+type utxoLockStore interface {
+	LockUtxo(op wire.OutPoint) error
+	UnlockUtxo(op wire.OutPoint) error
+	GetLockedUtxos() ([]wire.OutPoint, error)
+}
+
+// LockUnspent freezes an unspent output of the wallet so no spend selects it
+// until it is unlocked. The lock is saved in the datastore and its value is
+// reported by LockedBalance instead of Balance.
+func (w *SPVWallet) LockUnspent(op wire.OutPoint) error {
+	store, ok := w.txstore.Datastore.(utxoLockStore)
+	if !ok {
+		return ErrLocksNotSupported
+	}
+	utxos, err := w.txstore.Utxos().GetAll()
+	if err != nil {
+		return err
+	}
+	for _, u := range utxos {
+		if u.Op == op {
+			return store.LockUtxo(op)
+		}
+	}
+	return fmt.Errorf("Outpoint %s is not an unspent output of the wallet", op)
+}
+
+func (w *SPVWallet) UnlockUnspent(op wire.OutPoint) error {
+	store, ok := w.txstore.Datastore.(utxoLockStore)
+	if !ok {
+		return ErrLocksNotSupported
+	}
+	return store.UnlockUtxo(op)
+}
+
+// ListLockedUnspent returns the locked outputs which are still unspent.
+func (w *SPVWallet) ListLockedUnspent() ([]UnspentOutput, error) {
+	utxos, err := w.ListUnspent()
+	if err != nil {
+		return nil, err
+	}
+	var ret []UnspentOutput
+	for _, u := range utxos {
+		if u.Locked {
+			ret = append(ret, u)
+		}
+	}
+	return ret, nil
+}
+
+// LockedBalance returns the value of the locked outputs, which is left out of
+// Balance.
+func (w *SPVWallet) LockedBalance() (int64, error) {
+	utxos, err := w.ListLockedUnspent()
+	if err != nil {
+		return 0, err
+	}
+	var balance int64
+	for _, u := range utxos {
+		if !u.WatchOnly {
+			balance += u.Value
+		}
+	}
+	return balance, nil
+}
+
+// lockedUtxos returns the outpoints locked with LockUnspent.
+func (w *SPVWallet) lockedUtxos() map[wire.OutPoint]bool {
+	m := make(map[wire.OutPoint]bool)
+	store, ok := w.txstore.Datastore.(utxoLockStore)
+	if !ok {
+		return m
+	}
+	ops, err := store.GetLockedUtxos()
+	if err != nil {
+		log.Error(err)
+	}
+	for _, op := range ops {
+		m[op] = true
+	}
+	return m
+}
+
+// SpendWithOptions is like Spend but lets the caller choose which coins are
+// spent.
+func (w *SPVWallet) SpendWithOptions(amount int64, addr bch.Address, feeLevel wallet.FeeLevel, opts SpendOptions) (*chainhash.Hash, error) {
+	return w.SpendMany(payment(addr, amount), feeLevel, opts)
+}
+
+// filterCoins removes the coins opts don't allow to be spent. An error is
+// returned if a chosen input isn't one of the coins.
+func filterCoins(coins map[coinset.Coin]*hd.ExtendedKey, opts SpendOptions) (map[coinset.Coin]*hd.ExtendedKey, error) {
+	if len(opts.Inputs) == 0 && len(opts.ExcludeInputs) == 0 {
+		return coins, nil
+	}
+	excluded := make(map[wire.OutPoint]bool)
+	for _, op := range opts.ExcludeInputs {
+		excluded[op] = true
+	}
+	included := make(map[wire.OutPoint]bool)
+	for _, op := range opts.Inputs {
+		if excluded[op] {
+			return nil, fmt.Errorf("Input %s is both included and excluded", op)
+		}
+		included[op] = true
+	}
+	m := make(map[coinset.Coin]*hd.ExtendedKey)
+	for c, key := range coins {
+		op := *wire.NewOutPoint(c.Hash(), c.Index())
+		if excluded[op] || (len(opts.Inputs) > 0 && !included[op]) {
+			continue
+		}
+		m[c] = key
+		delete(included, op)
+	}
+	for op := range included {
+		return nil, fmt.Errorf("Input %s is not a spendable coin of the account", op)
+	}
+	return m, nil
+}
@@ -0,0 +1,290 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+// addTestUtxos pays each value to a fresh key of the default account and
+// returns the outpoints.
+func addTestUtxos(t *testing.T, w *SPVWallet, values ...int64) []wire.OutPoint {
+	h, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ops []wire.OutPoint
+	for i, value := range values {
+		key, err := w.keyManager.GetFreshKey(wallet.EXTERNAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := key.Address(w.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		script, err := w.AddressToScript(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		op := wire.NewOutPoint(h, uint32(i))
+		err = w.txstore.Utxos().Put(wallet.Utxo{Op: *op, ScriptPubkey: script, AtHeight: 5, Value: value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.keyManager.MarkKeyAsUsed(addr.ScriptAddress()); err != nil {
+			t.Fatal(err)
+		}
+		ops = append(ops, *op)
+	}
+	return ops
+}
+
+func spentOutpoints(tx *wire.MsgTx) map[wire.OutPoint]bool {
+	m := make(map[wire.OutPoint]bool)
+	for _, in := range tx.TxIn {
+		m[in.PreviousOutPoint] = true
+	}
+	return m
+}
+
+func TestSPVWallet_ListUnspent(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	ops := addTestUtxos(t, w, 10000, 20000)
+
+	utxos, err := w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 2 {
+		t.Fatalf("Expected 2 utxos, got %d", len(utxos))
+	}
+	height, _ := w.blockchain.db.Height()
+	for _, u := range utxos {
+		if u.Op != ops[0] && u.Op != ops[1] {
+			t.Errorf("Returned unknown outpoint %s", u.Op)
+		}
+		if u.Confirmations != height-5+1 {
+			t.Errorf("Returned %d confirmations, expected %d", u.Confirmations, height-4)
+		}
+		if u.Address == nil || !w.HasKey(u.Address) {
+			t.Error("Returned the wrong address")
+		}
+		if script, err := w.AddressToScript(u.Address); err != nil || !bytes.Equal(script, u.ScriptPubkey) {
+			t.Error("Returned the wrong script")
+		}
+		if u.Account != DefaultAccount || u.WatchOnly {
+			t.Error("Returned the wrong account or watch-only flag")
+		}
+	}
+
+	// A utxo from a block above our tip has no confirmations yet
+	script, err := w.AddressToScript(utxos[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ahead := wallet.Utxo{Op: wire.OutPoint{Hash: ops[0].Hash, Index: 2}, ScriptPubkey: script, AtHeight: int32(height) + 10, Value: 30000}
+	if err := w.txstore.Utxos().Put(ahead); err != nil {
+		t.Fatal(err)
+	}
+	utxos, err = w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range utxos {
+		if u.Op == ahead.Op && u.Confirmations != 0 {
+			t.Errorf("Returned %d confirmations for a utxo above the tip", u.Confirmations)
+		}
+	}
+}
+
+func Test_buildTxWithInputs(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	ops := addTestUtxos(t, w, 1000000, 2000000, 3000000)
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+
+	// Only the chosen inputs are spent, all of them
+	tx, err := w.buildTx(SpendOptions{Inputs: ops[:2]}, payment(addr, 10000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spent := spentOutpoints(tx)
+	if len(spent) != 2 || !spent[ops[0]] || !spent[ops[1]] {
+		t.Error("Didn't spend the chosen inputs")
+	}
+
+	// The coin selector prefers the largest coin unless it is excluded
+	tx, err = w.buildTx(SpendOptions{ExcludeInputs: ops[2:]}, payment(addr, 10000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spentOutpoints(tx)[ops[2]] {
+		t.Error("Spent an excluded input")
+	}
+
+	// The chosen inputs must cover the amount
+	if _, err := w.buildTx(SpendOptions{Inputs: ops[:1]}, payment(addr, 2000000), wallet.NORMAL); err == nil {
+		t.Error("Spent more than the chosen inputs")
+	}
+}
+
+func Test_filterCoins(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	ops := addTestUtxos(t, w, 10000, 20000)
+	coins := w.gatherCoins(DefaultAccount)
+
+	filtered, err := filterCoins(coins, SpendOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filtered) != 2 {
+		t.Error("Filtered coins without options")
+	}
+	filtered, err = filterCoins(coins, SpendOptions{ExcludeInputs: ops[:1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for c := range filtered {
+		if *wire.NewOutPoint(c.Hash(), c.Index()) == ops[0] {
+			t.Error("Returned an excluded coin")
+		}
+	}
+	if len(filtered) != 1 {
+		t.Errorf("Returned %d coins, expected 1", len(filtered))
+	}
+
+	unknown := *wire.NewOutPoint(&chainhash.Hash{}, 0)
+	if _, err := filterCoins(coins, SpendOptions{Inputs: []wire.OutPoint{unknown}}); err == nil {
+		t.Error("Accepted an unknown input")
+	}
+	if _, err := filterCoins(coins, SpendOptions{Inputs: ops[:1], ExcludeInputs: ops[:1]}); err == nil {
+		t.Error("Accepted an input which is both included and excluded")
+	}
+}
+
+func TestSPVWallet_LockUnspent(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	tx := payTo(t, w.CurrentAddress(wallet.EXTERNAL), 1000000)
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	op := *wire.NewOutPoint(&chainhash.Hash{}, 0)
+	if err := w.LockUnspent(op); err == nil {
+		t.Error("Locked an unknown outpoint")
+	}
+
+	txid := tx.TxHash()
+	op = *wire.NewOutPoint(&txid, 0)
+	if err := w.LockUnspent(op); err != nil {
+		t.Fatal(err)
+	}
+	if confirmed, unconfirmed := w.Balance(); confirmed+unconfirmed != 0 {
+		t.Errorf("Balance is %d, expected 0", confirmed+unconfirmed)
+	}
+	locked, err := w.LockedBalance()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locked != 1000000 {
+		t.Errorf("Locked balance is %d, expected 1000000", locked)
+	}
+	if len(w.gatherCoins(DefaultAccount)) != 0 {
+		t.Error("Gathered a locked coin")
+	}
+	utxos, err := w.ListLockedUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].Op != op || !utxos[0].Locked {
+		t.Error("Returned the wrong locked utxos")
+	}
+
+	if err := w.UnlockUnspent(op); err != nil {
+		t.Fatal(err)
+	}
+	if confirmed, unconfirmed := w.Balance(); confirmed+unconfirmed != 1000000 {
+		t.Errorf("Balance is %d, expected 1000000", confirmed+unconfirmed)
+	}
+	if len(w.gatherCoins(DefaultAccount)) != 1 {
+		t.Error("Didn't gather the unlocked coin")
+	}
+}
+
+func Test_buildTxWithFee(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	addTestUtxos(t, w, 1000000)
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+	fee := func(tx *wire.MsgTx) int64 {
+		paid := int64(1000000)
+		for _, out := range tx.TxOut {
+			paid -= out.Value
+		}
+		return paid
+	}
+
+	// A fee rate is paid in place of the fee level's
+	low, err := w.buildTx(SpendOptions{FeePerByte: 1}, payment(addr, 10000), wallet.PRIOIRTY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	high, err := w.buildTx(SpendOptions{FeePerByte: 4}, payment(addr, 10000), wallet.ECONOMIC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee(low) <= 0 || fee(high) != fee(low)*4 {
+		t.Errorf("Paid fees of %d and %d at 1 and 4 sat/byte", fee(low), fee(high))
+	}
+
+	// An exact fee is paid whatever the size of the transaction
+	tx, err := w.buildTx(SpendOptions{Fee: 1500}, payment(addr, 10000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee(tx) != 1500 || len(tx.TxOut) != 2 {
+		t.Errorf("Paid a fee of %d with %d outputs, expected 1500 with change", fee(tx), len(tx.TxOut))
+	}
+
+	tests := []struct {
+		opts SpendOptions
+		err  error
+	}{
+		{SpendOptions{FeePerByte: 11}, ErrFeeTooHigh},
+		{SpendOptions{Fee: 10}, ErrFeeTooLow},
+		{SpendOptions{Fee: 100000}, ErrFeeTooHigh},
+	}
+	for _, test := range tests {
+		if _, err := w.buildTx(test.opts, payment(addr, 10000), wallet.NORMAL); err != test.err {
+			t.Errorf("Expected %v for %+v, got %v", test.err, test.opts, err)
+		}
+	}
+	if _, err := w.buildTx(SpendOptions{FeePerByte: 2, Fee: 1500}, payment(addr, 10000), wallet.NORMAL); err == nil {
+		t.Error("Built a transaction with both a fee rate and a fee")
+	}
+	if _, err := w.buildTx(SpendOptions{Fee: -1}, payment(addr, 10000), wallet.NORMAL); err == nil {
+		t.Error("Built a transaction with a negative fee")
+	}
+
+	// The fee level's rate isn't looked up when the fee is chosen
+	source := &mockFeeSource{fees: &Fees{FastestFee: 5, HalfHourFee: 2, HourFee: 1}}
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, source)
+	for _, opts := range []SpendOptions{{FeePerByte: 3}, {Fee: 1500}} {
+		if _, err := w.buildTx(opts, payment(addr, 10000), wallet.NORMAL); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if source.lookups != 0 {
+		t.Errorf("Looked up fees %d times for spends with a chosen fee", source.lookups)
+	}
+}
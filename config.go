@@ -22,6 +22,15 @@ type Config struct {
 	// Bip39 mnemonic string. If empty a new mnemonic will be created.
 	Mnemonic string
 
+	// Optional Bip39 passphrase used with the mnemonic to derive the seed. It is only
+	// held in memory and must be supplied every time the wallet is started.
+	MnemonicPassphrase string
+
+	// An account level (m/44'/145'/0') extended public key. If set the wallet is watch-only:
+	// it tracks the account's addresses and can create unsigned transactions but holds no
+	// private keys. Mnemonic must be empty.
+	ExtendedPublicKey string
+
 	// The date the wallet was created.
 	// If before the earliest checkpoint the chain will be synced using the earliest checkpoint.
 	CreationDate time.Time
@@ -49,12 +58,17 @@ type Config struct {
 	// The highest allowable fee-per-byte
 	MaxFee uint64
 
-	// External API to query to look up fees. If this field is nil then the default fees will be used.
-	// If the API is unreachable then the default fees will likewise be used. If the API returns a fee
-	// greater than MaxFee then the MaxFee will be used in place. The API response must be formatted as
+	// External API to query to look up fees. If this field is empty then fees are worked out from the
+	// exchange rate, or the default fees are used if exchange rates are disabled. If the API is
+	// unreachable then the default fees will likewise be used. If the API returns a fee greater than
+	// MaxFee then the MaxFee will be used in place. The API response must be formatted as
 	// { "fastestFee": 40, "halfHourFee": 20, "hourFee": 10 }
+	// Requests are made through the Proxy if one is set.
 	FeeAPI url.URL
 
+	// A source to look up fees from instead of the FeeAPI or exchange rate.
+	FeeSource FeeSource
+
 	// A logger. You can write the logs to file or stdout or however else you want.
 	Logger logging.Backend
 
@@ -63,6 +77,15 @@ type Config struct {
 
 	// Disable exchange rate provider
 	DisableExchangeRates bool
+
+	// The signature algorithm used to sign P2PKH and multisig inputs. Defaults to ECDSA.
+	// Schnorr signatures are smaller so transactions signed with them pay lower fees.
+	SignatureType SignatureType
+
+	// How to find the wallet's transactions in new blocks. Defaults to BIP37 bloom filters.
+	// CompactFilterSync keeps our addresses private from peers but requires peers serving
+	// BIP157 compact filters and does not detect unconfirmed transactions.
+	SyncMode SyncMode
 }
 
 func NewDefaultConfig() *Config {
@@ -71,7 +94,6 @@ func NewDefaultConfig() *Config {
 	if os.IsNotExist(ferr) {
 		os.Mkdir(repoPath, os.ModePerm)
 	}
-	feeApi, _ := url.Parse("https://bitcoinfees.21.co/api/v1/fees/recommended")
 	return &Config{
 		Params:    &chaincfg.MainNetParams,
 		UserAgent: "spvwallet-cash",
@@ -80,7 +102,6 @@ func NewDefaultConfig() *Config {
 		MediumFee: 1,
 		HighFee:   2,
 		MaxFee:    5,
-		FeeAPI:    *feeApi,
 		Logger:    logging.NewLogBackend(os.Stdout, "", 0),
 	}
 }
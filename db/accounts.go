@@ -0,0 +1,98 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+// allAccounts is the account of the default utxo and transaction views. They
+// return the rows of every account so the wallet can track all of them.
+const allAccounts = -1
+
+// Account is a BIP44 account created in addition to the default account 0.
+type Account struct {
+	Number uint32
+
+	Name string
+
+	// The serialized extended public key m/44'/145'/n' which lets a locked
+	// wallet derive the addresses of the account.
+	PublicKey string
+}
+
+// Account returns a view of the datastore restricted to the keys, utxos and
+// transactions of one BIP44 account. Stxos and watched scripts are shared by
+// all accounts.
+func (s *SQLiteDatastore) Account(account uint32) wallet.Datastore {
+	return &SQLiteDatastore{
+		keys: &KeysDB{
+			db:      s.db,
+			lock:    s.lock,
+			crypter: s.crypter,
+			account: int(account),
+		},
+		utxos: &UtxoDB{
+			db:      s.db,
+			lock:    s.lock,
+			account: int(account),
+		},
+		stxos: s.stxos,
+		txns: &TxnsDB{
+			db:      s.db,
+			lock:    s.lock,
+			account: int(account),
+		},
+		watchedScripts: s.watchedScripts,
+		addressLabels:  s.addressLabels,
+		txMemos:        s.txMemos,
+		rates:          s.rates,
+		invoices:       s.invoices,
+		webhooks:       s.webhooks,
+		db:             s.db,
+		lock:           s.lock,
+		crypter:        s.crypter,
+	}
+}
+
+func (s *SQLiteDatastore) PutAccount(account Account) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err := s.db.Exec("insert or replace into accounts(account, name, publicKey) values(?,?,?)",
+		int(account.Number), account.Name, account.PublicKey)
+	return err
+}
+
+// GetAccounts returns the saved accounts ordered by number.
+func (s *SQLiteDatastore) GetAccounts() ([]Account, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var ret []Account
+	rows, err := s.db.Query("select account, name, publicKey from accounts order by account asc")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var number int
+		var name, publicKey sql.NullString
+		if err := rows.Scan(&number, &name, &publicKey); err != nil {
+			return ret, err
+		}
+		ret = append(ret, Account{
+			Number:    uint32(number),
+			Name:      name.String,
+			PublicKey: publicKey.String,
+		})
+	}
+	return ret, rows.Err()
+}
+
+// accountValue returns the account to store a new row under. Rows written
+// through the default views belong to account 0.
+func accountValue(account int) int {
+	if account == allAccounts {
+		return 0
+	}
+	return account
+}
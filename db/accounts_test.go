@@ -0,0 +1,190 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+func TestSQLiteDatastore_Account(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	account := ds.Account(1)
+
+	// Keys
+	if err := ds.Keys().Put([]byte("address0"), wallet.KeyPath{Purpose: wallet.EXTERNAL, Index: 0}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := account.Keys().Put([]byte{byte(i)}, wallet.KeyPath{Purpose: wallet.EXTERNAL, Index: i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err := ds.Keys().GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("Default account returned %d keys, expected 1", len(keys))
+	}
+	index, _, err := account.Keys().GetLastKeyIndex(wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index != 2 {
+		t.Errorf("Returned last key index %d, expected 2", index)
+	}
+	if windows := account.Keys().GetLookaheadWindows(); windows[wallet.EXTERNAL] != 3 {
+		t.Errorf("Returned lookahead window %d, expected 3", windows[wallet.EXTERNAL])
+	}
+	if _, err := ds.Keys().GetPathForKey([]byte{1}); err == nil {
+		t.Error("Default account returned the path of another account's key")
+	}
+	if _, err := account.Keys().GetPathForKey([]byte{1}); err != nil {
+		t.Error(err)
+	}
+
+	// Utxos
+	h, err := chainhash.NewHashFromStr("e941e1c32b3dd1a68edc3af9f7fe711f35aaca60f758c2dd49561e45ca2c41c0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := wallet.Utxo{Op: *wire.NewOutPoint(h, 0), Value: 1000, ScriptPubkey: []byte("script")}
+	if err := account.Utxos().Put(u); err != nil {
+		t.Fatal(err)
+	}
+	if utxos, err := ds.Account(0).Utxos().GetAll(); err != nil || len(utxos) != 0 {
+		t.Error("Default account returned another account's utxo")
+	}
+	// Updating through the default view keeps the account
+	u.AtHeight = 10
+	if err := ds.Utxos().Put(u); err != nil {
+		t.Fatal(err)
+	}
+	utxos, err := account.Utxos().GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].AtHeight != 10 {
+		t.Error("Failed to update utxo through the default view")
+	}
+	if utxos, err := ds.Utxos().GetAll(); err != nil || len(utxos) != 1 {
+		t.Error("Default view didn't return every account's utxos")
+	}
+
+	// Txns
+	if err := account.Txns().Put([]byte{}, h.String(), 1000, 0, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	if txns, err := ds.Account(0).Txns().GetAll(true); err != nil || len(txns) != 0 {
+		t.Error("Default account returned another account's transaction")
+	}
+	// The default view holds the net value to the wallet, the account view
+	// the value to the account
+	if err := ds.Txns().Put([]byte{}, h.String(), -200, 5, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Account(0).Txns().Put([]byte{}, h.String(), -1200, 5, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	txns, err := account.Txns().GetAll(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Height != 5 || txns[0].Value != 1000 {
+		t.Error("Failed to update transaction through the default view")
+	}
+	if txn, err := ds.Account(0).Txns().Get(*h); err != nil || txn.Value != -1200 {
+		t.Error("Returned the wrong value to the default account")
+	}
+	if txn, err := ds.Txns().Get(*h); err != nil || txn.Value != -200 {
+		t.Error("Returned the wrong net value")
+	}
+	if err := ds.Txns().Delete(h); err != nil {
+		t.Fatal(err)
+	}
+	if txns, err := account.Txns().GetAll(true); err != nil || len(txns) != 0 {
+		t.Error("Deleted transaction is still in the account")
+	}
+}
+
+func TestSQLiteDatastore_PutAccount(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+
+	accounts, err := ds.GetAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 0 {
+		t.Error("New datastore has accounts")
+	}
+	if err := ds.PutAccount(Account{Number: 2, Name: "savings", PublicKey: "xpub2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.PutAccount(Account{Number: 1, Name: "business", PublicKey: "xpub1"}); err != nil {
+		t.Fatal(err)
+	}
+	accounts, err = ds.GetAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 2 || accounts[0] != (Account{1, "business", "xpub1"}) || accounts[1] != (Account{2, "savings", "xpub2"}) {
+		t.Errorf("Returned the wrong accounts %v", accounts)
+	}
+}
+
+func TestInitDatabaseTables_AccountMigration(t *testing.T) {
+	conn, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// Tables as created before accounts were added
+	_, err = conn.Exec(`
+	create table keys (scriptAddress text primary key not null, purpose integer, keyIndex integer, used integer, key text);
+	create table utxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, watchOnly integer);
+	create table txns (txid text primary key not null, value integer, height integer, timestamp integer, watchOnly integer, tx blob);
+	insert into keys(scriptAddress, purpose, keyIndex, used) values('00', 0, 0, 0);
+	insert into utxos(outpoint, value, height, scriptPubKey, watchOnly) values('e941e1c32b3dd1a68edc3af9f7fe711f35aaca60f758c2dd49561e45ca2c41c0:0', 1000, 1, '00', 0);
+	insert into txns(txid, value, height, timestamp, watchOnly, tx) values('e941e1c32b3dd1a68edc3af9f7fe711f35aaca60f758c2dd49561e45ca2c41c0', 1000, 1, 0, 0, x'');
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := initDatabaseTables(conn); err != nil {
+		t.Fatal(err)
+	}
+	// Running it again is a no-op
+	if err := initDatabaseTables(conn); err != nil {
+		t.Fatal(err)
+	}
+	l := new(sync.RWMutex)
+	keys := &KeysDB{db: conn, lock: l}
+	paths, err := keys.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Error("Existing key was not moved to the default account")
+	}
+	utxos, err := (&UtxoDB{db: conn, lock: l}).GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 {
+		t.Error("Existing utxo was not moved to the default account")
+	}
+	txns, err := (&TxnsDB{db: conn, lock: l}).GetAll(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Value != 1000 {
+		t.Error("Existing transaction was not moved to the default account")
+	}
+}
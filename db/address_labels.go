@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+)
+
+type AddressLabelsDB struct {
+	db   *sql.DB
+	lock *sync.RWMutex
+}
+
+func (a *AddressLabelsDB) Put(address string, label string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, err := a.db.Exec("insert or replace into addressLabels(address, label) values(?,?)", address, label)
+	return err
+}
+
+func (a *AddressLabelsDB) Get(address string) (string, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	var label string
+	err := a.db.QueryRow("select label from addressLabels where address=?", address).Scan(&label)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return label, err
+}
+
+func (a *AddressLabelsDB) GetAll() (map[string]string, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	ret := make(map[string]string)
+	rows, err := a.db.Query("select address, label from addressLabels")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var address, label string
+		if err := rows.Scan(&address, &label); err != nil {
+			return ret, err
+		}
+		ret[address] = label
+	}
+	return ret, rows.Err()
+}
+
+func (a *AddressLabelsDB) Delete(address string) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	_, err := a.db.Exec("delete from addressLabels where address=?", address)
+	return err
+}
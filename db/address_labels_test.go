@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestAddressLabelsDB(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	labels := ds.AddressLabels()
+
+	if err := labels.Put("qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", "Savings"); err != nil {
+		t.Fatal(err)
+	}
+	if err := labels.Put("qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", "Rent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := labels.Put("qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy", "Shop"); err != nil {
+		t.Fatal(err)
+	}
+	label, err := labels.Get("qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "Rent" {
+		t.Errorf("Returned label %s, expected Rent", label)
+	}
+	all, err := labels.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all["qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy"] != "Shop" {
+		t.Errorf("Returned the wrong labels %v", all)
+	}
+
+	if err := labels.Delete("qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"); err != nil {
+		t.Fatal(err)
+	}
+	label, err = labels.Get("qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label != "" {
+		t.Error("Returned a deleted label")
+	}
+	// Account views share the labels
+	if all, err := ds.Account(1).(*SQLiteDatastore).AddressLabels().GetAll(); err != nil || len(all) != 1 {
+		t.Error("Account view returned the wrong labels")
+	}
+}
@@ -2,6 +2,8 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
+	"math/rand"
 	"path"
 	"sync"
 	"time"
@@ -18,8 +20,14 @@ type SQLiteDatastore struct {
 	stxos          wallet.Stxos
 	txns           wallet.Txns
 	watchedScripts wallet.WatchedScripts
+	addressLabels  wallet.AddressLabels
+	txMemos        wallet.TxMemos
+	rates          wallet.HistoricalRates
+	invoices       wallet.Invoices
+	webhooks       wallet.Webhooks
 	db             *sql.DB
 	lock           *sync.RWMutex
+	crypter        *crypter
 }
 
 func Create(repoPath string) (*SQLiteDatastore, error) {
@@ -29,32 +37,64 @@ func Create(repoPath string) (*SQLiteDatastore, error) {
 		return nil, err
 	}
 
+	if err := initDatabaseTables(conn); err != nil {
+		return nil, err
+	}
+	params, err := loadEncryptionParams(conn)
+	if err != nil {
+		return nil, err
+	}
+
 	l := new(sync.RWMutex)
+	c := &crypter{params: params}
 	sqliteDB := &SQLiteDatastore{
 		keys: &KeysDB{
+			db:      conn,
+			lock:    l,
+			crypter: c,
+		},
+		utxos: &UtxoDB{
+			db:      conn,
+			lock:    l,
+			account: allAccounts,
+		},
+		stxos: &StxoDB{
 			db:   conn,
 			lock: l,
 		},
-		utxos: &UtxoDB{
+		txns: &TxnsDB{
+			db:      conn,
+			lock:    l,
+			account: allAccounts,
+		},
+		watchedScripts: &WatchedScriptsDB{
 			db:   conn,
 			lock: l,
 		},
-		stxos: &StxoDB{
+		addressLabels: &AddressLabelsDB{
 			db:   conn,
 			lock: l,
 		},
-		txns: &TxnsDB{
+		txMemos: &TxMemosDB{
 			db:   conn,
 			lock: l,
 		},
-		watchedScripts: &WatchedScriptsDB{
+		rates: &HistoricalRatesDB{
+			db:   conn,
+			lock: l,
+		},
+		invoices: &InvoicesDB{
 			db:   conn,
 			lock: l,
 		},
-		db:   conn,
-		lock: l,
+		webhooks: &WebhooksDB{
+			db:   conn,
+			lock: l,
+		},
+		db:      conn,
+		lock:    l,
+		crypter: c,
 	}
-	initDatabaseTables(conn)
 	return sqliteDB, nil
 }
 
@@ -73,24 +113,102 @@ func (db *SQLiteDatastore) Txns() wallet.Txns {
 func (db *SQLiteDatastore) WatchedScripts() wallet.WatchedScripts {
 	return db.watchedScripts
 }
+func (db *SQLiteDatastore) AddressLabels() wallet.AddressLabels {
+	return db.addressLabels
+}
+func (db *SQLiteDatastore) TxMemos() wallet.TxMemos {
+	return db.txMemos
+}
+func (db *SQLiteDatastore) HistoricalRates() wallet.HistoricalRates {
+	return db.rates
+}
+func (db *SQLiteDatastore) Invoices() wallet.Invoices {
+	return db.invoices
+}
+func (db *SQLiteDatastore) Webhooks() wallet.Webhooks {
+	return db.webhooks
+}
 
 func initDatabaseTables(db *sql.DB) error {
+	// Checked before the table is created so existing transactions can be
+	// moved to it
+	var accountsTables int
+	if err := db.QueryRow("select count(*) from sqlite_master where type='table' and name='txnAccounts'").Scan(&accountsTables); err != nil {
+		return err
+	}
 	var sqlStmt string
 	sqlStmt = sqlStmt + `
-	create table if not exists keys (scriptAddress text primary key not null, purpose integer, keyIndex integer, used integer, key text);
-	create table if not exists utxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, watchOnly integer);
+	create table if not exists keys (scriptAddress text primary key not null, purpose integer, keyIndex integer, used integer, key text, pubKey text, account integer default 0);
+	create table if not exists utxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, watchOnly integer, account integer default 0);
 	create table if not exists stxos (outpoint text primary key not null, value integer, height integer, scriptPubKey text, watchOnly integer, spendHeight integer, spendTxid text);
-	create table if not exists txns (txid text primary key not null, value integer, height integer, timestamp integer, watchOnly integer, tx blob);
+	create table if not exists txns (txid text primary key not null, value integer, height integer, timestamp integer, watchOnly integer, tx blob, fee integer default 0);
+	create table if not exists txnAccounts (txid text not null, account integer not null, value integer, primary key (txid, account));
+	create table if not exists txnIO (txid text not null, output integer not null, idx integer not null, address text, value integer, primary key (txid, output, idx));
 	create table if not exists watchedScripts (scriptPubKey text primary key not null);
 	create table if not exists config(key text primary key not null, value blob);
+	create table if not exists accounts (account integer primary key not null, name text, publicKey text);
+	create table if not exists lockedUtxos (outpoint text primary key not null);
+	create table if not exists addressLabels (address text primary key not null, label text);
+	create table if not exists txMemos (txid text primary key not null, memo text);
+	create table if not exists historicalRates (currency text not null, day text not null, rate real, primary key (currency, day));
+	create table if not exists invoices (id text primary key not null, address text unique, amount integer, fiatAmount real, fiatCurrency text, exchangeRate real, memo text, created integer, expires integer);
+	create table if not exists invoicePayments (invoice text not null, txid text not null, idx integer not null, value integer, primary key (txid, idx));
+	create table if not exists webhooks (id text primary key not null, payload blob, attempts integer, nextAttempt integer, done integer);
 	`
 	_, err := db.Exec(sqlStmt)
 	if err != nil {
 		return err
 	}
+	if accountsTables == 0 {
+		// Transactions saved before accounts existed belong to the default account
+		if _, err := db.Exec("insert into txnAccounts(txid, account, value) select txid, 0, value from txns where watchOnly=0"); err != nil {
+			return err
+		}
+	}
+	// Columns added after the initial schema. Existing rows belong to the
+	// default account and have no recorded fee.
+	columns := []struct{ table, column, columnType string }{
+		{"keys", "pubKey", "text"},
+		{"keys", "account", "integer default 0"},
+		{"utxos", "account", "integer default 0"},
+		{"txns", "fee", "integer default 0"},
+	}
+	for _, c := range columns {
+		if err := addColumnIfMissing(db, c.table, c.column, c.columnType); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// addColumnIfMissing adds a column to a table created by an earlier version of
+// the schema.
+func addColumnIfMissing(db *sql.DB, table, column, columnType string) error {
+	rows, err := db.Query("pragma table_info(" + table + ")")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, ctype      string
+			dflt             interface{}
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf("alter table %s add column %s %s", table, column, columnType))
+	return err
+}
+
 func (s *SQLiteDatastore) GetMnemonic() (string, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -101,12 +219,20 @@ func (s *SQLiteDatastore) GetMnemonic() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return mnemonic, nil
+	plaintext, err := s.crypter.decode(mnemonic, configKeyMnemonic)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
 }
 
 func (s *SQLiteDatastore) SetMnemonic(mnemonic string) error {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
+	value, err := s.crypter.encode([]byte(mnemonic), configKeyMnemonic)
+	if err != nil {
+		return err
+	}
 	tx, err := s.db.Begin()
 	if err != nil {
 		return err
@@ -116,7 +242,7 @@ func (s *SQLiteDatastore) SetMnemonic(mnemonic string) error {
 		return err
 	}
 	defer stmt.Close()
-	_, err = stmt.Exec("mnemonic", mnemonic)
+	_, err = stmt.Exec("mnemonic", value)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -183,3 +309,53 @@ func (s *SQLiteDatastore) SetConfigKV(key, value string) error {
 	tx.Commit()
 	return nil
 }
+
+// ArchiveMnemonic keeps a copy of a mnemonic which is being replaced. It is
+// encrypted like the current mnemonic.
+func (s *SQLiteDatastore) ArchiveMnemonic(mnemonic string) error {
+	key := archivedMnemonicKey + fmt.Sprint(rand.Uint32())
+	value, err := s.crypter.encode([]byte(mnemonic), key)
+	if err != nil {
+		return err
+	}
+	return s.SetConfigKV(key, value)
+}
+
+// GetPublicKeys returns the serialized master and BIP44 account public keys
+// which let the wallet derive addresses while it is locked.
+func (s *SQLiteDatastore) GetPublicKeys() (masterPublicKey, accountPublicKey string, err error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var master, account []byte
+	if err := s.db.QueryRow("select value from config where key=?", "masterPublicKey").Scan(&master); err != nil {
+		return "", "", err
+	}
+	if err := s.db.QueryRow("select value from config where key=?", "accountPublicKey").Scan(&account); err != nil {
+		return "", "", err
+	}
+	return string(master), string(account), nil
+}
+
+func (s *SQLiteDatastore) SetPublicKeys(masterPublicKey, accountPublicKey string) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare("insert or replace into config(key, value) values(?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	if _, err := stmt.Exec("masterPublicKey", masterPublicKey); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := stmt.Exec("accountPublicKey", accountPublicKey); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
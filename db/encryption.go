@@ -0,0 +1,447 @@
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/gcash/bchd/bchec"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The mnemonic and imported private keys can be encrypted at rest with a key
+// derived from the wallet passphrase. The key is derived with scrypt and the
+// secrets are sealed with AES-256-GCM. The scrypt parameters, the salt and an
+// encrypted check value used to verify the passphrase are kept in the config
+// table under the "encryption" key.
+
+var (
+	// ErrLocked is returned when a secret is read or written while the
+	// database is encrypted and the passphrase has not been supplied.
+	ErrLocked = errors.New("Wallet is locked")
+
+	// ErrNotEncrypted is returned when locking or changing the passphrase of
+	// a database which has no passphrase set.
+	ErrNotEncrypted = errors.New("Wallet is not encrypted")
+
+	// ErrAlreadyEncrypted is returned when encrypting a database which
+	// already has a passphrase. Use ChangePassphrase instead.
+	ErrAlreadyEncrypted = errors.New("Wallet is already encrypted")
+
+	// ErrIncorrectPassphrase is returned when the passphrase does not
+	// decrypt the database.
+	ErrIncorrectPassphrase = errors.New("Incorrect passphrase")
+
+	// ErrEmptyPassphrase is returned when an empty passphrase is supplied.
+	ErrEmptyPassphrase = errors.New("Passphrase must not be empty")
+)
+
+const (
+	encryptedPrefix = "enc:"
+
+	configKeyEncryption = "encryption"
+	configKeyMnemonic   = "mnemonic"
+	archivedMnemonicKey = "archived_mnemonic_"
+
+	checkLabel = "check"
+
+	scryptR   = 8
+	scryptP   = 1
+	keyLength = 32
+	saltLen   = 16
+)
+
+// scryptN is the scrypt CPU/memory cost. It is a variable so the tests can
+// lower it.
+var scryptN = 1 << 15
+
+var checkPlaintext = []byte("spvwallet-cash")
+
+type encryptionParams struct {
+	Salt  []byte `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Check string `json:"check"`
+}
+
+// newEncryptionParams derives a key for the passphrase with a fresh salt and
+// returns it along with the parameters needed to derive it again.
+func newEncryptionParams(passphrase string) (*encryptionParams, []byte, error) {
+	params := &encryptionParams{
+		Salt: make([]byte, saltLen),
+		N:    scryptN,
+		R:    scryptR,
+		P:    scryptP,
+	}
+	if _, err := rand.Read(params.Salt); err != nil {
+		return nil, nil, err
+	}
+	key, err := params.deriveKey(passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.Check, err = encryptWithKey(key, checkPlaintext, checkLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return params, key, nil
+}
+
+func (p *encryptionParams) deriveKey(passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassphrase
+	}
+	return scrypt.Key([]byte(passphrase), p.Salt, p.N, p.R, p.P, keyLength)
+}
+
+// verify derives the key for the passphrase and checks it against the stored
+// check value.
+func (p *encryptionParams) verify(passphrase string) ([]byte, error) {
+	key, err := p.deriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decryptWithKey(key, p.Check, checkLabel); err != nil {
+		return nil, ErrIncorrectPassphrase
+	}
+	return key, nil
+}
+
+// crypter holds the state of the database encryption. It is shared between the
+// datastore and its key store. A nil crypter, or one with no parameters, means
+// secrets are stored in plaintext.
+type crypter struct {
+	mtx    sync.RWMutex
+	params *encryptionParams
+	key    []byte
+}
+
+func (c *crypter) isEncrypted() bool {
+	if c == nil {
+		return false
+	}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.params != nil
+}
+
+func (c *crypter) isLocked() bool {
+	if c == nil {
+		return false
+	}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.params != nil && c.key == nil
+}
+
+func (c *crypter) set(params *encryptionParams, key []byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.params = params
+	c.key = key
+}
+
+func (c *crypter) wipe() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for i := range c.key {
+		c.key[i] = 0
+	}
+	c.key = nil
+}
+
+// encode returns the value to store for a secret. If the database is not
+// encrypted the plaintext is returned as it is.
+func (c *crypter) encode(plaintext []byte, label string) (string, error) {
+	if c == nil {
+		return string(plaintext), nil
+	}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	if c.params == nil {
+		return string(plaintext), nil
+	}
+	if c.key == nil {
+		return "", ErrLocked
+	}
+	return encryptWithKey(c.key, plaintext, label)
+}
+
+// decode returns the plaintext of a stored secret. Values which were stored
+// before the database was encrypted are returned as they are.
+func (c *crypter) decode(value string, label string) ([]byte, error) {
+	if !isEncryptedValue(value) {
+		return []byte(value), nil
+	}
+	if c == nil {
+		return nil, ErrLocked
+	}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	if c.key == nil {
+		return nil, ErrLocked
+	}
+	return decryptWithKey(c.key, value, label)
+}
+
+func isEncryptedValue(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// encryptWithKey seals the plaintext with AES-256-GCM. The label is
+// authenticated with the ciphertext so a value cannot be moved to another row.
+func encryptWithKey(key, plaintext []byte, label string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(label))
+	return encryptedPrefix + hex.EncodeToString(sealed), nil
+}
+
+func decryptWithKey(key []byte, value string, label string) ([]byte, error) {
+	if !isEncryptedValue(value) {
+		return nil, errors.New("Value is not encrypted")
+	}
+	sealed, err := hex.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("Encrypted value is too short")
+	}
+	nonce := sealed[:aead.NonceSize()]
+	return aead.Open(nil, nonce, sealed[aead.NonceSize():], []byte(label))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func loadEncryptionParams(db *sql.DB) (*encryptionParams, error) {
+	var value []byte
+	err := db.QueryRow("select value from config where key=?", configKeyEncryption).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	params := new(encryptionParams)
+	if err := json.Unmarshal(value, params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// IsEncrypted returns whether the mnemonic and imported keys are encrypted.
+func (s *SQLiteDatastore) IsEncrypted() bool {
+	return s.crypter.isEncrypted()
+}
+
+// IsLocked returns whether the database is encrypted and no passphrase has been
+// supplied since it was opened or last locked.
+func (s *SQLiteDatastore) IsLocked() bool {
+	return s.crypter.isLocked()
+}
+
+// Encrypt sets the passphrase and encrypts the plaintext secrets with it. The
+// database is left unlocked.
+func (s *SQLiteDatastore) Encrypt(passphrase string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.crypter.isEncrypted() {
+		return ErrAlreadyEncrypted
+	}
+	return s.encrypt(passphrase)
+}
+
+// encrypt rewrites the plaintext secrets encrypted with a key derived from the
+// passphrase. The lock must be held.
+func (s *SQLiteDatastore) encrypt(passphrase string) error {
+	params, key, err := newEncryptionParams(passphrase)
+	if err != nil {
+		return err
+	}
+	if err := s.rewriteSecrets(nil, key, params); err != nil {
+		return err
+	}
+	s.crypter.set(params, key)
+	return nil
+}
+
+// Unlock supplies the passphrase needed to read and write secrets. A database
+// which has no passphrase yet is migrated by encrypting its secrets with it.
+func (s *SQLiteDatastore) Unlock(passphrase string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.crypter.mtx.RLock()
+	params := s.crypter.params
+	s.crypter.mtx.RUnlock()
+	if params == nil {
+		return s.encrypt(passphrase)
+	}
+	key, err := params.verify(passphrase)
+	if err != nil {
+		return err
+	}
+	s.crypter.set(params, key)
+	return nil
+}
+
+// Lock discards the key derived from the passphrase.
+func (s *SQLiteDatastore) Lock() error {
+	if !s.crypter.isEncrypted() {
+		return ErrNotEncrypted
+	}
+	s.crypter.wipe()
+	return nil
+}
+
+// ChangePassphrase re-encrypts all secrets with a key derived from the new
+// passphrase. The database is left unlocked with the new passphrase.
+func (s *SQLiteDatastore) ChangePassphrase(oldPassphrase, newPassphrase string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.crypter.mtx.RLock()
+	params := s.crypter.params
+	s.crypter.mtx.RUnlock()
+	if params == nil {
+		return ErrNotEncrypted
+	}
+	oldKey, err := params.verify(oldPassphrase)
+	if err != nil {
+		return err
+	}
+	newParams, newKey, err := newEncryptionParams(newPassphrase)
+	if err != nil {
+		return err
+	}
+	if err := s.rewriteSecrets(oldKey, newKey, newParams); err != nil {
+		return err
+	}
+	s.crypter.set(newParams, newKey)
+	return nil
+}
+
+type storedSecret struct {
+	id    string
+	value string
+}
+
+// rewriteSecrets decrypts every secret with oldKey, or reads it as plaintext if
+// oldKey is nil, and stores it encrypted with newKey along with the new
+// encryption parameters. Everything is written in a single transaction.
+func (s *SQLiteDatastore) rewriteSecrets(oldKey, newKey []byte, params *encryptionParams) error {
+	open := func(value, label string) ([]byte, error) {
+		if oldKey == nil {
+			return []byte(value), nil
+		}
+		return decryptWithKey(oldKey, value, label)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	mnemonics, err := querySecrets(tx, "select key, value from config where key=? or key like ?", configKeyMnemonic, archivedMnemonicKey+"%")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, secret := range mnemonics {
+		plaintext, err := open(secret.value, secret.id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		value, err := encryptWithKey(newKey, plaintext, secret.id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec("update config set value=? where key=?", value, secret.id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	keys, err := querySecrets(tx, "select scriptAddress, key from keys where purpose=-1")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, secret := range keys {
+		var keyBytes []byte
+		if oldKey == nil {
+			keyBytes, err = hex.DecodeString(secret.value)
+		} else {
+			keyBytes, err = open(secret.value, secret.id)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		value, err := encryptWithKey(newKey, keyBytes, secret.id)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		_, pub := bchec.PrivKeyFromBytes(bchec.S256(), keyBytes)
+		if _, err := tx.Exec("update keys set key=?, pubKey=? where scriptAddress=?", value, hex.EncodeToString(pub.SerializeCompressed()), secret.id); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	paramsJSON, err := json.Marshal(params)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("insert or replace into config(key, value) values(?,?)", configKeyEncryption, paramsJSON); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func querySecrets(tx *sql.Tx, query string, args ...interface{}) ([]storedSecret, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var secrets []storedSecret
+	for rows.Next() {
+		var secret storedSecret
+		var value []byte
+		if err := rows.Scan(&secret.id, &value); err != nil {
+			return nil, err
+		}
+		secret.value = string(value)
+		secrets = append(secrets, secret)
+	}
+	return secrets, rows.Err()
+}
@@ -0,0 +1,355 @@
+package db
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/gcash/bchd/bchec"
+	"github.com/gcash/bchutil"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func init() {
+	// Keep the tests fast
+	scryptN = 1 << 4
+}
+
+func createTestDatastore(t *testing.T) (*SQLiteDatastore, func()) {
+	dir, err := ioutil.TempDir("", "spvwallet-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds, err := Create(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds, func() { os.RemoveAll(dir) }
+}
+
+func importTestKey(t *testing.T, ds *SQLiteDatastore) (*bchec.PrivateKey, []byte) {
+	key, err := bchec.NewPrivateKey(bchec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptAddress := bchutil.Hash160(key.PubKey().SerializeCompressed())
+	if err := ds.Keys().ImportKey(scriptAddress, key); err != nil {
+		t.Fatal(err)
+	}
+	return key, scriptAddress
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := make([]byte, keyLength)
+	value, err := encryptWithKey(key, []byte("secret"), "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedValue(value) {
+		t.Error("Encrypted value is missing its prefix")
+	}
+	plaintext, err := decryptWithKey(key, value, "label")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "secret" {
+		t.Error("Decrypted the wrong plaintext")
+	}
+	if _, err := decryptWithKey(key, value, "other label"); err == nil {
+		t.Error("Decrypted a value with the wrong label")
+	}
+	otherKey := make([]byte, keyLength)
+	otherKey[0] = 1
+	if _, err := decryptWithKey(otherKey, value, "label"); err == nil {
+		t.Error("Decrypted a value with the wrong key")
+	}
+}
+
+func TestSQLiteDatastore_MigrateOnUnlock(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+
+	if err := ds.SetMnemonic(testMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.ArchiveMnemonic("old mnemonic"); err != nil {
+		t.Fatal(err)
+	}
+	key, scriptAddress := importTestKey(t, ds)
+	if ds.IsEncrypted() || ds.IsLocked() {
+		t.Fatal("New datastore should not be encrypted")
+	}
+	if err := ds.Lock(); err != ErrNotEncrypted {
+		t.Errorf("Expected ErrNotEncrypted, got %v", err)
+	}
+
+	if err := ds.Unlock(""); err != ErrEmptyPassphrase {
+		t.Errorf("Expected ErrEmptyPassphrase, got %v", err)
+	}
+	if err := ds.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if !ds.IsEncrypted() || ds.IsLocked() {
+		t.Fatal("Datastore should be encrypted and unlocked")
+	}
+
+	// Nothing is left in plaintext
+	rows, err := ds.db.Query("select value from config where key='mnemonic' or key like 'archived_mnemonic_%'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		if !isEncryptedValue(string(value)) {
+			t.Errorf("Mnemonic stored in plaintext: %s", value)
+		}
+		count++
+	}
+	rows.Close()
+	if count != 2 {
+		t.Errorf("Expected 2 mnemonics, got %d", count)
+	}
+	var keyValue string
+	if err := ds.db.QueryRow("select key from keys where purpose=-1").Scan(&keyValue); err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedValue(keyValue) {
+		t.Error("Imported key stored in plaintext")
+	}
+
+	mnemonic, err := ds.GetMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mnemonic != testMnemonic {
+		t.Error("Returned the wrong mnemonic")
+	}
+	got, err := ds.Keys().GetKey(scriptAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Serialize(), key.Serialize()) {
+		t.Error("Returned the wrong imported key")
+	}
+}
+
+func TestSQLiteDatastore_Encrypt(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+
+	if err := ds.SetMnemonic(testMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.ArchiveMnemonic("old mnemonic"); err != nil {
+		t.Fatal(err)
+	}
+	key, scriptAddress := importTestKey(t, ds)
+	if ds.IsEncrypted() || ds.IsLocked() {
+		t.Fatal("New datastore should not be encrypted")
+	}
+	if err := ds.Lock(); err != ErrNotEncrypted {
+		t.Errorf("Expected ErrNotEncrypted, got %v", err)
+	}
+
+	if err := ds.Encrypt(""); err != ErrEmptyPassphrase {
+		t.Errorf("Expected ErrEmptyPassphrase, got %v", err)
+	}
+	if err := ds.Encrypt("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if !ds.IsEncrypted() || ds.IsLocked() {
+		t.Fatal("Datastore should be encrypted and unlocked")
+	}
+	if err := ds.Encrypt("other"); err != ErrAlreadyEncrypted {
+		t.Errorf("Expected ErrAlreadyEncrypted, got %v", err)
+	}
+
+	// Nothing is left in plaintext
+	rows, err := ds.db.Query("select value from config where key='mnemonic' or key like 'archived_mnemonic_%'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			t.Fatal(err)
+		}
+		if !isEncryptedValue(string(value)) {
+			t.Errorf("Mnemonic stored in plaintext: %s", value)
+		}
+		count++
+	}
+	rows.Close()
+	if count != 2 {
+		t.Errorf("Expected 2 mnemonics, got %d", count)
+	}
+	var keyValue string
+	if err := ds.db.QueryRow("select key from keys where purpose=-1").Scan(&keyValue); err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedValue(keyValue) {
+		t.Error("Imported key stored in plaintext")
+	}
+
+	mnemonic, err := ds.GetMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mnemonic != testMnemonic {
+		t.Error("Returned the wrong mnemonic")
+	}
+	got, err := ds.Keys().GetKey(scriptAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Serialize(), key.Serialize()) {
+		t.Error("Returned the wrong imported key")
+	}
+}
+
+func TestSQLiteDatastore_Locked(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+
+	if err := ds.SetMnemonic(testMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	key, scriptAddress := importTestKey(t, ds)
+	if err := ds.Encrypt("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if !ds.IsLocked() {
+		t.Fatal("Datastore should be locked")
+	}
+
+	if _, err := ds.GetMnemonic(); err != ErrLocked {
+		t.Errorf("GetMnemonic: expected ErrLocked, got %v", err)
+	}
+	if err := ds.SetMnemonic(testMnemonic); err != ErrLocked {
+		t.Errorf("SetMnemonic: expected ErrLocked, got %v", err)
+	}
+	if _, err := ds.Keys().GetKey(scriptAddress); err != ErrLocked {
+		t.Errorf("GetKey: expected ErrLocked, got %v", err)
+	}
+	if _, err := ds.Keys().GetImported(); err != ErrLocked {
+		t.Errorf("GetImported: expected ErrLocked, got %v", err)
+	}
+	other, err := bchec.NewPrivateKey(bchec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Keys().ImportKey(bchutil.Hash160(other.PubKey().SerializeCompressed()), other); err != ErrLocked {
+		t.Errorf("ImportKey: expected ErrLocked, got %v", err)
+	}
+
+	// Public keys stay readable
+	pubKeys, err := ds.Keys().(*KeysDB).GetImportedPubKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubKeys) != 1 || !pubKeys[0].IsEqual(key.PubKey()) {
+		t.Error("Returned the wrong imported public keys")
+	}
+
+	if err := ds.Unlock("wrong"); err != ErrIncorrectPassphrase {
+		t.Errorf("Expected ErrIncorrectPassphrase, got %v", err)
+	}
+	if !ds.IsLocked() {
+		t.Error("Unlocked with the wrong passphrase")
+	}
+	if err := ds.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ds.Keys().GetImported(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSQLiteDatastore_ChangePassphrase(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+
+	if err := ds.ChangePassphrase("old", "new"); err != ErrNotEncrypted {
+		t.Errorf("Expected ErrNotEncrypted, got %v", err)
+	}
+	if err := ds.SetMnemonic(testMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	_, scriptAddress := importTestKey(t, ds)
+	if err := ds.Encrypt("old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.ChangePassphrase("wrong", "new"); err != ErrIncorrectPassphrase {
+		t.Errorf("Expected ErrIncorrectPassphrase, got %v", err)
+	}
+	if err := ds.ChangePassphrase("old", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Unlock("old"); err != ErrIncorrectPassphrase {
+		t.Errorf("Expected ErrIncorrectPassphrase, got %v", err)
+	}
+	if err := ds.Unlock("new"); err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := ds.GetMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mnemonic != testMnemonic {
+		t.Error("Returned the wrong mnemonic")
+	}
+	if _, err := ds.Keys().GetKey(scriptAddress); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSQLiteDatastore_ReopenEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spvwallet-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ds, err := Create(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.SetMnemonic(testMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.Encrypt("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	ds.db.Close()
+
+	ds, err = Create(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ds.IsEncrypted() || !ds.IsLocked() {
+		t.Fatal("Reopened datastore should be encrypted and locked")
+	}
+	if err := ds.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	mnemonic, err := ds.GetMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mnemonic != testMnemonic {
+		t.Error("Returned the wrong mnemonic")
+	}
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Rates are saved per UTC day
+const rateDayLayout = "2006-01-02"
+
+type HistoricalRatesDB struct {
+	db   *sql.DB
+	lock *sync.RWMutex
+}
+
+func (r *HistoricalRatesDB) Put(currencyCode string, day time.Time, rate float64) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err := r.db.Exec("insert or replace into historicalRates(currency, day, rate) values(?,?,?)",
+		currencyCode, day.UTC().Format(rateDayLayout), rate)
+	return err
+}
+
+func (r *HistoricalRatesDB) Get(currencyCode string, day time.Time) (float64, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	var rate float64
+	err := r.db.QueryRow("select rate from historicalRates where currency=? and day=?",
+		currencyCode, day.UTC().Format(rateDayLayout)).Scan(&rate)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return rate, err
+}
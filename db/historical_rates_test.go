@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistoricalRatesDB(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	rates := ds.HistoricalRates()
+	day := time.Date(2019, 3, 14, 9, 30, 0, 0, time.UTC)
+
+	if err := rates.Put("USD", day, 131.25); err != nil {
+		t.Fatal(err)
+	}
+	// Rates are saved for the whole day
+	rate, err := rates.Get("USD", day.Add(time.Hour*10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 131.25 {
+		t.Errorf("Returned rate %f, expected 131.25", rate)
+	}
+	if rate, err := rates.Get("EUR", day); err != nil || rate != 0 {
+		t.Error("Returned a rate for another currency")
+	}
+	if rate, err := rates.Get("USD", day.AddDate(0, 0, 1)); err != nil || rate != 0 {
+		t.Error("Returned a rate for another day")
+	}
+
+	if err := rates.Put("USD", day, 130); err != nil {
+		t.Fatal(err)
+	}
+	if rate, _ := rates.Get("USD", day); rate != 130 {
+		t.Error("Rate was not replaced")
+	}
+}
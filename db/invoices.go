@@ -0,0 +1,115 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+type InvoicesDB struct {
+	db   *sql.DB
+	lock *sync.RWMutex
+}
+
+func (i *InvoicesDB) Put(invoice wallet.Invoice) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	var expires int64
+	if !invoice.Expires.IsZero() {
+		expires = invoice.Expires.Unix()
+	}
+	_, err := i.db.Exec("insert or replace into invoices(id, address, amount, fiatAmount, fiatCurrency, exchangeRate, memo, created, expires) values(?,?,?,?,?,?,?,?,?)",
+		invoice.ID, invoice.Address, invoice.Amount, invoice.FiatAmount, invoice.FiatCurrency, invoice.ExchangeRate,
+		invoice.Memo, invoice.Created.Unix(), expires)
+	return err
+}
+
+func (i *InvoicesDB) Get(id string) (wallet.Invoice, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	invoices, err := i.query("select id, address, amount, fiatAmount, fiatCurrency, exchangeRate, memo, created, expires from invoices where id=?", id)
+	if err != nil {
+		return wallet.Invoice{}, err
+	}
+	if len(invoices) == 0 {
+		return wallet.Invoice{}, errors.New("Invoice not found")
+	}
+	return invoices[0], nil
+}
+
+func (i *InvoicesDB) GetAll() ([]wallet.Invoice, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.query("select id, address, amount, fiatAmount, fiatCurrency, exchangeRate, memo, created, expires from invoices order by created desc, rowid desc")
+}
+
+// query returns the invoices selected by a query with their payments. The
+// lock must be held.
+func (i *InvoicesDB) query(query string, args ...interface{}) ([]wallet.Invoice, error) {
+	var ret []wallet.Invoice
+	rows, err := i.db.Query(query, args...)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			invoice          wallet.Invoice
+			created, expires int64
+		)
+		if err := rows.Scan(&invoice.ID, &invoice.Address, &invoice.Amount, &invoice.FiatAmount, &invoice.FiatCurrency,
+			&invoice.ExchangeRate, &invoice.Memo, &created, &expires); err != nil {
+			return ret, err
+		}
+		invoice.Created = time.Unix(created, 0)
+		if expires > 0 {
+			invoice.Expires = time.Unix(expires, 0)
+		}
+		ret = append(ret, invoice)
+	}
+	if err := rows.Err(); err != nil {
+		return ret, err
+	}
+	for n := range ret {
+		ret[n].Payments, err = i.payments(ret[n].ID)
+		if err != nil {
+			return ret, err
+		}
+	}
+	return ret, nil
+}
+
+func (i *InvoicesDB) payments(id string) ([]wallet.InvoicePayment, error) {
+	var ret []wallet.InvoicePayment
+	rows, err := i.db.Query("select txid, idx, value from invoicePayments where invoice=? order by rowid asc", id)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var payment wallet.InvoicePayment
+		if err := rows.Scan(&payment.Txid, &payment.Index, &payment.Value); err != nil {
+			return ret, err
+		}
+		ret = append(ret, payment)
+	}
+	return ret, rows.Err()
+}
+
+func (i *InvoicesDB) AddPayment(address string, payment wallet.InvoicePayment) (bool, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	var id string
+	err := i.db.QueryRow("select id from invoices where address=?", address).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	_, err = i.db.Exec("insert or replace into invoicePayments(invoice, txid, idx, value) values(?,?,?,?)",
+		id, payment.Txid, payment.Index, payment.Value)
+	return err == nil, err
+}
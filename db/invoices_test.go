@@ -0,0 +1,71 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+func TestInvoicesDB(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	invoices := ds.Invoices()
+	created := time.Unix(1550000000, 0)
+
+	first := wallet.Invoice{
+		ID:           "a",
+		Address:      "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+		Amount:       400000,
+		FiatAmount:   1,
+		FiatCurrency: "USD",
+		ExchangeRate: 250,
+		Memo:         "Order 1",
+		Created:      created,
+		Expires:      created.Add(time.Hour),
+	}
+	second := wallet.Invoice{ID: "b", Address: "qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy", Amount: 5000, Created: created.Add(time.Minute)}
+	for _, invoice := range []wallet.Invoice{first, second} {
+		if err := invoices.Put(invoice); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	invoice, err := invoices.Get("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Address != first.Address || invoice.Amount != 400000 || invoice.FiatAmount != 1 || invoice.FiatCurrency != "USD" ||
+		invoice.ExchangeRate != 250 || invoice.Memo != "Order 1" || !invoice.Created.Equal(created) || !invoice.Expires.Equal(first.Expires) {
+		t.Errorf("Returned the wrong invoice %v", invoice)
+	}
+	if _, err := invoices.Get("c"); err == nil {
+		t.Error("Returned an invoice which does not exist")
+	}
+
+	// Payments are only recorded for the address of an invoice
+	payment := wallet.InvoicePayment{Txid: "6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad", Index: 1, Value: 100000}
+	if ok, err := invoices.AddPayment(first.Address, payment); err != nil || !ok {
+		t.Fatalf("Payment was not recorded: %v", err)
+	}
+	if ok, err := invoices.AddPayment(first.Address, payment); err != nil || !ok {
+		t.Fatalf("Payment was not recorded again: %v", err)
+	}
+	if ok, err := invoices.AddPayment("qz5c4lltzt8gvlv4g3ledfr0uk7lmeu8ts5a7ypkuv", payment); err != nil || ok {
+		t.Error("Recorded a payment to an address without an invoice")
+	}
+
+	all, err := invoices.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].ID != "b" || all[1].ID != "a" {
+		t.Fatalf("Invoices are not ordered newest first: %v", all)
+	}
+	if !all[0].Expires.IsZero() || len(all[0].Payments) != 0 {
+		t.Errorf("Unexpected invoice %v", all[0])
+	}
+	if len(all[1].Payments) != 1 || all[1].Payments[0] != payment {
+		t.Errorf("Returned payments %v, expected %v", all[1].Payments, payment)
+	}
+}
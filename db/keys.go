@@ -15,8 +15,12 @@ import (
 )
 
 type KeysDB struct {
-	db   *sql.DB
-	lock *sync.RWMutex
+	db      *sql.DB
+	lock    *sync.RWMutex
+	crypter *crypter
+
+	// The BIP44 account the keys belong to
+	account int
 }
 
 func (k *KeysDB) Put(scriptAddress []byte, keyPath wallet.KeyPath) error {
@@ -27,9 +31,9 @@ func (k *KeysDB) Put(scriptAddress []byte, keyPath wallet.KeyPath) error {
 		log.Error("Unable to put key in database")
 		return err
 	}
-	stmt, _ := tx.Prepare("insert into keys(scriptAddress, purpose, keyIndex, used) values(?,?,?,?)")
+	stmt, _ := tx.Prepare("insert into keys(scriptAddress, purpose, keyIndex, used, account) values(?,?,?,?,?)")
 	defer stmt.Close()
-	_, err = stmt.Exec(hex.EncodeToString(scriptAddress), int(keyPath.Purpose), keyPath.Index, 0)
+	_, err = stmt.Exec(hex.EncodeToString(scriptAddress), int(keyPath.Purpose), keyPath.Index, 0, k.account)
 	if err != nil {
 		log.Error("Unable to put key in database")
 		tx.Rollback()
@@ -42,14 +46,25 @@ func (k *KeysDB) Put(scriptAddress []byte, keyPath wallet.KeyPath) error {
 func (k *KeysDB) ImportKey(scriptAddress []byte, key *bchec.PrivateKey) error {
 	k.lock.Lock()
 	defer k.lock.Unlock()
+	scriptHex := hex.EncodeToString(scriptAddress)
+	var keyValue string
+	var err error
+	if k.crypter.isEncrypted() {
+		keyValue, err = k.crypter.encode(key.Serialize(), scriptHex)
+		if err != nil {
+			return err
+		}
+	} else {
+		keyValue = hex.EncodeToString(key.Serialize())
+	}
 	tx, err := k.db.Begin()
 	if err != nil {
 		return err
 	}
 	index := rand.Uint32()
-	stmt, _ := tx.Prepare("insert into keys(scriptAddress, purpose, keyIndex, used, key) values(?,?,?,?,?)")
+	stmt, _ := tx.Prepare("insert into keys(scriptAddress, purpose, keyIndex, used, key, pubKey, account) values(?,?,?,?,?,?,?)")
 	defer stmt.Close()
-	_, err = stmt.Exec(hex.EncodeToString(scriptAddress), -1, index, 1, hex.EncodeToString(key.Serialize()))
+	_, err = stmt.Exec(scriptHex, -1, index, 1, keyValue, hex.EncodeToString(key.PubKey().SerializeCompressed()), k.account)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -86,12 +101,12 @@ func (k *KeysDB) GetLastKeyIndex(purpose wallet.KeyPurpose) (int, bool, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 
-	stm := "select keyIndex, used from keys where purpose=" + strconv.Itoa(int(purpose)) + " order by rowid desc limit 1"
+	stm := "select keyIndex, used from keys where purpose=" + strconv.Itoa(int(purpose)) + " and account=? order by rowid desc limit 1"
 	stmt, err := k.db.Prepare(stm)
 	defer stmt.Close()
 	var index int
 	var usedInt int
-	err = stmt.QueryRow().Scan(&index, &usedInt)
+	err = stmt.QueryRow(k.account).Scan(&index, &usedInt)
 	if err != nil {
 		return 0, false, err
 	}
@@ -108,11 +123,11 @@ func (k *KeysDB) GetPathForKey(scriptAddress []byte) (wallet.KeyPath, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 
-	stmt, err := k.db.Prepare("select purpose, keyIndex from keys where scriptAddress=? and purpose!=-1")
+	stmt, err := k.db.Prepare("select purpose, keyIndex from keys where scriptAddress=? and purpose!=-1 and account=?")
 	defer stmt.Close()
 	var purpose int
 	var index int
-	err = stmt.QueryRow(hex.EncodeToString(scriptAddress)).Scan(&purpose, &index)
+	err = stmt.QueryRow(hex.EncodeToString(scriptAddress), k.account).Scan(&purpose, &index)
 	if err != nil {
 		return wallet.KeyPath{}, errors.New("Key not found")
 	}
@@ -127,14 +142,15 @@ func (k *KeysDB) GetKey(scriptAddress []byte) (*bchec.PrivateKey, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 
-	stmt, err := k.db.Prepare("select key from keys where scriptAddress=?")
+	stmt, err := k.db.Prepare("select key from keys where scriptAddress=? and account=?")
 	defer stmt.Close()
+	scriptHex := hex.EncodeToString(scriptAddress)
 	var keyHex string
-	err = stmt.QueryRow(hex.EncodeToString(scriptAddress)).Scan(&keyHex)
+	err = stmt.QueryRow(scriptHex, k.account).Scan(&keyHex)
 	if err != nil {
 		return nil, errors.New("Key not found")
 	}
-	keyBytes, err := hex.DecodeString(keyHex)
+	keyBytes, err := k.decodeKey(keyHex, scriptHex)
 	if err != nil {
 		return nil, err
 	}
@@ -146,8 +162,8 @@ func (k *KeysDB) GetUnused(purpose wallet.KeyPurpose) ([]int, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 	var ret []int
-	stm := "select keyIndex from keys where purpose=" + strconv.Itoa(int(purpose)) + " and used=0 order by rowid asc"
-	rows, err := k.db.Query(stm)
+	stm := "select keyIndex from keys where purpose=" + strconv.Itoa(int(purpose)) + " and used=0 and account=? order by rowid asc"
+	rows, err := k.db.Query(stm, k.account)
 	if err != nil {
 		return ret, err
 	}
@@ -167,19 +183,20 @@ func (k *KeysDB) GetImported() ([]*bchec.PrivateKey, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 	var ret []*bchec.PrivateKey
-	stm := "select key from keys where purpose=-1"
-	rows, err := k.db.Query(stm)
+	stm := "select scriptAddress, key from keys where purpose=-1 and account=?"
+	rows, err := k.db.Query(stm, k.account)
 	if err != nil {
 		return ret, err
 	}
 	defer rows.Close()
 	for rows.Next() {
+		var scriptHex string
 		var keyHex []byte
-		err = rows.Scan(&keyHex)
+		err = rows.Scan(&scriptHex, &keyHex)
 		if err != nil {
 			return ret, err
 		}
-		keyBytes, err := hex.DecodeString(string(keyHex))
+		keyBytes, err := k.decodeKey(string(keyHex), scriptHex)
 		if err != nil {
 			return ret, err
 		}
@@ -189,12 +206,53 @@ func (k *KeysDB) GetImported() ([]*bchec.PrivateKey, error) {
 	return ret, nil
 }
 
+// GetImportedPubKeys returns the public keys of the imported keys. Unlike
+// GetImported it works while the wallet is locked.
+func (k *KeysDB) GetImportedPubKeys() ([]*bchec.PublicKey, error) {
+	k.lock.RLock()
+	defer k.lock.RUnlock()
+	var ret []*bchec.PublicKey
+	stm := "select key, pubKey from keys where purpose=-1 and account=?"
+	rows, err := k.db.Query(stm, k.account)
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var keyHex []byte
+		var pubKeyHex sql.NullString
+		if err := rows.Scan(&keyHex, &pubKeyHex); err != nil {
+			return ret, err
+		}
+		if !pubKeyHex.Valid || pubKeyHex.String == "" {
+			// Imported before public keys were stored
+			keyBytes, err := hex.DecodeString(string(keyHex))
+			if err != nil {
+				return ret, err
+			}
+			_, pub := bchec.PrivKeyFromBytes(bchec.S256(), keyBytes)
+			ret = append(ret, pub)
+			continue
+		}
+		pubKeyBytes, err := hex.DecodeString(pubKeyHex.String)
+		if err != nil {
+			return ret, err
+		}
+		pub, err := bchec.ParsePubKey(pubKeyBytes, bchec.S256())
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, pub)
+	}
+	return ret, nil
+}
+
 func (k *KeysDB) GetAll() ([]wallet.KeyPath, error) {
 	k.lock.RLock()
 	defer k.lock.RUnlock()
 	var ret []wallet.KeyPath
-	stm := "select purpose, keyIndex from keys"
-	rows, err := k.db.Query(stm)
+	stm := "select purpose, keyIndex from keys where account=?"
+	rows, err := k.db.Query(stm, k.account)
 	defer rows.Close()
 	if err != nil {
 		fmt.Println(err)
@@ -220,8 +278,8 @@ func (k *KeysDB) GetLookaheadWindows() map[wallet.KeyPurpose]int {
 	defer k.lock.RUnlock()
 	windows := make(map[wallet.KeyPurpose]int)
 	for i := 0; i < 2; i++ {
-		stm := "select used from keys where purpose=" + strconv.Itoa(i) + " order by rowid desc"
-		rows, err := k.db.Query(stm)
+		stm := "select used from keys where purpose=" + strconv.Itoa(i) + " and account=? order by rowid desc"
+		rows, err := k.db.Query(stm, k.account)
 		if err != nil {
 			continue
 		}
@@ -243,3 +301,12 @@ func (k *KeysDB) GetLookaheadWindows() map[wallet.KeyPurpose]int {
 	}
 	return windows
 }
+
+// decodeKey returns the serialized private key stored for a script address,
+// decrypting it if needed.
+func (k *KeysDB) decodeKey(value string, scriptHex string) ([]byte, error) {
+	if isEncryptedValue(value) {
+		return k.crypter.decode(value, scriptHex)
+	}
+	return hex.DecodeString(value)
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+// LockUtxo saves an outpoint the wallet must not spend. The lock is kept
+// until UnlockUtxo is called, even if the outpoint is spent elsewhere.
+func (s *SQLiteDatastore) LockUtxo(op wire.OutPoint) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err := s.db.Exec("insert or replace into lockedUtxos(outpoint) values(?)", outpointString(op))
+	return err
+}
+
+func (s *SQLiteDatastore) UnlockUtxo(op wire.OutPoint) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err := s.db.Exec("delete from lockedUtxos where outpoint=?", outpointString(op))
+	return err
+}
+
+func (s *SQLiteDatastore) GetLockedUtxos() ([]wire.OutPoint, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	var ret []wire.OutPoint
+	rows, err := s.db.Query("select outpoint from lockedUtxos")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var outpoint string
+		if err := rows.Scan(&outpoint); err != nil {
+			return ret, err
+		}
+		parts := strings.Split(outpoint, ":")
+		if len(parts) != 2 {
+			continue
+		}
+		shaHash, err := chainhash.NewHashFromStr(parts[0])
+		if err != nil {
+			continue
+		}
+		index, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		ret = append(ret, *wire.NewOutPoint(shaHash, uint32(index)))
+	}
+	return ret, rows.Err()
+}
+
+func outpointString(op wire.OutPoint) string {
+	return op.Hash.String() + ":" + strconv.Itoa(int(op.Index))
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+func TestSQLiteDatastore_LockUtxo(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	h, err := chainhash.NewHashFromStr("e941e1c32b3dd1a68edc3af9f7fe711f35aaca60f758c2dd49561e45ca2c41c0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	op1 := *wire.NewOutPoint(h, 0)
+	op2 := *wire.NewOutPoint(h, 1)
+
+	for _, op := range []wire.OutPoint{op1, op2, op1} {
+		if err := ds.LockUtxo(op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	locked, err := ds.GetLockedUtxos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) != 2 {
+		t.Fatalf("Returned %d locked utxos, expected 2", len(locked))
+	}
+	if err := ds.UnlockUtxo(op1); err != nil {
+		t.Fatal(err)
+	}
+	locked, err = ds.GetLockedUtxos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locked) != 1 || locked[0] != op2 {
+		t.Errorf("Returned the wrong locked utxos %v", locked)
+	}
+	// Account views share the locks
+	if locked, err := ds.Account(1).(*SQLiteDatastore).GetLockedUtxos(); err != nil || len(locked) != 1 {
+		t.Error("Account view returned the wrong locked utxos")
+	}
+}
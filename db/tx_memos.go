@@ -0,0 +1,61 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/gcash/bchd/chaincfg/chainhash"
+)
+
+type TxMemosDB struct {
+	db   *sql.DB
+	lock *sync.RWMutex
+}
+
+func (m *TxMemosDB) Put(txid chainhash.Hash, memo string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, err := m.db.Exec("insert or replace into txMemos(txid, memo) values(?,?)", txid.String(), memo)
+	return err
+}
+
+func (m *TxMemosDB) Get(txid chainhash.Hash) (string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var memo string
+	err := m.db.QueryRow("select memo from txMemos where txid=?", txid.String()).Scan(&memo)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return memo, err
+}
+
+func (m *TxMemosDB) GetAll() (map[chainhash.Hash]string, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	ret := make(map[chainhash.Hash]string)
+	rows, err := m.db.Query("select txid, memo from txMemos")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var txid, memo string
+		if err := rows.Scan(&txid, &memo); err != nil {
+			return ret, err
+		}
+		h, err := chainhash.NewHashFromStr(txid)
+		if err != nil {
+			continue
+		}
+		ret[*h] = memo
+	}
+	return ret, rows.Err()
+}
+
+func (m *TxMemosDB) Delete(txid chainhash.Hash) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, err := m.db.Exec("delete from txMemos where txid=?", txid.String())
+	return err
+}
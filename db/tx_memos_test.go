@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/gcash/bchd/chaincfg/chainhash"
+)
+
+func TestTxMemosDB(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	memos := ds.TxMemos()
+	txid, err := chainhash.NewHashFromStr("e941e1c32b3dd1a68edc3af9f7fe711f35aaca60f758c2dd49561e45ca2c41c0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := memos.Put(*txid, "Order 42"); err != nil {
+		t.Fatal(err)
+	}
+	memo, err := memos.Get(*txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memo != "Order 42" {
+		t.Errorf("Returned memo %s, expected Order 42", memo)
+	}
+	all, err := memos.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[*txid] != "Order 42" {
+		t.Errorf("Returned the wrong memos %v", all)
+	}
+
+	if err := memos.Delete(*txid); err != nil {
+		t.Fatal(err)
+	}
+	memo, err = memos.Get(*txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memo != "" {
+		t.Error("Returned a deleted memo")
+	}
+	if memo, err := memos.Get(chainhash.Hash{}); err != nil || memo != "" {
+		t.Error("Returned a memo for an unknown transaction")
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"bytes"
 	"database/sql"
+	"strconv"
 	"sync"
 	"time"
 
@@ -14,8 +15,14 @@ import (
 type TxnsDB struct {
 	db   *sql.DB
 	lock *sync.RWMutex
+
+	// The BIP44 account of the transactions, or allAccounts
+	account int
 }
 
+// Put saves a transaction. Through the default view value is the net value of
+// the transaction to the whole wallet. Through an account view it is the value
+// to the account, which is recorded alongside the transaction.
 func (t *TxnsDB) Put(txn []byte, txid string, value, height int, timestamp time.Time, watchOnly bool) error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -23,30 +30,33 @@ func (t *TxnsDB) Put(txn []byte, txid string, value, height int, timestamp time.
 	if err != nil {
 		return err
 	}
-	stmt, err := tx.Prepare("insert or replace into txns(txid, value, height, timestamp, watchOnly, tx) values(?,?,?,?,?,?)")
-	defer stmt.Close()
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
 	watchOnlyInt := 0
 	if watchOnly {
 		watchOnlyInt = 1
 	}
-	_, err = stmt.Exec(txid, value, height, int(timestamp.Unix()), watchOnlyInt, txn)
+	if t.account == allAccounts {
+		// The fee saved with the transaction's details is kept
+		_, err = tx.Exec("insert or replace into txns(txid, value, height, timestamp, watchOnly, tx, fee) values(?,?,?,?,?,?,coalesce((select fee from txns where txid=?), 0))",
+			txid, value, height, int(timestamp.Unix()), watchOnlyInt, txn, txid)
+	} else {
+		_, err = tx.Exec("insert or ignore into txns(txid, value, height, timestamp, watchOnly, tx) values(?,?,?,?,?,?)",
+			txid, value, height, int(timestamp.Unix()), watchOnlyInt, txn)
+		if err == nil {
+			_, err = tx.Exec("insert or replace into txnAccounts(txid, account, value) values(?,?,?)", txid, t.account, value)
+		}
+	}
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
-	tx.Commit()
-	return nil
+	return tx.Commit()
 }
 
 func (t *TxnsDB) Get(txid chainhash.Hash) (wallet.Txn, error) {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 	var txn wallet.Txn
-	stmt, err := t.db.Prepare("select tx, value, height, timestamp, watchOnly from txns where txid=?")
+	stmt, err := t.db.Prepare("select tx, " + t.valueColumn() + ", height, timestamp, watchOnly, fee from txns " + t.accountJoin() + " where txns.txid=?")
 	if err != nil {
 		return txn, err
 	}
@@ -56,7 +66,12 @@ func (t *TxnsDB) Get(txid chainhash.Hash) (wallet.Txn, error) {
 	var height int
 	var timestamp int
 	var watchOnlyInt int
-	err = stmt.QueryRow(txid.String()).Scan(&ret, &value, &height, &timestamp, &watchOnlyInt)
+	var fee int64
+	err = stmt.QueryRow(txid.String()).Scan(&ret, &value, &height, &timestamp, &watchOnlyInt, &fee)
+	if err != nil {
+		return txn, err
+	}
+	io, err := t.txnIO(txid.String())
 	if err != nil {
 		return txn, err
 	}
@@ -74,6 +89,9 @@ func (t *TxnsDB) Get(txid chainhash.Hash) (wallet.Txn, error) {
 		Timestamp: time.Unix(int64(timestamp), 0),
 		WatchOnly: watchOnly,
 		Bytes:     ret,
+		Fee:       fee,
+		Inputs:    io[txid.String()].inputs,
+		Outputs:   io[txid.String()].outputs,
 	}
 	return txn, nil
 }
@@ -82,19 +100,25 @@ func (t *TxnsDB) GetAll(includeWatchOnly bool) ([]wallet.Txn, error) {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 	var ret []wallet.Txn
-	stm := "select tx, value, height, timestamp, watchOnly from txns"
+	io, err := t.txnIO("")
+	if err != nil {
+		return ret, err
+	}
+	stm := "select txns.txid, tx, " + t.valueColumn() + ", height, timestamp, watchOnly, fee from txns " + t.accountJoin()
 	rows, err := t.db.Query(stm)
 	if err != nil {
 		return ret, err
 	}
 	defer rows.Close()
 	for rows.Next() {
+		var txid string
 		var tx []byte
 		var value int
 		var height int
 		var timestamp int
 		var watchOnlyInt int
-		if err := rows.Scan(&tx, &value, &height, &timestamp, &watchOnlyInt); err != nil {
+		var fee int64
+		if err := rows.Scan(&txid, &tx, &value, &height, &timestamp, &watchOnlyInt, &fee); err != nil {
 			continue
 		}
 		r := bytes.NewReader(tx)
@@ -114,6 +138,9 @@ func (t *TxnsDB) GetAll(includeWatchOnly bool) ([]wallet.Txn, error) {
 			Timestamp: time.Unix(int64(timestamp), 0),
 			WatchOnly: watchOnly,
 			Bytes:     tx,
+			Fee:       fee,
+			Inputs:    io[txid].inputs,
+			Outputs:   io[txid].outputs,
 		}
 		ret = append(ret, txn)
 	}
@@ -127,7 +154,94 @@ func (t *TxnsDB) Delete(txid *chainhash.Hash) error {
 	if err != nil {
 		return err
 	}
-	return nil
+	_, err = t.db.Exec("delete from txnAccounts where txid=?", txid.String())
+	if err != nil {
+		return err
+	}
+	_, err = t.db.Exec("delete from txnIO where txid=?", txid.String())
+	return err
+}
+
+// accountJoin restricts a query of txns to the transactions of the view's
+// account.
+func (t *TxnsDB) accountJoin() string {
+	if t.account == allAccounts {
+		return ""
+	}
+	return "join txnAccounts on txnAccounts.txid=txns.txid and txnAccounts.account=" + strconv.Itoa(t.account)
+}
+
+// valueColumn returns the column holding the value of a transaction to the
+// view's account.
+func (t *TxnsDB) valueColumn() string {
+	if t.account == allAccounts {
+		return "txns.value"
+	}
+	return "txnAccounts.value"
+}
+
+func (t *TxnsDB) PutDetails(txid chainhash.Hash, fee int64, inputs []wallet.TxnIO, outputs []wallet.TxnIO) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	tx, err := t.db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("update txns set fee=? where txid=?", fee, txid.String()); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("delete from txnIO where txid=?", txid.String()); err != nil {
+		tx.Rollback()
+		return err
+	}
+	stmt, err := tx.Prepare("insert into txnIO(txid, output, idx, address, value) values(?,?,?,?,?)")
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for i, list := range [][]wallet.TxnIO{inputs, outputs} {
+		for _, io := range list {
+			if _, err := stmt.Exec(txid.String(), i, io.Index, io.Address, io.Value); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+type txnInputsOutputs struct {
+	inputs, outputs []wallet.TxnIO
+}
+
+// txnIO returns the recorded inputs and outputs of a transaction, or of all
+// transactions if txid is empty, keyed by txid. The caller must hold the lock.
+func (t *TxnsDB) txnIO(txid string) (map[string]txnInputsOutputs, error) {
+	ret := make(map[string]txnInputsOutputs)
+	rows, err := t.db.Query("select txid, output, idx, address, value from txnIO where ?='' or txid=? order by txid, output, idx", txid, txid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, address string
+		var output int
+		var index uint32
+		var value int64
+		if err := rows.Scan(&id, &output, &index, &address, &value); err != nil {
+			return nil, err
+		}
+		io := ret[id]
+		if output > 0 {
+			io.outputs = append(io.outputs, wallet.TxnIO{Index: index, Address: address, Value: value})
+		} else {
+			io.inputs = append(io.inputs, wallet.TxnIO{Index: index, Address: address, Value: value})
+		}
+		ret[id] = io
+	}
+	return ret, rows.Err()
 }
 
 func (t *TxnsDB) UpdateHeight(txid chainhash.Hash, height int, timestamp time.Time) error {
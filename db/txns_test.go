@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/hex"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
 	"github.com/gcash/bchd/wire"
 	"sync"
 	"testing"
@@ -153,3 +154,59 @@ func TestTxnsDB_UpdateHeight(t *testing.T) {
 		t.Error("Txn db failed to update height")
 	}
 }
+
+func TestTxnsDB_PutDetails(t *testing.T) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	txHex := "01000000018b5d47ec7ae47ae2e158345069fd38a1460f436c486fd3376de24b5df8da62a201000000da00483045022100d9dfd2bd3762fbb06d4b7d37ba3544aefd2ea9913a728b90b446abf530eed03d0220066f3fe0e7a652d2383cfa3b06188301e7ff02320d3a9b32675d1b0d62e9de740147304402206271eb865f0a5f92fdb4306711c17f53c959a09d401cd375bf60904191f70e080220231368d0bacde1775505f6978486b2732179f3d3f971aa67690475763c3d96de01475221024760c9ba5fa6241da6ee8601f0266f0e0592f53735703f0feaae23eda6673ae821038cfa8e97caaafbe21455803043618440c28c501ec32d6ece6865003165a0d4d152aeffffffff0224eb1400000000001976a91411a23852c4554182abb97f811509d60015071a5188acc4c59d260000000017a9140be09225644b4cfdbb472028d8ccaf6df736025c8700000000"
+	raw, _ := hex.DecodeString(txHex)
+	tx.Deserialize(bytes.NewReader(raw))
+
+	if err := txdb.Put(raw, tx.TxHash().String(), -1500000, 1, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	inputs := []wallet.TxnIO{{Index: 0, Address: "3MzDsFYdTgDBmkJe5LgEs8KDTcPhtXV8qm", Value: 1502000}}
+	outputs := []wallet.TxnIO{{Index: 1, Address: "12U1rw4ZAGrzSMX4i5Y9Pp4uNe6KKzkYjx", Value: 648051140}}
+	if err := txdb.PutDetails(tx.TxHash(), 2000, inputs, outputs); err != nil {
+		t.Fatal(err)
+	}
+	// Saving the transaction again keeps its fee
+	if err := txdb.Put(raw, tx.TxHash().String(), -1500000, 2, time.Now(), false); err != nil {
+		t.Fatal(err)
+	}
+	txn, err := txdb.Get(tx.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Fee != 2000 {
+		t.Errorf("Returned fee %d, expected 2000", txn.Fee)
+	}
+	if len(txn.Inputs) != 1 || txn.Inputs[0] != inputs[0] {
+		t.Errorf("Returned inputs %v, expected %v", txn.Inputs, inputs)
+	}
+	if len(txn.Outputs) != 1 || txn.Outputs[0] != outputs[0] {
+		t.Errorf("Returned outputs %v, expected %v", txn.Outputs, outputs)
+	}
+	txns, err := txdb.GetAll(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, txn := range txns {
+		if txn.Txid == tx.TxHash().String() {
+			found = txn.Fee == 2000 && len(txn.Inputs) == 1 && len(txn.Outputs) == 1
+		}
+	}
+	if !found {
+		t.Error("GetAll didn't return the details")
+	}
+
+	txid := tx.TxHash()
+	if err := txdb.Delete(&txid); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	txdb.db.QueryRow("select count(*) from txnIO where txid=?", txid.String()).Scan(&count)
+	if count != 0 {
+		t.Error("Deleting a transaction didn't delete its details")
+	}
+}
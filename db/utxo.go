@@ -15,13 +15,17 @@ import (
 type UtxoDB struct {
 	db   *sql.DB
 	lock *sync.RWMutex
+
+	// The BIP44 account of the utxos, or allAccounts
+	account int
 }
 
 func (u *UtxoDB) Put(utxo wallet.Utxo) error {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 	tx, _ := u.db.Begin()
-	stmt, err := tx.Prepare("insert or replace into utxos(outpoint, value, height, scriptPubKey, watchOnly) values(?,?,?,?,?)")
+	// Updating a utxo through the default view keeps its account
+	stmt, err := tx.Prepare("insert or replace into utxos(outpoint, value, height, scriptPubKey, watchOnly, account) values(?,?,?,?,?,coalesce((select account from utxos where outpoint=? and ?), ?))")
 	defer stmt.Close()
 	if err != nil {
 		tx.Rollback()
@@ -32,7 +36,8 @@ func (u *UtxoDB) Put(utxo wallet.Utxo) error {
 	if utxo.WatchOnly {
 		watchOnly = 1
 	}
-	_, err = stmt.Exec(outpoint, int(utxo.Value), int(utxo.AtHeight), hex.EncodeToString(utxo.ScriptPubkey), watchOnly)
+	_, err = stmt.Exec(outpoint, int(utxo.Value), int(utxo.AtHeight), hex.EncodeToString(utxo.ScriptPubkey), watchOnly,
+		outpoint, u.account == allAccounts, accountValue(u.account))
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -45,8 +50,8 @@ func (u *UtxoDB) GetAll() ([]wallet.Utxo, error) {
 	u.lock.RLock()
 	defer u.lock.RUnlock()
 	var ret []wallet.Utxo
-	stm := "select outpoint, value, height, scriptPubKey, watchOnly from utxos"
-	rows, err := u.db.Query(stm)
+	stm := "select outpoint, value, height, scriptPubKey, watchOnly from utxos where ?=-1 or account=?"
+	rows, err := u.db.Query(stm, u.account, u.account)
 	defer rows.Close()
 	if err != nil {
 		return ret, err
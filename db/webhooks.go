@@ -0,0 +1,65 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+type WebhooksDB struct {
+	db   *sql.DB
+	lock *sync.RWMutex
+}
+
+func (w *WebhooksDB) Put(event wallet.WebhookEvent) (bool, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	res, err := w.db.Exec("insert or ignore into webhooks(id, payload, attempts, nextAttempt, done) values(?,?,?,?,?)",
+		event.ID, event.Payload, event.Attempts, event.NextAttempt.UnixNano(), event.Done)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (w *WebhooksDB) Has(id string) (bool, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	var n int
+	err := w.db.QueryRow("select count(*) from webhooks where id=?", id).Scan(&n)
+	return n > 0, err
+}
+
+func (w *WebhooksDB) GetPending() ([]wallet.WebhookEvent, error) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	var ret []wallet.WebhookEvent
+	rows, err := w.db.Query("select id, payload, attempts, nextAttempt from webhooks where done=0 order by nextAttempt asc, rowid asc")
+	if err != nil {
+		return ret, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			event       wallet.WebhookEvent
+			nextAttempt int64
+		)
+		if err := rows.Scan(&event.ID, &event.Payload, &event.Attempts, &nextAttempt); err != nil {
+			return ret, err
+		}
+		event.NextAttempt = time.Unix(0, nextAttempt)
+		ret = append(ret, event)
+	}
+	return ret, rows.Err()
+}
+
+func (w *WebhooksDB) Update(event wallet.WebhookEvent) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err := w.db.Exec("update webhooks set attempts=?, nextAttempt=?, done=? where id=?",
+		event.Attempts, event.NextAttempt.UnixNano(), event.Done, event.ID)
+	return err
+}
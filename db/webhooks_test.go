@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+func TestWebhooksDB(t *testing.T) {
+	ds, cleanup := createTestDatastore(t)
+	defer cleanup()
+	webhooks := ds.Webhooks()
+	now := time.Now()
+
+	first := wallet.WebhookEvent{ID: "a:received", Payload: []byte(`{"id":"a:received"}`), NextAttempt: now.Add(time.Minute)}
+	second := wallet.WebhookEvent{ID: "b:received", Payload: []byte(`{"id":"b:received"}`), NextAttempt: now}
+	for _, event := range []wallet.WebhookEvent{first, second} {
+		if queued, err := webhooks.Put(event); err != nil || !queued {
+			t.Fatalf("Failed to queue %s: %v", event.ID, err)
+		}
+	}
+	// An event is only ever queued once
+	if queued, err := webhooks.Put(first); err != nil || queued {
+		t.Error("Queued an event twice")
+	}
+	if has, err := webhooks.Has("a:received"); err != nil || !has {
+		t.Error("Event was not found")
+	}
+	if has, _ := webhooks.Has("c:received"); has {
+		t.Error("Found an event which was not queued")
+	}
+
+	pending, err := webhooks.GetPending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 2 || pending[0].ID != second.ID || pending[1].ID != first.ID {
+		t.Fatalf("Pending events are not ordered by next attempt: %v", pending)
+	}
+	if string(pending[1].Payload) != string(first.Payload) || !pending[1].NextAttempt.Equal(first.NextAttempt) {
+		t.Errorf("Returned the wrong event %v", pending[1])
+	}
+
+	second.Attempts = 3
+	second.NextAttempt = now.Add(time.Hour)
+	if err := webhooks.Update(second); err != nil {
+		t.Fatal(err)
+	}
+	first.Done = true
+	if err := webhooks.Update(first); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = webhooks.GetPending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].Attempts != 3 || !pending[0].NextAttempt.Equal(second.NextAttempt) {
+		t.Errorf("Event was not updated: %v", pending)
+	}
+	// Done events are still known so they aren't queued again
+	if has, _ := webhooks.Has("a:received"); !has {
+		t.Error("Done event was forgotten")
+	}
+}
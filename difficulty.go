@@ -0,0 +1,193 @@
+package bitcoincash
+
+import (
+	"math/big"
+
+	"github.com/gcash/bchd/blockchain"
+	"github.com/gcash/bchd/chaincfg"
+)
+
+const (
+	// Number of blocks between legacy (pre-fork) difficulty retargets.
+	retargetInterval = 2016
+
+	// Target timespan of a legacy retarget interval.
+	retargetTimespan = retargetInterval * targetSpacing
+)
+
+// asertAnchor is the block the aserti3-2d algorithm computes all later
+// targets relative to. The anchor block is the last block mined under the
+// cw-144 rules. For every HalfLife seconds the chain is ahead of (or behind)
+// schedule the target is halved (or doubled).
+type asertAnchor struct {
+	Height          uint32
+	Bits            uint32
+	ParentTimestamp int64
+	HalfLife        int64
+}
+
+// difficultyRules holds the heights at which each difficulty algorithm was
+// activated on a network. Blocks above UahfHeight use the emergency
+// difficulty adjustment, blocks above DaaHeight use cw-144 and blocks above
+// the ASERT anchor height use aserti3-2d.
+type difficultyRules struct {
+	UahfHeight uint32
+	DaaHeight  uint32
+	Asert      *asertAnchor
+}
+
+var difficultyRulesByNetwork = map[string]difficultyRules{
+	chaincfg.MainNetParams.Name: {
+		UahfHeight: 478558,
+		DaaHeight:  504031,
+		Asert: &asertAnchor{
+			Height:          661647,
+			Bits:            0x1804dafe,
+			ParentTimestamp: 1605447844,
+			HalfLife:        2 * 24 * 60 * 60,
+		},
+	},
+	chaincfg.TestNet3Params.Name: {
+		UahfHeight: 1155875,
+		DaaHeight:  1188697,
+		Asert: &asertAnchor{
+			Height:          1421481,
+			Bits:            0x1d00ffff,
+			ParentTimestamp: 1605445400,
+			HalfLife:        60 * 60,
+		},
+	},
+}
+
+// getDifficultyRules returns the difficulty rules for the given network. Networks
+// without an entry (regtest) use cw-144 for every block.
+func getDifficultyRules(params *chaincfg.Params) difficultyRules {
+	if rules, ok := difficultyRulesByNetwork[params.Name]; ok {
+		return rules
+	}
+	return difficultyRules{}
+}
+
+// calcASERT computes the target for the block following prevHeader using the
+// aserti3-2d algorithm. This is a direct port of the reference implementation
+// and uses only integer arithmetic so every node arrives at the same result.
+func calcASERT(prevHeader StoredHeader, anchor asertAnchor, p *chaincfg.Params) uint32 {
+	timeDelta := prevHeader.header.Timestamp.Unix() - anchor.ParentTimestamp
+	heightDelta := int64(prevHeader.height) - int64(anchor.Height)
+
+	// Go's integer division truncates toward zero which matches the reference.
+	exponent := ((timeDelta - targetSpacing*(heightDelta+1)) * 65536) / anchor.HalfLife
+
+	// The right shift is arithmetic and therefore floors negative exponents.
+	shifts := exponent >> 16
+	frac := uint64(uint16(exponent))
+
+	// 2^x ~= (1 + 0.695502049*x + 0.2262698*x**2 + 0.0782318*x**3) for 0 <= x < 1
+	factor := 65536 + ((195766423245049*frac +
+		971821376*frac*frac +
+		5127*frac*frac*frac +
+		(1 << 47)) >> 48)
+
+	nextTarget := new(big.Int).Mul(blockchain.CompactToBig(anchor.Bits), new(big.Int).SetUint64(factor))
+
+	shifts -= 16
+	if shifts <= 0 {
+		nextTarget.Rsh(nextTarget, uint(-shifts))
+	} else {
+		nextTarget.Lsh(nextTarget, uint(shifts))
+	}
+
+	if nextTarget.Sign() == 0 {
+		// Zero is not a valid target but one is.
+		nextTarget.SetInt64(1)
+	} else if nextTarget.Cmp(p.PowLimit) > 0 {
+		nextTarget.Set(p.PowLimit)
+	}
+	return blockchain.BigToCompact(nextTarget)
+}
+
+// calcLegacyRetarget computes the target at a 2016 block boundary using the
+// original Bitcoin rules. firstHeader is the first block of the interval.
+func calcLegacyRetarget(firstHeader, prevHeader StoredHeader, p *chaincfg.Params) uint32 {
+	timespan := prevHeader.header.Timestamp.Unix() - firstHeader.header.Timestamp.Unix()
+	if timespan < retargetTimespan/4 {
+		timespan = retargetTimespan / 4
+	} else if timespan > retargetTimespan*4 {
+		timespan = retargetTimespan * 4
+	}
+
+	newTarget := blockchain.CompactToBig(prevHeader.header.Bits)
+	newTarget.Mul(newTarget, big.NewInt(timespan))
+	newTarget.Div(newTarget, big.NewInt(retargetTimespan))
+
+	if newTarget.Cmp(p.PowLimit) > 0 {
+		newTarget.Set(p.PowLimit)
+	}
+	return blockchain.BigToCompact(newTarget)
+}
+
+// calcEDA computes the target used between the August 2017 fork and the
+// November 2017 fork. A retarget happens every 2016 blocks and in between the
+// target is raised by 25% whenever the last six blocks took more than twelve hours.
+func (b *Blockchain) calcEDA(height int32, prevHeader StoredHeader) (uint32, error) {
+	if height%retargetInterval == 0 {
+		first, err := b.getAncestor(prevHeader, retargetInterval-1)
+		if err != nil {
+			return 0, err
+		}
+		return calcLegacyRetarget(first, prevHeader, b.params), nil
+	}
+
+	// Testnet blocks mined under the twenty minute rule carry the minimum difficulty
+	// so walk back to the last block that didn't.
+	if b.params.ReduceMinDifficulty {
+		sh := prevHeader
+		var err error
+		for sh.height%retargetInterval != 0 && sh.header.Bits == b.params.PowLimitBits {
+			sh, err = b.db.GetPreviousHeader(sh.header)
+			if err != nil {
+				return 0, err
+			}
+		}
+		return sh.header.Bits, nil
+	}
+
+	if prevHeader.height < getDifficultyRules(b.params).UahfHeight {
+		return prevHeader.header.Bits, nil
+	}
+
+	sixBack, err := b.getAncestor(prevHeader, 6)
+	if err != nil {
+		return 0, err
+	}
+	prevMTP, err := b.CalcMedianTimePast(prevHeader.header)
+	if err != nil {
+		return 0, err
+	}
+	sixBackMTP, err := b.CalcMedianTimePast(sixBack.header)
+	if err != nil {
+		return 0, err
+	}
+	if prevMTP.Sub(sixBackMTP).Seconds() < 12*60*60 {
+		return prevHeader.header.Bits, nil
+	}
+
+	newTarget := blockchain.CompactToBig(prevHeader.header.Bits)
+	newTarget.Add(newTarget, new(big.Int).Rsh(newTarget, 2))
+	if newTarget.Cmp(b.params.PowLimit) > 0 {
+		newTarget.Set(b.params.PowLimit)
+	}
+	return blockchain.BigToCompact(newTarget), nil
+}
+
+// getAncestor rolls back n headers from sh.
+func (b *Blockchain) getAncestor(sh StoredHeader, n int) (StoredHeader, error) {
+	var err error
+	for i := 0; i < n; i++ {
+		sh, err = b.db.GetPreviousHeader(sh.header)
+		if err != nil {
+			return sh, err
+		}
+	}
+	return sh, nil
+}
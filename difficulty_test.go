@@ -0,0 +1,229 @@
+package bitcoincash
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gcash/bchd/blockchain"
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchd/wire"
+)
+
+// putHeaderChain stores count headers on top of parent. The timestamp of each
+// header is returned by timestamp(i) where i is the offset from parent.
+func putHeaderChain(bc *Blockchain, parent StoredHeader, count int, bits uint32, timestamp func(i int) time.Time) (StoredHeader, error) {
+	if err := bc.db.(*HeaderDB).put(parent, true); err != nil {
+		return parent, err
+	}
+	last := parent
+	for i := 1; i <= count; i++ {
+		hdr := wire.BlockHeader{
+			Version:   536870912,
+			PrevBlock: last.header.BlockHash(),
+			Timestamp: timestamp(i),
+			Bits:      bits,
+			Nonce:     uint32(i),
+		}
+		sh := StoredHeader{
+			header:    hdr,
+			height:    last.height + 1,
+			totalWork: new(big.Int).Add(last.totalWork, blockchain.CalcWork(bits)),
+		}
+		if err := bc.db.(*HeaderDB).put(sh, true); err != nil {
+			return sh, err
+		}
+		last = sh
+	}
+	return last, nil
+}
+
+func TestCalcASERT(t *testing.T) {
+	anchor := *difficultyRulesByNetwork[chaincfg.MainNetParams.Name].Asert
+	tests := []struct {
+		name     string
+		skew     int64
+		expected uint32
+	}{
+		{"on schedule", 0, 0x1804dafe},
+		{"one hour ahead", -3600, 0x1804c938},
+		{"one day behind", anchor.HalfLife / 2, 0x1806ddb4},
+		{"one half life behind", anchor.HalfLife, 0x1809b5fc},
+		{"one half life ahead", -anchor.HalfLife, 0x18026d7f},
+		{"far behind", anchor.HalfLife * 100, chaincfg.MainNetParams.PowLimitBits},
+		{"far ahead", -anchor.HalfLife * 300, 0x01010000},
+	}
+	for _, test := range tests {
+		prev := StoredHeader{
+			header: wire.BlockHeader{
+				Timestamp: time.Unix(anchor.ParentTimestamp+targetSpacing*11+test.skew, 0),
+			},
+			height: anchor.Height + 10,
+		}
+		bits := calcASERT(prev, anchor, &chaincfg.MainNetParams)
+		if bits != test.expected {
+			t.Errorf("%s: expected bits %x, got %x", test.name, test.expected, bits)
+		}
+	}
+}
+
+func TestCalcASERTTestnet(t *testing.T) {
+	// Testnet targets halve or double every hour instead of every two days
+	anchor := *difficultyRulesByNetwork[chaincfg.TestNet3Params.Name].Asert
+	tests := []struct {
+		name     string
+		skew     int64
+		expected uint32
+	}{
+		{"on schedule", 0, 0x1d00ffff},
+		{"one hour ahead", -3600, 0x1c7fff80},
+		{"two hours ahead", -7200, 0x1c3fffc0},
+		{"one hour behind", 3600, chaincfg.TestNet3Params.PowLimitBits},
+	}
+	for _, test := range tests {
+		prev := StoredHeader{
+			header: wire.BlockHeader{
+				Timestamp: time.Unix(anchor.ParentTimestamp+targetSpacing*11+test.skew, 0),
+			},
+			height: anchor.Height + 10,
+		}
+		bits := calcASERT(prev, anchor, &chaincfg.TestNet3Params)
+		if bits != test.expected {
+			t.Errorf("%s: expected bits %x, got %x", test.name, test.expected, bits)
+		}
+	}
+}
+
+func TestBlockchain_calcRequiredWorkRules(t *testing.T) {
+	anchor := *difficultyRulesByNetwork[chaincfg.MainNetParams.Name].Asert
+	tests := []struct {
+		name string
+		// height of the first stored header
+		start uint32
+		// number of headers stored after the first
+		count int
+		// block spacing in seconds
+		spacing int64
+		// added to the timestamp of the last header
+		skew     int64
+		expected uint32
+	}{
+		{"asert first block", anchor.Height - 1, 1, targetSpacing, 0, anchor.Bits},
+		{"asert on schedule", anchor.Height - 1, 20, targetSpacing, 0, anchor.Bits},
+		{"asert behind", anchor.Height - 1, 20, targetSpacing, anchor.HalfLife, 0x1809b5fc},
+		{"asert ahead", anchor.Height - 1, 20, targetSpacing, -anchor.HalfLife, 0x18026d7f},
+		{"eda no change", 499980, 20, targetSpacing, 0, anchor.Bits},
+		{"eda slow blocks", 499980, 20, 3 * 60 * 60, 0, 0x180611bd},
+		{"legacy retarget", 489887 - 2015, 2015, targetSpacing, 0, 0x1804da60},
+	}
+	for _, test := range tests {
+		bc, err := NewBlockchain("", MockCreationTime, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatal(err)
+		}
+		startTime := anchor.ParentTimestamp
+		parent := StoredHeader{
+			header: wire.BlockHeader{
+				Timestamp: time.Unix(startTime, 0),
+				Bits:      anchor.Bits,
+			},
+			height:    test.start,
+			totalWork: big.NewInt(0),
+		}
+		prev, err := putHeaderChain(bc, parent, test.count, anchor.Bits, func(i int) time.Time {
+			ts := startTime + test.spacing*int64(i)
+			if i == test.count {
+				ts += test.skew
+			}
+			return time.Unix(ts, 0)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hdr := wire.BlockHeader{PrevBlock: prev.header.BlockHash()}
+		bits, err := bc.calcRequiredWork(hdr, int32(prev.height+1), prev)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		}
+		if bits != test.expected {
+			t.Errorf("%s: expected bits %x, got %x", test.name, test.expected, bits)
+		}
+		bc.Close()
+		os.RemoveAll("headers.bin")
+	}
+}
+
+func TestBlockchain_calcRequiredWorkCW144(t *testing.T) {
+	anchor := *difficultyRulesByNetwork[chaincfg.MainNetParams.Name].Asert
+	bc, err := NewBlockchain("", MockCreationTime, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("headers.bin")
+	parent := StoredHeader{
+		header: wire.BlockHeader{
+			Timestamp: time.Unix(anchor.ParentTimestamp, 0),
+			Bits:      anchor.Bits,
+		},
+		height:    anchor.Height - 150,
+		totalWork: big.NewInt(0),
+	}
+	// Blocks arriving every five minutes must increase the difficulty
+	prev, err := putHeaderChain(bc, parent, 149, anchor.Bits, func(i int) time.Time {
+		return time.Unix(anchor.ParentTimestamp+300*int64(i), 0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev.height != anchor.Height-1 {
+		t.Fatal("Built chain to incorrect height")
+	}
+	hdr := wire.BlockHeader{PrevBlock: prev.header.BlockHash()}
+	bits, err := bc.calcRequiredWork(hdr, int32(anchor.Height), prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suitable, err := bc.GetSuitableBlock(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	epoch, err := bc.GetEpoch(prev.header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bits != calcDiffAdjust(epoch, suitable, bc.params) {
+		t.Error("Anchor block was not computed with cw-144")
+	}
+	if blockchain.CompactToBig(bits).Cmp(blockchain.CompactToBig(anchor.Bits)) >= 0 {
+		t.Error("cw-144 failed to increase difficulty for fast blocks")
+	}
+}
+
+// The checkpoints are the only real headers bundled with the wallet. Longer
+// runs across the EDA, cw-144 and ASERT activations are still to be added.
+func TestCheckpointHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     *chaincfg.Params
+		checkpoint Checkpoint
+		hash       string
+	}{
+		{"mainnet first cw-144 block", &chaincfg.MainNetParams, mainnetCheckpoints[0], "00000000000000000343e9875012f2062554c8752929892c82a0c0743ac7dcfd"},
+		{"testnet3 cw-144 min difficulty block", &chaincfg.TestNet3Params, testnet3Checkpoints[0], "000000001f734385476b82be8eb10512c9fb5bd1534cf3ceb4af2d47a7b20ff7"},
+	}
+	for _, test := range tests {
+		hdr := test.checkpoint.Header
+		hash := hdr.BlockHash()
+		if hash.String() != test.hash {
+			t.Errorf("%s: hash %s, expected %s", test.name, hash, test.hash)
+		}
+		if blockchain.HashToBig(&hash).Cmp(blockchain.CompactToBig(hdr.Bits)) > 0 {
+			t.Errorf("%s: hash does not meet target %x", test.name, hdr.Bits)
+		}
+		rules := getDifficultyRules(test.params)
+		if test.checkpoint.Height <= rules.DaaHeight || test.checkpoint.Height > rules.Asert.Height {
+			t.Errorf("%s: height %d is not in the cw-144 range", test.name, test.checkpoint.Height)
+		}
+	}
+}
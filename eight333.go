@@ -69,6 +69,12 @@ type WireServiceConfig struct {
 	txStore            *TxStore
 	walletCreationDate time.Time
 	minPeersForSync    int
+	syncMode           SyncMode
+	events             *eventHub
+
+	// Disconnects a misbehaving peer and stops us reconnecting to it.
+	// Defaults to just disconnecting.
+	banPeer func(*peerpkg.Peer)
 }
 
 // peerSyncState stores additional information that the WireService tracks
@@ -87,6 +93,7 @@ type WireService struct {
 	chain              *Blockchain
 	txStore            *TxStore
 	walletCreationDate time.Time
+	syncMode           SyncMode
 	syncPeer           *peerpkg.Peer
 	peerStates         map[*peerpkg.Peer]*peerSyncState
 	requestedTxns      map[chainhash.Hash]heightAndTime
@@ -99,15 +106,31 @@ type WireService struct {
 	listeners   []func(wallet.BlockCallback)
 	cbMutex     *sync.Mutex
 
+	events *eventHub
+
+	// The height of the last EventSyncProgress and whether we were synced
+	syncProgress uint32
+	synced       bool
+
 	minPeersForSync int
 	zeroHash        chainhash.Hash
+
+	banPeer func(*peerpkg.Peer)
+
+	// Compact filter sync state. Only used in CompactFilterSync mode.
+	cf *cfSyncState
 }
 
 func NewWireService(config *WireServiceConfig) *WireService {
+	banPeer := config.banPeer
+	if banPeer == nil {
+		banPeer = func(peer *peerpkg.Peer) { peer.Disconnect() }
+	}
 	return &WireService{
 		params:             config.params,
 		chain:              config.chain,
 		walletCreationDate: config.walletCreationDate,
+		syncMode:           config.syncMode,
 		minPeersForSync:    config.minPeersForSync,
 		txStore:            config.txStore,
 		peerStates:         make(map[*peerpkg.Peer]*peerSyncState),
@@ -117,6 +140,9 @@ func NewWireService(config *WireServiceConfig) *WireService {
 		showTipOnly:        make(map[int]bool),
 		msgChan:            make(chan interface{}),
 		cbMutex:            new(sync.Mutex),
+		events:             config.events,
+		cf:                 newCFSyncState(),
+		banPeer:            banPeer,
 	}
 }
 
@@ -144,7 +170,11 @@ out:
 			case donePeerMsg:
 				ws.handleDonePeerMsg(msg.peer)
 			case headersMsg:
-				ws.handleHeadersMsg(&msg)
+				if ws.syncMode == CompactFilterSync {
+					ws.handleHeadersMsgCF(&msg)
+				} else {
+					ws.handleHeadersMsg(&msg)
+				}
 			case merkleBlockMsg:
 				ws.handleMerkleBlockMsg(&msg)
 			case invMsg:
@@ -153,6 +183,14 @@ out:
 				ws.handleTxMsg(&msg)
 			case updateFiltersMsg:
 				ws.handleUpdateFiltersMsg()
+			case cfheadersMsg:
+				ws.handleCFHeadersMsg(&msg)
+			case cfheadersTimeoutMsg:
+				ws.handleCFHeadersTimeout(&msg)
+			case cfilterMsg:
+				ws.handleCFilterMsg(&msg)
+			case blockMsg:
+				ws.handleBlockMsg(&msg)
 			default:
 				log.Warningf("Unknown message type sent to WireService message chan: %T", msg)
 			}
@@ -180,10 +218,14 @@ func (ws *WireService) handleNewPeerMsg(peer *peerpkg.Peer) {
 	}
 
 	ws.updateFilterAndSend(peer)
+	ws.events.publish(Event{Type: EventPeerConnected, Peer: peer, PeerCount: len(ws.peerStates)})
 
 	// If we don't have a sync peer and we are not current we should start a sync
 	if ws.syncPeer == nil && !ws.Current() {
 		ws.startSync(nil)
+	} else if ws.syncMode == CompactFilterSync && ws.Current() {
+		// Resume any filter sync which stalled for lack of peers
+		ws.cfSyncNext()
 	}
 }
 
@@ -273,9 +315,10 @@ func (ws *WireService) startSync(syncPeer *peerpkg.Peer) {
 		// up to the wallet creation date since we know there wont be any transactions in those
 		// blocks we're interested in. However, if we're past the wallet creation date we need to
 		// start downloading merkle blocks so we learn of the wallet's transactions. We'll use a
-		// buffer of one week to make sure we don't miss anything.
+		// buffer of one week to make sure we don't miss anything. In compact filter mode we
+		// always sync headers and fetch the filters once caught up.
 		log.Infof("Starting chain download from %s", bestPeer)
-		if bestBlock.header.Timestamp.Before(ws.walletCreationDate.Add(-time.Hour * 24 * 7)) {
+		if ws.syncMode == CompactFilterSync || bestBlock.header.Timestamp.Before(ws.walletCreationDate.Add(-time.Hour*24*7)) {
 			bestPeer.PushGetHeadersMsg(locator, &ws.zeroHash)
 		} else {
 			bestPeer.PushGetBlocksMsg(locator, &ws.zeroHash)
@@ -313,6 +356,7 @@ func (ws *WireService) handleDonePeerMsg(peer *peerpkg.Peer) {
 
 	// Remove the peer from the list of candidate peers.
 	delete(ws.peerStates, peer)
+	ws.events.publish(Event{Type: EventPeerDisconnected, Peer: peer, PeerCount: len(ws.peerStates)})
 
 	// Remove requested transactions from the global map so that they will
 	// be fetched from elsewhere next time we get an inv.
@@ -328,6 +372,10 @@ func (ws *WireService) handleDonePeerMsg(peer *peerpkg.Peer) {
 		delete(ws.requestedBlocks, blockHash)
 	}
 
+	if ws.syncMode == CompactFilterSync {
+		ws.handleDonePeerCF(peer)
+	}
+
 	// Attempt to find a new peer to sync from if the quitting peer is the
 	// sync peer.
 	if ws.syncPeer == peer && !ws.Current() {
@@ -390,6 +438,8 @@ func (ws *WireService) handleHeadersMsg(hmsg *headersMsg) {
 		return
 	}
 
+	ws.publishSyncProgress()
+
 	// Request the next batch of headers
 	locator := ws.chain.GetBlockLocator()
 	err := peer.PushGetHeadersMsg(locator, &ws.zeroHash)
@@ -496,29 +546,8 @@ func (ws *WireService) handleMerkleBlockMsg(bmsg *merkleBlockMsg) {
 		return
 	}
 
-	ws.cbMutex.Lock()
-	cb := wallet.BlockCallback{
-		Hash:      header.BlockHash().String(),
-		Height:    newHeight,
-		Timestamp: header.Timestamp,
-		ChainTip:  (len(state.requestQueue) == 0),
-		PrevBlock: header.PrevBlock.String(),
-		Version:   header.Version,
-	}
-
-	for i, listener := range ws.listeners {
-		header.BlockHash().String()
-		if showTip, ok := ws.showTipOnly[i]; ok && (listener != nil) {
-			if showTip {
-				if cb.ChainTip {
-					listener(cb)
-				}
-			} else {
-				listener(cb)
-			}
-		}
-	}
-	ws.cbMutex.Unlock()
+	ws.notifyBlockListeners(header, newHeight, len(state.requestQueue) == 0)
+	ws.publishSyncProgress()
 
 	log.Infof("Received merkle block %s at height %d", blockHash.String(), newHeight)
 
@@ -536,6 +565,9 @@ func (ws *WireService) handleMerkleBlockMsg(bmsg *merkleBlockMsg) {
 			log.Error(err)
 		}
 
+		tip := blockCallback(header, newHeight, true)
+		ws.events.publish(Event{Type: EventReorg, Block: &tip, ReorgHeight: reorg.height})
+
 		// Clear request state for new sync
 		state.requestQueue = []*wire.InvVect{}
 		state.requestedBlocks = make(map[chainhash.Hash]struct{})
@@ -563,6 +595,63 @@ func (ws *WireService) handleMerkleBlockMsg(bmsg *merkleBlockMsg) {
 	}
 }
 
+// notifyBlockListeners calls the block listeners for a new block. Listeners
+// which only want the chain tip are skipped unless chainTip is set.
+func (ws *WireService) notifyBlockListeners(header wire.BlockHeader, height uint32, chainTip bool) {
+	cb := blockCallback(header, height, chainTip)
+	if chainTip {
+		ws.events.publish(Event{Type: EventNewTip, Block: &cb})
+	}
+
+	ws.cbMutex.Lock()
+	defer ws.cbMutex.Unlock()
+	for i, listener := range ws.listeners {
+		if showTip, ok := ws.showTipOnly[i]; ok && (listener != nil) {
+			if showTip {
+				if cb.ChainTip {
+					listener(cb)
+				}
+			} else {
+				listener(cb)
+			}
+		}
+	}
+}
+
+func blockCallback(header wire.BlockHeader, height uint32, chainTip bool) wallet.BlockCallback {
+	return wallet.BlockCallback{
+		Hash:      header.BlockHash().String(),
+		Height:    height,
+		Timestamp: header.Timestamp,
+		ChainTip:  chainTip,
+		PrevBlock: header.PrevBlock.String(),
+		Version:   header.Version,
+	}
+}
+
+// publishSyncProgress sends an EventSyncProgress with the height of our best
+// header and the best height our peers report, if our height changed while
+// syncing. Once synced new blocks are only sent as EventNewTip.
+func (ws *WireService) publishSyncProgress() {
+	best, err := ws.chain.BestBlock()
+	if err != nil || best.height == ws.syncProgress {
+		return
+	}
+	ws.syncProgress = best.height
+	synced := ws.Current()
+	if synced && ws.synced {
+		return
+	}
+	ws.synced = synced
+	target := best.height
+	for peer := range ws.peerStates {
+		if peer.LastBlock() > int32(target) {
+			target = uint32(peer.LastBlock())
+		}
+	}
+	ws.events.publish(Event{Type: EventSyncProgress, Height: best.height, TargetHeight: target, Synced: synced})
+}
+
 // handleInvMsg handles inv messages from all peers.
 // We examine the inventory advertised by the remote peer and act accordingly.
 func (ws *WireService) handleInvMsg(imsg *invMsg) {
@@ -608,6 +697,18 @@ func (ws *WireService) handleInvMsg(imsg *invMsg) {
 		}
 	}
 
+	// In compact filter mode new blocks are fetched as headers. Their filters
+	// are requested once the headers are committed.
+	if ws.syncMode == CompactFilterSync {
+		if lastBlock != -1 {
+			if haveInv, _ := ws.haveInventory(invVects[lastBlock]); !haveInv {
+				locator := ws.chain.GetBlockLocator()
+				peer.PushGetHeadersMsg(locator, &ws.zeroHash)
+			}
+		}
+		return
+	}
+
 	// Request the advertised inventory if we don't already have it
 	gdmsg := wire.NewMsgGetData()
 	numRequested := 0
@@ -731,6 +832,10 @@ func (ws *WireService) Rebroadcast() {
 }
 
 func (ws *WireService) updateFilterAndSend(peer *peerpkg.Peer) {
+	// Compact filters are matched locally so there is nothing to send
+	if ws.syncMode == CompactFilterSync {
+		return
+	}
 	if ws.txStore != nil {
 		filter, err := ws.txStore.GimmeFilter()
 		if err == nil {
@@ -743,6 +848,10 @@ func (ws *WireService) updateFilterAndSend(peer *peerpkg.Peer) {
 
 // handleUpdateFiltersMsg sends a filter update message to all peers
 func (ws *WireService) handleUpdateFiltersMsg() {
+	if ws.syncMode == CompactFilterSync {
+		ws.cf.watchList = nil
+		return
+	}
 	for peer := range ws.peerStates {
 		ws.updateFilterAndSend(peer)
 	}
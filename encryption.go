@@ -0,0 +1,219 @@
+package bitcoincash
+
+import (
+	"time"
+
+	db "github.com/BubbaJoe/spvwallet-cash/db"
+	hd "github.com/gcash/bchutil/hdkeychain"
+	b39 "github.com/tyler-smith/go-bip39"
+)
+
+// ErrWalletLocked is returned by operations which need private keys while the
+// wallet is locked.
+var ErrWalletLocked = db.ErrLocked
+
+// ErrNotEncrypted is returned when locking or changing the passphrase of a
+// wallet which has not been encrypted.
+var ErrNotEncrypted = db.ErrNotEncrypted
+
+// secretStore is the part of the datastore which holds the mnemonic and the
+// wallet passphrase. It is implemented by db.SQLiteDatastore.
+type secretStore interface {
+	GetMnemonic() (string, error)
+	SetMnemonic(mnemonic string) error
+	ArchiveMnemonic(mnemonic string) error
+	SetCreationDate(creationDate time.Time) error
+	GetPublicKeys() (masterPublicKey, accountPublicKey string, err error)
+	SetPublicKeys(masterPublicKey, accountPublicKey string) error
+
+	IsEncrypted() bool
+	IsLocked() bool
+	Encrypt(passphrase string) error
+	Unlock(passphrase string) error
+	Lock() error
+	ChangePassphrase(oldPassphrase, newPassphrase string) error
+}
+
+// openSecretStore returns the configured datastore if it can store secrets,
+// otherwise the SQLite datastore in the repo path.
+func openSecretStore(config *Config) (secretStore, error) {
+	if store, ok := config.DB.(secretStore); ok {
+		return store, nil
+	}
+	return db.Create(config.RepoPath)
+}
+
+// loadPublicKeys returns the master and account public keys saved when the
+// wallet was created. They let a locked wallet derive its addresses.
+func loadPublicKeys(store secretStore) (master, account *hd.ExtendedKey, err error) {
+	masterStr, accountStr, err := store.GetPublicKeys()
+	if err != nil {
+		return nil, nil, err
+	}
+	master, err = hd.NewKeyFromString(masterStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	account, err = hd.NewKeyFromString(accountStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	return master, account, nil
+}
+
+// IsLocked returns whether the private keys are unavailable because the wallet
+// is encrypted and has not been unlocked.
+func (w *SPVWallet) IsLocked() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.masterPrivateKey == nil
+}
+
+// IsEncrypted returns whether the mnemonic and imported keys are encrypted
+// with a passphrase.
+func (w *SPVWallet) IsEncrypted() bool {
+	return w.secrets.IsEncrypted()
+}
+
+// checkUnlocked returns an error if the private keys are unavailable.
+func (w *SPVWallet) checkUnlocked() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.privateKeyError()
+}
+
+// privateKeyError returns ErrWatchOnly or ErrWalletLocked if the private keys
+// are unavailable. The mutex must be held.
+func (w *SPVWallet) privateKeyError() error {
+	if w.watchOnly {
+		return ErrWatchOnly
+	}
+	if w.masterPrivateKey == nil {
+		return ErrWalletLocked
+	}
+	return nil
+}
+
+// EncryptWallet sets the wallet passphrase and encrypts the mnemonic and
+// imported keys with it. The wallet stays unlocked until Lock is called.
+func (w *SPVWallet) EncryptWallet(passphrase string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.watchOnly {
+		return ErrWatchOnly
+	}
+	return w.secrets.Encrypt(passphrase)
+}
+
+// Unlock decrypts the mnemonic with the passphrase and restores the private
+// keys. A wallet which has no passphrase yet is encrypted with it, as
+// EncryptWallet does.
+func (w *SPVWallet) Unlock(passphrase string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.watchOnly {
+		return ErrWatchOnly
+	}
+
+	if err := w.secrets.Unlock(passphrase); err != nil {
+		return err
+	}
+	if w.masterPrivateKey != nil {
+		return nil
+	}
+	mnemonic, err := w.secrets.GetMnemonic()
+	if err != nil {
+		w.secrets.Lock()
+		return err
+	}
+	mPrivKey, err := hd.NewMaster(b39.NewSeed(mnemonic, w.mnemonicPassphrase), w.params)
+	if err != nil {
+		w.secrets.Lock()
+		return err
+	}
+	mPubKey, err := mPrivKey.Neuter()
+	if err != nil {
+		w.secrets.Lock()
+		return err
+	}
+	if mPubKey.String() != w.masterPublicKey.String() {
+		w.secrets.Lock()
+		return ErrMnemonicPassphrase
+	}
+	if err := w.setAccountKeys(mPrivKey, true); err != nil {
+		w.secrets.Lock()
+		return err
+	}
+	w.masterPrivateKey = mPrivKey
+	w.mnemonic = mnemonic
+	return nil
+}
+
+// Lock discards the private keys. Addresses can still be derived and the
+// wallet keeps syncing, but spending and exporting keys fail with
+// ErrWalletLocked until Unlock is called.
+func (w *SPVWallet) Lock() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.watchOnly {
+		return ErrWatchOnly
+	}
+
+	if err := w.secrets.Lock(); err != nil {
+		return err
+	}
+	if w.masterPrivateKey == nil {
+		return nil
+	}
+	if err := w.setAccountKeys(w.masterPrivateKey, false); err != nil {
+		return err
+	}
+	w.masterPrivateKey.Zero()
+	w.masterPrivateKey = nil
+	w.mnemonic = ""
+	return nil
+}
+
+// ChangePassphrase re-encrypts the mnemonic and imported keys with a new
+// passphrase.
+func (w *SPVWallet) ChangePassphrase(oldPassphrase, newPassphrase string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.watchOnly {
+		return ErrWatchOnly
+	}
+
+	if err := w.secrets.ChangePassphrase(oldPassphrase, newPassphrase); err != nil {
+		return err
+	}
+	if w.masterPrivateKey == nil {
+		// Changing the passphrase shouldn't unlock the wallet
+		return w.secrets.Lock()
+	}
+	return nil
+}
+
+// setAccountKeys derives the key of every account from the master key and
+// gives it to the account's key manager, neutered unless private is set.
+func (w *SPVWallet) setAccountKeys(masterPrivKey *hd.ExtendedKey, private bool) error {
+	for _, km := range w.txstore.keyManagers() {
+		accountKey, err := bip44Account(masterPrivKey, km.account)
+		if err != nil {
+			return err
+		}
+		if !private {
+			accountKey, err = accountKey.Neuter()
+			if err != nil {
+				return err
+			}
+		}
+		if err := km.setAccountKey(accountKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
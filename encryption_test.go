@@ -0,0 +1,27 @@
+package bitcoincash
+
+import (
+	"testing"
+
+	db "github.com/BubbaJoe/spvwallet-cash/db"
+)
+
+func TestSPVWallet_UnlockPlaintext(t *testing.T) {
+	w, ds, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.secrets = ds
+	if w.IsEncrypted() {
+		t.Fatal("New wallet should not be encrypted")
+	}
+
+	// Unlocking a wallet without a passphrase encrypts it
+	if err := w.Unlock("passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	if !w.IsEncrypted() || ds.IsLocked() {
+		t.Error("Wallet should be encrypted and unlocked")
+	}
+	if err := w.Unlock("wrong"); err != db.ErrIncorrectPassphrase {
+		t.Errorf("Expected ErrIncorrectPassphrase, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package bitcoincash
+
+import (
+	"sync"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/peer"
+)
+
+// EventType is the kind of an Event.
+type EventType int
+
+const (
+	// EventNewTransaction is sent when a transaction of the wallet is first
+	// seen, in the mempool or in a block
+	EventNewTransaction EventType = iota
+
+	// EventConfirmation is sent when an unconfirmed transaction is mined
+	EventConfirmation
+
+	// EventReorg is sent when the chain reorganizes. ReorgHeight is the height
+	// of the last block both chains share
+	EventReorg
+
+	// EventNewTip is sent for a new best block
+	EventNewTip
+
+	// EventBalance is sent when the confirmed or unconfirmed balance changes
+	EventBalance
+
+	EventPeerConnected
+	EventPeerDisconnected
+
+	// EventSyncProgress is sent as headers and blocks are downloaded
+	EventSyncProgress
+)
+
+var eventTypeNames = map[EventType]string{
+	EventNewTransaction:   "new transaction",
+	EventConfirmation:     "confirmation",
+	EventReorg:            "reorg",
+	EventNewTip:           "new tip",
+	EventBalance:          "balance",
+	EventPeerConnected:    "peer connected",
+	EventPeerDisconnected: "peer disconnected",
+	EventSyncProgress:     "sync progress",
+}
+
+func (t EventType) String() string {
+	return eventTypeNames[t]
+}
+
+// Event is a change of the wallet or its connection to the network. Only the
+// fields for its type are set.
+type Event struct {
+	Type EventType
+	Time time.Time
+
+	// The transaction of EventNewTransaction and EventConfirmation
+	Transaction *wallet.TransactionCallback
+
+	// The new best block of EventNewTip and EventReorg
+	Block *wallet.BlockCallback
+
+	ReorgHeight uint32
+
+	// The balance after EventBalance
+	Confirmed, Unconfirmed int64
+
+	// The peer of EventPeerConnected and EventPeerDisconnected, and the
+	// number of peers after it
+	Peer      *peer.Peer
+	PeerCount int
+
+	// The height of the best header for EventSyncProgress, the best height
+	// reported by our peers and whether the wallet is caught up
+	Height       uint32
+	TargetHeight uint32
+	Synced       bool
+}
+
+// The number of events buffered for each subscriber. Events for a subscriber
+// which falls further behind are dropped.
+const eventBuffer = 100
+
+// eventHub sends the wallet's events to its subscribers. Every method may be
+// called on a nil hub, which drops the events.
+type eventHub struct {
+	mutex       sync.Mutex
+	subscribers map[int]chan Event
+	nextID      int
+
+	// balance returns the wallet's balance, which is checked after events
+	// which can change it
+	balance                func() (confirmed, unconfirmed int64)
+	confirmed, unconfirmed int64
+}
+
+func newEventHub(balance func() (confirmed, unconfirmed int64)) *eventHub {
+	return &eventHub{subscribers: make(map[int]chan Event), balance: balance}
+}
+
+// subscribe returns a channel receiving every event and the function closing
+// it.
+func (h *eventHub) subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventBuffer)
+	if h == nil {
+		return ch, func() { close(ch) }
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	// The balance isn't followed without subscribers so the first one starts
+	// from the current balance
+	if len(h.subscribers) == 0 && h.balance != nil {
+		h.confirmed, h.unconfirmed = h.balance()
+	}
+	id := h.nextID
+	h.nextID++
+	h.subscribers[id] = ch
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+			delete(h.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+// publish sends an event to every subscriber without blocking, followed by
+// an EventBalance if the event changed the balance.
+func (h *eventHub) publish(e Event) {
+	if h == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.send(e)
+
+	switch e.Type {
+	case EventNewTransaction, EventConfirmation, EventNewTip, EventReorg:
+		// Nobody is told of the change so the balance isn't worth working out
+		if h.balance == nil || len(h.subscribers) == 0 {
+			return
+		}
+		confirmed, unconfirmed := h.balance()
+		if confirmed != h.confirmed || unconfirmed != h.unconfirmed {
+			h.confirmed, h.unconfirmed = confirmed, unconfirmed
+			h.send(Event{Type: EventBalance, Time: e.Time, Confirmed: confirmed, Unconfirmed: unconfirmed})
+		}
+	}
+}
+
+// send sends an event to the subscribers. The mutex must be held.
+func (h *eventHub) send(e Event) {
+	for _, ch := range h.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// SubscribeEvents returns a channel receiving the wallet's events and a
+// function to call once done with it, which closes the channel. Events are
+// dropped for a subscriber that falls behind.
+func (w *SPVWallet) SubscribeEvents() (<-chan Event, func()) {
+	return w.events.subscribe()
+}
@@ -0,0 +1,106 @@
+package bitcoincash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+// nextEvent returns the next event or fails if none arrives.
+func nextEvent(t *testing.T, events <-chan Event) Event {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+	return Event{}
+}
+
+func TestEventHub(t *testing.T) {
+	confirmed := int64(0)
+	h := newEventHub(func() (int64, int64) { return confirmed, 0 })
+	events, unsubscribe := h.subscribe()
+
+	h.publish(Event{Type: EventPeerConnected, PeerCount: 1})
+	if e := nextEvent(t, events); e.Type != EventPeerConnected || e.Time.IsZero() {
+		t.Errorf("Unexpected event %v", e)
+	}
+
+	// A balance change is sent after the event causing it
+	confirmed = 500
+	h.publish(Event{Type: EventNewTip, Block: &wallet.BlockCallback{Height: 10}})
+	if e := nextEvent(t, events); e.Type != EventNewTip {
+		t.Errorf("Expected a new tip, got %s", e.Type)
+	}
+	if e := nextEvent(t, events); e.Type != EventBalance || e.Confirmed != 500 {
+		t.Errorf("Expected a balance of 500, got %v", e)
+	}
+	h.publish(Event{Type: EventNewTip, Block: &wallet.BlockCallback{Height: 11}})
+	nextEvent(t, events)
+	select {
+	case e := <-events:
+		t.Errorf("Sent %s without a balance change", e.Type)
+	default:
+	}
+
+	// A subscriber which falls behind misses events instead of blocking
+	for i := 0; i < eventBuffer+10; i++ {
+		h.publish(Event{Type: EventSyncProgress, Height: uint32(i)})
+	}
+	if len(events) != eventBuffer {
+		t.Errorf("Expected %d buffered events, got %d", eventBuffer, len(events))
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if len(h.subscribers) != 0 {
+		t.Error("Subscriber was not removed")
+	}
+	for range events {
+	}
+
+	// The balance isn't worked out without subscribers
+	calls := 0
+	h = newEventHub(func() (int64, int64) { calls++; return confirmed, 0 })
+	h.publish(Event{Type: EventNewTip, Block: &wallet.BlockCallback{Height: 12}})
+	if calls != 0 {
+		t.Errorf("Checked the balance %d times without subscribers", calls)
+	}
+
+	// A wallet without a hub drops its events
+	var nilHub *eventHub
+	nilHub.publish(Event{Type: EventNewTip})
+	_, unsubscribe = nilHub.subscribe()
+	unsubscribe()
+}
+
+func TestTxStore_IngestEvents(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.events = newEventHub(w.Balance)
+	w.txstore.events = w.events
+	events, unsubscribe := w.SubscribeEvents()
+	defer unsubscribe()
+
+	tx := payTo(t, w.CurrentAddress(wallet.EXTERNAL), 100000)
+	if _, err := w.txstore.Ingest(tx, 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	e := nextEvent(t, events)
+	if e.Type != EventNewTransaction || e.Transaction.Txid != tx.TxHash().String() || e.Transaction.Value != 100000 {
+		t.Errorf("Unexpected event %s %v", e.Type, e.Transaction)
+	}
+	if e := nextEvent(t, events); e.Type != EventBalance || e.Unconfirmed != 100000 {
+		t.Errorf("Expected an unconfirmed balance of 100000, got %s %d", e.Type, e.Unconfirmed)
+	}
+
+	// Seeing it again in a block confirms it
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if e := nextEvent(t, events); e.Type != EventConfirmation || e.Transaction.Height != 1 {
+		t.Errorf("Expected a confirmation at height 1, got %s", e.Type)
+	}
+}
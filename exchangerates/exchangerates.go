@@ -7,8 +7,10 @@ import (
 	"golang.org/x/net/proxy"
 	"net"
 	"net/http"
+	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -30,10 +32,24 @@ type BitfinexDecoder struct{}
 type BittrexDecoder struct{}
 type PoloniexDecoder struct{}
 
+// HistoricalRateSource looks up the price of BCH on a past day. Sources are
+// pluggable with SetHistoricalRateSource.
+type HistoricalRateSource interface {
+	GetHistoricalRate(currencyCode string, t time.Time) (float64, error)
+}
+
+// CoinGeckoHistory is a HistoricalRateSource using the daily prices of the
+// CoinGecko API.
+type CoinGeckoHistory struct {
+	fetchUrl string
+	client   *http.Client
+}
+
 type BitcoinCashPriceFetcher struct {
 	sync.Mutex
 	cache     map[string]float64
 	providers []*ExchangeRateProvider
+	history   HistoricalRateSource
 }
 
 func NewBitcoinCashPriceFetcher(dialer proxy.Dialer) *BitcoinCashPriceFetcher {
@@ -52,9 +68,14 @@ func NewBitcoinCashPriceFetcher(dialer proxy.Dialer) *BitcoinCashPriceFetcher {
 		{"https://poloniex.com/public?command=returnTicker", b.cache, client, PoloniexDecoder{}},
 		{"https://api.kraken.com/0/public/Ticker?pair=BCHUSD", b.cache, client, KrakenDecoder{}},
 	}
+	b.history = NewCoinGeckoHistory(client)
 	return &b
 }
 
+func NewCoinGeckoHistory(client *http.Client) *CoinGeckoHistory {
+	return &CoinGeckoHistory{"https://api.coingecko.com/api/v3/coins/bitcoin-cash/history", client}
+}
+
 func (b *BitcoinCashPriceFetcher) GetExchangeRate(currencyCode string) (float64, error) {
 	b.Lock()
 	defer b.Unlock()
@@ -88,6 +109,23 @@ func (b *BitcoinCashPriceFetcher) GetAllRates(cacheOK bool) (map[string]float64,
 	return b.cache, nil
 }
 
+// SetHistoricalRateSource replaces the source of past prices.
+func (b *BitcoinCashPriceFetcher) SetHistoricalRateSource(source HistoricalRateSource) {
+	b.Lock()
+	defer b.Unlock()
+	b.history = source
+}
+
+func (b *BitcoinCashPriceFetcher) GetHistoricalRate(currencyCode string, t time.Time) (float64, error) {
+	b.Lock()
+	history := b.history
+	b.Unlock()
+	if history == nil {
+		return 0, errors.New("No historical rate source")
+	}
+	return history.GetHistoricalRate(currencyCode, t)
+}
+
 func (b *BitcoinCashPriceFetcher) UnitsPerCoin() int {
 	return 100000000
 }
@@ -131,6 +169,46 @@ func (provider *ExchangeRateProvider) fetch() (err error) {
 	return provider.decoder.decode(dataMap, provider.cache)
 }
 
+// GetHistoricalRate returns the price of BCH in the currency on the UTC day of t.
+func (c *CoinGeckoHistory) GetHistoricalRate(currencyCode string, t time.Time) (float64, error) {
+	q := url.Values{}
+	q.Set("date", t.UTC().Format("02-01-2006"))
+	q.Set("localization", "false")
+	resp, err := c.client.Get(c.fetchUrl + "?" + q.Encode())
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("CoinGeckoHistory: API returned %s", resp.Status)
+	}
+	var dat interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&dat); err != nil {
+		return 0, err
+	}
+	return c.decode(dat, currencyCode)
+}
+
+func (c *CoinGeckoHistory) decode(dat interface{}, currencyCode string) (float64, error) {
+	data, ok := dat.(map[string]interface{})
+	if !ok {
+		return 0, errors.New("CoinGeckoHistory.decode: Type assertion failed")
+	}
+	marketData, ok := data["market_data"].(map[string]interface{})
+	if !ok {
+		return 0, errors.New("CoinGeckoHistory.decode: No price data for that day")
+	}
+	prices, ok := marketData["current_price"].(map[string]interface{})
+	if !ok {
+		return 0, errors.New("CoinGeckoHistory.decode: Type assertion failed, missing 'current_price' field")
+	}
+	price, ok := prices[strings.ToLower(currencyCode)].(float64)
+	if !ok || price == 0 {
+		return 0, errors.New("CoinGeckoHistory.decode: Currency not tracked")
+	}
+	return price, nil
+}
+
 func (b OpenBazaarDecoder) decode(dat interface{}, cache map[string]float64) (err error) {
 	data, ok := dat.(map[string]interface{})
 	if !ok {
@@ -0,0 +1,36 @@
+package exchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCoinGeckoHistory_GetHistoricalRate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("date") != "14-03-2019" {
+			w.Write([]byte(`{"id":"bitcoin-cash"}`))
+			return
+		}
+		w.Write([]byte(`{"id":"bitcoin-cash","market_data":{"current_price":{"usd":131.25,"eur":116.5}}}`))
+	}))
+	defer ts.Close()
+	b := NewBitcoinCashPriceFetcher(nil)
+	b.SetHistoricalRateSource(&CoinGeckoHistory{ts.URL, ts.Client()})
+
+	day := time.Date(2019, 3, 14, 23, 0, 0, 0, time.UTC)
+	rate, err := b.GetHistoricalRate("USD", day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 131.25 {
+		t.Errorf("Returned rate %f, expected 131.25", rate)
+	}
+	if _, err := b.GetHistoricalRate("JPY", day); err == nil {
+		t.Error("Returned a rate for an untracked currency")
+	}
+	if _, err := b.GetHistoricalRate("USD", day.AddDate(0, 0, -1)); err == nil {
+		t.Error("Returned a rate for a day without prices")
+	}
+}
@@ -1,105 +1,260 @@
 package bitcoincash
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchwallet/wallet/txrules"
+	"golang.org/x/net/proxy"
 )
 
-type httpClient interface {
-	Get(string) (*http.Response, error)
-}
+var (
+	// ErrFeeTooLow is returned when a spend's fee is below the minimum relay
+	// fee, so nodes wouldn't relay its transaction.
+	ErrFeeTooLow = errors.New("Fee is below the minimum relay fee")
+
+	// ErrFeeTooHigh is returned when a spend's fee is above the maximum fee
+	// per byte.
+	ErrFeeTooHigh = errors.New("Fee is above the maximum fee")
+)
+
+const (
+	// feeCacheExpiry is how long fees from a FeeSource are used before they
+	// are looked up again.
+	feeCacheExpiry = time.Minute
+
+	// feeRetryInterval is the wait before a lookup which failed is retried.
+	feeRetryInterval = 5 * time.Second
+
+	// minFeePerByte is the minimum relay fee in satoshis per byte. Fees from
+	// a FeeSource are never below it.
+	minFeePerByte = 1
+)
 
 type feeCache struct {
-	fees        *Fees
-	lastUpdated time.Time
+	fees       *Fees
+	nextUpdate time.Time
 }
 
+// Fees are fee rates in satoshis per byte. A rate of zero is treated as
+// missing and the default fee of its level is used instead.
 type Fees struct {
-	FastestFee  uint64
-	HalfHourFee uint64
-	HourFee     uint64
+	FastestFee  uint64 `json:"fastestFee"`
+	HalfHourFee uint64 `json:"halfHourFee"`
+	HourFee     uint64 `json:"hourFee"`
 }
 
-type FeeProvider struct {
-	maxFee      uint64
-	priorityFee uint64
-	normalFee   uint64
-	economicFee uint64
+// FeeSource looks up the current fee rates. FastestFee is used for priority,
+// HalfHourFee for normal and HourFee for economic transactions.
+type FeeSource interface {
+	GetFees() (*Fees, error)
+}
 
-	exchangeRates wallet.ExchangeRates
+// StaticFeeSource always returns the same fees.
+type StaticFeeSource struct {
+	Fees Fees
+}
 
-	cache *feeCache
+func (s *StaticFeeSource) GetFees() (*Fees, error) {
+	fees := s.Fees
+	return &fees, nil
+}
+
+// HTTPFeeSource fetches fees from an API whose response is formatted as
+// { "fastestFee": 40, "halfHourFee": 20, "hourFee": 10 }
+type HTTPFeeSource struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPFeeSource returns a source fetching fees from the API at url. If
+// dialer is not nil requests are made through it.
+func NewHTTPFeeSource(url string, dialer proxy.Dialer) *HTTPFeeSource {
+	dial := net.Dial
+	if dialer != nil {
+		dial = dialer.Dial
+	}
+	transport := &http.Transport{Dial: dial}
+	return &HTTPFeeSource{url, &http.Client{Transport: transport, Timeout: time.Second * 30}}
+}
+
+func (s *HTTPFeeSource) GetFees() (*Fees, error) {
+	resp, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Fee API returned %s", resp.Status)
+	}
+	fees := new(Fees)
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(fees); err != nil {
+		return nil, err
+	}
+	return fees, nil
 }
 
 // We will target a fee per byte such that it would equal
-// 1 USD cent for economic, 5 USD cents for normal and
-// 10 USD cents for priority for a median (226 byte) transaction.
+// 0.1 USD cents for economic, 0.2 USD cents for normal and
+// 0.5 USD cents for priority for a median (226 byte) transaction.
+// Targets are in tenths of a USD cent.
 type FeeTarget int
 
 const (
 	EconomicTarget FeeTarget = 1
-	NormalTarget   FeeTarget = 5
-	PriorityTarget FeeTarget = 10
+	NormalTarget   FeeTarget = 2
+	PriorityTarget FeeTarget = 5
 )
 
-func NewFeeProvider(maxFee, priorityFee, normalFee, economicFee uint64, exchangeRates wallet.ExchangeRates) *FeeProvider {
+// medianTxSize is the size in bytes of the transaction fee targets are for.
+const medianTxSize = 226
+
+// FiatFeeSource works out the fees which make a median transaction cost each
+// FeeTarget at the current USD exchange rate.
+type FiatFeeSource struct {
+	exchangeRates wallet.ExchangeRates
+}
+
+func NewFiatFeeSource(exchangeRates wallet.ExchangeRates) *FiatFeeSource {
+	return &FiatFeeSource{exchangeRates}
+}
+
+func (s *FiatFeeSource) GetFees() (*Fees, error) {
+	rate, err := s.exchangeRates.GetLatestRate("USD")
+	if err != nil {
+		return nil, err
+	}
+	if rate <= 0 {
+		return nil, errors.New("No USD exchange rate")
+	}
+	// A fee rounded down to zero would be taken as missing
+	feePerByte := func(target FeeTarget) uint64 {
+		fee := uint64(((float64(target) / 1000) / rate) * float64(s.exchangeRates.UnitsPerCoin()) / medianTxSize)
+		if fee < minFeePerByte {
+			return minFeePerByte
+		}
+		return fee
+	}
+	return &Fees{
+		FastestFee:  feePerByte(PriorityTarget),
+		HalfHourFee: feePerByte(NormalTarget),
+		HourFee:     feePerByte(EconomicTarget),
+	}, nil
+}
+
+type FeeProvider struct {
+	maxFee      uint64
+	priorityFee uint64
+	normalFee   uint64
+	economicFee uint64
+
+	source FeeSource
+
+	lock  sync.Mutex
+	cache *feeCache
+}
+
+// NewFeeProvider returns a provider using fees from source, or the default
+// fees given if source is nil or its fees can't be looked up. Fees are never
+// more than maxFee.
+func NewFeeProvider(maxFee, priorityFee, normalFee, economicFee uint64, source FeeSource) *FeeProvider {
 	return &FeeProvider{
-		maxFee:        maxFee,
-		priorityFee:   priorityFee,
-		normalFee:     normalFee,
-		economicFee:   economicFee,
-		exchangeRates: exchangeRates,
-		cache:         new(feeCache),
+		maxFee:      maxFee,
+		priorityFee: priorityFee,
+		normalFee:   normalFee,
+		economicFee: economicFee,
+		source:      source,
+		cache:       new(feeCache),
 	}
 }
 
 func (fp *FeeProvider) GetFeePerByte(feeLevel wallet.FeeLevel) uint64 {
-	defaultFee := func() uint64 {
-		switch feeLevel {
-		case wallet.PRIOIRTY:
-			return fp.priorityFee
-		case wallet.NORMAL:
-			return fp.normalFee
-		case wallet.ECONOMIC:
-			return fp.economicFee
-		case wallet.FEE_BUMP:
-			return fp.priorityFee * 2
-		default:
-			return fp.normalFee
+	fees := Fees{fp.priorityFee, fp.normalFee, fp.economicFee}
+	if current := fp.currentFees(); current != nil {
+		if current.FastestFee > 0 {
+			fees.FastestFee = current.FastestFee
+		}
+		if current.HalfHourFee > 0 {
+			fees.HalfHourFee = current.HalfHourFee
+		}
+		if current.HourFee > 0 {
+			fees.HourFee = current.HourFee
 		}
 	}
-	return defaultFee()
-	// if fp.exchangeRates == nil {
-	// 	return defaultFee()
-	// }
-
-	// rate, err := fp.exchangeRates.GetLatestRate("USD")
-	// if err != nil || rate == 0 {
-	// 	log.Errorf("Error using exchange rate to calculate fee: %s\n", err.Error())
-	// 	return defaultFee()
-	// }
-
-	// var target FeeTarget
-	// switch feeLevel {
-	// case wallet.PRIOIRTY:
-	// 	target = PriorityTarget
-	// case wallet.NORMAL:
-	// 	target = NormalTarget
-	// case wallet.ECONOMIC:
-	// 	target = EconomicTarget
-	// case wallet.FEE_BUMP:
-	// 	target = PriorityTarget * 2
-	// default:
-	// 	target = NormalTarget
-	// }
-
-	// feePerByte := (((float64(target) / 100) / rate) * 100000000) / 226
-
-	// if uint64(feePerByte) > fp.maxFee {
-	// 	return fp.maxFee
-	// }
-
-	// return uint64(feePerByte)
+
+	var fee uint64
+	switch feeLevel {
+	case wallet.PRIOIRTY:
+		fee = fees.FastestFee
+	case wallet.NORMAL:
+		fee = fees.HalfHourFee
+	case wallet.ECONOMIC:
+		fee = fees.HourFee
+	case wallet.FEE_BUMP:
+		fee = fees.FastestFee * 2
+	default:
+		fee = fees.HalfHourFee
+	}
+	if fp.maxFee > 0 && fee > fp.maxFee {
+		return fp.maxFee
+	}
+	if fee < minFeePerByte {
+		return minFeePerByte
+	}
+	return fee
+}
+
+// currentFees returns the fees from the source, looking them up again if the
+// cached fees have expired. If the lookup fails the last fees it returned are
+// kept, which are nil if it has never succeeded, and it is retried after
+// feeRetryInterval.
+func (fp *FeeProvider) currentFees() *Fees {
+	if fp.source == nil {
+		return nil
+	}
+	fp.lock.Lock()
+	defer fp.lock.Unlock()
+	if time.Now().Before(fp.cache.nextUpdate) {
+		return fp.cache.fees
+	}
+	fees, err := fp.source.GetFees()
+	if err != nil {
+		log.Errorf("Error looking up fees, using the previous fees: %s", err)
+		fp.cache.nextUpdate = time.Now().Add(feeRetryInterval)
+	} else {
+		fp.cache.fees = fees
+		fp.cache.nextUpdate = time.Now().Add(feeCacheExpiry)
+	}
+	return fp.cache.fees
+}
+
+// checkFeePerByte returns an error if a fee rate chosen for a spend is below
+// the minimum relay fee or above the maximum fee.
+func (fp *FeeProvider) checkFeePerByte(feePerByte uint64) error {
+	if feePerByte*1000 < uint64(txrules.DefaultRelayFeePerKb) {
+		return ErrFeeTooLow
+	}
+	if fp.maxFee > 0 && feePerByte > fp.maxFee {
+		return ErrFeeTooHigh
+	}
+	return nil
+}
+
+// checkFee is like checkFeePerByte for the fee paid by a transaction of size
+// bytes.
+func (fp *FeeProvider) checkFee(fee int64, size int) error {
+	if fee < int64(txrules.FeeForSerializeSize(txrules.DefaultRelayFeePerKb, size)) {
+		return ErrFeeTooLow
+	}
+	if fp.maxFee > 0 && fee > int64(fp.maxFee)*int64(size) {
+		return ErrFeeTooHigh
+	}
+	return nil
 }
@@ -1,18 +1,14 @@
 package bitcoincash
 
 import (
-	"bytes"
-	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
-)
+	"time"
 
-type ClosingBuffer struct {
-	*bytes.Buffer
-}
-
-func (cb *ClosingBuffer) Close() (err error) {
-	return
-}
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
 
 type mockExchangeRate struct{}
 
@@ -31,53 +27,131 @@ func (m *mockExchangeRate) GetAllRates(usecache bool) (map[string]float64, error
 }
 
 func (m *mockExchangeRate) UnitsPerCoin() int {
-	return 0
+	return 100000000
 }
 
-func TestFeeProvider_GetFeePerByte(t *testing.T) {
-	fp := NewFeeProvider(2000, 360, 320, 280, &mockExchangeRate{})
+type mockFeeSource struct {
+	fees    *Fees
+	err     error
+	lookups int
+}
 
-	// Test using exchange rates
-	if fp.GetFeePerByte(wallet.PRIOIRTY) != 101 {
-		t.Error("Returned incorrect fee per byte")
-	}
-	if fp.GetFeePerByte(wallet.NORMAL) != 50 {
-		t.Error("Returned incorrect fee per byte")
-	}
-	if fp.GetFeePerByte(wallet.ECONOMIC) != 10 {
-		t.Error("Returned incorrect fee per byte")
+func (m *mockFeeSource) GetFees() (*Fees, error) {
+	m.lookups++
+	return m.fees, m.err
+}
+
+func checkFees(t *testing.T, fp *FeeProvider, priority, normal, economic, bump uint64) {
+	t.Helper()
+	levels := []struct {
+		level wallet.FeeLevel
+		fee   uint64
+	}{
+		{wallet.PRIOIRTY, priority},
+		{wallet.NORMAL, normal},
+		{wallet.ECONOMIC, economic},
+		{wallet.FEE_BUMP, bump},
 	}
-	if fp.GetFeePerByte(wallet.FEE_BUMP) != 202 {
-		t.Error("Returned incorrect fee per byte")
+	for _, l := range levels {
+		if fee := fp.GetFeePerByte(l.level); fee != l.fee {
+			t.Errorf("Returned fee per byte %d for level %d, expected %d", fee, l.level, l.fee)
+		}
 	}
+}
+
+func TestFeeProvider_GetFeePerByte(t *testing.T) {
+	fp := NewFeeProvider(2000, 360, 320, 280, NewFiatFeeSource(&mockExchangeRate{}))
+
+	// Test using exchange rates
+	returnRate = 438
+	checkFees(t, fp, 5, 2, 1, 10)
 
 	// Test exchange rate is limited at max if bad exchange rate is returned
 	returnRate = 0.1
-	if fp.GetFeePerByte(wallet.PRIOIRTY) != 2000 {
-		t.Error("Returned incorrect fee per byte")
-	}
-	if fp.GetFeePerByte(wallet.NORMAL) != 2000 {
-		t.Error("Returned incorrect fee per byte")
+	fp.cache.nextUpdate = time.Time{}
+	checkFees(t, fp, 2000, 2000, 2000, 2000)
+	returnRate = 438
+
+	// Fees are never below the minimum relay fee
+	returnRate = 1000000
+	fp.cache.nextUpdate = time.Time{}
+	checkFees(t, fp, 1, 1, 1, 2)
+	returnRate = 438
+
+	// Test no fee source provided
+	fp = NewFeeProvider(2000, 360, 320, 280, nil)
+	checkFees(t, fp, 360, 320, 280, 720)
+}
+
+func TestFeeProvider_DefaultConfig(t *testing.T) {
+	// The fee levels of NewDefaultConfig stay apart at realistic exchange rates
+	fp := NewFeeProvider(5, 2, 1, 1, NewFiatFeeSource(&mockExchangeRate{}))
+	returnRate = 438
+	checkFees(t, fp, 5, 2, 1, 5)
+
+	returnRate = 200
+	fp.cache.nextUpdate = time.Time{}
+	checkFees(t, fp, 5, 4, 2, 5)
+	returnRate = 438
+}
+
+func TestFeeProvider_Cache(t *testing.T) {
+	source := &mockFeeSource{fees: &Fees{FastestFee: 40, HalfHourFee: 20, HourFee: 10}}
+	fp := NewFeeProvider(60, 360, 320, 280, source)
+	checkFees(t, fp, 40, 20, 10, 60)
+	if source.lookups != 1 {
+		t.Errorf("Looked up fees %d times, expected once", source.lookups)
 	}
-	if fp.GetFeePerByte(wallet.ECONOMIC) != 2000 {
-		t.Error("Returned incorrect fee per byte")
+
+	// Failed lookups keep the previous fees and are retried sooner than the
+	// cache expires
+	source.fees, source.err = nil, errors.New("unreachable")
+	fp.cache.nextUpdate = time.Time{}
+	checkFees(t, fp, 40, 20, 10, 60)
+	if source.lookups != 2 {
+		t.Errorf("Looked up fees %d times, expected twice", source.lookups)
 	}
-	if fp.GetFeePerByte(wallet.FEE_BUMP) != 2000 {
-		t.Error("Returned incorrect fee per byte")
+	if retry := time.Until(fp.cache.nextUpdate); retry > feeRetryInterval {
+		t.Errorf("Retrying a failed lookup in %s, expected at most %s", retry, feeRetryInterval)
 	}
 
-	// Test no Exchange rate provided
-	fp.exchangeRates = nil
-	if fp.GetFeePerByte(wallet.PRIOIRTY) != 360 {
-		t.Error("Returned incorrect fee per byte")
+	// Missing fees fall back to the defaults
+	source.fees, source.err = &Fees{FastestFee: 30}, nil
+	fp.cache.nextUpdate = time.Time{}
+	checkFees(t, fp, 30, 60, 60, 60)
+
+	// The defaults are used if the source never succeeds
+	failing := &mockFeeSource{err: errors.New("unreachable")}
+	fp = NewFeeProvider(2000, 360, 320, 280, failing)
+	checkFees(t, fp, 360, 320, 280, 720)
+	if failing.lookups != 1 {
+		t.Errorf("Looked up fees %d times, expected once", failing.lookups)
 	}
-	if fp.GetFeePerByte(wallet.NORMAL) != 320 {
-		t.Error("Returned incorrect fee per byte")
+
+	// The first fees are used as soon as a retry succeeds
+	failing.fees, failing.err = &Fees{FastestFee: 40, HalfHourFee: 20, HourFee: 10}, nil
+	fp.cache.nextUpdate = time.Now()
+	checkFees(t, fp, 40, 20, 10, 80)
+}
+
+func TestHTTPFeeSource(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{ "fastestFee": 40, "halfHourFee": 20, "hourFee": 10 }`))
+	}))
+	defer ts.Close()
+	fees, err := NewHTTPFeeSource(ts.URL, nil).GetFees()
+	if err != nil {
+		t.Fatal(err)
 	}
-	if fp.GetFeePerByte(wallet.ECONOMIC) != 280 {
-		t.Error("Returned incorrect fee per byte")
+	if *fees != (Fees{40, 20, 10}) {
+		t.Errorf("Returned fees %v", *fees)
 	}
-	if fp.GetFeePerByte(wallet.FEE_BUMP) != 720 {
-		t.Error("Returned incorrect fee per byte")
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	if _, err := NewHTTPFeeSource(failing.URL, nil).GetFees(); err == nil {
+		t.Error("Returned fees from a failing API")
 	}
 }
@@ -35,6 +35,7 @@ require (
 	github.com/tyler-smith/go-bip39 v1.0.0
 	github.com/yawning/bulb v0.0.0-20170405033506-85d80d893c3d
 	github.com/zquestz/grab v2.0.0+incompatible // indirect
+	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
 	golang.org/x/net v0.0.0-20190613194153-d28f0bde5980
 	golang.org/x/text v0.3.1-0.20180807135948-17ff2d5776d2 // indirect
 	google.golang.org/genproto v0.0.0-20190201180003-4b09977fb922 // indirect
@@ -54,6 +54,25 @@ type Headers interface {
 
 	// Print all headers
 	Print(io.Writer)
+
+	// Put the compact filter headers for the given blocks and make the last
+	// block the filter header tip
+	PutFilterHeaders(blockHashes []chainhash.Hash, filterHeaders []chainhash.Hash) error
+
+	// Grab the compact filter header committed to for a block
+	GetFilterHeader(blockHash chainhash.Hash) (chainhash.Hash, error)
+
+	// Set the last block we have a validated filter header for
+	PutFilterHeaderTip(blockHash chainhash.Hash) error
+
+	// Returns the last block we have a validated filter header for
+	GetFilterHeaderTip() (chainhash.Hash, error)
+
+	// Set the last block whose compact filter has been matched against our scripts
+	PutFilterTip(blockHash chainhash.Hash) error
+
+	// Returns the last block whose compact filter has been matched against our scripts
+	GetFilterTip() (chainhash.Hash, error)
 }
 
 type StoredHeader struct {
@@ -72,9 +91,12 @@ type HeaderDB struct {
 }
 
 var (
-	BKTHeaders  = []byte("Headers")
-	BKTChainTip = []byte("ChainTip")
-	KEYChainTip = []byte("ChainTip")
+	BKTHeaders         = []byte("Headers")
+	BKTChainTip        = []byte("ChainTip")
+	BKTFilterHeaders   = []byte("FilterHeaders")
+	KEYChainTip        = []byte("ChainTip")
+	KEYFilterHeaderTip = []byte("FilterHeaderTip")
+	KEYFilterTip       = []byte("FilterTip")
 )
 
 func NewHeaderDB(filePath string) (*HeaderDB, error) {
@@ -100,6 +122,10 @@ func NewHeaderDB(filePath string) (*HeaderDB, error) {
 		if err != nil {
 			return err
 		}
+		_, err = btx.CreateBucketIfNotExists(BKTFilterHeaders)
+		if err != nil {
+			return err
+		}
 		return nil
 	})
 
@@ -194,11 +220,16 @@ func (h *HeaderDB) Prune() error {
 			if err != nil {
 				return err
 			}
+			filterHdrs := btx.Bucket(BKTFilterHeaders)
 			for _, k := range toDelete {
 				err := hdrs.Delete(k)
 				if err != nil {
 					return err
 				}
+				err = filterHdrs.Delete(k)
+				if err != nil {
+					return err
+				}
 			}
 
 		}
@@ -225,11 +256,16 @@ func (h *HeaderDB) DeleteAfter(height uint32) error {
 		if err != nil {
 			return err
 		}
+		filterHdrs := btx.Bucket(BKTFilterHeaders)
 		for _, k := range toDelete {
 			err := hdrs.Delete(k)
 			if err != nil {
 				return err
 			}
+			err = filterHdrs.Delete(k)
+			if err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -312,6 +348,81 @@ func (h *HeaderDB) Height() (uint32, error) {
 	return height, nil
 }
 
+func (h *HeaderDB) PutFilterHeaders(blockHashes []chainhash.Hash, filterHeaders []chainhash.Hash) error {
+	if len(blockHashes) != len(filterHeaders) {
+		return errors.New("Number of block hashes and filter headers do not match")
+	}
+	if len(blockHashes) == 0 {
+		return nil
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.db.Update(func(btx *bolt.Tx) error {
+		filterHdrs := btx.Bucket(BKTFilterHeaders)
+		for i := range blockHashes {
+			err := filterHdrs.Put(blockHashes[i].CloneBytes(), filterHeaders[i].CloneBytes())
+			if err != nil {
+				return err
+			}
+		}
+		tip := btx.Bucket(BKTChainTip)
+		return tip.Put(KEYFilterHeaderTip, blockHashes[len(blockHashes)-1].CloneBytes())
+	})
+}
+
+func (h *HeaderDB) GetFilterHeader(blockHash chainhash.Hash) (filterHeader chainhash.Hash, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	err = h.db.View(func(btx *bolt.Tx) error {
+		filterHdrs := btx.Bucket(BKTFilterHeaders)
+		b := filterHdrs.Get(blockHash.CloneBytes())
+		if b == nil {
+			return errors.New("No filter header found: " + blockHash.String())
+		}
+		return filterHeader.SetBytes(b)
+	})
+	return filterHeader, err
+}
+
+func (h *HeaderDB) PutFilterHeaderTip(blockHash chainhash.Hash) error {
+	return h.putTip(KEYFilterHeaderTip, blockHash)
+}
+
+func (h *HeaderDB) GetFilterHeaderTip() (chainhash.Hash, error) {
+	return h.getTip(KEYFilterHeaderTip)
+}
+
+func (h *HeaderDB) PutFilterTip(blockHash chainhash.Hash) error {
+	return h.putTip(KEYFilterTip, blockHash)
+}
+
+func (h *HeaderDB) GetFilterTip() (chainhash.Hash, error) {
+	return h.getTip(KEYFilterTip)
+}
+
+func (h *HeaderDB) putTip(key []byte, blockHash chainhash.Hash) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.db.Update(func(btx *bolt.Tx) error {
+		tip := btx.Bucket(BKTChainTip)
+		return tip.Put(key, blockHash.CloneBytes())
+	})
+}
+
+func (h *HeaderDB) getTip(key []byte) (hash chainhash.Hash, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	err = h.db.View(func(btx *bolt.Tx) error {
+		tip := btx.Bucket(BKTChainTip)
+		b := tip.Get(key)
+		if b == nil {
+			return errors.New(string(key) + " not set")
+		}
+		return hash.SetBytes(b)
+	})
+	return hash, err
+}
+
 func (h *HeaderDB) Print(w io.Writer) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
@@ -419,3 +419,53 @@ func TestHeaderDB_DeleteAfter(t *testing.T) {
 	}
 	os.RemoveAll("headers.bin")
 }
+
+func TestHeaderDB_FilterHeaders(t *testing.T) {
+	headers, err := NewHeaderDB("")
+	if err != nil {
+		t.Error(err)
+	}
+	defer os.RemoveAll("headers.bin")
+
+	if _, err := headers.GetFilterHeaderTip(); err == nil {
+		t.Error("Filter header tip should not be set")
+	}
+	blockHashes := []chainhash.Hash{testSh1.header.BlockHash(), testSh2.header.BlockHash()}
+	filterHeaders := []chainhash.Hash{chainhash.DoubleHashH([]byte{1}), chainhash.DoubleHashH([]byte{2})}
+	if err := headers.PutFilterHeaders(blockHashes, filterHeaders[:1]); err == nil {
+		t.Error("Failed to reject mismatched filter headers")
+	}
+	if err := headers.PutFilterHeaders(blockHashes, filterHeaders); err != nil {
+		t.Fatal(err)
+	}
+	for i, blockHash := range blockHashes {
+		filterHeader, err := headers.GetFilterHeader(blockHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filterHeader != filterHeaders[i] {
+			t.Errorf("Returned incorrect filter header for block %d", i)
+		}
+	}
+	if _, err := headers.GetFilterHeader(chainhash.Hash{}); err == nil {
+		t.Error("Returned filter header for unknown block")
+	}
+	tip, err := headers.GetFilterHeaderTip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip != blockHashes[1] {
+		t.Error("Filter header tip not set to the last block")
+	}
+
+	if err := headers.PutFilterTip(blockHashes[0]); err != nil {
+		t.Fatal(err)
+	}
+	tip, err = headers.GetFilterTip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip != blockHashes[0] {
+		t.Error("Returned incorrect filter tip")
+	}
+}
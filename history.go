@@ -0,0 +1,256 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil"
+)
+
+// HistoryFormat is the file format of an exported transaction history.
+type HistoryFormat string
+
+const (
+	HistoryCSV  HistoryFormat = "csv"
+	HistoryJSON HistoryFormat = "json"
+)
+
+// HistoryEntry is a transaction of the wallet as it is exported for
+// bookkeeping.
+type HistoryEntry struct {
+	Txid      string    `json:"txid"`
+	Timestamp time.Time `json:"timestamp"`
+	Height    int32     `json:"height"`
+
+	// The net value of the transaction to the wallet, including the fee
+	Value int64 `json:"value"`
+
+	// The fee paid by the wallet, zero for transactions funded by others
+	Fee int64 `json:"fee"`
+
+	// The addresses paid by a spend, or those a payment came from when they
+	// can be told from its inputs
+	Addresses []string `json:"addresses"`
+
+	Memo string `json:"memo"`
+
+	// The value in a fiat currency at the exchange rate of the day. Zero if
+	// no currency was requested or the rate is not available.
+	FiatValue    float64 `json:"fiatValue,omitempty"`
+	FiatCurrency string  `json:"fiatCurrency,omitempty"`
+}
+
+// ExportHistory returns the wallet's transactions between from and to in the
+// given format, see History. A zero time leaves that end of the range open.
+func (w *SPVWallet) ExportHistory(format HistoryFormat, from, to time.Time, currencyCode string) ([]byte, error) {
+	entries, err := w.History(from, to, currencyCode)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case HistoryCSV:
+		return historyCSV(entries)
+	case HistoryJSON:
+		return json.MarshalIndent(entries, "", "    ")
+	default:
+		return nil, errors.New("Unknown history format " + string(format))
+	}
+}
+
+// History returns the wallet's transactions between from and to, oldest first.
+// Dead transactions are left out. If currencyCode is set each value is also
+// converted at the exchange rate of its day. Rates are cached in the datastore
+// if it supports it so exporting the history again needn't fetch them.
+func (w *SPVWallet) History(from, to time.Time, currencyCode string) ([]HistoryEntry, error) {
+	txns, err := w.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	all, err := w.txstore.Txns().GetAll(true)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make(map[chainhash.Hash]*wire.MsgTx)
+	for _, txn := range all {
+		tx := wire.NewMsgTx(1)
+		if err := tx.BchDecode(bytes.NewReader(txn.Bytes), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+			continue
+		}
+		decoded[tx.TxHash()] = tx
+	}
+
+	rates := make(map[string]float64)
+	var entries []HistoryEntry
+	for _, txn := range txns {
+		if txn.Status == wallet.StatusDead || txn.Status == wallet.StatusError {
+			continue
+		}
+		if (!from.IsZero() && txn.Timestamp.Before(from)) || (!to.IsZero() && !txn.Timestamp.Before(to)) {
+			continue
+		}
+		entry := HistoryEntry{
+			Txid:      txn.Txid,
+			Timestamp: txn.Timestamp,
+			Height:    txn.Height,
+			Value:     txn.Value,
+			Memo:      txn.Memo,
+			Addresses: []string{},
+		}
+		txid, err := chainhash.NewHashFromStr(txn.Txid)
+		if err != nil {
+			return nil, err
+		}
+		if tx, ok := decoded[*txid]; ok {
+			if txn.Value < 0 {
+				entry.Fee = txn.Fee
+				if entry.Fee == 0 {
+					entry.Fee = txFee(tx, decoded)
+				}
+				entry.Addresses = w.spendCounterparties(tx)
+			} else {
+				entry.Addresses = w.paymentCounterparties(tx, decoded)
+			}
+		}
+		if currencyCode != "" {
+			day := txn.Timestamp.UTC().Format("2006-01-02")
+			rate, ok := rates[day]
+			if !ok {
+				rate, err = w.historicalRate(currencyCode, txn.Timestamp)
+				if err != nil {
+					log.Warningf("No %s exchange rate for %s: %s", currencyCode, day, err)
+				}
+				rates[day] = rate
+			}
+			if rate > 0 {
+				entry.FiatValue = math.Round(float64(txn.Value)/bchutil.SatoshiPerBitcoin*rate*100) / 100
+				entry.FiatCurrency = currencyCode
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// historicalRate returns the exchange rate of a currency on the day of t from
+// the datastore's cache or else the exchange rate provider.
+func (w *SPVWallet) historicalRate(currencyCode string, t time.Time) (float64, error) {
+	cache, cached := w.txstore.Datastore.(wallet.HistoricalRatesDatastore)
+	if cached {
+		rate, err := cache.HistoricalRates().Get(currencyCode, t)
+		if err != nil {
+			return 0, err
+		}
+		if rate > 0 {
+			return rate, nil
+		}
+	}
+	provider, ok := w.exchangeRates.(wallet.HistoricalExchangeRates)
+	if !ok {
+		return 0, errors.New("Exchange rate provider has no historical rates")
+	}
+	rate, err := provider.GetHistoricalRate(currencyCode, t)
+	if err != nil {
+		return 0, err
+	}
+	if cached {
+		if err := cache.HistoricalRates().Put(currencyCode, t, rate); err != nil {
+			log.Error(err)
+		}
+	}
+	return rate, nil
+}
+
+// txFee returns the fee of tx if the transactions it spends are known.
+func txFee(tx *wire.MsgTx, txs map[chainhash.Hash]*wire.MsgTx) int64 {
+	var fee int64
+	for _, in := range tx.TxIn {
+		prev, ok := txs[in.PreviousOutPoint.Hash]
+		if !ok || int(in.PreviousOutPoint.Index) >= len(prev.TxOut) {
+			return 0
+		}
+		fee += prev.TxOut[in.PreviousOutPoint.Index].Value
+	}
+	for _, out := range tx.TxOut {
+		fee -= out.Value
+	}
+	return fee
+}
+
+// spendCounterparties returns the addresses paid by tx other than the wallet's.
+func (w *SPVWallet) spendCounterparties(tx *wire.MsgTx) []string {
+	addrs := []string{}
+	for _, out := range tx.TxOut {
+		addr, err := w.ScriptToAddress(out.PkScript)
+		if err != nil || w.HasKey(addr) {
+			continue
+		}
+		addrs = appendAddress(addrs, addr.String())
+	}
+	return addrs
+}
+
+// paymentCounterparties returns the addresses the inputs of tx spend from. The
+// outputs they spend are usually unknown so for P2PKH inputs the address is
+// derived from the public key in the signature script.
+func (w *SPVWallet) paymentCounterparties(tx *wire.MsgTx, txs map[chainhash.Hash]*wire.MsgTx) []string {
+	addrs := []string{}
+	for _, in := range tx.TxIn {
+		if prev, ok := txs[in.PreviousOutPoint.Hash]; ok && int(in.PreviousOutPoint.Index) < len(prev.TxOut) {
+			if addr, err := w.ScriptToAddress(prev.TxOut[in.PreviousOutPoint.Index].PkScript); err == nil {
+				addrs = appendAddress(addrs, addr.String())
+			}
+			continue
+		}
+		if addr := sigScriptAddress(in.SignatureScript, w.params); addr != nil {
+			addrs = appendAddress(addrs, addr.String())
+		}
+	}
+	return addrs
+}
+
+func appendAddress(addrs []string, addr string) []string {
+	for _, a := range addrs {
+		if a == addr {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
+
+func historyCSV(entries []HistoryEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	cw.Write([]string{"txid", "date", "height", "value", "fee", "addresses", "memo", "fiatValue", "fiatCurrency"})
+	for _, e := range entries {
+		var fiatValue string
+		if e.FiatCurrency != "" {
+			fiatValue = strconv.FormatFloat(e.FiatValue, 'f', 2, 64)
+		}
+		cw.Write([]string{
+			e.Txid,
+			e.Timestamp.UTC().Format(time.RFC3339),
+			strconv.Itoa(int(e.Height)),
+			strconv.FormatInt(e.Value, 10),
+			strconv.FormatInt(e.Fee, 10),
+			strings.Join(e.Addresses, " "),
+			e.Memo,
+			fiatValue,
+			e.FiatCurrency,
+		})
+	}
+	cw.Flush()
+	return buf.Bytes(), cw.Error()
+}
@@ -0,0 +1,136 @@
+package bitcoincash
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchutil"
+)
+
+// testHistoricalRates returns a fixed rate for every day and counts lookups.
+type testHistoricalRates struct {
+	rate    float64
+	lookups int
+}
+
+func (r *testHistoricalRates) GetExchangeRate(currencyCode string) (float64, error) {
+	return r.rate, nil
+}
+
+func (r *testHistoricalRates) GetLatestRate(currencyCode string) (float64, error) {
+	return r.rate, nil
+}
+
+func (r *testHistoricalRates) GetAllRates(cacheOK bool) (map[string]float64, error) {
+	return map[string]float64{"USD": r.rate}, nil
+}
+
+func (r *testHistoricalRates) UnitsPerCoin() int {
+	return 100000000
+}
+
+func (r *testHistoricalRates) GetHistoricalRate(currencyCode string, t time.Time) (float64, error) {
+	r.lookups++
+	if r.rate == 0 {
+		return 0, errors.New("Offline")
+	}
+	return r.rate, nil
+}
+
+func TestSPVWallet_ExportHistory(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	rates := &testHistoricalRates{rate: 250}
+	w.exchangeRates = rates
+
+	tx := payTo(t, w.CurrentAddress(wallet.EXTERNAL), 1000000)
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	external, err := bchutil.NewAddressPubKeyHash(make([]byte, 20), w.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spend, err := w.Spend(100000, external, wallet.NORMAL, "Rent")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := w.History(time.Time{}, time.Time{}, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Returned %d entries, expected 2", len(entries))
+	}
+	payment := entries[0]
+	if payment.Txid != tx.TxHash().String() || payment.Value != 1000000 || payment.Fee != 0 {
+		t.Errorf("Returned the wrong payment %v", payment)
+	}
+	if payment.FiatValue != 2.5 || payment.FiatCurrency != "USD" {
+		t.Errorf("Returned fiat value %f %s, expected 2.50 USD", payment.FiatValue, payment.FiatCurrency)
+	}
+	sent := entries[1]
+	if sent.Txid != spend.String() || sent.Memo != "Rent" {
+		t.Errorf("Returned the wrong spend %v", sent)
+	}
+	if sent.Fee <= 0 || sent.Value != -100000-sent.Fee {
+		t.Errorf("Spend has value %d and fee %d", sent.Value, sent.Fee)
+	}
+	if len(sent.Addresses) != 1 || sent.Addresses[0] != external.String() {
+		t.Errorf("Returned counterparties %v, expected %s", sent.Addresses, external)
+	}
+	if rates.lookups != 1 {
+		t.Errorf("Looked up the rate %d times, expected once", rates.lookups)
+	}
+
+	// Exporting again uses the cached rates
+	rates.rate = 0
+	data, err := w.ExportHistory(HistoryCSV, time.Time{}, time.Time{}, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[1][0] != payment.Txid || rows[1][7] != "2.50" || rows[1][8] != "USD" {
+		t.Errorf("Exported the wrong CSV %v", rows)
+	}
+	if rates.lookups != 1 {
+		t.Error("Fetched a cached rate")
+	}
+
+	// Transactions outside the range are left out
+	data, err = w.ExportHistory(HistoryJSON, time.Now().Add(time.Hour), time.Time{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported []HistoryEntry
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 0 {
+		t.Errorf("Exported %d transactions, expected none", len(exported))
+	}
+	data, err = w.ExportHistory(HistoryJSON, time.Time{}, time.Now().Add(time.Hour), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 2 || exported[1].Memo != "Rent" || exported[1].FiatCurrency != "" {
+		t.Errorf("Exported the wrong transactions %v", exported)
+	}
+
+	if _, err := w.ExportHistory("xml", time.Time{}, time.Time{}, ""); err == nil {
+		t.Error("Exported an unknown format")
+	}
+}
@@ -0,0 +1,264 @@
+package bitcoincash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/bip21"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+)
+
+// ErrInvoicesNotSupported is returned when creating an invoice in a datastore
+// which can't save invoices.
+var ErrInvoicesNotSupported = errors.New("Datastore does not support invoices")
+
+// InvoiceStatus is the state of an invoice's payment.
+type InvoiceStatus int
+
+const (
+	// InvoiceUnpaid has no payments yet
+	InvoiceUnpaid InvoiceStatus = iota
+
+	// InvoicePartiallyPaid has received less than its amount
+	InvoicePartiallyPaid
+
+	// InvoicePaid has received its amount in transactions which are not
+	// all confirmed
+	InvoicePaid
+
+	// InvoiceOverpaid has received more than its amount in transactions
+	// which are not all confirmed
+	InvoiceOverpaid
+
+	// InvoiceExpired expired before receiving its amount
+	InvoiceExpired
+
+	// InvoiceConfirmed has received at least its amount in confirmed
+	// transactions
+	InvoiceConfirmed
+)
+
+var invoiceStatusNames = map[InvoiceStatus]string{
+	InvoiceUnpaid:        "unpaid",
+	InvoicePartiallyPaid: "partially paid",
+	InvoicePaid:          "paid",
+	InvoiceOverpaid:      "overpaid",
+	InvoiceExpired:       "expired",
+	InvoiceConfirmed:     "confirmed",
+}
+
+func (s InvoiceStatus) String() string {
+	return invoiceStatusNames[s]
+}
+
+// Invoice is a request for a payment to a fresh address of the wallet.
+type Invoice struct {
+	ID string
+
+	// The address the invoice is paid to and a payment URI for its amount
+	Address string
+	URI     string
+
+	// The amount due in satoshis
+	Amount int64
+
+	// The fiat amount the invoice was created for and the exchange rate its
+	// amount was locked at
+	FiatAmount   float64
+	FiatCurrency string
+	ExchangeRate float64
+
+	Memo    string
+	Created time.Time
+
+	// When the invoice expires, zero if it doesn't
+	Expires time.Time
+
+	Payments []InvoicePayment
+
+	// The value of the payments in transactions which are not dead
+	Received int64
+
+	Status InvoiceStatus
+}
+
+// InvoicePayment is an output paying an invoice. The height is that of its
+// transaction, zero if it is unconfirmed and negative if it is dead.
+type InvoicePayment struct {
+	Txid          string
+	Index         uint32
+	Value         int64
+	Height        int32
+	Confirmations uint32
+}
+
+// CreateInvoice creates an invoice paid to a new address of the wallet. Its
+// amount is given either in satoshis or in a fiat currency, which is converted
+// at the current exchange rate. That rate is kept with the invoice so the
+// amount due doesn't change. An expiry of zero creates an invoice which never
+// expires.
+func (w *SPVWallet) CreateInvoice(amount int64, fiatAmount float64, currencyCode string, expiry time.Duration, memo string) (Invoice, error) {
+	store, ok := w.txstore.Datastore.(wallet.InvoiceDatastore)
+	if !ok {
+		return Invoice{}, ErrInvoicesNotSupported
+	}
+	if amount < 0 || fiatAmount < 0 || expiry < 0 {
+		return Invoice{}, errors.New("Amount and expiry must not be negative")
+	}
+	if (amount > 0) == (fiatAmount > 0) {
+		return Invoice{}, errors.New("Give either an amount in satoshis or a fiat amount")
+	}
+	invoice := wallet.Invoice{Amount: amount, Memo: memo, Created: time.Now()}
+	if fiatAmount > 0 {
+		if currencyCode == "" {
+			return Invoice{}, errors.New("A currency is needed for a fiat amount")
+		}
+		if w.exchangeRates == nil {
+			return Invoice{}, errors.New("Exchange rates are disabled")
+		}
+		rate, err := w.exchangeRates.GetLatestRate(currencyCode)
+		if err != nil {
+			return Invoice{}, err
+		}
+		if rate <= 0 {
+			return Invoice{}, errors.New("No exchange rate for " + currencyCode)
+		}
+		invoice.Amount = int64(math.Round(fiatAmount / rate * float64(w.exchangeRates.UnitsPerCoin())))
+		if invoice.Amount <= 0 {
+			return Invoice{}, errors.New("Fiat amount is worth less than a satoshi")
+		}
+		invoice.FiatAmount = fiatAmount
+		invoice.FiatCurrency = currencyCode
+		invoice.ExchangeRate = rate
+	}
+	if expiry > 0 {
+		invoice.Expires = invoice.Created.Add(expiry)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Invoice{}, err
+	}
+	invoice.ID = hex.EncodeToString(id)
+
+	// The address is encoded as TxStore.Ingest encodes the outputs it matches
+	script, err := w.AddressToScript(w.NewAddress(wallet.EXTERNAL))
+	if err != nil {
+		return Invoice{}, err
+	}
+	addr, err := scriptToAddress(script, w.params)
+	if err != nil {
+		return Invoice{}, err
+	}
+	invoice.Address = encodeAddress(addr)
+	if err := store.Invoices().Put(invoice); err != nil {
+		return Invoice{}, err
+	}
+	return w.invoiceStatus(invoice, time.Now()), nil
+}
+
+// GetInvoice returns an invoice with its payments and status.
+func (w *SPVWallet) GetInvoice(id string) (Invoice, error) {
+	store, ok := w.txstore.Datastore.(wallet.InvoiceDatastore)
+	if !ok {
+		return Invoice{}, ErrInvoicesNotSupported
+	}
+	invoice, err := store.Invoices().Get(id)
+	if err != nil {
+		return Invoice{}, err
+	}
+	return w.invoiceStatus(invoice, time.Now()), nil
+}
+
+// Invoices returns every invoice, newest first.
+func (w *SPVWallet) Invoices() ([]Invoice, error) {
+	store, ok := w.txstore.Datastore.(wallet.InvoiceDatastore)
+	if !ok {
+		return nil, ErrInvoicesNotSupported
+	}
+	invoices, err := store.Invoices().GetAll()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ret := make([]Invoice, 0, len(invoices))
+	for _, invoice := range invoices {
+		ret = append(ret, w.invoiceStatus(invoice, now))
+	}
+	return ret, nil
+}
+
+// invoiceStatus adds the heights of an invoice's payments and works out its
+// status at the time now. Payments arriving after it expired still count, so
+// an expired invoice which is paid in full is reported as paid.
+func (w *SPVWallet) invoiceStatus(invoice wallet.Invoice, now time.Time) Invoice {
+	ret := Invoice{
+		ID:           invoice.ID,
+		Address:      invoice.Address,
+		Amount:       invoice.Amount,
+		FiatAmount:   invoice.FiatAmount,
+		FiatCurrency: invoice.FiatCurrency,
+		ExchangeRate: invoice.ExchangeRate,
+		Memo:         invoice.Memo,
+		Created:      invoice.Created,
+		Expires:      invoice.Expires,
+	}
+	if addr, err := w.DecodeAddress(invoice.Address); err == nil {
+		u := bip21.URI{Address: addr, Amount: invoice.Amount, Message: invoice.Memo}
+		ret.URI = u.String()
+	}
+
+	tip, _ := w.ChainTip()
+	confirmed := true
+	for _, p := range invoice.Payments {
+		payment := InvoicePayment{Txid: p.Txid, Index: p.Index, Value: p.Value, Height: -1}
+		if txid, err := chainhash.NewHashFromStr(p.Txid); err == nil {
+			if txn, err := w.txstore.Txns().Get(*txid); err == nil {
+				payment.Height = txn.Height
+			}
+		}
+		if payment.Height > 0 && uint32(payment.Height) <= tip {
+			payment.Confirmations = tip - uint32(payment.Height) + 1
+		}
+		if payment.Height >= 0 {
+			ret.Received += payment.Value
+			confirmed = confirmed && payment.Height > 0
+		}
+		ret.Payments = append(ret.Payments, payment)
+	}
+
+	expired := !invoice.Expires.IsZero() && now.After(invoice.Expires)
+	switch {
+	case ret.Received >= ret.Amount && confirmed:
+		ret.Status = InvoiceConfirmed
+	case ret.Received > ret.Amount:
+		ret.Status = InvoiceOverpaid
+	case ret.Received == ret.Amount:
+		ret.Status = InvoicePaid
+	case expired:
+		ret.Status = InvoiceExpired
+	case ret.Received > 0:
+		ret.Status = InvoicePartiallyPaid
+	default:
+		ret.Status = InvoiceUnpaid
+	}
+	return ret
+}
+
+// recordInvoicePayments records the outputs of a new transaction which pay an
+// invoice, if the datastore saves invoices.
+func (ts *TxStore) recordInvoicePayments(txid chainhash.Hash, outputs []wallet.TxnIO) {
+	store, ok := ts.Datastore.(wallet.InvoiceDatastore)
+	if !ok {
+		return
+	}
+	for _, out := range outputs {
+		payment := wallet.InvoicePayment{Txid: txid.String(), Index: out.Index, Value: out.Value}
+		if _, err := store.Invoices().AddPayment(out.Address, payment); err != nil {
+			log.Errorf("Error recording payment of invoice to %s: %s", out.Address, err)
+		}
+	}
+}
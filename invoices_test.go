@@ -0,0 +1,169 @@
+package bitcoincash
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/wire"
+)
+
+func TestSPVWallet_CreateInvoice(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.exchangeRates = &testHistoricalRates{rate: 250}
+
+	invoice, err := w.CreateInvoice(0, 5, "USD", time.Hour, "Order 7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The amount is locked at the exchange rate when it was created
+	w.exchangeRates = &testHistoricalRates{rate: 500}
+	if invoice.Amount != 2000000 || invoice.FiatAmount != 5 || invoice.FiatCurrency != "USD" || invoice.ExchangeRate != 250 {
+		t.Errorf("Unexpected amounts %d, %f %s at %f", invoice.Amount, invoice.FiatAmount, invoice.FiatCurrency, invoice.ExchangeRate)
+	}
+	if invoice.Status != InvoiceUnpaid || invoice.Memo != "Order 7" || !invoice.Expires.Equal(invoice.Created.Add(time.Hour)) {
+		t.Errorf("Unexpected invoice %v", invoice)
+	}
+	if !strings.Contains(invoice.URI, invoice.Address) || !strings.Contains(invoice.URI, "amount=0.02") {
+		t.Errorf("Unexpected URI %s", invoice.URI)
+	}
+	addr, err := w.DecodeAddress(invoice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.HasKey(addr) {
+		t.Error("Invoice is not paid to the wallet")
+	}
+	if current := w.CurrentAddress(wallet.EXTERNAL); current.String() == addr.String() {
+		t.Error("Invoice address is still the current address")
+	}
+
+	// Payments are tracked as the transactions paying it are ingested
+	first := payTo(t, addr, 1000000)
+	second := payTo(t, addr, 1500000)
+	// Spending the same outpoint would make them double spends
+	second.TxIn[0].PreviousOutPoint.Index = 1
+	tests := []struct {
+		ingest   *wire.MsgTx
+		height   int32
+		status   InvoiceStatus
+		received int64
+	}{
+		{first, 0, InvoicePartiallyPaid, 1000000},
+		{second, 0, InvoiceOverpaid, 2500000},
+		{first, 1, InvoiceOverpaid, 2500000},
+		{second, 1, InvoiceConfirmed, 2500000},
+	}
+	for _, test := range tests {
+		if _, err := w.txstore.Ingest(test.ingest, test.height, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		invoice, err = w.GetInvoice(invoice.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if invoice.Status != test.status || invoice.Received != test.received {
+			t.Errorf("Expected %s with %d received, got %s with %d", test.status, test.received, invoice.Status, invoice.Received)
+		}
+	}
+	if len(invoice.Payments) != 2 || invoice.Payments[0].Txid != first.TxHash().String() || invoice.Payments[0].Height != 1 {
+		t.Errorf("Unexpected payments %v", invoice.Payments)
+	}
+	tip, _ := w.ChainTip()
+	if invoice.Payments[1].Confirmations != tip {
+		t.Errorf("Expected %d confirmations, got %d", tip, invoice.Payments[1].Confirmations)
+	}
+
+	other, err := w.CreateInvoice(1000, 0, "", time.Minute, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	invoices, err := w.Invoices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invoices) != 2 || invoices[0].ID != other.ID || invoices[1].Status != InvoiceConfirmed {
+		t.Errorf("Unexpected invoices %v", invoices)
+	}
+}
+
+func TestSPVWallet_invoiceStatus(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	invoice, err := w.CreateInvoice(100000, 0, "", time.Hour, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.DecodeAddress(invoice.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := w.txstore.Datastore.(wallet.InvoiceDatastore)
+	stored, err := store.Invoices().Get(invoice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour * 2)
+	if status := w.invoiceStatus(stored, later).Status; status != InvoiceExpired {
+		t.Errorf("Expected an expired invoice, got %s", status)
+	}
+
+	// An invoice paid in full after it expired is still paid
+	tx := payTo(t, addr, 100000)
+	if _, err := w.txstore.Ingest(tx, 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	stored, err = store.Invoices().Get(invoice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := w.invoiceStatus(stored, later).Status; status != InvoicePaid {
+		t.Errorf("Expected a paid invoice, got %s", status)
+	}
+
+	// Dead payments don't count
+	if err := w.txstore.markAsDead(tx.TxHash()); err != nil {
+		t.Fatal(err)
+	}
+	if status := w.invoiceStatus(stored, time.Now()).Status; status != InvoiceUnpaid {
+		t.Errorf("Expected an unpaid invoice, got %s", status)
+	}
+}
+
+func TestSPVWallet_CreateInvoiceErrors(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.exchangeRates = nil
+	tests := []struct {
+		amount     int64
+		fiatAmount float64
+		currency   string
+		expiry     time.Duration
+	}{
+		{0, 0, "", 0},
+		{1000, 5, "USD", 0},
+		{-1000, 0, "", 0},
+		{1000, 0, "", -time.Hour},
+		{0, 5, "", 0},
+		{0, 5, "USD", 0},
+	}
+	for _, test := range tests {
+		if _, err := w.CreateInvoice(test.amount, test.fiatAmount, test.currency, test.expiry, ""); err == nil {
+			t.Errorf("Created an invoice for %d satoshis, %f %s, expiring in %s", test.amount, test.fiatAmount, test.currency, test.expiry)
+		}
+	}
+}
+
+func TestSPVWallet_InvoicesNotSupported(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	if _, err := w.CreateInvoice(1000, 0, "", 0, ""); err != ErrInvoicesNotSupported {
+		t.Errorf("Expected ErrInvoicesNotSupported, got %v", err)
+	}
+	if _, err := w.Invoices(); err != ErrInvoicesNotSupported {
+		t.Errorf("Expected ErrInvoicesNotSupported, got %v", err)
+	}
+}
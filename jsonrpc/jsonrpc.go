@@ -0,0 +1,257 @@
+// Package jsonrpc serves a subset of the bitcoind wallet JSON-RPC interface so
+// tools written for bitcoind can use the wallet.
+package jsonrpc
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+
+	bitcoincash "github.com/BubbaJoe/spvwallet-cash"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchutil"
+)
+
+// Error codes as returned by bitcoind
+const (
+	ErrMisc                = -1
+	ErrType                = -3
+	ErrWallet              = -4
+	ErrInvalidAddressOrKey = -5
+	ErrInsufficientFunds   = -6
+	ErrInvalidParameter    = -8
+	ErrWalletUnlockNeeded  = -13
+	ErrInvalidRequest      = -32600
+	ErrMethodNotFound      = -32601
+	ErrInternal            = -32603
+	ErrParse               = -32700
+)
+
+// The largest request body accepted
+const maxRequestSize = 1 << 20
+
+// Error is a JSON-RPC error with a bitcoind error code.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Config configures the JSON-RPC server.
+type Config struct {
+	// The address to listen on
+	ListenAddr string
+
+	// The basic auth credentials of every request. The password is required.
+	User     string
+	Password string
+}
+
+type request struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Result interface{}     `json:"result"`
+	Error  *Error          `json:"error"`
+	ID     json.RawMessage `json:"id"`
+}
+
+type handler func(s *Server, params []json.RawMessage) (interface{}, error)
+
+var handlers = map[string]handler{
+	"getbalance":       (*Server).getBalance,
+	"getnewaddress":    (*Server).getNewAddress,
+	"listtransactions": (*Server).listTransactions,
+	"gettransaction":   (*Server).getTransaction,
+	"sendtoaddress":    (*Server).sendToAddress,
+	"sendmany":         (*Server).sendMany,
+	"listunspent":      (*Server).listUnspent,
+	"validateaddress":  (*Server).validateAddress,
+	"getblockcount":    (*Server).getBlockCount,
+	"getbestblockhash": (*Server).getBestBlockHash,
+	"estimatefee":      (*Server).estimateFee,
+}
+
+// Server is an http.Handler serving JSON-RPC requests for a wallet.
+type Server struct {
+	w              *bitcoincash.SPVWallet
+	user, password string
+}
+
+func NewServer(w *bitcoincash.SPVWallet, user, password string) *Server {
+	return &Server{w: w, user: user, password: password}
+}
+
+// ListenAndServe serves JSON-RPC over HTTP. It isn't encrypted so it should
+// only be reached over a trusted network or through a TLS proxy.
+func ListenAndServe(w *bitcoincash.SPVWallet, config Config) error {
+	if config.Password == "" {
+		return errors.New("A JSON-RPC password is required")
+	}
+	return http.ListenAndServe(config.ListenAddr, NewServer(w, config.User, config.Password))
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(rw, "JSON-RPC server handles only POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+	user, password, ok := r.BasicAuth()
+	if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(s.user)) != 1 ||
+		subtle.ConstantTimeCompare([]byte(password), []byte(s.password)) != 1 {
+		rw.Header().Set("WWW-Authenticate", `Basic realm="jsonrpc"`)
+		http.Error(rw, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, maxRequestSize))
+	if err != nil {
+		writeResponse(rw, http.StatusBadRequest, response{Error: &Error{ErrInvalidRequest, err.Error()}})
+		return
+	}
+
+	// A batch is an array of requests answered with an array of responses
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []request
+		if err := json.Unmarshal(body, &batch); err != nil {
+			writeResponse(rw, http.StatusInternalServerError, response{Error: &Error{ErrParse, "Parse error"}})
+			return
+		}
+		responses := make([]response, 0, len(batch))
+		for _, req := range batch {
+			responses = append(responses, s.handle(req))
+		}
+		writeResponse(rw, http.StatusOK, responses)
+		return
+	}
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeResponse(rw, http.StatusInternalServerError, response{Error: &Error{ErrParse, "Parse error"}})
+		return
+	}
+	resp := s.handle(req)
+	status := http.StatusOK
+	if resp.Error != nil {
+		// bitcoind's status codes for JSON-RPC 1.0 errors
+		switch resp.Error.Code {
+		case ErrInvalidRequest:
+			status = http.StatusBadRequest
+		case ErrMethodNotFound:
+			status = http.StatusNotFound
+		default:
+			status = http.StatusInternalServerError
+		}
+	}
+	writeResponse(rw, status, resp)
+}
+
+func (s *Server) handle(req request) response {
+	resp := response{ID: req.ID}
+	if req.Method == "" {
+		resp.Error = &Error{ErrInvalidRequest, "Method must be a string"}
+		return resp
+	}
+	h, ok := handlers[req.Method]
+	if !ok {
+		resp.Error = &Error{ErrMethodNotFound, "Method not found"}
+		return resp
+	}
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			resp.Error = &Error{ErrInvalidRequest, "Params must be an array"}
+			return resp
+		}
+	}
+	result, err := h(s, params)
+	if err != nil {
+		resp.Error = rpcError(err)
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+// rpcError converts a wallet error to the code bitcoind would return.
+func rpcError(err error) *Error {
+	switch err := err.(type) {
+	case *Error:
+		return err
+	}
+	// Coin selection returns its own error with the same message
+	if strings.EqualFold(err.Error(), wallet.ErrorInsuffientFunds.Error()) {
+		return &Error{ErrInsufficientFunds, "Insufficient funds"}
+	}
+	if err == bitcoincash.ErrWalletLocked {
+		return &Error{ErrWalletUnlockNeeded, "Error: Please unlock the wallet first"}
+	}
+	return &Error{ErrWallet, err.Error()}
+}
+
+func writeResponse(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(v)
+}
+
+// param decodes the parameter at index i into v. A missing or null parameter
+// leaves v unchanged.
+func param(params []json.RawMessage, i int, v interface{}) error {
+	if i >= len(params) || string(params[i]) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params[i], v); err != nil {
+		return &Error{ErrType, fmt.Sprintf("Expected type %T for parameter %d", v, i+1)}
+	}
+	return nil
+}
+
+// requireParams returns an error if there are fewer than n parameters.
+func requireParams(params []json.RawMessage, n int) error {
+	if len(params) < n {
+		return &Error{ErrMisc, fmt.Sprintf("Expected at least %d parameters", n)}
+	}
+	return nil
+}
+
+// amount is a value in satoshi which is encoded in BCH with eight decimals.
+type amount int64
+
+func (a amount) MarshalJSON() ([]byte, error) {
+	sign, v := "", int64(a)
+	if v < 0 {
+		sign, v = "-", -v
+	}
+	return []byte(fmt.Sprintf("%s%d.%08d", sign, v/bchutil.SatoshiPerBitcoin, v%bchutil.SatoshiPerBitcoin)), nil
+}
+
+// parseAmount parses an amount in BCH given as a number or a string. It must
+// be exact to the satoshi.
+func parseAmount(raw json.RawMessage) (int64, error) {
+	invalid := &Error{ErrType, "Invalid amount"}
+	s := strings.Trim(string(raw), `"`)
+	if strings.Contains(s, "/") {
+		return 0, invalid
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() < 0 {
+		return 0, invalid
+	}
+	r.Mul(r, big.NewRat(bchutil.SatoshiPerBitcoin, 1))
+	if !r.IsInt() || r.Num().Cmp(big.NewInt(bchutil.MaxSatoshi)) > 0 {
+		return 0, invalid
+	}
+	return r.Num().Int64(), nil
+}
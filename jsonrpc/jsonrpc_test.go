@@ -0,0 +1,143 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func post(s *Server, user, password, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+	req.SetBasicAuth(user, password)
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := NewServer(nil, "user", "secret")
+	rec := post(s, "user", "wrong", `{"method":"getblockcount","id":1}`)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("Expected 401 with a challenge, got %d", rec.Code)
+	}
+	rec = post(s, "other", "secret", `{"method":"getblockcount","id":1}`)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for the wrong user, got %d", rec.Code)
+	}
+	req := httptest.NewRequest("GET", "/", nil)
+	req.SetBasicAuth("user", "secret")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", rec.Code)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	s := NewServer(nil, "user", "secret")
+	tests := []struct {
+		body   string
+		status int
+		code   int
+	}{
+		{`{"method":`, http.StatusInternalServerError, ErrParse},
+		{`{"method":"unknown","id":1}`, http.StatusNotFound, ErrMethodNotFound},
+		{`{"id":1}`, http.StatusBadRequest, ErrInvalidRequest},
+		{`{"method":"gettransaction","params":{"txid":"00"},"id":1}`, http.StatusBadRequest, ErrInvalidRequest},
+		{`{"method":"gettransaction","params":[],"id":1}`, http.StatusInternalServerError, ErrMisc},
+		{`{"method":"gettransaction","params":["nothex"],"id":1}`, http.StatusInternalServerError, ErrInvalidParameter},
+		{`{"method":"gettransaction","params":[5],"id":1}`, http.StatusInternalServerError, ErrType},
+		{`{"method":"sendtoaddress","params":["addr",1,"","",true],"id":1}`, http.StatusInternalServerError, ErrInvalidParameter},
+		{`{"method":"sendmany","params":["",{"addr":1},-1],"id":1}`, http.StatusInternalServerError, ErrInvalidParameter},
+		{`{"method":"estimatefee","params":[0],"id":1}`, http.StatusInternalServerError, ErrInvalidParameter},
+	}
+	for _, test := range tests {
+		rec := post(s, "user", "secret", test.body)
+		var resp struct {
+			Result interface{}
+			Error  *Error
+			ID     interface{}
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != test.status {
+			t.Errorf("%s: expected status %d, got %d", test.body, test.status, rec.Code)
+		}
+		if resp.Error == nil || resp.Error.Code != test.code {
+			t.Errorf("%s: expected error code %d, got %v", test.body, test.code, resp.Error)
+		}
+	}
+}
+
+func TestServer_Batch(t *testing.T) {
+	s := NewServer(nil, "user", "secret")
+	rec := post(s, "user", "secret", `[{"method":"unknown","id":"a"},{"method":"gettransaction","id":2}]`)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 for a batch, got %d", rec.Code)
+	}
+	var resps []struct {
+		Error *Error
+		ID    interface{}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resps); err != nil {
+		t.Fatal(err)
+	}
+	if len(resps) != 2 {
+		t.Fatalf("Expected 2 responses, got %d", len(resps))
+	}
+	if resps[0].ID != "a" || resps[0].Error.Code != ErrMethodNotFound {
+		t.Errorf("Unexpected first response %v %v", resps[0].ID, resps[0].Error)
+	}
+	if resps[1].ID != float64(2) || resps[1].Error.Code != ErrMisc {
+		t.Errorf("Unexpected second response %v %v", resps[1].ID, resps[1].Error)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		raw   string
+		value int64
+		valid bool
+	}{
+		{`1`, 100000000, true},
+		{`0.00000001`, 1, true},
+		{`"0.5"`, 50000000, true},
+		{`1e-8`, 1, true},
+		{`21000000`, 2100000000000000, true},
+		{`0.000000001`, 0, false},
+		{`-1`, 0, false},
+		{`21000001`, 0, false},
+		{`"1/3"`, 0, false},
+		{`"abc"`, 0, false},
+	}
+	for _, test := range tests {
+		value, err := parseAmount(json.RawMessage(test.raw))
+		if (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %t, got %v", test.raw, test.valid, err)
+		}
+		if value != test.value {
+			t.Errorf("%s: expected %d, got %d", test.raw, test.value, value)
+		}
+	}
+}
+
+func TestAmount_MarshalJSON(t *testing.T) {
+	tests := map[amount]string{
+		0:          "0.00000000",
+		1:          "0.00000001",
+		150000000:  "1.50000000",
+		-123456789: "-1.23456789",
+	}
+	for a, expected := range tests {
+		b, err := json.Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != expected {
+			t.Errorf("Expected %s, got %s", expected, b)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package jsonrpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	bitcoincash "github.com/BubbaJoe/spvwallet-cash"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchutil"
+)
+
+// txDetail is an entry of the details of a transaction, one for each address
+// paid.
+type txDetail struct {
+	Address  string  `json:"address,omitempty"`
+	Category string  `json:"category"`
+	Amount   amount  `json:"amount"`
+	Label    string  `json:"label,omitempty"`
+	Vout     uint32  `json:"vout"`
+	Fee      *amount `json:"fee,omitempty"`
+}
+
+// txEntry is an entry of listtransactions, a detail with its transaction.
+type txEntry struct {
+	txDetail
+	Confirmations int64  `json:"confirmations"`
+	BlockHeight   int32  `json:"blockheight,omitempty"`
+	Txid          string `json:"txid"`
+	Time          int64  `json:"time"`
+	TimeReceived  int64  `json:"timereceived"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+type txResult struct {
+	Amount        amount     `json:"amount"`
+	Fee           *amount    `json:"fee,omitempty"`
+	Confirmations int64      `json:"confirmations"`
+	BlockHeight   int32      `json:"blockheight,omitempty"`
+	Txid          string     `json:"txid"`
+	Time          int64      `json:"time"`
+	TimeReceived  int64      `json:"timereceived"`
+	Comment       string     `json:"comment,omitempty"`
+	Details       []txDetail `json:"details"`
+	Hex           string     `json:"hex"`
+}
+
+type unspentResult struct {
+	Txid          string `json:"txid"`
+	Vout          uint32 `json:"vout"`
+	Address       string `json:"address,omitempty"`
+	Label         string `json:"label,omitempty"`
+	ScriptPubKey  string `json:"scriptPubKey"`
+	Amount        amount `json:"amount"`
+	Confirmations uint32 `json:"confirmations"`
+	Spendable     bool   `json:"spendable"`
+	Solvable      bool   `json:"solvable"`
+}
+
+type addressResult struct {
+	IsValid      bool   `json:"isvalid"`
+	Address      string `json:"address"`
+	ScriptPubKey string `json:"scriptPubKey"`
+	IsMine       bool   `json:"ismine"`
+	IsScript     bool   `json:"isscript"`
+	Label        string `json:"label,omitempty"`
+}
+
+// getbalance ( "dummy" minconf )
+func (s *Server) getBalance(params []json.RawMessage) (interface{}, error) {
+	var dummy string
+	minconf := 0
+	if err := param(params, 0, &dummy); err != nil {
+		return nil, err
+	}
+	if err := param(params, 1, &minconf); err != nil {
+		return nil, err
+	}
+	confirmed, unconfirmed := s.w.Balance()
+	if minconf == 0 {
+		confirmed += unconfirmed
+	}
+	return amount(confirmed), nil
+}
+
+// getnewaddress ( "label" )
+func (s *Server) getNewAddress(params []json.RawMessage) (interface{}, error) {
+	var label string
+	if err := param(params, 0, &label); err != nil {
+		return nil, err
+	}
+	addr := s.w.NewAddress(wallet.EXTERNAL)
+	if label != "" {
+		if err := s.w.SetAddressLabel(addr, label); err != nil && err != bitcoincash.ErrMetadataNotSupported {
+			return nil, err
+		}
+	}
+	return addr.String(), nil
+}
+
+// listtransactions ( "label" count skip )
+func (s *Server) listTransactions(params []json.RawMessage) (interface{}, error) {
+	label, count, skip := "*", 10, 0
+	if err := param(params, 0, &label); err != nil {
+		return nil, err
+	}
+	if err := param(params, 1, &count); err != nil {
+		return nil, err
+	}
+	if err := param(params, 2, &skip); err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, &Error{ErrInvalidParameter, "Negative count"}
+	}
+	if skip < 0 {
+		return nil, &Error{ErrInvalidParameter, "Negative from"}
+	}
+	txns, err := s.w.Transactions()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(txns, func(i, j int) bool {
+		return txns[i].Timestamp.Before(txns[j].Timestamp)
+	})
+
+	// Entries are listed oldest first and skip counts back from the newest
+	entries := []txEntry{}
+	for _, txn := range txns {
+		if txn.Status == wallet.StatusDead {
+			continue
+		}
+		txid, err := chainhash.NewHashFromStr(txn.Txid)
+		if err != nil {
+			return nil, err
+		}
+		detail, err := s.w.GetTransactionDetail(*txid)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range s.details(detail) {
+			if label != "*" && d.Label != label {
+				continue
+			}
+			entries = append(entries, txEntry{
+				txDetail:      d,
+				Confirmations: detail.Confirmations,
+				BlockHeight:   detail.Height,
+				Txid:          detail.Txid,
+				Time:          detail.Timestamp.Unix(),
+				TimeReceived:  detail.Timestamp.Unix(),
+				Comment:       detail.Memo,
+			})
+		}
+	}
+	end := len(entries) - skip
+	if end < 0 {
+		end = 0
+	}
+	start := end - count
+	if start < 0 {
+		start = 0
+	}
+	return entries[start:end], nil
+}
+
+// gettransaction "txid"
+func (s *Server) getTransaction(params []json.RawMessage) (interface{}, error) {
+	if err := requireParams(params, 1); err != nil {
+		return nil, err
+	}
+	var txidStr string
+	if err := param(params, 0, &txidStr); err != nil {
+		return nil, err
+	}
+	txid, err := chainhash.NewHashFromStr(txidStr)
+	if err != nil {
+		return nil, &Error{ErrInvalidParameter, "Invalid txid"}
+	}
+	detail, err := s.w.GetTransactionDetail(*txid)
+	if err != nil {
+		return nil, &Error{ErrInvalidAddressOrKey, "Invalid or non-wallet transaction id"}
+	}
+	result := txResult{
+		Amount:        amount(detail.Value),
+		Confirmations: detail.Confirmations,
+		BlockHeight:   detail.Height,
+		Txid:          detail.Txid,
+		Time:          detail.Timestamp.Unix(),
+		TimeReceived:  detail.Timestamp.Unix(),
+		Comment:       detail.Memo,
+		Details:       s.details(detail),
+		Hex:           hex.EncodeToString(detail.Raw),
+	}
+	// Like bitcoind the amount of a send excludes its fee
+	if detail.Value < 0 && detail.Fee > 0 {
+		fee := amount(-detail.Fee)
+		result.Amount = amount(detail.Value + detail.Fee)
+		result.Fee = &fee
+	}
+	if result.BlockHeight < 0 {
+		result.BlockHeight = 0
+	}
+	return result, nil
+}
+
+// details returns a send entry for each output a transaction pays from the
+// wallet, or a receive entry for each output it pays to the wallet. A payment
+// to ourselves has both for each of its outputs.
+func (s *Server) details(detail bitcoincash.TransactionDetail) []txDetail {
+	var fee *amount
+	if detail.Value < 0 && detail.Fee > 0 {
+		f := amount(-detail.Fee)
+		fee = &f
+	}
+	details := []txDetail{}
+	if detail.Value < 0 {
+		for _, out := range detail.Outputs {
+			if !out.Mine && !out.OpReturn {
+				details = append(details, txDetail{Address: out.Address, Category: "send", Amount: amount(-out.Value), Vout: out.Index, Fee: fee})
+			}
+		}
+		if len(details) > 0 {
+			return details
+		}
+	}
+	for _, out := range detail.Outputs {
+		if !out.Mine {
+			continue
+		}
+		label := s.label(out.Address)
+		if detail.Value < 0 {
+			details = append(details, txDetail{Address: out.Address, Category: "send", Amount: amount(-out.Value), Label: label, Vout: out.Index, Fee: fee})
+		}
+		details = append(details, txDetail{Address: out.Address, Category: "receive", Amount: amount(out.Value), Label: label, Vout: out.Index})
+	}
+	return details
+}
+
+// label returns the label of an address, empty if it has none.
+func (s *Server) label(address string) string {
+	addr, err := s.w.DecodeAddress(address)
+	if err != nil {
+		return ""
+	}
+	return s.w.AddressLabel(addr)
+}
+
+// sendtoaddress "address" amount ( "comment" "comment_to" subtractfeefromamount )
+func (s *Server) sendToAddress(params []json.RawMessage) (interface{}, error) {
+	if err := requireParams(params, 2); err != nil {
+		return nil, err
+	}
+	var addrStr, comment, commentTo string
+	var subtractFee bool
+	if err := param(params, 0, &addrStr); err != nil {
+		return nil, err
+	}
+	if err := param(params, 2, &comment); err != nil {
+		return nil, err
+	}
+	if err := param(params, 3, &commentTo); err != nil {
+		return nil, err
+	}
+	if err := param(params, 4, &subtractFee); err != nil {
+		return nil, err
+	}
+	if subtractFee {
+		return nil, &Error{ErrInvalidParameter, "Subtracting the fee from the amount is not supported"}
+	}
+	addr, err := s.w.DecodeAddress(addrStr)
+	if err != nil {
+		return nil, &Error{ErrInvalidAddressOrKey, "Invalid Bitcoin Cash address"}
+	}
+	value, err := sendAmount(params[1])
+	if err != nil {
+		return nil, err
+	}
+	txid, err := s.w.Spend(value, addr, wallet.NORMAL, comment)
+	if err != nil {
+		return nil, err
+	}
+	return txid.String(), nil
+}
+
+// sendmany "dummy" {"address":amount,...} ( minconf "comment" )
+func (s *Server) sendMany(params []json.RawMessage) (interface{}, error) {
+	if err := requireParams(params, 2); err != nil {
+		return nil, err
+	}
+	var dummy, comment string
+	var amounts map[string]json.RawMessage
+	minconf := 1
+	if err := param(params, 0, &dummy); err != nil {
+		return nil, err
+	}
+	if err := param(params, 1, &amounts); err != nil {
+		return nil, err
+	}
+	if err := param(params, 2, &minconf); err != nil {
+		return nil, err
+	}
+	if err := param(params, 3, &comment); err != nil {
+		return nil, err
+	}
+	if minconf < 0 {
+		return nil, &Error{ErrInvalidParameter, "Invalid parameter, minconf must not be negative"}
+	}
+	if len(amounts) == 0 {
+		return nil, &Error{ErrInvalidParameter, "Transaction must have at least one recipient"}
+	}
+	seen := make(map[string]bool)
+	var outputs []wallet.TransactionOutput
+	for addrStr, raw := range amounts {
+		addr, err := s.w.DecodeAddress(addrStr)
+		if err != nil {
+			return nil, &Error{ErrInvalidAddressOrKey, "Invalid Bitcoin Cash address: " + addrStr}
+		}
+		if seen[addr.String()] {
+			return nil, &Error{ErrInvalidParameter, "Invalid parameter, duplicated address: " + addrStr}
+		}
+		seen[addr.String()] = true
+		value, err := sendAmount(raw)
+		if err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: value})
+	}
+	sort.Slice(outputs, func(i, j int) bool {
+		return outputs[i].Address.String() < outputs[j].Address.String()
+	})
+	// Coins with fewer than minconf confirmations are kept out of the spend
+	opts := bitcoincash.SpendOptions{Memo: comment}
+	if minconf > 0 {
+		utxos, err := s.w.ListUnspent()
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range utxos {
+			if int(u.Confirmations) < minconf {
+				opts.ExcludeInputs = append(opts.ExcludeInputs, u.Op)
+			}
+		}
+	}
+	txid, err := s.w.SpendMany(outputs, wallet.NORMAL, opts)
+	if err != nil {
+		return nil, err
+	}
+	return txid.String(), nil
+}
+
+// sendAmount parses the amount of a payment, which must be above zero.
+func sendAmount(raw json.RawMessage) (int64, error) {
+	value, err := parseAmount(raw)
+	if err != nil {
+		return 0, err
+	}
+	if value == 0 {
+		return 0, &Error{ErrType, "Invalid amount for send"}
+	}
+	return value, nil
+}
+
+// listunspent ( minconf maxconf ["address",...] )
+func (s *Server) listUnspent(params []json.RawMessage) (interface{}, error) {
+	minconf, maxconf := 1, 9999999
+	var addresses []string
+	if err := param(params, 0, &minconf); err != nil {
+		return nil, err
+	}
+	if err := param(params, 1, &maxconf); err != nil {
+		return nil, err
+	}
+	if err := param(params, 2, &addresses); err != nil {
+		return nil, err
+	}
+	filter := make(map[string]bool)
+	for _, addrStr := range addresses {
+		addr, err := s.w.DecodeAddress(addrStr)
+		if err != nil {
+			return nil, &Error{ErrInvalidAddressOrKey, "Invalid Bitcoin Cash address: " + addrStr}
+		}
+		if filter[addr.String()] {
+			return nil, &Error{ErrInvalidParameter, "Invalid parameter, duplicated address: " + addrStr}
+		}
+		filter[addr.String()] = true
+	}
+	utxos, err := s.w.ListUnspent()
+	if err != nil {
+		return nil, err
+	}
+	results := []unspentResult{}
+	for _, u := range utxos {
+		if u.Locked || int(u.Confirmations) < minconf || int(u.Confirmations) > maxconf {
+			continue
+		}
+		// Watched non standard scripts have no address
+		var addr string
+		if u.Address != nil {
+			addr = u.Address.String()
+		}
+		if len(filter) > 0 && !filter[addr] {
+			continue
+		}
+		results = append(results, unspentResult{
+			Txid:          u.Op.Hash.String(),
+			Vout:          u.Op.Index,
+			Address:       addr,
+			Label:         u.Label,
+			ScriptPubKey:  hex.EncodeToString(u.ScriptPubkey),
+			Amount:        amount(u.Value),
+			Confirmations: u.Confirmations,
+			Spendable:     !u.WatchOnly,
+			Solvable:      !u.WatchOnly,
+		})
+	}
+	return results, nil
+}
+
+// validateaddress "address"
+func (s *Server) validateAddress(params []json.RawMessage) (interface{}, error) {
+	if err := requireParams(params, 1); err != nil {
+		return nil, err
+	}
+	var addrStr string
+	if err := param(params, 0, &addrStr); err != nil {
+		return nil, err
+	}
+	addr, err := s.w.DecodeAddress(addrStr)
+	if err != nil {
+		return map[string]bool{"isvalid": false}, nil
+	}
+	script, err := s.w.AddressToScript(addr)
+	if err != nil {
+		return map[string]bool{"isvalid": false}, nil
+	}
+	_, isScript := addr.(*bchutil.AddressScriptHash)
+	return addressResult{
+		IsValid:      true,
+		Address:      addr.String(),
+		ScriptPubKey: hex.EncodeToString(script),
+		IsMine:       s.w.HasKey(addr),
+		IsScript:     isScript,
+		Label:        s.w.AddressLabel(addr),
+	}, nil
+}
+
+// getblockcount
+func (s *Server) getBlockCount(params []json.RawMessage) (interface{}, error) {
+	height, _ := s.w.ChainTip()
+	return height, nil
+}
+
+// getbestblockhash
+func (s *Server) getBestBlockHash(params []json.RawMessage) (interface{}, error) {
+	_, hash := s.w.ChainTip()
+	return hash.String(), nil
+}
+
+// estimatefee nblocks
+//
+// Returns the fee per kilobyte of the fee level for confirming within nblocks.
+func (s *Server) estimateFee(params []json.RawMessage) (interface{}, error) {
+	if err := requireParams(params, 1); err != nil {
+		return nil, err
+	}
+	var nblocks int
+	if err := param(params, 0, &nblocks); err != nil {
+		return nil, err
+	}
+	if nblocks < 1 {
+		return nil, &Error{ErrInvalidParameter, fmt.Sprintf("Invalid nblocks %d", nblocks)}
+	}
+	var level wallet.FeeLevel
+	switch {
+	case nblocks <= 2:
+		level = wallet.PRIOIRTY
+	case nblocks <= 6:
+		level = wallet.NORMAL
+	default:
+		level = wallet.ECONOMIC
+	}
+	return amount(s.w.GetFeePerByte(level) * 1000), nil
+}
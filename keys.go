@@ -1,8 +1,11 @@
 package bitcoincash
 
 import (
+	"sync"
+
 	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
 	"github.com/btcsuite/goleveldb/leveldb/errors"
+	"github.com/gcash/bchd/bchec"
 	"github.com/gcash/bchd/chaincfg"
 	hd "github.com/gcash/bchutil/hdkeychain"
 )
@@ -13,20 +16,34 @@ type KeyManager struct {
 	datastore wallet.Keys
 	params    *chaincfg.Params
 
+	// The BIP44 account the keys are derived for
+	account uint32
+
+	keyLock     sync.RWMutex
 	internalKey *hd.ExtendedKey
 	externalKey *hd.ExtendedKey
 }
 
 func NewKeyManager(db wallet.Keys, params *chaincfg.Params, masterPrivKey *hd.ExtendedKey) (*KeyManager, error) {
-	internal, external, err := Bip44Derivation(masterPrivKey)
+	accountKey, err := bip44Account(masterPrivKey, DefaultAccount)
 	if err != nil {
 		return nil, err
 	}
+	return newKeyManager(db, params, DefaultAccount, accountKey)
+}
+
+// newKeyManager returns a key manager for a BIP44 account key. db must only
+// hold the keys of that account. If the account key is public the manager can
+// derive addresses but not private keys until setAccountKey is called with the
+// private account key.
+func newKeyManager(db wallet.Keys, params *chaincfg.Params, account uint32, accountKey *hd.ExtendedKey) (*KeyManager, error) {
 	km := &KeyManager{
-		datastore:   db,
-		params:      params,
-		internalKey: internal,
-		externalKey: external,
+		datastore: db,
+		params:    params,
+		account:   account,
+	}
+	if err := km.setAccountKey(accountKey); err != nil {
+		return nil, err
 	}
 	if err := km.lookahead(); err != nil {
 		return nil, err
@@ -34,23 +51,47 @@ func NewKeyManager(db wallet.Keys, params *chaincfg.Params, masterPrivKey *hd.Ex
 	return km, nil
 }
 
+// setAccountKey replaces the account key the child keys are derived from. It
+// is used to swap between the private and public account key when the wallet
+// is unlocked and locked.
+func (km *KeyManager) setAccountKey(account *hd.ExtendedKey) error {
+	internal, external, err := accountChains(account)
+	if err != nil {
+		return err
+	}
+	km.keyLock.Lock()
+	defer km.keyLock.Unlock()
+	km.internalKey = internal
+	km.externalKey = external
+	return nil
+}
+
 // m / purpose' / coin_type' / account' / change / address_index
 func Bip44Derivation(masterPrivKey *hd.ExtendedKey) (internal, external *hd.ExtendedKey, err error) {
+	account, err := bip44Account(masterPrivKey, DefaultAccount)
+	if err != nil {
+		return nil, nil, err
+	}
+	return accountChains(account)
+}
+
+// bip44Account derives m / 44' / 145' / account'
+func bip44Account(masterPrivKey *hd.ExtendedKey, account uint32) (*hd.ExtendedKey, error) {
 	// Purpose = bip44
 	fourtyFour, err := masterPrivKey.Child(hd.HardenedKeyStart + 44)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// Cointype = bitcoin
 	bitcoin, err := fourtyFour.Child(hd.HardenedKeyStart + 145)
 	if err != nil {
-		return nil, nil, err
-	}
-	// Account = 0
-	account, err := bitcoin.Child(hd.HardenedKeyStart + 0)
-	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return bitcoin.Child(hd.HardenedKeyStart + account)
+}
+
+// accountChains derives the external and internal chains of an account key.
+func accountChains(account *hd.ExtendedKey) (internal, external *hd.ExtendedKey, err error) {
 	// Change(0) = external
 	external, err = account.Child(0)
 	if err != nil {
@@ -156,20 +197,44 @@ func (km *KeyManager) GetKeys() []*hd.ExtendedKey {
 		keys = append(keys, k)
 	}
 	imported, err := km.datastore.GetImported()
+	if err == nil {
+		for _, key := range imported {
+			hdKey := hd.NewExtendedKey(
+				km.params.HDPrivateKeyID[:],
+				key.Serialize(), make([]byte, 32),
+				[]byte{0x00, 0x00, 0x00, 0x00},
+				0, 0, true)
+			keys = append(keys, hdKey)
+		}
+		return keys
+	}
+	// The private keys can't be read while the wallet is locked but the
+	// public keys are enough to watch the imported addresses.
+	pubKeyStore, ok := km.datastore.(importedPubKeyStore)
+	if !ok {
+		return keys
+	}
+	pubKeys, err := pubKeyStore.GetImportedPubKeys()
 	if err != nil {
 		return keys
 	}
-	for _, key := range imported {
+	for _, key := range pubKeys {
 		hdKey := hd.NewExtendedKey(
-			km.params.HDPrivateKeyID[:],
-			key.Serialize(), make([]byte, 32),
+			km.params.HDPublicKeyID[:],
+			key.SerializeCompressed(), make([]byte, 32),
 			[]byte{0x00, 0x00, 0x00, 0x00},
-			0, 0, true)
+			0, 0, false)
 		keys = append(keys, hdKey)
 	}
 	return keys
 }
 
+// importedPubKeyStore is implemented by key stores which keep the public keys
+// of imported keys readable while the private keys are encrypted.
+type importedPubKeyStore interface {
+	GetImportedPubKeys() ([]*bchec.PublicKey, error)
+}
+
 // func (km *KeyManager) GetKeyForAddress(address bchutil.Address) (*hd.ExtendedKey, error) {
 // 	keyPaths, err := km.datastore.GetAll()
 // 	if err != nil {
@@ -234,6 +299,8 @@ func (km *KeyManager) MarkKeyAsUsed(scriptAddress []byte) error {
 }
 
 func (km *KeyManager) generateChildKey(purpose wallet.KeyPurpose, index uint32) (*hd.ExtendedKey, error) {
+	km.keyLock.RLock()
+	defer km.keyLock.RUnlock()
 	if purpose == wallet.EXTERNAL {
 		return km.externalKey.Child(index)
 	} else if purpose == wallet.INTERNAL {
@@ -284,3 +284,65 @@ func TestKeyManager_GetKeyForScript(t *testing.T) {
 		t.Error("Failed to return imported key")
 	}
 }
+
+func TestKeyManager_setAccountKey(t *testing.T) {
+	km, err := createKeyManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	masterPrivKey, err := hdkeychain.NewKeyFromString("xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := bip44Account(masterPrivKey, DefaultAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountPub, err := account.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, external, err := accountChains(account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedKey, err := external.Child(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedAddr, err := expectedKey.Address(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Locked
+	if err := km.setAccountKey(accountPub); err != nil {
+		t.Fatal(err)
+	}
+	externalKey, err := km.generateChildKey(wallet.EXTERNAL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	externalAddr, err := externalKey.Address(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if externalAddr.String() != expectedAddr.String() {
+		t.Error("Public account key derived the wrong address")
+	}
+	if _, err := externalKey.ECPrivKey(); err == nil {
+		t.Error("Derived a private key from the public account key")
+	}
+
+	// Unlocked
+	if err := km.setAccountKey(account); err != nil {
+		t.Fatal(err)
+	}
+	externalKey, err = km.generateChildKey(wallet.EXTERNAL, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := externalKey.ECPrivKey(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,113 @@
+package bitcoincash
+
+import (
+	"errors"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchutil"
+)
+
+// ErrMetadataNotSupported is returned when labelling an address or transaction
+// in a datastore which can't save labels and memos.
+var ErrMetadataNotSupported = errors.New("Datastore does not support labels and memos")
+
+// SetAddressLabel saves a label for addr, which needn't be an address of the
+// wallet. An empty label removes it.
+func (w *SPVWallet) SetAddressLabel(addr bchutil.Address, label string) error {
+	store, ok := w.txstore.Datastore.(wallet.MetadataDatastore)
+	if !ok {
+		return ErrMetadataNotSupported
+	}
+	key, err := w.labelKey(addr)
+	if err != nil {
+		return err
+	}
+	if label == "" {
+		return store.AddressLabels().Delete(key)
+	}
+	return store.AddressLabels().Put(key, label)
+}
+
+// AddressLabel returns the label of addr, an empty string if it has none.
+func (w *SPVWallet) AddressLabel(addr bchutil.Address) string {
+	store, ok := w.txstore.Datastore.(wallet.MetadataDatastore)
+	if !ok {
+		return ""
+	}
+	key, err := w.labelKey(addr)
+	if err != nil {
+		return ""
+	}
+	label, err := store.AddressLabels().Get(key)
+	if err != nil {
+		log.Error(err)
+	}
+	return label
+}
+
+// AddressLabels returns every label keyed by the cash address it labels. It
+// is empty if the datastore can't save labels.
+func (w *SPVWallet) AddressLabels() map[string]string {
+	store, ok := w.txstore.Datastore.(wallet.MetadataDatastore)
+	if !ok {
+		return make(map[string]string)
+	}
+	labels, err := store.AddressLabels().GetAll()
+	if err != nil {
+		log.Error(err)
+	}
+	return labels
+}
+
+// SetTxMemo saves a memo for a transaction. An empty memo removes it.
+func (w *SPVWallet) SetTxMemo(txid chainhash.Hash, memo string) error {
+	store, ok := w.txstore.Datastore.(wallet.MetadataDatastore)
+	if !ok {
+		return ErrMetadataNotSupported
+	}
+	if memo == "" {
+		return store.TxMemos().Delete(txid)
+	}
+	return store.TxMemos().Put(txid, memo)
+}
+
+// labelKey returns the encoding of addr labels are saved under so a legacy
+// address shares the label of its cash address.
+func (w *SPVWallet) labelKey(addr bchutil.Address) (string, error) {
+	script, err := w.AddressToScript(addr)
+	if err != nil {
+		return "", err
+	}
+	cashaddr, err := w.ScriptToAddress(script)
+	if err != nil {
+		return "", err
+	}
+	return cashaddr.String(), nil
+}
+
+// txMemo returns the memo of a transaction, an empty string if it has none.
+func (w *SPVWallet) txMemo(txid chainhash.Hash) string {
+	store, ok := w.txstore.Datastore.(wallet.MetadataDatastore)
+	if !ok {
+		return ""
+	}
+	memo, err := store.TxMemos().Get(txid)
+	if err != nil {
+		log.Error(err)
+	}
+	return memo
+}
+
+// txMemos returns the saved memos, none if the datastore can't save them.
+func (w *SPVWallet) txMemos() map[chainhash.Hash]string {
+	store, ok := w.txstore.Datastore.(wallet.MetadataDatastore)
+	if !ok {
+		return make(map[chainhash.Hash]string)
+	}
+	memos, err := store.TxMemos().GetAll()
+	if err != nil {
+		log.Error(err)
+	}
+	return memos
+}
@@ -0,0 +1,112 @@
+package bitcoincash
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+)
+
+func TestSPVWallet_SetAddressLabel(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+	tx := payTo(t, addr, 1000000)
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.SetAddressLabel(addr, "Savings"); err != nil {
+		t.Fatal(err)
+	}
+	if label := w.AddressLabel(addr); label != "Savings" {
+		t.Errorf("Returned label %s, expected Savings", label)
+	}
+	if labels := w.AddressLabels(); len(labels) != 1 || labels[addr.String()] != "Savings" {
+		t.Errorf("Returned the wrong labels %v", labels)
+	}
+	utxos, err := w.ListUnspent()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(utxos) != 1 || utxos[0].Label != "Savings" {
+		t.Error("ListUnspent didn't return the label")
+	}
+
+	if err := w.SetAddressLabel(addr, ""); err != nil {
+		t.Fatal(err)
+	}
+	if label := w.AddressLabel(addr); label != "" {
+		t.Error("Label was not removed")
+	}
+}
+
+func TestSPVWallet_SetTxMemo(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	tx := payTo(t, w.CurrentAddress(wallet.EXTERNAL), 1000000)
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	txid := tx.TxHash()
+	if err := w.SetTxMemo(txid, "Salary"); err != nil {
+		t.Fatal(err)
+	}
+	txns, err := w.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Memo != "Salary" {
+		t.Error("Transactions didn't return the memo")
+	}
+	txn, err := w.GetTransaction(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Memo != "Salary" {
+		t.Error("GetTransaction didn't return the memo")
+	}
+
+	// The reference ID of a spend is its memo
+	spend, err := w.Spend(10000, w.NewAddress(wallet.EXTERNAL), wallet.NORMAL, "Rent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err = w.GetTransaction(*spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Memo != "Rent" {
+		t.Errorf("Spend saved memo %q, expected Rent", txn.Memo)
+	}
+
+	spend, err = w.SpendWithOptions(10000, w.NewAddress(wallet.EXTERNAL), wallet.NORMAL, SpendOptions{FeePerByte: 3, Memo: "Food"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txn, err = w.GetTransaction(*spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Memo != "Food" {
+		t.Errorf("SpendWithOptions saved memo %q, expected Food", txn.Memo)
+	}
+}
+
+func TestSPVWallet_MetadataNotSupported(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	if err := w.SetAddressLabel(w.CurrentAddress(wallet.EXTERNAL), "Savings"); err != ErrMetadataNotSupported {
+		t.Errorf("Expected ErrMetadataNotSupported, got %v", err)
+	}
+	if err := w.SetTxMemo(chainhash.Hash{}, "Salary"); err != ErrMetadataNotSupported {
+		t.Errorf("Expected ErrMetadataNotSupported, got %v", err)
+	}
+	if labels := w.AddressLabels(); len(labels) != 0 {
+		t.Error("Returned labels from a datastore which can't save them")
+	}
+}
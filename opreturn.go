@@ -0,0 +1,102 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+)
+
+// MaxDataCarrierSize is the largest total size of the OP_RETURN scripts of a
+// transaction relayed by nodes, counting the OP_RETURN and push opcodes.
+const MaxDataCarrierSize = 223
+
+// DataOutput returns an OP_RETURN output pushing each item of data. A raw
+// payload is a single push.
+func DataOutput(data ...[]byte) wallet.TransactionOutput {
+	return wallet.TransactionOutput{OpReturn: true, Data: data}
+}
+
+// dataScript returns an OP_RETURN script with a push of each item of data.
+// Unlike txscript.ScriptBuilder it never replaces a one byte push with a
+// small integer opcode so the data is decoded unchanged.
+func dataScript(data [][]byte) []byte {
+	script := []byte{txscript.OP_RETURN}
+	for _, d := range data {
+		switch n := len(d); {
+		case n == 0:
+			script = append(script, txscript.OP_0)
+		case n <= txscript.OP_DATA_75:
+			script = append(script, byte(n))
+		case n <= 0xff:
+			script = append(script, txscript.OP_PUSHDATA1, byte(n))
+		default:
+			script = append(script, txscript.OP_PUSHDATA2, byte(n), byte(n>>8))
+		}
+		script = append(script, d...)
+	}
+	return script
+}
+
+// opReturnData returns the data pushes of an OP_RETURN script. ok is false if
+// the script isn't an OP_RETURN script.
+func opReturnData(script []byte) (data [][]byte, ok bool) {
+	if len(script) == 0 || script[0] != txscript.OP_RETURN {
+		return nil, false
+	}
+	// A malformed script still carries no value
+	data, _ = txscript.PushedData(script[1:])
+	return data, true
+}
+
+// outputScript returns the output script paying out.
+func outputScript(out wallet.TransactionOutput) ([]byte, error) {
+	if out.OpReturn {
+		return dataScript(out.Data), nil
+	}
+	if out.Address == nil {
+		return nil, errors.New("Output has no address")
+	}
+	return txscript.PayToAddrScript(out.Address)
+}
+
+// checkDataOutputs checks the OP_RETURN outputs of a transaction are standard.
+func checkDataOutputs(outputs []wallet.TransactionOutput) error {
+	var size int
+	for _, out := range outputs {
+		if !out.OpReturn {
+			continue
+		}
+		if out.Value != 0 {
+			return errors.New("OP_RETURN outputs can't have a value")
+		}
+		size += len(dataScript(out.Data))
+	}
+	if size > MaxDataCarrierSize {
+		return fmt.Errorf("OP_RETURN outputs are %d bytes, the limit is %d", size, MaxDataCarrierSize)
+	}
+	return nil
+}
+
+// txDataOutputs returns the OP_RETURN outputs of a serialized transaction.
+func txDataOutputs(txBytes []byte) []wallet.TransactionOutput {
+	tx := wire.NewMsgTx(1)
+	if err := tx.BchDecode(bytes.NewReader(txBytes), 1, wire.BaseEncoding); err != nil {
+		return nil
+	}
+	var outputs []wallet.TransactionOutput
+	for i, txout := range tx.TxOut {
+		if data, ok := opReturnData(txout.PkScript); ok {
+			outputs = append(outputs, wallet.TransactionOutput{
+				Value:    txout.Value,
+				Index:    uint32(i),
+				OpReturn: true,
+				Data:     data,
+			})
+		}
+	}
+	return outputs
+}
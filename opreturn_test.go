@@ -0,0 +1,124 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/txscript"
+)
+
+func Test_dataScript(t *testing.T) {
+	data := [][]byte{{0x6d, 0x02}, {0x01}, {}, bytes.Repeat([]byte{0xaa}, 100)}
+	script := dataScript(data)
+	if script[0] != txscript.OP_RETURN {
+		t.Fatal("Script doesn't start with OP_RETURN")
+	}
+	if !txscript.IsPushOnlyScript(script[1:]) {
+		t.Error("Script has opcodes other than pushes")
+	}
+	decoded, ok := opReturnData(script)
+	if !ok {
+		t.Fatal("Failed to decode OP_RETURN script")
+	}
+	if len(decoded) != len(data) {
+		t.Fatalf("Decoded %d pushes, expected %d", len(decoded), len(data))
+	}
+	for i := range data {
+		if !bytes.Equal(decoded[i], data[i]) {
+			t.Errorf("Push %d: decoded %x, expected %x", i, decoded[i], data[i])
+		}
+	}
+	if _, ok := opReturnData([]byte{txscript.OP_DUP}); ok {
+		t.Error("Decoded a script which isn't an OP_RETURN script")
+	}
+}
+
+func Test_checkDataOutputs(t *testing.T) {
+	// OP_RETURN, OP_PUSHDATA1 and its length leave 220 bytes of data
+	if err := checkDataOutputs([]wallet.TransactionOutput{DataOutput(make([]byte, 220))}); err != nil {
+		t.Error(err)
+	}
+	if err := checkDataOutputs([]wallet.TransactionOutput{DataOutput(make([]byte, 221))}); err == nil {
+		t.Error("Accepted a script over the size limit")
+	}
+	// Two scripts of 113 bytes are each under the limit but not together
+	outputs := []wallet.TransactionOutput{DataOutput(make([]byte, 110)), DataOutput(make([]byte, 110))}
+	if err := checkDataOutputs(outputs); err == nil {
+		t.Error("Accepted outputs over the size limit together")
+	}
+	output := DataOutput([]byte("memo"))
+	output.Value = 1000
+	if err := checkDataOutputs([]wallet.TransactionOutput{output}); err == nil {
+		t.Error("Accepted an OP_RETURN output with a value")
+	}
+}
+
+func Test_buildTxDataOutput(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	addTestUtxos(t, w, 1000000)
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+
+	plain, err := w.buildTx(SpendOptions{}, payment(addr, 10000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := [][]byte{{0x6d, 0x02}, []byte("hello")}
+	tx, err := w.buildTx(SpendOptions{Data: data}, payment(addr, 10000), wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, out := range tx.TxOut {
+		if bytes.Equal(out.PkScript, dataScript(data)) && out.Value == 0 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("Transaction has no OP_RETURN output")
+	}
+	// The OP_RETURN output is paid for by the change
+	var plainOut, dataOut int64
+	for _, out := range plain.TxOut {
+		plainOut += out.Value
+	}
+	for _, out := range tx.TxOut {
+		dataOut += out.Value
+	}
+	if plainOut-dataOut < int64(len(dataScript(data))+9)*2 {
+		t.Error("Fee doesn't include the OP_RETURN output")
+	}
+
+	// The transaction surfaces the data once it is recorded
+	cbs := make(chan wallet.TransactionCallback, 1)
+	w.txstore.listeners = append(w.txstore.listeners, func(c wallet.TransactionCallback) { cbs <- c })
+	if _, err := w.txstore.Ingest(tx, 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	var cb wallet.TransactionCallback
+	select {
+	case cb = <-cbs:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timed out waiting for the transaction callback")
+	}
+	var cbData [][]byte
+	for _, out := range cb.Outputs {
+		if out.OpReturn {
+			cbData = out.Data
+		}
+	}
+	if len(cbData) != 2 || !bytes.Equal(cbData[1], []byte("hello")) {
+		t.Error("Callback didn't surface the OP_RETURN output")
+	}
+	txns, err := w.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || len(txns[0].DataOutputs) != 1 || !bytes.Equal(txns[0].DataOutputs[0].Data[1], []byte("hello")) {
+		t.Error("Transactions didn't surface the OP_RETURN output")
+	}
+}
@@ -0,0 +1,202 @@
+// Package paymentprotocol is a client for the JSON payment protocol used by
+// Bitcoin Cash merchants. Instead of broadcasting a payment the wallet fetches
+// a PaymentRequest from the r= parameter of a payment URI, posts a Payment with
+// the signed transaction to the merchant and waits for its PaymentACK.
+package paymentprotocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchutil"
+	"golang.org/x/net/proxy"
+)
+
+// The content types of the messages of the protocol.
+const (
+	PaymentRequestType = "application/payment-request"
+	PaymentType        = "application/payment"
+	PaymentACKType     = "application/payment-ack"
+)
+
+// Currency is the currency of payment requests the wallet can pay.
+const Currency = "BCH"
+
+// maxResponseSize limits how much of a merchant's response is read.
+const maxResponseSize = 1 << 20
+
+// ErrExpired is returned when verifying a payment request after it expired.
+var ErrExpired = errors.New("Payment request has expired")
+
+// PaymentRequest is the invoice of a merchant.
+type PaymentRequest struct {
+	Network         string    `json:"network"`
+	Currency        string    `json:"currency"`
+	RequiredFeeRate float64   `json:"requiredFeeRate"`
+	Outputs         []Output  `json:"outputs"`
+	Time            time.Time `json:"time"`
+	Expires         time.Time `json:"expires"`
+	Memo            string    `json:"memo"`
+	PaymentURL      string    `json:"paymentUrl"`
+	PaymentID       string    `json:"paymentId"`
+}
+
+// Output is an output the payment transaction must have. Amount is in
+// satoshi.
+type Output struct {
+	Amount  int64  `json:"amount"`
+	Address string `json:"address"`
+}
+
+// Payment is sent to the merchant with the hex encoded transactions paying
+// the request.
+type Payment struct {
+	Currency     string   `json:"currency"`
+	Transactions []string `json:"transactions"`
+}
+
+// PaymentACK is the merchant's acknowledgement of a payment.
+type PaymentACK struct {
+	Payment Payment `json:"payment"`
+	Memo    string  `json:"memo"`
+}
+
+// Amount returns the sum of the request's outputs.
+func (pr *PaymentRequest) Amount() int64 {
+	var total int64
+	for _, out := range pr.Outputs {
+		total += out.Amount
+	}
+	return total
+}
+
+// Verify checks the request is for params and hasn't expired at now, and that
+// its outputs pay valid amounts to valid addresses.
+func (pr *PaymentRequest) Verify(params *chaincfg.Params, now time.Time) error {
+	if pr.Network != NetworkName(params) {
+		return fmt.Errorf("Payment request is for the %s network", pr.Network)
+	}
+	if pr.Currency != Currency {
+		return fmt.Errorf("Payment request is for %s", pr.Currency)
+	}
+	if !pr.Expires.IsZero() && !now.Before(pr.Expires) {
+		return ErrExpired
+	}
+	if pr.PaymentURL == "" {
+		return errors.New("Payment request has no payment URL")
+	}
+	if len(pr.Outputs) == 0 {
+		return errors.New("Payment request has no outputs")
+	}
+	var total int64
+	for _, out := range pr.Outputs {
+		if out.Amount <= 0 || out.Amount > bchutil.MaxSatoshi {
+			return fmt.Errorf("Payment request has an invalid amount %d", out.Amount)
+		}
+		total += out.Amount
+		if total > bchutil.MaxSatoshi {
+			return errors.New("Payment request amount is too large")
+		}
+		addr, err := bchutil.DecodeAddress(out.Address, params)
+		if err != nil {
+			return fmt.Errorf("Payment request has an invalid address %s: %s", out.Address, err)
+		}
+		if !addr.IsForNet(params) {
+			return fmt.Errorf("Payment request address %s is not for %s", out.Address, params.Name)
+		}
+	}
+	return nil
+}
+
+// NetworkName returns the name of the network of params used by the protocol.
+func NetworkName(params *chaincfg.Params) string {
+	if params.Net == chaincfg.MainNetParams.Net {
+		return "main"
+	}
+	return "test"
+}
+
+// Client fetches payment requests and sends payments to merchants.
+type Client struct {
+	client *http.Client
+}
+
+// NewClient returns a client which connects through dialer if it isn't nil.
+func NewClient(dialer proxy.Dialer) *Client {
+	dial := net.Dial
+	if dialer != nil {
+		dial = dialer.Dial
+	}
+	transport := &http.Transport{Dial: dial}
+	return &Client{client: &http.Client{Transport: transport, Timeout: time.Minute}}
+}
+
+// FetchPaymentRequest gets the payment request at url. It isn't verified.
+func (c *Client) FetchPaymentRequest(url string) (*PaymentRequest, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", PaymentRequestType)
+	pr := new(PaymentRequest)
+	if err := c.do(req, PaymentRequestType, pr); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// SendPayment posts the serialized transactions paying pr to its payment URL
+// and returns the merchant's acknowledgement. The transactions shouldn't be
+// broadcast unless the payment is acknowledged.
+func (c *Client) SendPayment(pr *PaymentRequest, txs ...[]byte) (*PaymentACK, error) {
+	payment := Payment{Currency: pr.Currency}
+	for _, tx := range txs {
+		payment.Transactions = append(payment.Transactions, hex.EncodeToString(tx))
+	}
+	body, err := json.Marshal(payment)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("POST", pr.PaymentURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", PaymentType)
+	req.Header.Set("Accept", PaymentACKType)
+	ack := new(PaymentACK)
+	if err := c.do(req, PaymentACKType, ack); err != nil {
+		return nil, err
+	}
+	return ack, nil
+}
+
+// do sends req and decodes the JSON response of contentType into v. Merchants
+// reply to a rejected request with an error message which is returned.
+func (c *Client) do(req *http.Request, contentType string, v interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body := io.LimitReader(resp.Body, maxResponseSize)
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(body)
+		return fmt.Errorf("Merchant returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != contentType {
+		return fmt.Errorf("Merchant returned %s instead of %s", resp.Header.Get("Content-Type"), contentType)
+	}
+	return json.NewDecoder(body).Decode(v)
+}
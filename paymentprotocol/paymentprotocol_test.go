@@ -0,0 +1,123 @@
+package paymentprotocol
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gcash/bchd/chaincfg"
+)
+
+const testAddress = "qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"
+
+func testRequest() *PaymentRequest {
+	now := time.Now()
+	return &PaymentRequest{
+		Network:    "main",
+		Currency:   "BCH",
+		Outputs:    []Output{{Amount: 10000, Address: testAddress}, {Amount: 5000, Address: "bitcoincash:" + testAddress}},
+		Time:       now,
+		Expires:    now.Add(time.Minute * 15),
+		PaymentURL: "https://merchant.example/i/1",
+	}
+}
+
+func TestPaymentRequest_Verify(t *testing.T) {
+	pr := testRequest()
+	if err := pr.Verify(&chaincfg.MainNetParams, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if pr.Amount() != 15000 {
+		t.Errorf("Returned amount %d, expected 15000", pr.Amount())
+	}
+	if err := pr.Verify(&chaincfg.MainNetParams, pr.Expires); err != ErrExpired {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+	if err := pr.Verify(&chaincfg.TestNet3Params, time.Now()); err == nil {
+		t.Error("Verified a request for another network")
+	}
+
+	tests := []func(pr *PaymentRequest){
+		func(pr *PaymentRequest) { pr.Currency = "BTC" },
+		func(pr *PaymentRequest) { pr.PaymentURL = "" },
+		func(pr *PaymentRequest) { pr.Outputs = nil },
+		func(pr *PaymentRequest) { pr.Outputs[0].Amount = 0 },
+		func(pr *PaymentRequest) { pr.Outputs[0].Address = "notanaddress" },
+		func(pr *PaymentRequest) { pr.Outputs[1].Amount = 21000000 * 1e8 },
+	}
+	for i, modify := range tests {
+		pr := testRequest()
+		modify(pr)
+		if err := pr.Verify(&chaincfg.MainNetParams, time.Now()); err == nil {
+			t.Errorf("Test %d: verified an invalid request", i)
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	pr := testRequest()
+	var payment Payment
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			if r.Header.Get("Accept") != PaymentRequestType {
+				http.Error(w, "Unsupported Accept header", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", PaymentRequestType)
+			json.NewEncoder(w).Encode(pr)
+		case "POST":
+			if r.Header.Get("Content-Type") != PaymentType {
+				http.Error(w, "Unsupported Content-Type", http.StatusBadRequest)
+				return
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if len(payment.Transactions) != 1 || payment.Transactions[0] != "0102" {
+				http.Error(w, "Invalid transaction", http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", PaymentACKType+"; charset=utf-8")
+			json.NewEncoder(w).Encode(PaymentACK{Payment: payment, Memo: "Thanks"})
+		}
+	}))
+	defer merchant.Close()
+	pr.PaymentURL = merchant.URL
+
+	c := NewClient(nil)
+	fetched, err := c.FetchPaymentRequest(merchant.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched.PaymentURL != merchant.URL || fetched.Amount() != 15000 || !fetched.Expires.Equal(pr.Expires) {
+		t.Errorf("Fetched the wrong payment request %v", fetched)
+	}
+	ack, err := c.SendPayment(fetched, []byte{0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ack.Memo != "Thanks" || payment.Currency != "BCH" {
+		t.Error("Returned the wrong acknowledgement")
+	}
+
+	// Rejected payments return the merchant's message
+	_, err = c.SendPayment(fetched, []byte{0x03})
+	if err == nil || err.Error() != "Merchant returned 400 Bad Request: Invalid transaction" {
+		t.Errorf("Returned the wrong error %v", err)
+	}
+}
+
+func TestClient_WrongContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+	if _, err := NewClient(nil).FetchPaymentRequest(server.URL); err == nil {
+		t.Error("Accepted a response which isn't a payment request")
+	}
+}
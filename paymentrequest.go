@@ -0,0 +1,78 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/paymentprotocol"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+// FetchPaymentRequest gets the payment protocol request at url, the r=
+// parameter of a payment URI, and verifies it can be paid.
+func (w *SPVWallet) FetchPaymentRequest(url string) (*paymentprotocol.PaymentRequest, error) {
+	pr, err := w.paymentClient.FetchPaymentRequest(url)
+	if err != nil {
+		return nil, err
+	}
+	if err := pr.Verify(w.params, time.Now()); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// PayPaymentRequest pays a request from FetchPaymentRequest. The transaction
+// is sent to the merchant and only broadcast once the merchant acknowledges
+// it, so nothing is spent if the payment is rejected. The merchant's memo is
+// returned with the txid.
+func (w *SPVWallet) PayPaymentRequest(pr *paymentprotocol.PaymentRequest, feeLevel wallet.FeeLevel) (*chainhash.Hash, string, error) {
+	return w.PayPaymentRequestWithOptions(pr, feeLevel, SpendOptions{})
+}
+
+// PayPaymentRequestWithOptions is like PayPaymentRequest but lets the caller
+// choose the account, coins and fee of the payment. The fee must still meet the
+// request's required fee rate.
+func (w *SPVWallet) PayPaymentRequestWithOptions(pr *paymentprotocol.PaymentRequest, feeLevel wallet.FeeLevel, opts SpendOptions) (*chainhash.Hash, string, error) {
+	if err := pr.Verify(w.params, time.Now()); err != nil {
+		return nil, "", err
+	}
+	feePerByte := w.GetFeePerByte(feeLevel)
+	if opts.FeePerByte > 0 {
+		feePerByte = opts.FeePerByte
+	}
+	if opts.Fee == 0 && float64(feePerByte) < pr.RequiredFeeRate {
+		return nil, "", fmt.Errorf("Payment request requires a fee of %v sat/byte, the fee level pays %d", pr.RequiredFeeRate, feePerByte)
+	}
+	var outputs []wallet.TransactionOutput
+	for _, out := range pr.Outputs {
+		addr, err := w.DecodeAddress(out.Address)
+		if err != nil {
+			return nil, "", err
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: out.Amount})
+	}
+	tx, err := w.buildTx(opts, outputs, feeLevel)
+	if err != nil {
+		return nil, "", err
+	}
+	if opts.Fee > 0 && float64(opts.Fee) < pr.RequiredFeeRate*float64(tx.SerializeSize()) {
+		return nil, "", fmt.Errorf("Payment request requires a fee of %v sat/byte, a fee of %d is too low", pr.RequiredFeeRate, opts.Fee)
+	}
+	var buf bytes.Buffer
+	if err := tx.BchEncode(&buf, wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		return nil, "", err
+	}
+	ack, err := w.paymentClient.SendPayment(pr, buf.Bytes())
+	if err != nil {
+		return nil, "", err
+	}
+	if err := w.Broadcast(tx); err != nil {
+		return nil, "", err
+	}
+	txid := tx.TxHash()
+	w.saveMemo(txid, opts.Memo)
+	return &txid, ack.Memo, nil
+}
@@ -0,0 +1,205 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/paymentprotocol"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil"
+)
+
+// testMerchant is a payment protocol server for a single request. It accepts a
+// payment if the transaction pays the request's outputs and accept is set.
+type testMerchant struct {
+	*httptest.Server
+	pr       paymentprotocol.PaymentRequest
+	accept   bool
+	payments int
+}
+
+func newTestMerchant(outputs ...paymentprotocol.Output) *testMerchant {
+	m := &testMerchant{accept: true}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Header().Set("Content-Type", paymentprotocol.PaymentRequestType)
+			json.NewEncoder(w).Encode(m.pr)
+			return
+		}
+		var payment paymentprotocol.Payment
+		if err := json.NewDecoder(r.Body).Decode(&payment); err != nil || len(payment.Transactions) != 1 {
+			http.Error(w, "Invalid payment", http.StatusBadRequest)
+			return
+		}
+		raw, err := hex.DecodeString(payment.Transactions[0])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tx := wire.NewMsgTx(1)
+		if err := tx.BchDecode(bytes.NewReader(raw), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !m.accept || !paysOutputs(tx, m.pr.Outputs) {
+			http.Error(w, "Payment rejected", http.StatusBadRequest)
+			return
+		}
+		m.payments++
+		w.Header().Set("Content-Type", paymentprotocol.PaymentACKType)
+		json.NewEncoder(w).Encode(paymentprotocol.PaymentACK{Payment: payment, Memo: "Thanks"})
+	}))
+	m.pr = paymentprotocol.PaymentRequest{
+		Network:    "test",
+		Currency:   "BCH",
+		Outputs:    outputs,
+		Time:       time.Now(),
+		Expires:    time.Now().Add(time.Minute * 15),
+		PaymentURL: m.URL,
+	}
+	return m
+}
+
+func paysOutputs(tx *wire.MsgTx, outputs []paymentprotocol.Output) bool {
+	for _, out := range outputs {
+		addr, err := bchutil.DecodeAddress(out.Address, &chaincfg.TestNet3Params)
+		if err != nil {
+			return false
+		}
+		script, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return false
+		}
+		found := false
+		for _, txout := range tx.TxOut {
+			if bytes.Equal(txout.PkScript, script) && txout.Value == out.Amount {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSPVWallet_PayPaymentRequest(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	addTestUtxos(t, w, 1000000)
+	m := newTestMerchant(
+		paymentprotocol.Output{Amount: 20000, Address: w.NewAddress(wallet.EXTERNAL).String()},
+		paymentprotocol.Output{Amount: 30000, Address: w.NewAddress(wallet.EXTERNAL).String()},
+	)
+	defer m.Close()
+
+	// A rejected payment isn't broadcast
+	m.accept = false
+	pr, err := w.FetchPaymentRequest(m.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := w.PayPaymentRequest(pr, wallet.NORMAL); err == nil {
+		t.Fatal("Paid a rejected payment")
+	}
+	if txns, _ := w.Transactions(); len(txns) != 0 {
+		t.Error("Recorded a rejected payment")
+	}
+
+	m.accept = true
+	txid, memo, err := w.PayPaymentRequest(pr, wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memo != "Thanks" || m.payments != 1 {
+		t.Error("Payment was not acknowledged")
+	}
+	if _, err := w.GetTransaction(*txid); err != nil {
+		t.Error("Acknowledged payment was not broadcast")
+	}
+}
+
+func TestSPVWallet_PayPaymentRequestWithOptions(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	addTestUtxos(t, w, 1000000)
+	m := newTestMerchant(paymentprotocol.Output{Amount: 20000, Address: w.NewAddress(wallet.EXTERNAL).String()})
+	defer m.Close()
+	m.pr.RequiredFeeRate = 3
+	pr, err := w.FetchPaymentRequest(m.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The fee must meet the required fee rate, however it is given
+	if _, _, err := w.PayPaymentRequestWithOptions(pr, wallet.NORMAL, SpendOptions{FeePerByte: 2}); err == nil {
+		t.Error("Paid with a fee rate below the required fee rate")
+	}
+	if _, _, err := w.PayPaymentRequestWithOptions(pr, wallet.NORMAL, SpendOptions{Fee: 300}); err == nil {
+		t.Error("Paid with a fee below the required fee rate")
+	}
+	if _, _, err := w.PayPaymentRequestWithOptions(pr, wallet.NORMAL, SpendOptions{Account: 1, FeePerByte: 4}); err != ErrUnknownAccount {
+		t.Errorf("Expected ErrUnknownAccount, got %v", err)
+	}
+	if m.payments != 0 {
+		t.Error("Paid an invalid payment")
+	}
+
+	if _, _, err := w.PayPaymentRequestWithOptions(pr, wallet.NORMAL, SpendOptions{FeePerByte: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if m.payments != 1 {
+		t.Error("Payment was not sent to the merchant")
+	}
+}
+
+func TestSPVWallet_PayURIPaymentRequest(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	addTestUtxos(t, w, 1000000)
+	m := newTestMerchant(paymentprotocol.Output{Amount: 20000, Address: w.NewAddress(wallet.EXTERNAL).String()})
+	defer m.Close()
+
+	uri := "bchtest:?r=" + url.QueryEscape(m.URL)
+	if _, err := w.PayURI(uri, wallet.NORMAL); err != nil {
+		t.Fatal(err)
+	}
+	if m.payments != 1 {
+		t.Error("URI wasn't paid with the payment protocol")
+	}
+
+	// Expired requests and those needing a higher fee aren't paid
+	m.pr.Expires = time.Now().Add(-time.Minute)
+	if _, err := w.PayURI(uri, wallet.NORMAL); err != paymentprotocol.ErrExpired {
+		t.Errorf("Expected ErrExpired, got %v", err)
+	}
+	m.pr.Expires = time.Now().Add(time.Minute)
+	m.pr.RequiredFeeRate = 3
+	if _, err := w.PayURI(uri, wallet.NORMAL); err == nil {
+		t.Error("Paid with a fee below the required fee rate")
+	}
+	if m.payments != 1 {
+		t.Error("Paid an invalid request")
+	}
+
+	// A fee rate chosen for the spend is paid instead of the fee level's
+	if _, err := w.PayURIWithOptions(uri, wallet.NORMAL, SpendOptions{FeePerByte: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if m.payments != 2 {
+		t.Error("URI wasn't paid with the chosen fee rate")
+	}
+}
@@ -0,0 +1,76 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/BubbaJoe/spvwallet-cash/bip21"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/txscript"
+)
+
+// PayURI pays a bitcoincash: payment URI. If it has an r= parameter the
+// payment protocol request is paid instead, see PayPaymentRequest. Otherwise
+// the URI must request an amount and if it has an op_return_raw parameter the
+// data output is added to the spend.
+func (w *SPVWallet) PayURI(uri string, feeLevel wallet.FeeLevel) (*chainhash.Hash, error) {
+	return w.PayURIWithOptions(uri, feeLevel, SpendOptions{})
+}
+
+// PayURIWithOptions is like PayURI but spends with opts. The op_return_raw
+// data of the URI replaces opts.Data if it has any.
+func (w *SPVWallet) PayURIWithOptions(uri string, feeLevel wallet.FeeLevel, opts SpendOptions) (*chainhash.Hash, error) {
+	u, err := bip21.Parse(uri, w.params)
+	if err != nil {
+		return nil, err
+	}
+	if u.PaymentURL != "" {
+		pr, err := w.FetchPaymentRequest(u.PaymentURL)
+		if err != nil {
+			return nil, err
+		}
+		txid, memo, err := w.PayPaymentRequestWithOptions(pr, feeLevel, opts)
+		if err != nil {
+			return nil, err
+		}
+		log.Noticef("Payment %s acknowledged: %s", txid, memo)
+		return txid, nil
+	}
+	if u.Amount <= 0 {
+		return nil, errors.New("Payment URI has no amount")
+	}
+	if u.OpReturnRaw != nil {
+		opts.Data, err = uriData(u.OpReturnRaw)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return w.SpendWithOptions(u.Amount, u.Address, feeLevel, opts)
+}
+
+// CurrentAddressURI returns a payment URI for the current receiving address.
+// An amount of zero leaves it to the payer.
+func (w *SPVWallet) CurrentAddressURI(amount int64, label, message string) string {
+	u := bip21.URI{
+		Address: w.CurrentAddress(wallet.EXTERNAL),
+		Amount:  amount,
+		Label:   label,
+		Message: message,
+	}
+	return u.String()
+}
+
+// uriData splits the op_return_raw script of a payment URI into its data
+// pushes. Scripts with other opcodes are rejected as the data output couldn't
+// reproduce them.
+func uriData(raw []byte) ([][]byte, error) {
+	data, err := txscript.PushedData(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(dataScript(data)[1:], raw) {
+		return nil, errors.New("op_return_raw must only contain minimal data pushes")
+	}
+	return data, nil
+}
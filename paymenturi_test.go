@@ -0,0 +1,62 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/BubbaJoe/spvwallet-cash/bip21"
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+)
+
+func TestSPVWallet_CurrentAddressURI(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+
+	s := w.CurrentAddressURI(2500000, "Invoice 7", "")
+	u, err := bip21.Parse(s, w.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Address.String() != w.CurrentAddress(wallet.EXTERNAL).String() {
+		t.Error("URI doesn't pay the current address")
+	}
+	if u.Amount != 2500000 || u.Label != "Invoice 7" || u.Message != "" {
+		t.Errorf("Returned the wrong URI %s", s)
+	}
+}
+
+func TestSPVWallet_PayURIErrors(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+
+	tests := []string{
+		w.CurrentAddressURI(0, "", ""),
+		w.CurrentAddressURI(10000, "", "") + "&req-unknown=1",
+		w.CurrentAddressURI(10000, "", "") + "&op_return_raw=76",
+		addr.String(),
+	}
+	for _, uri := range tests {
+		if _, err := w.PayURI(uri, wallet.NORMAL); err == nil {
+			t.Errorf("Paid invalid URI %s", uri)
+		}
+	}
+}
+
+func Test_uriData(t *testing.T) {
+	data, err := uriData([]byte{0x02, 0x6d, 0x02, 0x05, 'h', 'e', 'l', 'l', 'o'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 2 || !bytes.Equal(data[0], []byte{0x6d, 0x02}) || !bytes.Equal(data[1], []byte("hello")) {
+		t.Errorf("Returned the wrong pushes %x", data)
+	}
+	// OP_DUP and a small integer can't be made by a data push
+	if _, err := uriData([]byte{0x76}); err == nil {
+		t.Error("Accepted a script with a non push opcode")
+	}
+	if _, err := uriData([]byte{0x51}); err == nil {
+		t.Error("Accepted a small integer push")
+	}
+}
@@ -31,6 +31,9 @@ var (
 
 const MaxGetAddressAttempts = 10
 
+// How long we refuse to reconnect to a peer which was banned for misbehaving
+const banDuration = time.Hour * 24
+
 var SFNodeBitcoinCash wire.ServiceFlag = 1 << 5
 
 type PeerManagerConfig struct {
@@ -70,6 +73,10 @@ type PeerManagerConfig struct {
 
 	// The main channel over which to send outgoing events
 	MsgChan chan interface{}
+
+	// The services a peer must offer or it will be disconnected. Defaults to
+	// bloom filtering on a full Bitcoin Cash node.
+	RequiredServices wire.ServiceFlag
 }
 
 type PeerManager struct {
@@ -84,6 +91,9 @@ type PeerManager struct {
 	recentlyTriedAddresses map[string]bool
 	connectedPeers         map[uint64]*peer.Peer
 	msgChan                chan interface{}
+	requiredServices       wire.ServiceFlag
+	bannedAddresses        map[string]time.Time
+	banMutex               *sync.Mutex
 }
 
 func NewPeerManager(config *PeerManagerConfig) (*PeerManager, error) {
@@ -102,6 +112,12 @@ func NewPeerManager(config *PeerManagerConfig) (*PeerManager, error) {
 		recentlyTriedAddresses: make(map[string]bool),
 		connectedPeers:         make(map[uint64]*peer.Peer),
 		msgChan:                config.MsgChan,
+		requiredServices:       config.RequiredServices,
+		bannedAddresses:        make(map[string]time.Time),
+		banMutex:               new(sync.Mutex),
+	}
+	if pm.requiredServices == 0 {
+		pm.requiredServices = wire.SFNodeBloom | wire.SFNodeNetwork | wire.SFNodeBitcoinCash
 	}
 
 	targetOutbound := config.TargetOutbound
@@ -152,6 +168,9 @@ func NewPeerManager(config *PeerManagerConfig) (*PeerManager, error) {
 	listeners.OnInv = pm.onInv
 	listeners.OnTx = pm.onTx
 	listeners.OnReject = pm.onReject
+	listeners.OnCFHeaders = pm.onCFHeaders
+	listeners.OnCFilter = pm.onCFilter
+	listeners.OnBlock = pm.onBlock
 
 	pm.peerConfig = &peer.Config{
 		UserAgentName:    config.UserAgentName,
@@ -204,12 +223,12 @@ func (pm *PeerManager) onConnection(req *connmgr.ConnReq, conn net.Conn) {
 }
 
 func (pm *PeerManager) onVerack(p *peer.Peer, msg *wire.MsgVerAck) {
-	// Check this peer offers bloom filtering services. If not dump them.
+	// Check this peer offers the services we need (bloom filtering by default). If not dump them.
 	p.NA().Services = p.Services()
-	if !(p.NA().HasService(wire.SFNodeBloom) && p.NA().HasService(wire.SFNodeNetwork) && p.NA().HasService(wire.SFNodeBitcoinCash)) {
+	if p.Services()&pm.requiredServices != pm.requiredServices {
 		// onDisconnection will be called
 		// which will remove the peer from openPeers
-		log.Warningf("Peer %s does not support bloom filtering, diconnecting", p)
+		log.Warningf("Peer %s does not offer the required services %s, diconnecting", p, pm.requiredServices)
 		p.Disconnect()
 		return
 	}
@@ -236,6 +255,31 @@ func (pm *PeerManager) onDisconnection(req *connmgr.ConnReq) {
 	}
 }
 
+// BanPeer disconnects the peer and refuses to connect to its address again
+// until the ban expires. This uses its own lock as it is called from the
+// WireService, which onDisconnection blocks on while holding the peerMutex.
+func (pm *PeerManager) BanPeer(p *peer.Peer) {
+	pm.banMutex.Lock()
+	pm.bannedAddresses[p.NA().IP.String()] = time.Now().Add(banDuration)
+	pm.banMutex.Unlock()
+	log.Warningf("Banning peer %s for %s", p, banDuration)
+	p.Disconnect()
+}
+
+func (pm *PeerManager) isBanned(ip string) bool {
+	pm.banMutex.Lock()
+	defer pm.banMutex.Unlock()
+	expiry, ok := pm.bannedAddresses[ip]
+	if !ok {
+		return false
+	}
+	if time.Now().After(expiry) {
+		delete(pm.bannedAddresses, ip)
+		return false
+	}
+	return true
+}
+
 // Called by connManager when it adds a new connection
 func (pm *PeerManager) getNewAddress() (net.Addr, error) {
 	// If we have a trusted peer we'll just return it
@@ -264,6 +308,11 @@ func (pm *PeerManager) getNewAddress() (net.Addr, error) {
 
 			knownAddress := ka.NetAddress()
 
+			// Don't return addresses which are banned
+			if pm.isBanned(knownAddress.IP.String()) {
+				continue
+			}
+
 			// Don't return addresses we're still connected to
 			for _, p := range pm.connectedPeers {
 				if p.NA().IP.String() == knownAddress.IP.String() {
@@ -367,6 +416,24 @@ func (pm *PeerManager) onTx(p *peer.Peer, msg *wire.MsgTx) {
 	}
 }
 
+func (pm *PeerManager) onCFHeaders(p *peer.Peer, msg *wire.MsgCFHeaders) {
+	if pm.msgChan != nil {
+		pm.msgChan <- cfheadersMsg{msg, p}
+	}
+}
+
+func (pm *PeerManager) onCFilter(p *peer.Peer, msg *wire.MsgCFilter) {
+	if pm.msgChan != nil {
+		pm.msgChan <- cfilterMsg{msg, p}
+	}
+}
+
+func (pm *PeerManager) onBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
+	if pm.msgChan != nil {
+		pm.msgChan <- blockMsg{msg, p}
+	}
+}
+
 func (pm *PeerManager) onReject(p *peer.Peer, msg *wire.MsgReject) {
 	log.Warningf("Received reject message from peer %d: Code: %s, Hash %s, Reason: %s", int(p.ID()), msg.Code.String(), msg.Hash.String(), msg.Reason)
 }
@@ -0,0 +1,323 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/bchec"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+	bch "github.com/gcash/bchutil"
+	hd "github.com/gcash/bchutil/hdkeychain"
+)
+
+// PartiallySignedTransaction is a transaction along with everything an offline
+// signer needs to sign its inputs. It is created by a watch-only or locked
+// wallet, signed on an air-gapped wallet holding the keys and broadcast by the
+// online wallet again. It serializes to JSON so it can be moved as a file.
+type PartiallySignedTransaction struct {
+	// Name of the network the transaction is for
+	Network string
+
+	// The transaction. Signed inputs have a signature script.
+	Tx *wire.MsgTx
+
+	// Information about the previous output of each input of Tx, in the same order
+	Inputs []PartiallySignedInput
+
+	// The signature type the fee was estimated for. DefaultSignatureType signs
+	// with the signature type of the signing wallet.
+	SignatureType SignatureType
+}
+
+// PartiallySignedInput describes the output spent by an input.
+type PartiallySignedInput struct {
+	Value    int64  `json:"value"`
+	PkScript []byte `json:"pkScript"`
+
+	// The BIP32 path of the key from the master key, for example
+	// m/44'/145'/0'/0/1. Empty for imported keys.
+	DerivationPath string `json:"derivationPath,omitempty"`
+
+	// Set if the previous output is P2SH
+	RedeemScript []byte `json:"redeemScript,omitempty"`
+}
+
+type partiallySignedTransactionJSON struct {
+	Network       string                 `json:"network"`
+	Tx            string                 `json:"tx"`
+	Inputs        []PartiallySignedInput `json:"inputs"`
+	SignatureType string                 `json:"signatureType,omitempty"`
+}
+
+func (pst *PartiallySignedTransaction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pst.Tx.BchEncode(&buf, wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		return nil, err
+	}
+	var sigType string
+	switch pst.SignatureType {
+	case DefaultSignatureType:
+		// Left to the signing wallet's config
+	case ECDSA:
+		sigType = "ecdsa"
+	case Schnorr:
+		sigType = "schnorr"
+	default:
+		return nil, fmt.Errorf("Unknown signature type %d", pst.SignatureType)
+	}
+	return json.Marshal(partiallySignedTransactionJSON{
+		Network:       pst.Network,
+		Tx:            hex.EncodeToString(buf.Bytes()),
+		Inputs:        pst.Inputs,
+		SignatureType: sigType,
+	})
+}
+
+func (pst *PartiallySignedTransaction) UnmarshalJSON(data []byte) error {
+	var j partiallySignedTransactionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	txBytes, err := hex.DecodeString(j.Tx)
+	if err != nil {
+		return err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.BchDecode(bytes.NewReader(txBytes), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		return err
+	}
+	if len(j.Inputs) != len(tx.TxIn) {
+		return errors.New("Partially signed transaction must describe every input")
+	}
+	switch j.SignatureType {
+	case "":
+		pst.SignatureType = DefaultSignatureType
+	case "ecdsa":
+		pst.SignatureType = ECDSA
+	case "schnorr":
+		pst.SignatureType = Schnorr
+	default:
+		return fmt.Errorf("Unknown signature type %s", j.SignatureType)
+	}
+	pst.Network = j.Network
+	pst.Tx = tx
+	pst.Inputs = j.Inputs
+	return nil
+}
+
+// Complete returns whether every input has been signed.
+func (pst *PartiallySignedTransaction) Complete() bool {
+	for _, in := range pst.Tx.TxIn {
+		if len(in.SignatureScript) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateUnsignedTransaction selects coins paying amount to addr and returns the
+// transaction with the information needed to sign it offline. It works on
+// locked and watch-only wallets.
+func (w *SPVWallet) CreateUnsignedTransaction(amount int64, addr bch.Address, feeLevel wallet.FeeLevel) (*PartiallySignedTransaction, error) {
+	return w.CreateUnsignedTransactionWithOptions(amount, addr, feeLevel, SpendOptions{})
+}
+
+// CreateUnsignedTransactionWithOptions is CreateUnsignedTransaction with coin
+// control. The inputs may spend keys of any account.
+func (w *SPVWallet) CreateUnsignedTransactionWithOptions(amount int64, addr bch.Address, feeLevel wallet.FeeLevel, opts SpendOptions) (*PartiallySignedTransaction, error) {
+	utx, err := w.buildUnsignedTx(opts, payment(addr, amount), feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	pst := &PartiallySignedTransaction{
+		Network:       w.params.Name,
+		Tx:            utx.tx,
+		SignatureType: w.signatureType(opts.SignatureType),
+	}
+	for _, in := range utx.tx.TxIn {
+		prevScript := utx.prevScripts[in.PreviousOutPoint]
+		input := PartiallySignedInput{
+			Value:    utx.inVals[in.PreviousOutPoint],
+			PkScript: prevScript,
+		}
+		addr, err := w.ScriptToAddress(prevScript)
+		if err != nil {
+			return nil, err
+		}
+		// Imported keys have no path and must be imported in the signing wallet too
+		for _, km := range w.txstore.keyManagers() {
+			if keyPath, err := km.datastore.GetPathForKey(addr.ScriptAddress()); err == nil {
+				account := km.account
+				if w.watchOnly {
+					account = w.watchOnlyAccount
+				}
+				input.DerivationPath = bip44Path(account, keyPath)
+				break
+			}
+		}
+		pst.Inputs = append(pst.Inputs, input)
+	}
+	return pst, nil
+}
+
+// SignTransaction signs the inputs of pst the wallet has keys for. Inputs which
+// are already signed are left alone. The wallet doesn't need to be synced so
+// this can run on an offline machine.
+func (w *SPVWallet) SignTransaction(pst *PartiallySignedTransaction) error {
+	if pst.Network != w.params.Name {
+		return fmt.Errorf("Transaction is for %s but the wallet is on %s", pst.Network, w.params.Name)
+	}
+	if len(pst.Inputs) != len(pst.Tx.TxIn) {
+		return errors.New("Partially signed transaction must describe every input")
+	}
+	masterPrivKey, err := w.MasterPrivateKey()
+	if err != nil {
+		return err
+	}
+	sigType := w.signatureType(pst.SignatureType)
+	for i, txIn := range pst.Tx.TxIn {
+		if len(txIn.SignatureScript) > 0 {
+			continue
+		}
+		input := pst.Inputs[i]
+		key, err := w.signingKey(masterPrivKey, input)
+		if err != nil {
+			return fmt.Errorf("Input %d: %s", i, err)
+		}
+		script, err := w.signInput(pst.Tx, i, input, key, sigType)
+		if err != nil {
+			return fmt.Errorf("Input %d: %s", i, err)
+		}
+		txIn.SignatureScript = script
+	}
+	return nil
+}
+
+// FinalizeAndBroadcast checks every input of pst is signed with a valid
+// signature and broadcasts the transaction.
+func (w *SPVWallet) FinalizeAndBroadcast(pst *PartiallySignedTransaction) (*chainhash.Hash, error) {
+	if pst.Network != w.params.Name {
+		return nil, fmt.Errorf("Transaction is for %s but the wallet is on %s", pst.Network, w.params.Name)
+	}
+	if !pst.Complete() {
+		return nil, errors.New("Transaction is not fully signed")
+	}
+	if err := verifyInputs(pst); err != nil {
+		return nil, err
+	}
+	if err := w.Broadcast(pst.Tx); err != nil {
+		return nil, err
+	}
+	txid := pst.Tx.TxHash()
+	return &txid, nil
+}
+
+// signingKey returns the private key for an input, derived from the master
+// key if the input has a derivation path or looked up among the imported keys
+// otherwise. The key is checked against the previous output script.
+func (w *SPVWallet) signingKey(masterPrivKey *hd.ExtendedKey, input PartiallySignedInput) (*bchec.PrivateKey, error) {
+	script := input.PkScript
+	if input.RedeemScript != nil {
+		script = input.RedeemScript
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, w.params)
+	if err != nil {
+		return nil, err
+	}
+	if input.DerivationPath == "" {
+		for _, addr := range addrs {
+			if key, err := w.GetKey(addr); err == nil {
+				return key, nil
+			}
+		}
+		return nil, errors.New("Key not found")
+	}
+	path, err := parseDerivationPath(input.DerivationPath)
+	if err != nil {
+		return nil, err
+	}
+	hdKey := masterPrivKey
+	for _, i := range path {
+		hdKey, err = hdKey.Child(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	key, err := hdKey.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	pkHash := bch.Hash160(key.PubKey().SerializeCompressed())
+	for _, addr := range addrs {
+		if bytes.Equal(addr.ScriptAddress(), pkHash) {
+			return key, nil
+		}
+	}
+	return nil, errors.New("Derivation path does not match the previous output")
+}
+
+func (w *SPVWallet) signInput(tx *wire.MsgTx, idx int, input PartiallySignedInput, key *bchec.PrivateKey, sigType SignatureType) ([]byte, error) {
+	if input.RedeemScript == nil {
+		return p2pkhSignatureScript(tx, idx, input.Value, input.PkScript, txscript.SigHashAll, key, true, sigType)
+	} else if sigType == Schnorr {
+		return nil, errors.New("Schnorr signing of P2SH inputs is not supported")
+	}
+	getKey := txscript.KeyClosure(func(addr bch.Address) (*bchec.PrivateKey, bool, error) {
+		return key, true, nil
+	})
+	getScript := txscript.ScriptClosure(func(addr bch.Address) ([]byte, error) {
+		return input.RedeemScript, nil
+	})
+	return txscript.SignTxOutput(w.params, tx, idx, input.Value, input.PkScript,
+		txscript.SigHashAll, getKey, getScript, nil)
+}
+
+// verifyInputs runs the script of every input against its previous output.
+func verifyInputs(pst *PartiallySignedTransaction) error {
+	for i, input := range pst.Inputs {
+		vm, err := txscript.NewEngine(input.PkScript, pst.Tx, i, txscript.StandardVerifyFlags, nil, nil, input.Value)
+		if err != nil {
+			return err
+		}
+		if err := vm.Execute(); err != nil {
+			return fmt.Errorf("Input %d: %s", i, err)
+		}
+	}
+	return nil
+}
+
+// bip44Path returns the derivation path of a key of account from the master key.
+func bip44Path(account uint32, keyPath wallet.KeyPath) string {
+	return fmt.Sprintf("m/44'/145'/%d'/%d/%d", account, keyPath.Purpose, keyPath.Index)
+}
+
+// parseDerivationPath parses a BIP32 path such as m/44'/145'/0'/0/1 into child
+// indexes.
+func parseDerivationPath(path string) ([]uint32, error) {
+	elems := strings.Split(path, "/")
+	if len(elems) == 0 || elems[0] != "m" {
+		return nil, fmt.Errorf("Invalid derivation path %s", path)
+	}
+	var indexes []uint32
+	for _, elem := range elems[1:] {
+		var offset uint32
+		if strings.HasSuffix(elem, "'") {
+			offset = hd.HardenedKeyStart
+			elem = strings.TrimSuffix(elem, "'")
+		}
+		i, err := strconv.ParseUint(elem, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid derivation path %s", path)
+		}
+		indexes = append(indexes, uint32(i)+offset)
+	}
+	return indexes, nil
+}
@@ -0,0 +1,254 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+	hd "github.com/gcash/bchutil/hdkeychain"
+)
+
+func createTestPST(t *testing.T, w *SPVWallet) *PartiallySignedTransaction {
+	h1, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, err := w.keyManager.GetFreshKey(wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1, err := key1.Address(&chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script1, err := w.AddressToScript(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wire.NewOutPoint(h1, 0)
+	err = w.txstore.Utxos().Put(wallet.Utxo{Op: *op, ScriptPubkey: script1, AtHeight: 5, Value: 1000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pst, err := w.CreateUnsignedTransaction(10000, addr1, wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pst
+}
+
+func TestSPVWallet_SignTransaction(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	pst := createTestPST(t, w)
+
+	if len(pst.Inputs) != 1 {
+		t.Fatalf("Expected 1 input, got %d", len(pst.Inputs))
+	}
+	addr, err := w.ScriptToAddress(pst.Inputs[0].PkScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath, err := w.keyManager.datastore.GetPathForKey(addr.ScriptAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("m/44'/145'/0'/0/%d", keyPath.Index)
+	if keyPath.Purpose != wallet.EXTERNAL || pst.Inputs[0].DerivationPath != expected {
+		t.Errorf("Returned the wrong derivation path %s", pst.Inputs[0].DerivationPath)
+	}
+	if pst.Complete() {
+		t.Error("Unsigned transaction is complete")
+	}
+	if err := verifyInputs(pst); err == nil {
+		t.Error("Verified an unsigned transaction")
+	}
+
+	// Move the transaction to the signer as a file would
+	data, err := json.Marshal(pst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := new(PartiallySignedTransaction)
+	if err := json.Unmarshal(data, signed); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.SignTransaction(signed); err != nil {
+		t.Fatal(err)
+	}
+	if !signed.Complete() {
+		t.Fatal("Signed transaction is not complete")
+	}
+	if err := verifyInputs(signed); err != nil {
+		t.Error(err)
+	}
+	if signed.Tx.TxHash() == pst.Tx.TxHash() {
+		t.Error("Signing didn't change the transaction")
+	}
+}
+
+func TestSPVWallet_CreateUnsignedTransactionAccount(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+
+	account, err := w.CreateAccount("savings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := w.AccountCurrentAddress(account.Number, wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := w.AddressToScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo := wallet.Utxo{Op: *wire.NewOutPoint(h, 0), ScriptPubkey: script, AtHeight: 5, Value: 1000000}
+	if err := w.txstore.accountDatastore(account.Number).Utxos().Put(utxo); err != nil {
+		t.Fatal(err)
+	}
+
+	pst, err := w.CreateUnsignedTransactionWithOptions(10000, addr, wallet.NORMAL, SpendOptions{Account: account.Number})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pst.Inputs) != 1 {
+		t.Fatalf("Expected 1 input, got %d", len(pst.Inputs))
+	}
+	km, _ := w.txstore.accountKeyManager(account.Number)
+	keyPath, err := km.datastore.GetPathForKey(addr.ScriptAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := fmt.Sprintf("m/44'/145'/%d'/0/%d", account.Number, keyPath.Index)
+	if pst.Inputs[0].DerivationPath != expected {
+		t.Errorf("Returned the wrong derivation path %s", pst.Inputs[0].DerivationPath)
+	}
+	if err := w.SignTransaction(pst); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyInputs(pst); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSPVWallet_SignTransactionErrors(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	pst := createTestPST(t, w)
+
+	// A wallet with a different seed doesn't have the key
+	seed := make([]byte, 32)
+	rand.Read(seed)
+	otherKey, err := hd.NewMaster(seed, w.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := *w
+	other.masterPrivateKey = otherKey
+	if err := other.SignTransaction(pst); err == nil {
+		t.Error("Signed with the wrong master key")
+	}
+
+	pst.Network = chaincfg.MainNetParams.Name
+	if err := w.SignTransaction(pst); err == nil {
+		t.Error("Signed a transaction for another network")
+	}
+	pst.Network = w.params.Name
+
+	w.masterPrivateKey = nil
+	if err := w.SignTransaction(pst); err != ErrWalletLocked {
+		t.Errorf("Expected ErrWalletLocked, got %v", err)
+	}
+	if _, err := w.FinalizeAndBroadcast(pst); err == nil {
+		t.Error("Broadcast an unsigned transaction")
+	}
+}
+
+func TestPartiallySignedTransaction_JSON(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	pst := createTestPST(t, w)
+	pst.SignatureType = Schnorr
+
+	data, err := json.Marshal(pst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(PartiallySignedTransaction)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Network != pst.Network || got.SignatureType != Schnorr {
+		t.Error("Returned the wrong network or signature type")
+	}
+	if got.Tx.TxHash() != pst.Tx.TxHash() {
+		t.Error("Returned the wrong transaction")
+	}
+	if len(got.Inputs) != 1 || got.Inputs[0].Value != pst.Inputs[0].Value ||
+		!bytes.Equal(got.Inputs[0].PkScript, pst.Inputs[0].PkScript) ||
+		got.Inputs[0].DerivationPath != pst.Inputs[0].DerivationPath {
+		t.Error("Returned the wrong inputs")
+	}
+
+	// Files without a signature type sign with the signing wallet's
+	pst.SignatureType = DefaultSignatureType
+	data, err = json.Marshal(pst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = new(PartiallySignedTransaction)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.SignatureType != DefaultSignatureType {
+		t.Errorf("Expected the default signature type, got %d", got.SignatureType)
+	}
+	if err := w.SignTransaction(got); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyInputs(got); err != nil {
+		t.Error(err)
+	}
+
+	pst.Inputs = nil
+	data, err = json.Marshal(pst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, got); err == nil {
+		t.Error("Decoded a transaction with missing inputs")
+	}
+}
+
+func Test_parseDerivationPath(t *testing.T) {
+	path, err := parseDerivationPath(bip44Path(2, wallet.KeyPath{Purpose: wallet.INTERNAL, Index: 7}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint32{hd.HardenedKeyStart + 44, hd.HardenedKeyStart + 145, hd.HardenedKeyStart + 2, 1, 7}
+	if len(path) != len(expected) {
+		t.Fatalf("Expected %d indexes, got %d", len(expected), len(path))
+	}
+	for i := range path {
+		if path[i] != expected[i] {
+			t.Errorf("Index %d: expected %d, got %d", i, expected[i], path[i])
+		}
+	}
+	for _, p := range []string{"", "44'/0", "m/a", "m/-1", "m/2147483648"} {
+		if _, err := parseDerivationPath(p); err == nil {
+			t.Errorf("Parsed invalid path %q", p)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package bitcoincash
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/gcash/bchd/bchec"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+)
+
+// SignatureType selects the algorithm used to sign transaction inputs.
+type SignatureType int
+
+const (
+	// DefaultSignatureType signs with the signature type from the config.
+	DefaultSignatureType SignatureType = iota
+	ECDSA
+	Schnorr
+)
+
+// SchnorrSignatureSize is the size of a Schnorr signature without the sighash byte.
+const SchnorrSignatureSize = 64
+
+// rawTxInSignature returns the signature of the input at idx with the sighash
+// byte appended, made with the algorithm sigType selects.
+func rawTxInSignature(tx *wire.MsgTx, idx int, subScript []byte, hashType txscript.SigHashType, key *bchec.PrivateKey, amt int64, sigType SignatureType) ([]byte, error) {
+	if sigType == Schnorr {
+		return txscript.RawTxInSchnorrSignature(tx, idx, subScript, hashType, key, amt)
+	}
+	return txscript.RawTxInECDSASignature(tx, idx, subScript, hashType, key, amt)
+}
+
+// p2pkhSignatureScript returns a P2PKH signature script spending the input at
+// idx. It is used instead of txscript.SignatureScript, which always signs with
+// Schnorr.
+func p2pkhSignatureScript(tx *wire.MsgTx, idx int, amt int64, subScript []byte, hashType txscript.SigHashType, key *bchec.PrivateKey, compress bool, sigType SignatureType) ([]byte, error) {
+	sig, err := rawTxInSignature(tx, idx, subScript, hashType, key, amt, sigType)
+	if err != nil {
+		return nil, err
+	}
+	pk := key.PubKey()
+	var pkData []byte
+	if compress {
+		pkData = pk.SerializeCompressed()
+	} else {
+		pkData = pk.SerializeUncompressed()
+	}
+	return txscript.NewScriptBuilder().AddData(sig).AddData(pkData).Script()
+}
+
+// isSchnorrSignature reports whether sig is a Schnorr signature with sighash byte.
+// DER encoded ECDSA signatures are never 65 bytes long so the length is sufficient.
+func isSchnorrSignature(sig []byte) bool {
+	return len(sig) == SchnorrSignatureSize+1
+}
+
+// multisigPubKeys returns the keys of the CHECKMULTISIG branch of a redeem
+// script in script order. The timeout key of a timelocked script is excluded.
+func multisigPubKeys(redeemScript []byte) ([]*bchec.PublicKey, error) {
+	pushes, err := txscript.PushedData(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	var keys []*bchec.PublicKey
+	for _, data := range pushes {
+		if len(data) != 33 && len(data) != 65 {
+			continue
+		}
+		key, err := bchec.ParsePubKey(data, bchec.S256())
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(redeemScript) > 0 && redeemScript[0] == txscript.OP_IF && len(keys) > 0 {
+		keys = keys[:len(keys)-1]
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("No public keys in redeem script")
+	}
+	return keys, nil
+}
+
+// schnorrMultisigKeyIndex returns the position in the redeem script of the key
+// that produced the Schnorr signature for the input at idx.
+func schnorrMultisigKeyIndex(keys []*bchec.PublicKey, sig []byte, tx *wire.MsgTx, idx int, redeemScript []byte, amt int64) (int, error) {
+	if !isSchnorrSignature(sig) {
+		return 0, errors.New("Not a Schnorr signature")
+	}
+	hashType := txscript.SigHashType(sig[SchnorrSignatureSize])
+	hash, err := txscript.CalcSignatureHash(redeemScript, txscript.NewTxSigHashes(tx), hashType, tx, idx, amt, true)
+	if err != nil {
+		return 0, err
+	}
+	signature, err := bchec.ParseSchnorrSignature(sig[:SchnorrSignatureSize])
+	if err != nil {
+		return 0, err
+	}
+	for i, key := range keys {
+		if signature.Verify(hash, key) {
+			return i, nil
+		}
+	}
+	return 0, errors.New("Signature does not match any key in redeem script")
+}
+
+// schnorrMultisigScript builds the signature script for a Schnorr CHECKMULTISIG
+// spend. sigs maps the index of each signing key in the redeem script to its
+// signature. In place of the usual dummy element the script starts with a
+// little endian bitfield marking which keys signed, and the signatures follow
+// in key order.
+func schnorrMultisigScript(redeemScript []byte, sigs map[int][]byte) ([]byte, error) {
+	keys, err := multisigPubKeys(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	checkBits := make([]byte, (len(keys)+7)/8)
+	var indexes []int
+	for i := range sigs {
+		if i < 0 || i >= len(keys) {
+			return nil, errors.New("Signature key index out of range")
+		}
+		checkBits[i/8] |= 1 << uint(i%8)
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	builder := txscript.NewScriptBuilder()
+	builder.AddData(checkBits)
+	for _, i := range indexes {
+		builder.AddData(sigs[i])
+	}
+	if redeemScript[0] == txscript.OP_IF {
+		builder.AddOp(txscript.OP_1)
+	}
+	builder.AddData(redeemScript)
+	return builder.Script()
+}
@@ -0,0 +1,149 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gcash/bchd/bchec"
+	"github.com/gcash/bchd/chaincfg"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil"
+)
+
+func schnorrTestTx() *wire.MsgTx {
+	tx := wire.NewMsgTx(1)
+	h, _ := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(h, 0), nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(h, 1), nil))
+	tx.AddTxOut(wire.NewTxOut(90000, make([]byte, P2PKHPkScriptSize)))
+	return tx
+}
+
+// verifyInput runs the script of input idx of tx against pkScript with the
+// standard verification flags.
+func verifyInput(tx *wire.MsgTx, idx int, pkScript []byte, amt int64) error {
+	vm, err := txscript.NewEngine(pkScript, tx, idx, txscript.StandardVerifyFlags, nil, nil, amt)
+	if err != nil {
+		return err
+	}
+	return vm.Execute()
+}
+
+func TestP2PKHSignatureScript(t *testing.T) {
+	priv, err := bchec.NewPrivateKey(bchec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := bchutil.NewAddressPubKeyHash(bchutil.Hash160(priv.PubKey().SerializeCompressed()), &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sigType := range []SignatureType{ECDSA, Schnorr} {
+		tx := schnorrTestTx()
+		script, err := p2pkhSignatureScript(tx, 1, 50000, prevScript, txscript.SigHashAll, priv, true, sigType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pushes, err := txscript.PushedData(script)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig := pushes[0]
+		if isSchnorrSignature(sig) != (sigType == Schnorr) {
+			t.Errorf("Signature type %d: returned a signature of %d bytes", sigType, len(sig))
+		}
+		if sigType == Schnorr && len(script) != RedeemP2PKHSchnorrSigScriptSize {
+			t.Errorf("Expected script size %d, got %d", RedeemP2PKHSchnorrSigScriptSize, len(script))
+		}
+		if len(script) > RedeemP2PKHSigScriptSize {
+			t.Errorf("Script size %d is larger than the estimate", len(script))
+		}
+		if txscript.SigHashType(sig[len(sig)-1]) != txscript.SigHashAll|txscript.SigHashForkID {
+			t.Error("Incorrect sighash type")
+		}
+		if !bytes.Equal(pushes[1], priv.PubKey().SerializeCompressed()) {
+			t.Error("Incorrect public key in signature script")
+		}
+		tx.TxIn[1].SignatureScript = script
+		if err := verifyInput(tx, 1, prevScript, 50000); err != nil {
+			t.Errorf("Signature type %d: failed to verify signature: %s", sigType, err)
+		}
+		if err := verifyInput(tx, 1, prevScript, 50001); err == nil {
+			t.Errorf("Signature type %d: signature does not commit to the input amount", sigType)
+		}
+	}
+}
+
+func TestSchnorrMultisigScript(t *testing.T) {
+	var privs []*bchec.PrivateKey
+	builder := txscript.NewScriptBuilder().AddOp(txscript.OP_2)
+	for i := 0; i < 3; i++ {
+		priv, err := bchec.NewPrivateKey(bchec.S256())
+		if err != nil {
+			t.Fatal(err)
+		}
+		privs = append(privs, priv)
+		builder.AddData(priv.PubKey().SerializeCompressed())
+	}
+	redeemScript, err := builder.AddOp(txscript.OP_3).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := multisigPubKeys(redeemScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("Expected 3 keys, got %d", len(keys))
+	}
+
+	tx := schnorrTestTx()
+	sigs := make(map[int][]byte)
+	// Sign out of order to check the signatures are sorted by key.
+	for _, i := range []int{2, 0} {
+		sig, err := rawTxInSignature(tx, 0, redeemScript, txscript.SigHashAll, privs[i], 100000, Schnorr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		index, err := schnorrMultisigKeyIndex(keys, sig, tx, 0, redeemScript, 100000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index != i {
+			t.Errorf("Expected key index %d, got %d", i, index)
+		}
+		sigs[index] = sig
+	}
+
+	script, err := schnorrMultisigScript(redeemScript, sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keys 0 and 2 signed so the checkbits are 0b101 which is pushed as OP_5.
+	if script[0] != txscript.OP_5 {
+		t.Errorf("Expected checkbits OP_5, got %x", script[0])
+	}
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pushes) != 3 {
+		t.Fatalf("Expected 3 pushes, got %d", len(pushes))
+	}
+	if !bytes.Equal(pushes[0], sigs[0]) || !bytes.Equal(pushes[1], sigs[2]) {
+		t.Error("Signatures are not in key order")
+	}
+	if !bytes.Equal(pushes[2], redeemScript) {
+		t.Error("Incorrect redeem script")
+	}
+
+	if _, err := schnorrMultisigScript(redeemScript, map[int][]byte{3: sigs[0]}); err == nil {
+		t.Error("Failed to reject out of range key index")
+	}
+}
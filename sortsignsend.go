@@ -42,7 +42,9 @@ func (s *SPVWallet) Broadcast(tx *wire.MsgTx) error {
 		return err
 	}
 
-	s.wireService.MsgChan() <- updateFiltersMsg{}
+	if s.running {
+		s.wireService.MsgChan() <- updateFiltersMsg{}
+	}
 	log.Noticef("Broadcasting tx %s to peers", tx.TxHash().String())
 	for _, peer := range s.peerManager.ConnectedPeers() {
 		peer.QueueMessage(tx, nil)
@@ -77,12 +79,19 @@ func NewCoin(txid []byte, index uint32, value bch.Amount, numConfs int64, script
 	return coinset.Coin(c)
 }
 
-func (w *SPVWallet) gatherCoins() map[coinset.Coin]*hd.ExtendedKey {
+// gatherCoins returns the spendable coins of an account with their keys. Locked
+// coins are left out.
+func (w *SPVWallet) gatherCoins(account uint32) map[coinset.Coin]*hd.ExtendedKey {
 	height, _ := w.blockchain.db.Height()
-	utxos, _ := w.txstore.Utxos().GetAll()
+	km, ok := w.txstore.accountKeyManager(account)
+	if !ok {
+		return nil
+	}
+	utxos, _ := w.txstore.accountDatastore(account).Utxos().GetAll()
+	locked := w.lockedUtxos()
 	m := make(map[coinset.Coin]*hd.ExtendedKey)
 	for _, u := range utxos {
-		if u.WatchOnly {
+		if u.WatchOnly || locked[u.Op] {
 			continue
 		}
 		var confirmations int32
@@ -95,7 +104,7 @@ func (w *SPVWallet) gatherCoins() map[coinset.Coin]*hd.ExtendedKey {
 			log.Error(err)
 			continue
 		}
-		key, err := w.keyManager.GetKeyForScript(addr.ScriptAddress())
+		key, err := km.GetKeyForScript(addr.ScriptAddress())
 		if err != nil {
 			log.Error(err)
 			continue
@@ -105,8 +114,11 @@ func (w *SPVWallet) gatherCoins() map[coinset.Coin]*hd.ExtendedKey {
 	return m
 }
 
+// Spend sends amount to addr from the default account. The inputs are signed
+// using the configured signature type, see SpendWithOptions to override it. A
+// referenceID is saved as the transaction's memo if the datastore supports it.
 func (w *SPVWallet) Spend(amount int64, addr bch.Address, feeLevel wallet.FeeLevel, referenceID string) (*chainhash.Hash, error) {
-	tx, err := w.buildTx(amount, addr, feeLevel, nil)
+	tx, err := w.buildTx(SpendOptions{}, payment(addr, amount), feeLevel)
 	if err != nil {
 		return nil, err
 	}
@@ -116,14 +128,51 @@ func (w *SPVWallet) Spend(amount int64, addr bch.Address, feeLevel wallet.FeeLev
 		return nil, err
 	}
 	ch := tx.TxHash()
+	w.saveMemo(ch, referenceID)
+	return &ch, nil
+}
+
+// SpendMany sends one transaction paying every output with a single change
+// output. Each output must be above the dust threshold except OP_RETURN outputs,
+// see DataOutput.
+func (w *SPVWallet) SpendMany(outputs []wallet.TransactionOutput, feeLevel wallet.FeeLevel, opts SpendOptions) (*chainhash.Hash, error) {
+	tx, err := w.buildTx(opts, outputs, feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	err = w.Broadcast(tx)
+	if err != nil {
+		return nil, err
+	}
+	ch := tx.TxHash()
+	w.saveMemo(ch, opts.Memo)
 	return &ch, nil
 }
 
+// saveMemo saves the memo of a transaction which was just broadcast. Failing
+// to save it is only logged as the payment has already been sent.
+func (w *SPVWallet) saveMemo(txid chainhash.Hash, memo string) {
+	if memo == "" {
+		return
+	}
+	if err := w.SetTxMemo(txid, memo); err != nil && err != ErrMetadataNotSupported {
+		log.Errorf("Error saving memo of %s: %s", txid, err)
+	}
+}
+
+// payment returns the outputs of a transaction paying amount to addr.
+func payment(addr bch.Address, amount int64) []wallet.TransactionOutput {
+	return []wallet.TransactionOutput{{Address: addr, Value: amount}}
+}
+
 var BumpFeeAlreadyConfirmedError = errors.New("Transaction is confirmed, cannot bump fee")
 var BumpFeeTransactionDeadError = errors.New("Cannot bump fee of dead transaction")
 var BumpFeeNotFoundError = errors.New("Transaction either doesn't exist or has already been spent")
 
 func (w *SPVWallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
+	if err := w.checkUnlocked(); err != nil {
+		return nil, err
+	}
 	txn, err := w.txstore.Txns().Get(txid)
 	if err != nil {
 		return nil, err
@@ -194,7 +243,7 @@ func (w *SPVWallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
 			if err != nil {
 				return nil, err
 			}
-			key, err := w.keyManager.GetKeyForScript(addr.ScriptAddress())
+			key, err := w.keyForScript(addr.ScriptAddress())
 			if err != nil {
 				return nil, err
 			}
@@ -221,11 +270,11 @@ func (w *SPVWallet) BumpFee(txid chainhash.Hash) (*chainhash.Hash, error) {
 func (w *SPVWallet) EstimateFee(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, feePerByte uint64) uint64 {
 	tx := wire.NewMsgTx(1)
 	for _, out := range outs {
-		scriptPubKey, _ := txscript.PayToAddrScript(out.Address)
+		scriptPubKey, _ := outputScript(out)
 		output := wire.NewTxOut(out.Value, scriptPubKey)
 		tx.TxOut = append(tx.TxOut, output)
 	}
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, P2PKH)
+	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, P2PKH.WithSignatureType(w.sigType))
 	fee := estimatedSize * int(feePerByte)
 	return uint64(fee)
 }
@@ -237,10 +286,11 @@ func (w *SPVWallet) EstimateSpendFee(amount int64, feeLevel wallet.FeeLevel) (ui
 	if err != nil {
 		return 0, err
 	}
-	tx, err := w.buildTx(amount, addr, feeLevel, nil)
+	utx, err := w.buildUnsignedTx(SpendOptions{}, payment(addr, amount), feeLevel)
 	if err != nil {
 		return 0, err
 	}
+	tx := utx.tx
 	var outval int64
 	for _, output := range tx.TxOut {
 		outval += output.Value
@@ -326,7 +376,23 @@ func (w *SPVWallet) GenerateMultisigScript(keys []hd.ExtendedKey, threshold int,
 	return addr, redeemScript, nil
 }
 
+// SignOptions control how the inputs of a sweep or multisig spend are signed.
+type SignOptions struct {
+	// If set, the signature type used instead of the one from the config.
+	SignatureType SignatureType
+}
+
+// CreateMultisigSignature signs each input spending redeemScript using the
+// configured signature type.
 func (w *SPVWallet) CreateMultisigSignature(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte uint64) ([]wallet.Signature, error) {
+	return w.CreateMultisigSignatureWithOptions(ins, outs, key, redeemScript, feePerByte, SignOptions{})
+}
+
+// CreateMultisigSignatureWithOptions is like CreateMultisigSignature but signs
+// as opts says. Every party must use the same signature type as it changes the
+// fee subtracted from the outputs.
+func (w *SPVWallet) CreateMultisigSignatureWithOptions(ins []wallet.TransactionInput, outs []wallet.TransactionOutput, key *hd.ExtendedKey, redeemScript []byte, feePerByte uint64, opts SignOptions) ([]wallet.Signature, error) {
+	signatureType := w.signatureType(opts.SignatureType)
 	var sigs []wallet.Signature
 	tx := wire.NewMsgTx(1)
 	for _, in := range ins {
@@ -353,7 +419,7 @@ func (w *SPVWallet) CreateMultisigSignature(ins []wallet.TransactionInput, outs
 	if err == nil {
 		txType = P2SH_Multisig_Timelock_2Sigs
 	}
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType)
+	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType.WithSignatureType(signatureType))
 	fee := estimatedSize * int(feePerByte)
 	if len(tx.TxOut) > 0 {
 		feePerOutput := fee / len(tx.TxOut)
@@ -371,7 +437,7 @@ func (w *SPVWallet) CreateMultisigSignature(ins []wallet.TransactionInput, outs
 	}
 
 	for i := range tx.TxIn {
-		sig, err := txscript.SignatureScript(tx, i, ins[i].Value, redeemScript, txscript.SigHashAll, signingKey, true)
+		sig, err := rawTxInSignature(tx, i, redeemScript, txscript.SigHashAll, signingKey, ins[i].Value, signatureType)
 		if err != nil {
 			continue
 		}
@@ -401,13 +467,17 @@ func (w *SPVWallet) Multisign(ins []wallet.TransactionInput, outs []wallet.Trans
 		tx.TxOut = append(tx.TxOut, output)
 	}
 
+	// Schnorr signatures need the new-mode CHECKMULTISIG encoding and a
+	// smaller fee was subtracted when they were created.
+	signatureType := multisigSignatureType(sigs1, sigs2)
+
 	// Subtract fee
 	txType := P2SH_2of3_Multisig
 	_, err := LockTimeFromRedeemScript(redeemScript)
 	if err == nil {
 		txType = P2SH_Multisig_Timelock_2Sigs
 	}
-	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType)
+	estimatedSize := EstimateSerializeSize(len(ins), tx.TxOut, false, txType.WithSignatureType(signatureType))
 	fee := estimatedSize * int(feePerByte)
 	if len(tx.TxOut) > 0 {
 		feePerOutput := fee / len(tx.TxOut)
@@ -419,6 +489,14 @@ func (w *SPVWallet) Multisign(ins []wallet.TransactionInput, outs []wallet.Trans
 	// BIP 69 sorting
 	txsort.InPlaceSort(tx)
 
+	var keys []*bchec.PublicKey
+	if signatureType == Schnorr {
+		keys, err = multisigPubKeys(redeemScript)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Check if time locked
 	var timeLocked bool
 	if redeemScript[0] == txscript.OP_IF {
@@ -438,6 +516,22 @@ func (w *SPVWallet) Multisign(ins []wallet.TransactionInput, outs []wallet.Trans
 				sig2 = sig.Signature
 			}
 		}
+		if signatureType == Schnorr {
+			sigsByKey := make(map[int][]byte)
+			for _, sig := range [][]byte{sig1, sig2} {
+				index, err := schnorrMultisigKeyIndex(keys, sig, tx, i, redeemScript, ins[i].Value)
+				if err != nil {
+					return nil, err
+				}
+				sigsByKey[index] = sig
+			}
+			scriptSig, err := schnorrMultisigScript(redeemScript, sigsByKey)
+			if err != nil {
+				return nil, err
+			}
+			input.SignatureScript = scriptSig
+			continue
+		}
 		builder := txscript.NewScriptBuilder()
 		builder.AddOp(txscript.OP_0)
 		builder.AddData(sig1)
@@ -463,7 +557,15 @@ func (w *SPVWallet) Multisign(ins []wallet.TransactionInput, outs []wallet.Trans
 	return buf.Bytes(), nil
 }
 
+// SweepAddress sends the value of ins to address, or to an internal address if nil.
+// The inputs are signed using the configured signature type.
 func (w *SPVWallet) SweepAddress(ins []wallet.TransactionInput, address *bch.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel wallet.FeeLevel) (*chainhash.Hash, error) {
+	return w.SweepAddressWithOptions(ins, address, key, redeemScript, feeLevel, SignOptions{})
+}
+
+// SweepAddressWithOptions is like SweepAddress but signs the inputs as opts says.
+func (w *SPVWallet) SweepAddressWithOptions(ins []wallet.TransactionInput, address *bch.Address, key *hd.ExtendedKey, redeemScript *[]byte, feeLevel wallet.FeeLevel, opts SignOptions) (*chainhash.Hash, error) {
+	signatureType := w.signatureType(opts.SignatureType)
 	var internalAddr bch.Address
 	if address != nil {
 		internalAddr = *address
@@ -503,7 +605,7 @@ func (w *SPVWallet) SweepAddress(ins []wallet.TransactionInput, address *bch.Add
 			txType = P2SH_Multisig_Timelock_1Sig
 		}
 	}
-	estimatedSize := EstimateSerializeSize(len(ins), []*wire.TxOut{out}, false, txType)
+	estimatedSize := EstimateSerializeSize(len(ins), []*wire.TxOut{out}, false, txType.WithSignatureType(signatureType))
 
 	// Calculate the fee
 	feePerByte := int(w.GetFeePerByte(feeLevel))
@@ -530,32 +632,10 @@ func (w *SPVWallet) SweepAddress(ins []wallet.TransactionInput, address *bch.Add
 	if err != nil {
 		return nil, err
 	}
-	pk := privKey.PubKey().SerializeCompressed()
-	addressPub, err := bch.NewAddressPubKey(pk, w.params)
-
-	getKey := txscript.KeyClosure(func(addr bch.Address) (*bchec.PrivateKey, bool, error) {
-		if addressPub.EncodeAddress() == addr.EncodeAddress() {
-			wif, err := bch.NewWIF(privKey, w.params, true)
-			if err != nil {
-				return nil, false, err
-			}
-			return wif.PrivKey, wif.CompressPubKey, nil
-		}
-		return nil, false, errors.New("Not found")
-	})
-	getScript := txscript.ScriptClosure(func(addr bch.Address) ([]byte, error) {
-		if redeemScript == nil {
-			return []byte{}, nil
-		}
-		return *redeemScript, nil
-	})
-
 	// Check if time locked
-	var timeLocked bool
 	if redeemScript != nil {
 		rs := *redeemScript
 		if rs[0] == txscript.OP_IF {
-			timeLocked = true
 			tx.Version = 2
 			for _, txIn := range tx.TxIn {
 				locktime, err := LockTimeFromRedeemScript(*redeemScript)
@@ -568,31 +648,11 @@ func (w *SPVWallet) SweepAddress(ins []wallet.TransactionInput, address *bch.Add
 	}
 
 	for i, txIn := range tx.TxIn {
-		if !timeLocked {
-			prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-			script, err := txscript.SignTxOutput(w.params,
-				tx, i, ins[i].Value, prevOutScript, txscript.SigHashAll, getKey,
-				getScript, txIn.SignatureScript)
-			if err != nil {
-				return nil, errors.New("Failed to sign transaction")
-			}
-			txIn.SignatureScript = script
-		} else {
-			priv, err := key.ECPrivKey()
-			if err != nil {
-				return nil, err
-			}
-			script, err := txscript.SignatureScript(tx, i, ins[i].Value, *redeemScript, txscript.SigHashAll, priv, true)
-			if err != nil {
-				return nil, err
-			}
-			builder := txscript.NewScriptBuilder().
-				AddData(script).
-				AddOp(txscript.OP_0).
-				AddData(*redeemScript)
-			scriptSig, _ := builder.Script()
-			txIn.SignatureScript = scriptSig
+		script, err := sweepSignatureScript(tx, i, ins[i].Value, privKey, redeemScript, additionalPrevScripts[txIn.PreviousOutPoint], signatureType)
+		if err != nil {
+			return nil, err
 		}
+		txIn.SignatureScript = script
 	}
 
 	// broadcast
@@ -601,67 +661,166 @@ func (w *SPVWallet) SweepAddress(ins []wallet.TransactionInput, address *bch.Add
 	return &txid, nil
 }
 
-func (w *SPVWallet) buildTx(amount int64, addr bch.Address, feeLevel wallet.FeeLevel, optionalOutput *wire.TxOut) (*wire.MsgTx, error) {
+// sweepSignatureScript returns the signature script for input idx of a sweep.
+// Without a redeem script the input is P2PKH. Otherwise it is a 1 of 2 multisig
+// or the timeout branch of a timelocked multisig.
+func sweepSignatureScript(tx *wire.MsgTx, idx int, amt int64, privKey *bchec.PrivateKey, redeemScript *[]byte, prevOutScript []byte, sigType SignatureType) ([]byte, error) {
+	if redeemScript == nil {
+		return p2pkhSignatureScript(tx, idx, amt, prevOutScript, txscript.SigHashAll, privKey, true, sigType)
+	}
+	rs := *redeemScript
+	sig, err := rawTxInSignature(tx, idx, rs, txscript.SigHashAll, privKey, amt, sigType)
+	if err != nil {
+		return nil, err
+	}
+	if rs[0] == txscript.OP_IF {
+		return txscript.NewScriptBuilder().
+			AddData(sig).
+			AddOp(txscript.OP_0).
+			AddData(rs).
+			Script()
+	}
+	if sigType != Schnorr {
+		return txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).
+			AddData(sig).
+			AddData(rs).
+			Script()
+	}
+	keys, err := multisigPubKeys(rs)
+	if err != nil {
+		return nil, err
+	}
+	pk := privKey.PubKey().SerializeCompressed()
+	for i, key := range keys {
+		if bytes.Equal(key.SerializeCompressed(), pk) {
+			return schnorrMultisigScript(rs, map[int][]byte{i: sig})
+		}
+	}
+	return nil, errors.New("Key not found in redeem script")
+}
+
+func (w *SPVWallet) buildTx(opts SpendOptions, outputs []wallet.TransactionOutput, feeLevel wallet.FeeLevel) (*wire.MsgTx, error) {
+	if err := w.checkUnlocked(); err != nil {
+		return nil, err
+	}
+	utx, err := w.buildUnsignedTx(opts, outputs, feeLevel)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.signTx(utx, w.signatureType(opts.SignatureType)); err != nil {
+		return nil, err
+	}
+	return utx.tx, nil
+}
+
+// unsignedTx is a transaction spending the wallet's coins along with what is
+// needed to sign its inputs.
+type unsignedTx struct {
+	tx          *wire.MsgTx
+	prevScripts map[wire.OutPoint][]byte
+	inVals      map[wire.OutPoint]int64
+	keys        map[wire.OutPoint]*hd.ExtendedKey
+}
+
+// buildUnsignedTx selects coins of the account in opts paying outputs, adds a
+// single change output to the account and sorts the transaction. The keys of
+// the inputs are public if the wallet is locked or watch-only. The signature
+// type is only used to estimate the size of the signatures.
+func (w *SPVWallet) buildUnsignedTx(opts SpendOptions, outputs []wallet.TransactionOutput, feeLevel wallet.FeeLevel) (*unsignedTx, error) {
+	if _, ok := w.txstore.accountKeyManager(opts.Account); !ok {
+		return nil, ErrUnknownAccount
+	}
+	if opts.Data != nil {
+		outputs = append(outputs[:len(outputs):len(outputs)], DataOutput(opts.Data...))
+	}
+	if len(outputs) == 0 {
+		return nil, errors.New("Transaction has no outputs")
+	}
+	if err := checkDataOutputs(outputs); err != nil {
+		return nil, err
+	}
+	if opts.Fee < 0 {
+		return nil, errors.New("Fee must not be negative")
+	}
+	if opts.Fee > 0 && opts.FeePerByte > 0 {
+		return nil, errors.New("Set either a fee rate or a fee")
+	}
+	// The fee level's rate is only looked up if no fee was chosen
+	feePerByte := opts.FeePerByte
+	if feePerByte > 0 {
+		if err := w.feeProvider.checkFeePerByte(feePerByte); err != nil {
+			return nil, err
+		}
+	} else if opts.Fee == 0 {
+		feePerByte = w.GetFeePerByte(feeLevel)
+	}
+
 	// Check for dust
-	script, _ := txscript.PayToAddrScript(addr)
-	if txrules.IsDustAmount(bch.Amount(amount), len(script), txrules.DefaultRelayFeePerKb) {
-		return nil, errors.New("Amount is below dust threshold")
+	txOuts := make([]*wire.TxOut, 0, len(outputs))
+	for _, output := range outputs {
+		script, err := outputScript(output)
+		if err != nil {
+			return nil, err
+		}
+		if !output.OpReturn && txrules.IsDustAmount(bch.Amount(output.Value), len(script), txrules.DefaultRelayFeePerKb) {
+			return nil, fmt.Errorf("Amount sent to %s is below dust threshold", output.Address)
+		}
+		txOuts = append(txOuts, wire.NewTxOut(output.Value, script))
 	}
 
-	var additionalPrevScripts map[wire.OutPoint][]byte
-	var additionalKeysByAddress map[string]*bch.WIF
-	var inVals map[wire.OutPoint]int64
+	utx := &unsignedTx{}
 
 	// Create input source
-	coinMap := w.gatherCoins()
+	coinMap, err := filterCoins(w.gatherCoins(opts.Account), opts)
+	if err != nil {
+		return nil, err
+	}
 	coins := make([]coinset.Coin, 0, len(coinMap))
 	for k := range coinMap {
 		coins = append(coins, k)
 		log.Debug(k.Value(), k.NumConfs(), k.Hash().String())
 	}
 	inputSource := func(target bch.Amount) (total bch.Amount, inputs []*wire.TxIn, amounts []bch.Amount, scripts [][]byte, err error) {
-		coinSelector := coinset.MaxValueAgeCoinSelector{MaxInputs: 10000, MinChangeAmount: bch.Amount(0)}
-		coins, err := coinSelector.CoinSelect(target, coins)
+		var selected coinset.Coins
+		if len(opts.Inputs) > 0 {
+			// The chosen inputs are all spent
+			set := coinset.NewCoinSet(coins)
+			if set.TotalValue() < target {
+				err = errors.New("insuffient funds")
+			}
+			selected = set
+		} else {
+			coinSelector := coinset.MaxValueAgeCoinSelector{MaxInputs: 10000, MinChangeAmount: bch.Amount(0)}
+			selected, err = coinSelector.CoinSelect(target, coins)
+		}
 		if err != nil {
 			log.Error("insuffient funds: target > ", target)
 			return total, inputs, []bch.Amount{}, scripts, errors.New("insuffient funds")
 		}
-		additionalPrevScripts = make(map[wire.OutPoint][]byte)
-		inVals = make(map[wire.OutPoint]int64)
-		additionalKeysByAddress = make(map[string]*bch.WIF)
-		for _, c := range coins.Coins() {
+		utx.prevScripts = make(map[wire.OutPoint][]byte)
+		utx.inVals = make(map[wire.OutPoint]int64)
+		utx.keys = make(map[wire.OutPoint]*hd.ExtendedKey)
+		for _, c := range selected.Coins() {
 			total += c.Value()
 			outpoint := wire.NewOutPoint(c.Hash(), c.Index())
 			in := wire.NewTxIn(outpoint, []byte{})
 			inputs = append(inputs, in)
-			additionalPrevScripts[*outpoint] = c.PkScript()
-			key := coinMap[c]
-			addr, err := key.Address(w.params)
-			if err != nil {
-				continue
-			}
-			privKey, err := key.ECPrivKey()
-			if err != nil {
-				continue
-			}
-			wif, _ := bch.NewWIF(privKey, w.params, true)
-			additionalKeysByAddress[addr.EncodeAddress()] = wif
+			utx.prevScripts[*outpoint] = c.PkScript()
+			utx.keys[*outpoint] = coinMap[c]
 			val := c.Value()
 			sat := val.ToUnit(bch.AmountSatoshi)
-			inVals[*outpoint] = int64(sat)
+			utx.inVals[*outpoint] = int64(sat)
 		}
 		return total, inputs, []bch.Amount{}, scripts, nil
 	}
 
-	// Get the fee per kilobyte
-	feePerKB := int64(w.GetFeePerByte(feeLevel)) * 1000
-
-	// outputs
-	out := wire.NewTxOut(amount, script)
-
 	// Create change source
 	changeSource := func() ([]byte, error) {
-		addr := w.CurrentAddress(wallet.INTERNAL)
+		addr, err := w.AccountCurrentAddress(opts.Account, wallet.INTERNAL)
+		if err != nil {
+			return []byte{}, err
+		}
 		script, err := txscript.PayToAddrScript(addr)
 		if err != nil {
 			return []byte{}, err
@@ -669,50 +828,100 @@ func (w *SPVWallet) buildTx(amount int64, addr bch.Address, feeLevel wallet.FeeL
 		return script, nil
 	}
 
-	outputs := []*wire.TxOut{out}
-	if optionalOutput != nil {
-		outputs = append(outputs, optionalOutput)
-	}
-	authoredTx, err := NewUnsignedTransaction(outputs, bch.Amount(feePerKB), inputSource, changeSource)
-	if err != nil {
-		return nil, err
+	inputType := P2PKH.WithSignatureType(w.signatureType(opts.SignatureType))
+	var authoredTx *txauthor.AuthoredTx
+	if opts.Fee > 0 {
+		authoredTx, err = NewUnsignedTransactionWithFee(txOuts, bch.Amount(opts.Fee), inputSource, changeSource, inputType)
+		if err != nil {
+			return nil, err
+		}
+		paid := int64(authoredTx.TotalInput)
+		for _, out := range authoredTx.Tx.TxOut {
+			paid -= out.Value
+		}
+		size := EstimateSerializeSize(len(authoredTx.Tx.TxIn), authoredTx.Tx.TxOut, false, inputType)
+		if err := w.feeProvider.checkFee(paid, size); err != nil {
+			return nil, err
+		}
+	} else {
+		authoredTx, err = NewUnsignedTransaction(txOuts, bch.Amount(feePerByte*1000), inputSource, changeSource, inputType)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// BIP 69 sorting
 	txsort.InPlaceSort(authoredTx.Tx)
 
-	// Sign tx
-	getKey := txscript.KeyClosure(func(addr bch.Address) (*bchec.PrivateKey, bool, error) {
-		addrStr := addr.EncodeAddress()
-		wif := additionalKeysByAddress[addrStr]
-		return wif.PrivKey, wif.CompressPubKey, nil
-	})
-	getScript := txscript.ScriptClosure(func(
-		addr bch.Address) ([]byte, error) {
-		return []byte{}, nil
-	})
-	for i, txIn := range authoredTx.Tx.TxIn {
-		prevOutScript := additionalPrevScripts[txIn.PreviousOutPoint]
-		script, err := txscript.SignTxOutput(w.params,
-			authoredTx.Tx, i, inVals[txIn.PreviousOutPoint], prevOutScript,
-			txscript.SigHashAll, getKey, getScript, txIn.SignatureScript)
+	utx.tx = authoredTx.Tx
+	return utx, nil
+}
+
+// signTx signs the inputs of utx with the wallet's private keys.
+func (w *SPVWallet) signTx(utx *unsignedTx, sigType SignatureType) error {
+	additionalKeysByAddress := make(map[string]*bch.WIF)
+	for _, key := range utx.keys {
+		addr, err := key.Address(w.params)
+		if err != nil {
+			continue
+		}
+		privKey, err := key.ECPrivKey()
 		if err != nil {
-			return nil, errors.New("Failed to sign transaction")
+			continue
+		}
+		wif, _ := bch.NewWIF(privKey, w.params, true)
+		additionalKeysByAddress[addr.EncodeAddress()] = wif
+	}
+
+	for i, txIn := range utx.tx.TxIn {
+		prevOutScript := utx.prevScripts[txIn.PreviousOutPoint]
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(prevOutScript, w.params)
+		if err != nil || len(addrs) != 1 {
+			return errors.New("Failed to sign transaction")
+		}
+		wif, ok := additionalKeysByAddress[addrs[0].EncodeAddress()]
+		if !ok {
+			return errors.New("Failed to sign transaction")
+		}
+		script, err := p2pkhSignatureScript(utx.tx, i, utx.inVals[txIn.PreviousOutPoint],
+			prevOutScript, txscript.SigHashAll, wif.PrivKey, wif.CompressPubKey, sigType)
+		if err != nil {
+			return errors.New("Failed to sign transaction")
 		}
 		txIn.SignatureScript = script
 	}
-	return authoredTx.Tx, nil
+	return nil
+}
+
+// NewUnsignedTransaction selects inputs paying for outputs plus a fee estimated
+// from the size of inputType inputs and adds a change output if it isn't dust.
+func NewUnsignedTransaction(outputs []*wire.TxOut, feePerKb bch.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource, inputType InputType) (*txauthor.AuthoredTx, error) {
+	feeForSize := func(size int) bch.Amount {
+		return txrules.FeeForSerializeSize(feePerKb, size)
+	}
+	return newUnsignedTransaction(outputs, feeForSize, fetchInputs, fetchChange, inputType)
 }
 
-func NewUnsignedTransaction(outputs []*wire.TxOut, feePerKb bch.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource) (*txauthor.AuthoredTx, error) {
+// NewUnsignedTransactionWithFee is like NewUnsignedTransaction but pays
+// exactly fee, plus any change which would be dust.
+func NewUnsignedTransactionWithFee(outputs []*wire.TxOut, fee bch.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource, inputType InputType) (*txauthor.AuthoredTx, error) {
+	feeForSize := func(int) bch.Amount {
+		return fee
+	}
+	return newUnsignedTransaction(outputs, feeForSize, fetchInputs, fetchChange, inputType)
+}
+
+// newUnsignedTransaction builds a transaction paying the fee feeForSize returns
+// for the estimated size of the signed transaction.
+func newUnsignedTransaction(outputs []*wire.TxOut, feeForSize func(size int) bch.Amount, fetchInputs txauthor.InputSource, fetchChange txauthor.ChangeSource, inputType InputType) (*txauthor.AuthoredTx, error) {
 
 	var targetAmount bch.Amount
 	for _, txOut := range outputs {
 		targetAmount += bch.Amount(txOut.Value)
 	}
 
-	estimatedSize := EstimateSerializeSize(1, outputs, true, P2PKH)
-	targetFee := txrules.FeeForSerializeSize(feePerKb, estimatedSize)
+	estimatedSize := EstimateSerializeSize(1, outputs, true, inputType)
+	targetFee := feeForSize(estimatedSize)
 
 	for {
 		inputAmount, inputs, _, scripts, err := fetchInputs(targetAmount + targetFee)
@@ -723,8 +932,8 @@ func NewUnsignedTransaction(outputs []*wire.TxOut, feePerKb bch.Amount, fetchInp
 			return nil, errors.New("insufficient funds available to construct transaction")
 		}
 
-		maxSignedSize := EstimateSerializeSize(len(inputs), outputs, true, P2PKH)
-		maxRequiredFee := txrules.FeeForSerializeSize(feePerKb, maxSignedSize)
+		maxSignedSize := EstimateSerializeSize(len(inputs), outputs, true, inputType)
+		maxRequiredFee := feeForSize(maxSignedSize)
 		remainingAmount := inputAmount - targetAmount
 		if remainingAmount < maxRequiredFee {
 			targetFee = maxRequiredFee
@@ -768,6 +977,29 @@ func (w *SPVWallet) GetFeePerByte(feeLevel wallet.FeeLevel) uint64 {
 	return w.feeProvider.GetFeePerByte(feeLevel)
 }
 
+// multisigSignatureType returns Schnorr if every signature is a Schnorr signature.
+func multisigSignatureType(sigLists ...[]wallet.Signature) SignatureType {
+	sigType := ECDSA
+	for _, sigs := range sigLists {
+		for _, sig := range sigs {
+			if !isSchnorrSignature(sig.Signature) {
+				return ECDSA
+			}
+			sigType = Schnorr
+		}
+	}
+	return sigType
+}
+
+// signatureType returns the per-call override if one was set, otherwise the
+// signature type from the config.
+func (w *SPVWallet) signatureType(override SignatureType) SignatureType {
+	if override != DefaultSignatureType {
+		return override
+	}
+	return w.sigType
+}
+
 func LockTimeFromRedeemScript(redeemScript []byte) (uint32, error) {
 	if len(redeemScript) < 113 {
 		return 0, errors.New("Redeem script invalid length")
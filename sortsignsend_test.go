@@ -2,17 +2,25 @@ package bitcoincash
 
 import (
 	"bytes"
+	"crypto/rand"
 	"os"
+	"sync"
 	"testing"
 
+	"github.com/BubbaJoe/spvwallet-cash/paymentprotocol"
 	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
 	"github.com/gcash/bchd/chaincfg"
 	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/txscript"
 	"github.com/gcash/bchd/wire"
+	"github.com/gcash/bchutil/hdkeychain"
 )
 
 func MockWallet() *SPVWallet {
-	txstore, _ := createTxStore()
+	seed := make([]byte, 32)
+	rand.Read(seed)
+	masterPrivKey, _ := hdkeychain.NewMaster(seed, &chaincfg.TestNet3Params)
+	txstore, _ := createTxStoreWithKey(masterPrivKey)
 
 	peerCfg := &PeerManagerConfig{
 		UserAgentVersion: WALLET_VERSION,
@@ -23,7 +31,7 @@ func MockWallet() *SPVWallet {
 	createBlockChain(bc)
 
 	peerManager, _ := NewPeerManager(peerCfg)
-	return &SPVWallet{txstore: txstore, peerManager: peerManager, blockchain: bc, keyManager: txstore.keyManager, params: &chaincfg.TestNet3Params}
+	return &SPVWallet{txstore: txstore, peerManager: peerManager, blockchain: bc, keyManager: txstore.keyManager, params: &chaincfg.TestNet3Params, masterPrivateKey: masterPrivKey, sigType: ECDSA, mutex: new(sync.RWMutex), feeProvider: NewFeeProvider(2000, 300, 200, 100, nil), paymentClient: paymentprotocol.NewClient(nil)}
 }
 
 func Test_gatherCoins(t *testing.T) {
@@ -49,7 +57,7 @@ func Test_gatherCoins(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	coinmap := w.gatherCoins()
+	coinmap := w.gatherCoins(DefaultAccount)
 	for coin, key := range coinmap {
 		if !bytes.Equal(coin.PkScript(), script1) {
 			t.Error("Pubkey script in coin is incorrect")
@@ -77,3 +85,176 @@ func Test_gatherCoins(t *testing.T) {
 	}
 	os.Remove("headers.bin")
 }
+
+func Test_buildTxSignatureTypes(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	h1, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, err := w.keyManager.GetFreshKey(wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1, err := key1.Address(&chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script1, err := w.AddressToScript(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wire.NewOutPoint(h1, 0)
+	err = w.txstore.Utxos().Put(wallet.Utxo{Op: *op, ScriptPubkey: script1, AtHeight: 5, Value: 1000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sigType := range []SignatureType{ECDSA, Schnorr} {
+		tx, err := w.buildTx(SpendOptions{SignatureType: sigType}, payment(addr1, 10000), wallet.NORMAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tx.TxIn) != 1 {
+			t.Fatalf("Expected 1 input, got %d", len(tx.TxIn))
+		}
+		pushes, err := txscript.PushedData(tx.TxIn[0].SignatureScript)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pushes) != 2 || isSchnorrSignature(pushes[0]) != (sigType == Schnorr) {
+			t.Fatalf("Signature type %d: input was signed with the wrong signature type", sigType)
+		}
+		if err := verifyInput(tx, 0, script1, 1000000); err != nil {
+			t.Errorf("Signature type %d: failed to verify signature: %s", sigType, err)
+		}
+	}
+}
+
+func Test_buildTxManyOutputs(t *testing.T) {
+	w := MockWallet()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	defer os.Remove("headers.bin")
+	addTestUtxos(t, w, 1000000)
+	var outputs []wallet.TransactionOutput
+	for i := 0; i < 3; i++ {
+		key, err := w.keyManager.GetFreshKey(wallet.EXTERNAL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, err := key.Address(w.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, wallet.TransactionOutput{Address: addr, Value: int64(10000 * (i + 1))})
+	}
+	tx, err := w.buildTx(SpendOptions{}, outputs, wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxOut) != len(outputs)+1 {
+		t.Fatalf("Expected %d outputs, got %d", len(outputs)+1, len(tx.TxOut))
+	}
+	for _, output := range outputs {
+		script, err := w.AddressToScript(output.Address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, out := range tx.TxOut {
+			if bytes.Equal(out.PkScript, script) && out.Value == output.Value {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Transaction doesn't pay %d to %s", output.Value, output.Address)
+		}
+	}
+
+	outputs[1].Value = 100
+	if _, err := w.buildTx(SpendOptions{}, outputs, wallet.NORMAL); err == nil {
+		t.Error("Built a transaction with a dust output")
+	}
+	if _, err := w.buildTx(SpendOptions{}, nil, wallet.NORMAL); err == nil {
+		t.Error("Built a transaction without outputs")
+	}
+}
+
+func TestSPVWallet_watchOnly(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	defer w.blockchain.Close()
+	w.masterPrivateKey = nil
+	w.watchOnly = true
+	w.watchOnlyAccount = 2
+	var err error
+	w.keyManager.internalKey, err = w.keyManager.internalKey.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.keyManager.externalKey, err = w.keyManager.externalKey.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h1, err := chainhash.NewHashFromStr("6f7a58ad92702601fcbaac0e039943a384f5274a205c16bb8bbab54f9ea2fbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key1, err := w.keyManager.GetFreshKey(wallet.EXTERNAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr1, err := key1.Address(&chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script1, err := w.AddressToScript(addr1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := wire.NewOutPoint(h1, 0)
+	err = w.txstore.Utxos().Put(wallet.Utxo{Op: *op, ScriptPubkey: script1, AtHeight: 5, Value: 1000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pst, err := w.CreateUnsignedTransaction(10000, addr1, wallet.NORMAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := pst.Tx
+	if len(tx.TxIn) != 1 || tx.TxIn[0].PreviousOutPoint != *op {
+		t.Fatal("Unsigned transaction spends the wrong inputs")
+	}
+	if len(tx.TxIn[0].SignatureScript) != 0 {
+		t.Error("Watch-only wallet signed a transaction")
+	}
+	keyPath, err := w.keyManager.datastore.GetPathForKey(addr1.ScriptAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := bip44Path(2, keyPath); pst.Inputs[0].DerivationPath != expected {
+		t.Errorf("Returned derivation path %s, expected %s", pst.Inputs[0].DerivationPath, expected)
+	}
+	if _, err := w.EstimateSpendFee(10000, wallet.NORMAL); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := w.Spend(10000, addr1, wallet.NORMAL, ""); err != ErrWatchOnly {
+		t.Errorf("Spend: expected ErrWatchOnly, got %v", err)
+	}
+	if _, err := w.GetKey(addr1); err != ErrWatchOnly {
+		t.Errorf("GetKey: expected ErrWatchOnly, got %v", err)
+	}
+	if _, err := w.ListKeys(); err != ErrWatchOnly {
+		t.Errorf("ListKeys: expected ErrWatchOnly, got %v", err)
+	}
+	if _, err := w.MasterPrivateKey(); err != ErrWatchOnly {
+		t.Errorf("MasterPrivateKey: expected ErrWatchOnly, got %v", err)
+	}
+	if err := w.Unlock("passphrase"); err != ErrWatchOnly {
+		t.Errorf("Unlock: expected ErrWatchOnly, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+// TransactionDetail is a transaction of the wallet with its inputs and outputs
+// decoded.
+type TransactionDetail struct {
+	Txid          string
+	Value         int64
+	Height        int32
+	Timestamp     time.Time
+	Confirmations int64
+	Status        wallet.StatusCode
+	Memo          string
+	Inputs        []TransactionDetailInput
+	Outputs       []TransactionDetailOutput
+
+	// The fee and fee per byte, zero if the value of an input is unknown
+	Fee     int64
+	FeeRate float64
+
+	// The serialized size in bytes
+	Size int
+
+	Raw []byte
+}
+
+type TransactionDetailInput struct {
+	Outpoint wire.OutPoint
+
+	// The address spent from, empty if it is unknown
+	Address string
+
+	// The value of the spent output, -1 if it is unknown
+	Value int64
+
+	// Whether the input spends one of the wallet's outputs
+	Mine bool
+}
+
+type TransactionDetailOutput struct {
+	Index   uint32
+	Address string
+	Value   int64
+	Mine    bool
+
+	// OpReturn is set for an OP_RETURN data output. It has no address and
+	// Data holds its data pushes.
+	OpReturn bool
+	Data     [][]byte
+}
+
+// GetTransactionDetail returns a transaction of the wallet with its decoded
+// inputs and outputs. The value of an input is known if it spends an output of
+// one of the wallet's transactions. Otherwise only the address of a P2PKH
+// input can be told.
+func (w *SPVWallet) GetTransactionDetail(txid chainhash.Hash) (TransactionDetail, error) {
+	txn, err := w.txstore.Txns().Get(txid)
+	if err != nil {
+		return TransactionDetail{}, err
+	}
+	txn = w.setTxnStatus([]wallet.Txn{txn})[0]
+	tx := wire.NewMsgTx(1)
+	if err := tx.BchDecode(bytes.NewReader(txn.Bytes), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		return TransactionDetail{}, err
+	}
+	detail := TransactionDetail{
+		Txid:          txn.Txid,
+		Value:         txn.Value,
+		Height:        txn.Height,
+		Timestamp:     txn.Timestamp,
+		Confirmations: txn.Confirmations,
+		Status:        txn.Status,
+		Memo:          txn.Memo,
+		Size:          tx.SerializeSize(),
+		Raw:           txn.Bytes,
+	}
+
+	ourInputs := make(map[uint32]wallet.TxnIO)
+	for _, in := range txn.Inputs {
+		ourInputs[in.Index] = in
+	}
+	fee, feeKnown := int64(0), true
+	for i, txin := range tx.TxIn {
+		in := TransactionDetailInput{Outpoint: txin.PreviousOutPoint}
+		if io, ok := ourInputs[uint32(i)]; ok {
+			in.Address, in.Value, in.Mine = io.Address, io.Value, true
+		} else {
+			foreign := w.txstore.foreignInput(txin)
+			in.Address = encodeAddress(foreign.LinkedAddress)
+			in.Value = foreign.Value
+			in.Mine = foreign.LinkedAddress != nil && w.HasKey(foreign.LinkedAddress)
+		}
+		if in.Value < 0 {
+			feeKnown = false
+		}
+		fee += in.Value
+		detail.Inputs = append(detail.Inputs, in)
+	}
+
+	ourOutputs := make(map[uint32]bool)
+	for _, out := range txn.Outputs {
+		ourOutputs[out.Index] = true
+	}
+	for i, txout := range tx.TxOut {
+		out := TransactionDetailOutput{Index: uint32(i), Value: txout.Value, Mine: ourOutputs[uint32(i)]}
+		if data, ok := opReturnData(txout.PkScript); ok {
+			out.OpReturn = true
+			out.Data = data
+		} else if addr, err := w.ScriptToAddress(txout.PkScript); err == nil {
+			out.Address = addr.String()
+			out.Mine = out.Mine || w.HasKey(addr)
+		}
+		fee -= txout.Value
+		detail.Outputs = append(detail.Outputs, out)
+	}
+
+	switch {
+	case txn.Fee > 0:
+		detail.Fee = txn.Fee
+	case feeKnown:
+		detail.Fee = fee
+	}
+	if detail.Size > 0 {
+		detail.FeeRate = float64(detail.Fee) / float64(detail.Size)
+	}
+	return detail, nil
+}
@@ -0,0 +1,83 @@
+package bitcoincash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchutil"
+)
+
+func TestSPVWallet_GetTransactionDetail(t *testing.T) {
+	w, _, cleanup := createAccountsWallet(t)
+	defer cleanup()
+	w.feeProvider = NewFeeProvider(10, 5, 2, 1, nil)
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+	tx := payTo(t, addr, 1000000)
+	if _, err := w.txstore.Ingest(tx, 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The input of a payment from someone else has no known value or fee
+	detail, err := w.GetTransactionDetail(tx.TxHash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.Inputs) != 1 || detail.Inputs[0].Value != -1 || detail.Inputs[0].Mine {
+		t.Errorf("Returned the wrong inputs %v", detail.Inputs)
+	}
+	if len(detail.Outputs) != 1 || !detail.Outputs[0].Mine || detail.Outputs[0].Address != addr.String() {
+		t.Errorf("Returned the wrong outputs %v", detail.Outputs)
+	}
+	if detail.Fee != 0 || detail.Size != tx.SerializeSize() {
+		t.Errorf("Returned fee %d and size %d", detail.Fee, detail.Size)
+	}
+
+	recipient, err := bchutil.NewAddressPubKeyHash(make([]byte, 20), w.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spend, err := w.Spend(100000, recipient, wallet.NORMAL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fee and our inputs and outputs are saved with the spend
+	txn, err := w.GetTransaction(*spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txn.Fee <= 0 || txn.Value != -100000-txn.Fee {
+		t.Errorf("Saved fee %d for a spend of value %d", txn.Fee, txn.Value)
+	}
+	if len(txn.Inputs) != 1 || txn.Inputs[0].Value != 1000000 || txn.Inputs[0].Address != addr.String() {
+		t.Errorf("Saved the wrong inputs %v", txn.Inputs)
+	}
+	if len(txn.Outputs) != 1 || txn.Outputs[0].Value != 1000000-100000-txn.Fee {
+		t.Errorf("Saved the wrong outputs %v", txn.Outputs)
+	}
+
+	detail, err = w.GetTransactionDetail(*spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Fee != txn.Fee || detail.Status != wallet.StatusUnconfirmed {
+		t.Errorf("Returned fee %d and status %s", detail.Fee, detail.Status)
+	}
+	if detail.FeeRate != float64(detail.Fee)/float64(detail.Size) || detail.FeeRate <= 0 {
+		t.Errorf("Returned fee rate %f", detail.FeeRate)
+	}
+	if len(detail.Inputs) != 1 || !detail.Inputs[0].Mine || detail.Inputs[0].Value != 1000000 {
+		t.Errorf("Returned the wrong inputs %v", detail.Inputs)
+	}
+	var paid, change int
+	for _, out := range detail.Outputs {
+		if out.Mine {
+			change++
+		} else if out.Address == recipient.String() && out.Value == 100000 {
+			paid++
+		}
+	}
+	if paid != 1 || change != 1 {
+		t.Errorf("Returned the wrong outputs %v", detail.Outputs)
+	}
+}
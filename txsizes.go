@@ -121,6 +121,67 @@ const (
 	//   - OP_ENDIF
 	RedeemP2SHMultisigTimelock2SigScriptSize = 1 + 1 + 72 + +1 + 72 + 1 + 1 + 1 + 1 + 1 + 33 + 1 + 33 + 1 + 33 + 1 + 1 + 1 + 1 + 2 + 1 + 1 + 1 + 33 + 1 + 1
 
+	// RedeemP2PKHSchnorrSigScriptSize is the serialize size of a transaction
+	// input script that redeems a compressed P2PKH output with a Schnorr signature.
+	// It is calculated as:
+	//
+	//   - OP_DATA_65
+	//   - 64 bytes Schnorr signature + 1 byte sighash
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	RedeemP2PKHSchnorrSigScriptSize = 1 + 65 + 1 + 33
+
+	// RedeemP2SH2of3MultisigSchnorrSigScriptSize is the serialize size of a transaction
+	// input script that redeems a 2 of 3 P2SH multisig output with Schnorr signatures.
+	// It is calculated as:
+	//
+	//   - OP_N checkbits
+	//   - OP_DATA_65
+	//   - 64 bytes Schnorr signature + 1 byte sighash
+	//   - OP_DATA_65
+	//   - 64 bytes Schnorr signature + 1 byte sighash
+	//   - OP_PUSHDATA
+	//   - OP_2
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	//   - OP3
+	//   - OP_CHECKMULTISIG
+	RedeemP2SH2of3MultisigSchnorrSigScriptSize = 1 + 1 + 65 + 1 + 65 + 1 + 1 + 1 + 33 + 1 + 33 + 1 + 33 + 1 + 1
+
+	// RedeemP2SH1of2MultisigSchnorrSigScriptSize is the serialize size of a transaction
+	// input script that redeems a 1 of 2 P2SH multisig output with a Schnorr signature.
+	// It is calculated as:
+	//
+	//   - OP_N checkbits
+	//   - OP_DATA_65
+	//   - 64 bytes Schnorr signature + 1 byte sighash
+	//   - OP_PUSHDATA
+	//   - OP_1
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	//   - OP_DATA_33
+	//   - 33 bytes serialized compressed pubkey
+	//   - OP2
+	//   - OP_CHECKMULTISIG
+	RedeemP2SH1of2MultisigSchnorrSigScriptSize = 1 + 1 + 65 + 1 + 1 + 1 + 33 + 1 + 33 + 1 + 1
+
+	// RedeemP2SHMultisigTimelock1SchnorrSigScriptSize is the serialize size of a
+	// transaction input script that redeems a P2SH timelocked multisig using the
+	// timeout with a Schnorr signature. It is RedeemP2SHMultisigTimelock1SigScriptSize
+	// with the DER signature replaced by a 64 byte Schnorr signature and sighash byte.
+	RedeemP2SHMultisigTimelock1SchnorrSigScriptSize = RedeemP2SHMultisigTimelock1SigScriptSize - 72 + 65
+
+	// RedeemP2SHMultisigTimelock2SchnorrSigScriptSize is the serialize size of a
+	// transaction input script that redeems a P2SH timelocked multisig without using
+	// the timeout with Schnorr signatures. It is RedeemP2SHMultisigTimelock2SigScriptSize
+	// with both DER signatures replaced by 64 byte Schnorr signatures and sighash bytes.
+	// The OP_0 dummy is replaced by a single byte OP_N checkbits.
+	RedeemP2SHMultisigTimelock2SchnorrSigScriptSize = RedeemP2SHMultisigTimelock2SigScriptSize - 2*72 + 2*65
+
 	// P2PKHPkScriptSize is the size of a transaction output script that
 	// pays to a compressed pubkey hash.  It is calculated as:
 	//
@@ -187,6 +248,37 @@ const (
 	///  - witness discounted signature script
 	RedeemP2SHMultisigTimelock2InputSize = 32 + 4 + 1 + 4 + (RedeemP2SHMultisigTimelock2SigScriptSize / 4)
 
+	// RedeemP2PKHSchnorrInputSize is the serialize size of a transaction input
+	// redeeming a compressed P2PKH output with a Schnorr signature.  It is
+	// calculated as:
+	//
+	//   - 32 bytes previous tx
+	//   - 4 bytes output index
+	//   - 1 byte script len
+	//   - signature script
+	//   - 4 bytes sequence
+	RedeemP2PKHSchnorrInputSize = 32 + 4 + 1 + RedeemP2PKHSchnorrSigScriptSize + 4
+
+	// RedeemP2SH2of3MultisigSchnorrInputSize is the serialize size of a transaction
+	// input redeeming a compressed P2SH 2 of 3 multisig output with Schnorr signatures.
+	// It is calculated the same way as RedeemP2SH2of3MultisigInputSize.
+	RedeemP2SH2of3MultisigSchnorrInputSize = 32 + 4 + 1 + 4 + (RedeemP2SH2of3MultisigSchnorrSigScriptSize / 4)
+
+	// RedeemP2SH1of2MultisigSchnorrInputSize is the serialize size of a transaction
+	// input redeeming a compressed P2SH 1 of 2 multisig output with a Schnorr signature.
+	// It is calculated the same way as RedeemP2SH1of2MultisigInputSize.
+	RedeemP2SH1of2MultisigSchnorrInputSize = 32 + 4 + 1 + 4 + (RedeemP2SH1of2MultisigSchnorrSigScriptSize / 4)
+
+	// RedeemP2SHMultisigTimelock1SchnorrInputSize is the serialize size of a transaction
+	// input redeeming a P2SH timelocked multisig output using the timeout with a Schnorr
+	// signature. It is calculated the same way as RedeemP2SHMultisigTimelock1InputSize.
+	RedeemP2SHMultisigTimelock1SchnorrInputSize = 32 + 4 + 1 + 4 + (RedeemP2SHMultisigTimelock1SchnorrSigScriptSize / 4)
+
+	// RedeemP2SHMultisigTimelock2SchnorrInputSize is the serialize size of a transaction
+	// input redeeming a P2SH timelocked multisig output without using the timeout with
+	// Schnorr signatures. It is calculated the same way as RedeemP2SHMultisigTimelock2InputSize.
+	RedeemP2SHMultisigTimelock2SchnorrInputSize = 32 + 4 + 1 + 4 + (RedeemP2SHMultisigTimelock2SchnorrSigScriptSize / 4)
+
 	// P2PKHOutputSize is the serialize size of a transaction output with a
 	// P2PKH output script.  It is calculated as:
 	//
@@ -204,8 +296,34 @@ const (
 	P2SH_2of3_Multisig
 	P2SH_Multisig_Timelock_1Sig
 	P2SH_Multisig_Timelock_2Sigs
+	P2PKH_Schnorr
+	P2SH_1of2_Multisig_Schnorr
+	P2SH_2of3_Multisig_Schnorr
+	P2SH_Multisig_Timelock_1Sig_Schnorr
+	P2SH_Multisig_Timelock_2Sigs_Schnorr
 )
 
+// WithSignatureType returns the input type for inputs of this kind signed
+// using sigType.
+func (t InputType) WithSignatureType(sigType SignatureType) InputType {
+	if sigType != Schnorr {
+		return t
+	}
+	switch t {
+	case P2PKH:
+		return P2PKH_Schnorr
+	case P2SH_1of2_Multisig:
+		return P2SH_1of2_Multisig_Schnorr
+	case P2SH_2of3_Multisig:
+		return P2SH_2of3_Multisig_Schnorr
+	case P2SH_Multisig_Timelock_1Sig:
+		return P2SH_Multisig_Timelock_1Sig_Schnorr
+	case P2SH_Multisig_Timelock_2Sigs:
+		return P2SH_Multisig_Timelock_2Sigs_Schnorr
+	}
+	return t
+}
+
 // EstimateSerializeSize returns a worst case serialize size estimate for a
 // signed transaction that spends inputCount number of compressed P2PKH outputs
 // and contains each transaction output from txOuts.  The estimated size is
@@ -230,6 +348,16 @@ func EstimateSerializeSize(inputCount int, txOuts []*wire.TxOut, addChangeOutput
 		redeemScriptSize = RedeemP2SHMultisigTimelock1InputSize
 	case P2SH_Multisig_Timelock_2Sigs:
 		redeemScriptSize = RedeemP2SHMultisigTimelock2InputSize
+	case P2PKH_Schnorr:
+		redeemScriptSize = RedeemP2PKHSchnorrInputSize
+	case P2SH_1of2_Multisig_Schnorr:
+		redeemScriptSize = RedeemP2SH1of2MultisigSchnorrInputSize
+	case P2SH_2of3_Multisig_Schnorr:
+		redeemScriptSize = RedeemP2SH2of3MultisigSchnorrInputSize
+	case P2SH_Multisig_Timelock_1Sig_Schnorr:
+		redeemScriptSize = RedeemP2SHMultisigTimelock1SchnorrInputSize
+	case P2SH_Multisig_Timelock_2Sigs_Schnorr:
+		redeemScriptSize = RedeemP2SHMultisigTimelock2SchnorrInputSize
 	}
 
 	// 10 additional bytes are for version, locktime, and segwit flags
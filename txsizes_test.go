@@ -67,6 +67,36 @@ func TestEstimateSerializeSize(t *testing.T) {
 	}
 }
 
+func TestEstimateSerializeSizeSchnorr(t *testing.T) {
+	tests := []struct {
+		InputType   InputType
+		SchnorrType InputType
+		SavedBytes  int
+	}{
+		{P2PKH, P2PKH_Schnorr, 8},
+		{P2SH_1of2_Multisig, P2SH_1of2_Multisig_Schnorr, 2},
+		{P2SH_2of3_Multisig, P2SH_2of3_Multisig_Schnorr, 4},
+		{P2SH_Multisig_Timelock_1Sig, P2SH_Multisig_Timelock_1Sig_Schnorr, 1},
+		{P2SH_Multisig_Timelock_2Sigs, P2SH_Multisig_Timelock_2Sigs_Schnorr, 4},
+	}
+	outputs := []*wire.TxOut{{PkScript: make([]byte, p2pkhScriptSize)}}
+	for i, test := range tests {
+		if test.InputType.WithSignatureType(Schnorr) != test.SchnorrType {
+			t.Errorf("Test %d: incorrect Schnorr input type", i)
+		}
+		if test.InputType.WithSignatureType(ECDSA) != test.InputType {
+			t.Errorf("Test %d: incorrect ECDSA input type", i)
+		}
+		for _, inputCount := range []int{1, 2, 0xfd} {
+			ecdsaEstimate := EstimateSerializeSize(inputCount, outputs, true, test.InputType)
+			schnorrEstimate := EstimateSerializeSize(inputCount, outputs, true, test.SchnorrType)
+			if ecdsaEstimate-schnorrEstimate != inputCount*test.SavedBytes {
+				t.Errorf("Test %d: Got %v: Expected %v", i, ecdsaEstimate-schnorrEstimate, inputCount*test.SavedBytes)
+			}
+		}
+	}
+}
+
 func TestSumOutputSerializeSizes(t *testing.T) {
 	testTx := "0100000001066b78efa7d66d271cae6d6eb799e1d10953fb1a4a760226cc93186d52b55613010000006a47304402204e6c32cc214c496546c3277191ca734494fe49fed0af1d800db92fed2021e61802206a14d063b67f2f1c8fc18f9e9a5963fe33e18c549e56e3045e88b4fc6219be11012103f72d0a11727219bff66b8838c3c5e1c74a5257a325b0c84247bd10bdb9069e88ffffffff0200c2eb0b000000001976a914426e80ad778792e3e19c20977fb93ec0591e1a3988ac35b7cb59000000001976a914e5b6dc0b297acdd99d1a89937474df77db5743c788ac00000000"
 	txBytes, err := hex.DecodeString(testTx)
@@ -6,10 +6,12 @@ package bitcoincash
 import (
 	"bytes"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/bchec"
 	"github.com/gcash/bchd/blockchain"
 	"github.com/gcash/bchd/chaincfg"
 	"github.com/gcash/bchd/chaincfg/chainhash"
@@ -30,10 +32,18 @@ type TxStore struct {
 
 	keyManager *KeyManager
 
+	// Key managers of every BIP44 account, including the default account,
+	// and the account of each address in adrs
+	accounts      map[uint32]*KeyManager
+	adrAccounts   map[string]uint32
+	accountsMutex *sync.RWMutex
+
 	params *chaincfg.Params
 
 	listeners []func(wallet.TransactionCallback)
 
+	events *eventHub
+
 	additionalFilters [][]byte
 
 	wallet.Datastore
@@ -43,6 +53,9 @@ func NewTxStore(p *chaincfg.Params, db wallet.Datastore, keyManager *KeyManager,
 	txs := &TxStore{
 		params:            p,
 		keyManager:        keyManager,
+		accounts:          map[uint32]*KeyManager{keyManager.account: keyManager},
+		adrAccounts:       make(map[string]uint32),
+		accountsMutex:     new(sync.RWMutex),
 		addrMutex:         new(sync.Mutex),
 		cbMutex:           new(sync.Mutex),
 		txidsMutex:        new(sync.RWMutex),
@@ -102,6 +115,44 @@ func (ts *TxStore) GimmeFilter() (*bloom.Filter, error) {
 	return f, nil
 }
 
+// watchList returns the data to match against compact block filters. It covers
+// the same addresses, outpoints and scripts as GimmeFilter. Both the full output
+// scripts and the data they push are included so filters built from either match.
+func (ts *TxStore) watchList() (*cfWatchList, error) {
+	ts.PopulateAdrs()
+
+	allUtxos, err := ts.Utxos().GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	wl := newCFWatchList()
+	ts.addrMutex.Lock()
+	for _, a := range ts.adrs {
+		script, err := txscript.PayToAddrScript(a)
+		if err == nil {
+			wl.entries = append(wl.entries, script)
+		}
+		wl.addPattern(a.ScriptAddress())
+	}
+	ts.addrMutex.Unlock()
+	for _, u := range allUtxos {
+		wl.addOutPoint(u.Op)
+	}
+	for _, w := range ts.watchedScripts {
+		wl.addPattern(w)
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(w, ts.params)
+		if err != nil || len(addrs) == 0 {
+			continue
+		}
+		wl.addPattern(addrs[0].ScriptAddress())
+	}
+	for _, toAdd := range ts.additionalFilters {
+		wl.addPattern(toAdd)
+	}
+	return wl, nil
+}
+
 // GetDoubleSpends takes a transaction and compares it with
 // all transactions in the db.  It returns a slice of all txids in the db
 // which are double spent by the received tx.
@@ -178,17 +229,24 @@ func (ts *TxStore) GetPendingInv() (*wire.MsgInv, error) {
 
 // PopulateAdrs just puts a bunch of adrs in ram; it doesn't touch the DB
 func (ts *TxStore) PopulateAdrs() error {
-	keys := ts.keyManager.GetKeys()
+	keyManagers := ts.keyManagers()
 	ts.addrMutex.Lock()
 	ts.adrs = []bchutil.Address{}
-	for _, k := range keys {
-		addr, err := k.Address(ts.params)
-		if err != nil {
-			continue
+	adrAccounts := make(map[string]uint32)
+	for _, km := range keyManagers {
+		for _, k := range km.GetKeys() {
+			addr, err := k.Address(ts.params)
+			if err != nil {
+				continue
+			}
+			ts.adrs = append(ts.adrs, addr)
+			adrAccounts[string(addr.ScriptAddress())] = km.account
 		}
-		ts.adrs = append(ts.adrs, addr)
 	}
 	ts.addrMutex.Unlock()
+	ts.accountsMutex.Lock()
+	ts.adrAccounts = adrAccounts
+	ts.accountsMutex.Unlock()
 	ts.watchedScripts, _ = ts.WatchedScripts().GetAll()
 	txns, _ := ts.Txns().GetAll(true)
 	ts.txidsMutex.Lock()
@@ -256,15 +314,31 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 	cb := wallet.TransactionCallback{Txid: cachedSha.String(), Height: height}
 	value := int64(0)
 	matchesWatchOnly := false
+
+	// The transaction is recorded with its net value to the wallet and with
+	// its value to each account it spends from or pays.
+	accountValues := make(map[uint32]int64)
+
+	// Our inputs and outputs are saved with the transaction, and the fee if
+	// the values of all the inputs are known.
+	var ourInputs, ourOutputs []wallet.TxnIO
+	fee, feeKnown := int64(0), true
 	for i, txout := range tx.TxOut {
 		// Ignore the error here because the sender could have used and exotic script
 		// for his change and we don't want to fail in that case.
 		addr, _ := scriptToAddress(txout.PkScript, ts.params)
 		out := wallet.TransactionOutput{Address: addr, Value: txout.Value, Index: uint32(i)}
+		if data, ok := opReturnData(txout.PkScript); ok {
+			out.OpReturn = true
+			out.Data = data
+		}
 		for _, script := range PKscripts {
 			if bytes.Equal(txout.PkScript, script) { // new utxo found
 				scriptAddress, _ := ts.extractScriptAddress(txout.PkScript)
-				ts.keyManager.MarkKeyAsUsed(scriptAddress)
+				account := ts.accountForScript(txout.PkScript)
+				if km, ok := ts.accountKeyManager(account); ok {
+					km.MarkKeyAsUsed(scriptAddress)
+				}
 				newop := wire.OutPoint{
 					Hash:  cachedSha,
 					Index: uint32(i),
@@ -277,7 +351,9 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 					WatchOnly:    false,
 				}
 				value += newu.Value
-				ts.Utxos().Put(newu)
+				accountValues[account] += newu.Value
+				ourOutputs = append(ourOutputs, wallet.TxnIO{Index: uint32(i), Address: encodeAddress(addr), Value: txout.Value})
+				ts.accountDatastore(account).Utxos().Put(newu)
 				hits++
 				break
 			}
@@ -307,12 +383,13 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 			}
 		}
 		cb.Outputs = append(cb.Outputs, out)
+		fee -= txout.Value
 	}
 	utxos, err := ts.Utxos().GetAll()
 	if err != nil {
 		return 0, err
 	}
-	for _, txin := range tx.TxIn {
+	for n, txin := range tx.TxIn {
 		_bcutxo := false
 		for i, u := range utxos {
 			if outPointsEqual(txin.PreviousOutPoint, u.Op) {
@@ -325,16 +402,20 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 				ts.Stxos().Put(st)
 				ts.Utxos().Delete(u)
 				utxos = append(utxos[:i], utxos[i+1:]...)
+				// Ignore the error here because the sender could have used and exotic script
+				// for his change and we don't want to fail in that case.
+				addr, _ := scriptToAddress(u.ScriptPubkey, ts.params)
+
 				if !u.WatchOnly {
 					value -= u.Value
+					ourInputs = append(ourInputs, wallet.TxnIO{Index: uint32(n), Address: encodeAddress(addr), Value: u.Value})
+					account := ts.accountForScript(u.ScriptPubkey)
+					accountValues[account] -= u.Value
 					hits++
 				} else {
 					matchesWatchOnly = true
 				}
-
-				// Ignore the error here because the sender could have used and exotic script
-				// for his change and we don't want to fail in that case.
-				addr, _ := scriptToAddress(u.ScriptPubkey, ts.params)
+				fee += u.Value
 
 				in := wallet.TransactionInput{
 					OutpointHash:  u.Op.Hash.CloneBytes(),
@@ -347,12 +428,11 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 			}
 		}
 		if !_bcutxo {
-			in := wallet.TransactionInput{
-				OutpointHash:  txin.PreviousOutPoint.Hash.CloneBytes(),
-				OutpointIndex: txin.PreviousOutPoint.Index,
-				LinkedAddress: nil,
-				Value:         -1,
+			in := ts.foreignInput(txin)
+			if in.Value < 0 {
+				feeKnown = false
 			}
+			fee += in.Value
 			cb.Inputs = append(cb.Inputs, in)
 		}
 	}
@@ -381,14 +461,28 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 	if hits > 0 || matchesWatchOnly {
 		ts.txidsMutex.Lock()
 		txn, err := ts.Txns().Get(tx.TxHash())
-		shouldCallback := false
+		shouldCallback, isNew := false, false
 		if err != nil {
+			isNew = true
 			cb.Value = value
 			txn.Timestamp = timestamp
 			shouldCallback = true
 			var buf bytes.Buffer
 			tx.BchEncode(&buf, 1, wire.BaseEncoding)
-			ts.Txns().Put(buf.Bytes(), tx.TxHash().String(), int(value), int(height), txn.Timestamp, hits == 0)
+			txns := ts.Txns()
+			txns.Put(buf.Bytes(), tx.TxHash().String(), int(value), int(height), txn.Timestamp, hits == 0)
+			if _, ok := ts.Datastore.(accountStore); ok {
+				for account, accountValue := range accountValues {
+					ts.accountDatastore(account).Txns().Put(buf.Bytes(), tx.TxHash().String(), int(accountValue), int(height), txn.Timestamp, false)
+				}
+			}
+			if details, ok := txns.(wallet.TxnDetails); ok {
+				if !feeKnown {
+					fee = 0
+				}
+				details.PutDetails(cachedSha, fee, ourInputs, ourOutputs)
+			}
+			ts.recordInvoicePayments(cachedSha, ourOutputs)
 			ts.txids[tx.TxHash().String()] = height
 		}
 		// Let's check the height before committing so we don't allow rogue peers to send us a lose
@@ -404,16 +498,21 @@ func (ts *TxStore) Ingest(tx *wire.MsgTx, height int32, timestamp time.Time) (ui
 		cb.BlockTime = timestamp
 		ts.txidsMutex.Unlock()
 		if shouldCallback {
-			ts.cbMutex.Lock()
 			go func() {
 				// Callback on listeners
+				ts.cbMutex.Lock()
 				for _, listener := range ts.listeners {
 					if listener != nil {
 						listener(cb)
 					}
 				}
+				ts.cbMutex.Unlock()
 			}()
-			ts.cbMutex.Unlock()
+			event := Event{Type: EventConfirmation, Transaction: &cb}
+			if isNew {
+				event.Type = EventNewTransaction
+			}
+			ts.events.publish(event)
 		}
 		ts.PopulateAdrs()
 		hits++
@@ -455,7 +554,7 @@ func (ts *TxStore) markAsDead(txid chainhash.Hash) error {
 			if err := markStxoAsDead(s); err != nil {
 				return err
 			}
-			if err := ts.Utxos().Put(s.Utxo); err != nil {
+			if err := ts.accountDatastore(ts.accountForScript(s.Utxo.ScriptPubkey)).Utxos().Put(s.Utxo); err != nil {
 				return err
 			}
 		}
@@ -508,6 +607,54 @@ func (ts *TxStore) processReorg(lastGoodHeight uint32) error {
 	return nil
 }
 
+// addAccount starts tracking the keys of another BIP44 account.
+func (ts *TxStore) addAccount(km *KeyManager) {
+	ts.accountsMutex.Lock()
+	ts.accounts[km.account] = km
+	ts.accountsMutex.Unlock()
+	ts.PopulateAdrs()
+}
+
+func (ts *TxStore) accountKeyManager(account uint32) (*KeyManager, bool) {
+	ts.accountsMutex.RLock()
+	defer ts.accountsMutex.RUnlock()
+	km, ok := ts.accounts[account]
+	return km, ok
+}
+
+// keyManagers returns the key managers of all accounts ordered by account.
+func (ts *TxStore) keyManagers() []*KeyManager {
+	ts.accountsMutex.RLock()
+	defer ts.accountsMutex.RUnlock()
+	kms := make([]*KeyManager, 0, len(ts.accounts))
+	for _, km := range ts.accounts {
+		kms = append(kms, km)
+	}
+	sort.Slice(kms, func(i, j int) bool { return kms[i].account < kms[j].account })
+	return kms
+}
+
+// accountForScript returns the account of the key an output script pays. Scripts
+// which don't pay a wallet key belong to the default account.
+func (ts *TxStore) accountForScript(script []byte) uint32 {
+	scriptAddress, err := ts.extractScriptAddress(script)
+	if err != nil {
+		return DefaultAccount
+	}
+	ts.accountsMutex.RLock()
+	defer ts.accountsMutex.RUnlock()
+	return ts.adrAccounts[string(scriptAddress)]
+}
+
+// accountDatastore returns the datastore restricted to one account. Datastores
+// without support for accounts only hold the default account.
+func (ts *TxStore) accountDatastore(account uint32) wallet.Datastore {
+	if store, ok := ts.Datastore.(accountStore); ok {
+		return store.Account(account)
+	}
+	return ts.Datastore
+}
+
 func (ts *TxStore) extractScriptAddress(script []byte) ([]byte, error) {
 	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, ts.params)
 	if err != nil {
@@ -519,6 +666,54 @@ func (ts *TxStore) extractScriptAddress(script []byte) ([]byte, error) {
 	return addrs[0].ScriptAddress(), nil
 }
 
+// foreignInput returns the callback input for txin when it doesn't spend one
+// of our utxos. Its value is -1 unless it spends an output of one of our
+// transactions. Otherwise the address is only known for P2PKH inputs.
+func (ts *TxStore) foreignInput(txin *wire.TxIn) wallet.TransactionInput {
+	in := wallet.TransactionInput{
+		OutpointHash:  txin.PreviousOutPoint.Hash.CloneBytes(),
+		OutpointIndex: txin.PreviousOutPoint.Index,
+		Value:         -1,
+	}
+	if prev, err := ts.Txns().Get(txin.PreviousOutPoint.Hash); err == nil {
+		prevTx := wire.NewMsgTx(1)
+		err := prevTx.BchDecode(bytes.NewReader(prev.Bytes), 1, wire.BaseEncoding)
+		if err == nil && int(txin.PreviousOutPoint.Index) < len(prevTx.TxOut) {
+			out := prevTx.TxOut[txin.PreviousOutPoint.Index]
+			in.Value = out.Value
+			in.LinkedAddress, _ = scriptToAddress(out.PkScript, ts.params)
+			return in
+		}
+	}
+	in.LinkedAddress = sigScriptAddress(txin.SignatureScript, ts.params)
+	return in
+}
+
+// sigScriptAddress returns the address a P2PKH signature script spends from,
+// derived from its public key, or nil for other scripts.
+func sigScriptAddress(sigScript []byte, params *chaincfg.Params) bchutil.Address {
+	pushes, err := txscript.PushedData(sigScript)
+	if err != nil || len(pushes) != 2 {
+		return nil
+	}
+	if _, err := bchec.ParsePubKey(pushes[1], bchec.S256()); err != nil {
+		return nil
+	}
+	addr, err := bchutil.NewAddressPubKeyHash(bchutil.Hash160(pushes[1]), params)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+// encodeAddress returns the encoding of addr, an empty string if it is nil.
+func encodeAddress(addr bchutil.Address) string {
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
 func outPointsEqual(a, b wire.OutPoint) bool {
 	if !a.Hash.IsEqual(&b.Hash) {
 		return false
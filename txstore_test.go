@@ -15,6 +15,13 @@ import (
 )
 
 func createTxStore() (*TxStore, error) {
+	seed := make([]byte, 32)
+	rand.Read(seed)
+	key, _ := hdkeychain.NewMaster(seed, &chaincfg.TestNet3Params)
+	return createTxStoreWithKey(key)
+}
+
+func createTxStoreWithKey(key *hdkeychain.ExtendedKey) (*TxStore, error) {
 	mockDb := MockDatastore{
 		&mockKeyStore{make(map[string]*keyStoreEntry)},
 		&mockUtxoStore{make(map[string]*wallet.Utxo)},
@@ -22,9 +29,6 @@ func createTxStore() (*TxStore, error) {
 		&mockTxnStore{make(map[string]*txnStoreEntry)},
 		&mockWatchedScriptsStore{make(map[string][]byte)},
 	}
-	seed := make([]byte, 32)
-	rand.Read(seed)
-	key, _ := hdkeychain.NewMaster(seed, &chaincfg.TestNet3Params)
 	km, _ := NewKeyManager(mockDb.Keys(), &chaincfg.TestNet3Params, key)
 	return NewTxStore(&chaincfg.TestNet3Params, &mockDb, km)
 }
@@ -134,6 +134,14 @@ type Txns interface {
 	Delete(txid *chainhash.Hash) error
 }
 
+// TxnDetails is implemented by Txns which also record the fee of a transaction
+// and which of its inputs and outputs are the wallet's. They are returned in
+// the Fee, Inputs and Outputs of a Txn.
+type TxnDetails interface {
+	// Put the fee, zero if unknown, and the wallet's inputs and outputs of a transaction
+	PutDetails(txid chainhash.Hash, fee int64, inputs []TxnIO, outputs []TxnIO) error
+}
+
 // Keys provides a database interface for the wallet to save key material, track
 // used keys, and manage the look ahead window.
 type Keys interface {
@@ -181,6 +189,148 @@ type WatchedScripts interface {
 	Delete(scriptPubKey []byte) error
 }
 
+// MetadataDatastore is implemented by datastores which can also save the
+// user's labels and memos.
+type MetadataDatastore interface {
+	Datastore
+	AddressLabels() AddressLabels
+	TxMemos() TxMemos
+}
+
+type AddressLabels interface {
+	// Put or replace the label of an encoded address
+	Put(address string, label string) error
+
+	// Fetch the label of an address, an empty string if it has none
+	Get(address string) (string, error)
+
+	// Fetch all labels keyed by address
+	GetAll() (map[string]string, error)
+
+	// Delete the label of an address
+	Delete(address string) error
+}
+
+type TxMemos interface {
+	// Put or replace the memo of a transaction
+	Put(txid chainhash.Hash, memo string) error
+
+	// Fetch the memo of a transaction, an empty string if it has none
+	Get(txid chainhash.Hash) (string, error)
+
+	// Fetch all memos keyed by txid
+	GetAll() (map[chainhash.Hash]string, error)
+
+	// Delete the memo of a transaction
+	Delete(txid chainhash.Hash) error
+}
+
+// HistoricalRatesDatastore is implemented by datastores which can cache past
+// exchange rates so they needn't be fetched again.
+type HistoricalRatesDatastore interface {
+	Datastore
+	HistoricalRates() HistoricalRates
+}
+
+type HistoricalRates interface {
+	// Put the exchange rate of a currency on a day
+	Put(currencyCode string, day time.Time, rate float64) error
+
+	// Fetch the exchange rate of a currency on a day, zero if it isn't cached
+	Get(currencyCode string, day time.Time) (float64, error)
+}
+
+// WebhookDatastore is implemented by datastores which can keep the queue of
+// webhooks waiting to be delivered, so they survive a restart.
+type WebhookDatastore interface {
+	Datastore
+	Webhooks() Webhooks
+}
+
+type Webhooks interface {
+	// Queue an event unless an event with the same ID was ever queued.
+	// Returns whether it was queued.
+	Put(event WebhookEvent) (bool, error)
+
+	// Returns whether an event with the ID was ever queued
+	Has(id string) (bool, error)
+
+	// Returns the events which are not done, earliest next attempt first
+	GetPending() ([]WebhookEvent, error)
+
+	// Update the attempts, next attempt and done flag of an event
+	Update(event WebhookEvent) error
+}
+
+// InvoiceDatastore is implemented by datastores which can save invoices and
+// the payments made to them.
+type InvoiceDatastore interface {
+	Datastore
+	Invoices() Invoices
+}
+
+type Invoices interface {
+	// Put an invoice. Its payments are not saved.
+	Put(invoice Invoice) error
+
+	// Fetch an invoice with its payments
+	Get(id string) (Invoice, error)
+
+	// Fetch every invoice with its payments, newest first
+	GetAll() ([]Invoice, error)
+
+	// Record a payment to an address if it is the address of an invoice.
+	// Returns whether it was.
+	AddPayment(address string, payment InvoicePayment) (bool, error)
+}
+
+type Invoice struct {
+	ID string
+
+	// The encoded address the invoice is paid to
+	Address string
+
+	// The amount due in satoshis
+	Amount int64
+
+	// The fiat amount the invoice was created for and the exchange rate the
+	// amount due was locked at. Zero if it was created in satoshis.
+	FiatAmount   float64
+	FiatCurrency string
+	ExchangeRate float64
+
+	Memo    string
+	Created time.Time
+
+	// When the invoice stops accepting payments, zero if it never does
+	Expires time.Time
+
+	Payments []InvoicePayment
+}
+
+// InvoicePayment is an output of a transaction paying an invoice. Its height
+// is that of the transaction.
+type InvoicePayment struct {
+	Txid  string
+	Index uint32
+	Value int64
+}
+
+type WebhookEvent struct {
+	// A unique ID, which is also sent to the receiver
+	ID string
+
+	// The JSON body of the webhook
+	Payload []byte
+
+	// The number of failed deliveries and the time of the next one
+	Attempts    int
+	NextAttempt time.Time
+
+	// Set once the event is delivered or given up on
+	Done bool
+}
+
 type Utxo struct {
 	// Previous txid and output index
 	Op wire.OutPoint
@@ -289,6 +439,33 @@ type Txn struct {
 
 	// Raw transaction bytes
 	Bytes []byte
+
+	// The OP_RETURN outputs of the transaction. Like confirmations, these are
+	// decoded from Bytes when the Transactions() method is called.
+	DataOutputs []TransactionOutput
+
+	// The user's memo for the transaction, saved separately in a
+	// MetadataDatastore and added when the Transactions() method is called.
+	Memo string
+
+	// The fee of the transaction, zero if the value of one of its inputs is unknown
+	Fee int64
+
+	// The inputs and outputs of the transaction which are the wallet's. Like the
+	// fee these are only saved by Txns implementing TxnDetails.
+	Inputs  []TxnIO
+	Outputs []TxnIO
+}
+
+// TxnIO is an input or output of a transaction which belongs to the wallet.
+type TxnIO struct {
+	// The index of the input or output in the transaction
+	Index uint32
+
+	// The encoded address of the input or output
+	Address string
+
+	Value int64
 }
 
 type StatusCode string
@@ -1,5 +1,7 @@
 package wallet
 
+import "time"
+
 type ExchangeRates interface {
 
 	/* Fetch the exchange rate for the given currency
@@ -17,3 +19,11 @@ type ExchangeRates interface {
 	   to the smaller currency unit. */
 	UnitsPerCoin() int
 }
+
+// HistoricalExchangeRates is implemented by exchange rate providers which can
+// also look up past prices.
+type HistoricalExchangeRates interface {
+
+	// Fetch the exchange rate for the given currency on the (UTC) day of t
+	GetHistoricalRate(currencyCode string, t time.Time) (float64, error)
+}
@@ -327,6 +327,11 @@ type TransactionOutput struct {
 	Value   int64
 	Index   uint32
 	OrderID string
+
+	// OpReturn is set for an OP_RETURN data carrier output. It has no
+	// Address and Data holds each of its data pushes.
+	OpReturn bool
+	Data     [][]byte
 }
 
 type TransactionInput struct {
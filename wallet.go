@@ -1,29 +1,31 @@
 package bitcoincash
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"os/signal"
-	"strconv"
 	"sync"
 	"time"
 
 	"github.com/BubbaJoe/spvwallet-cash/exchangerates"
+	"github.com/BubbaJoe/spvwallet-cash/paymentprotocol"
 	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
-	db "github.com/bubbajoe/spvwallet-cash/db"
 	"github.com/gcash/bchd/bchec"
 	"github.com/gcash/bchd/chaincfg"
 	"github.com/gcash/bchd/chaincfg/chainhash"
 	"github.com/gcash/bchd/peer"
 	"github.com/gcash/bchd/txscript"
+	"github.com/gcash/bchd/wire"
 	"github.com/gcash/bchutil"
+	"github.com/gcash/bchutil/base58"
 	hd "github.com/gcash/bchutil/hdkeychain"
 	"github.com/gcash/bchwallet/wallet/txrules"
 	"github.com/op/go-logging"
 	b39 "github.com/tyler-smith/go-bip39"
+	"golang.org/x/net/proxy"
 )
 
 type SPVWallet struct {
@@ -32,10 +34,19 @@ type SPVWallet struct {
 	masterPrivateKey *hd.ExtendedKey
 	masterPublicKey  *hd.ExtendedKey
 
-	mnemonic string
+	mnemonic           string
+	mnemonicPassphrase string
+
+	watchOnly bool
+	// The BIP44 account index of the watch-only account key
+	watchOnlyAccount uint32
+
+	secrets secretStore
 
 	feeProvider *FeeProvider
 
+	sigType SignatureType
+
 	repoPath string
 
 	blockchain  *Blockchain
@@ -56,6 +67,13 @@ type SPVWallet struct {
 	config *PeerManagerConfig
 
 	exchangeRates wallet.ExchangeRates
+
+	paymentClient *paymentprotocol.Client
+
+	// The dialer HTTP requests are made through, nil to dial directly
+	proxy proxy.Dialer
+
+	events *eventHub
 }
 
 var log = logging.MustGetLogger("bitcoin")
@@ -65,66 +83,128 @@ const WALLET_VERSION = "0.4.0"
 func NewSPVWallet(config *Config) (*SPVWallet, error) {
 	log.SetBackend(logging.AddModuleLevel(config.Logger))
 
-	if config.Mnemonic == "" {
-		ent, err := b39.NewEntropy(128)
+	secrets, err := openSecretStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var mPrivKey, mPubKey, account *hd.ExtendedKey
+	var watchOnlyAccount uint32
+	if config.ExtendedPublicKey != "" {
+		// Watch-only. The account key is the root of everything we derive so
+		// it stands in for the master public key.
+		if config.Mnemonic != "" {
+			return nil, errors.New("A watch-only wallet cannot have a mnemonic")
+		}
+		account, watchOnlyAccount, err = parseWatchOnlyKey(secrets, config.ExtendedPublicKey, config.Params)
 		if err != nil {
 			return nil, err
 		}
-		mnemonic, err := b39.NewMnemonic(ent)
+		mPubKey = account
+	} else if secrets.IsEncrypted() {
+		// The wallet starts locked and derives its addresses from the
+		// public keys until it is unlocked.
+		if config.Mnemonic != "" {
+			return nil, errors.New("Cannot replace the mnemonic of an encrypted wallet")
+		}
+		mPubKey, account, err = loadPublicKeys(secrets)
 		if err != nil {
 			return nil, err
 		}
-		config.Mnemonic = mnemonic
-		config.CreationDate = time.Now()
-	}
-	seed := b39.NewSeed(config.Mnemonic, "")
+	} else {
+		if config.Mnemonic == "" {
+			ent, err := b39.NewEntropy(128)
+			if err != nil {
+				return nil, err
+			}
+			mnemonic, err := b39.NewMnemonic(ent)
+			if err != nil {
+				return nil, err
+			}
+			config.Mnemonic = mnemonic
+			config.CreationDate = time.Now()
+		}
+		seed := b39.NewSeed(config.Mnemonic, config.MnemonicPassphrase)
 
-	mPrivKey, err := hd.NewMaster(seed, config.Params)
-	if err != nil {
-		return nil, err
-	}
-	mPubKey, err := mPrivKey.Neuter()
-	if err != nil {
-		return nil, err
-	}
+		mPrivKey, err = hd.NewMaster(seed, config.Params)
+		if err != nil {
+			return nil, err
+		}
+		mPubKey, err = mPrivKey.Neuter()
+		if err != nil {
+			return nil, err
+		}
+		account, err = bip44Account(mPrivKey, DefaultAccount)
+		if err != nil {
+			return nil, err
+		}
 
-	err = saveConfig(config)
-	if err != nil {
-		return nil, err
+		err = saveConfig(secrets, config, mPubKey, account)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	w := &SPVWallet{
-		repoPath:         config.RepoPath,
-		masterPrivateKey: mPrivKey,
-		masterPublicKey:  mPubKey,
-		mnemonic:         config.Mnemonic,
-		params:           config.Params,
-		creationDate:     config.CreationDate,
-		feeProvider:      NewFeeProvider(3, 2, 1, 1, nil),
-		fPositives:       make(chan *peer.Peer),
-		stopChan:         make(chan int),
-		fpAccumulator:    make(map[int32]int32),
-		mutex:            new(sync.RWMutex),
+		repoPath:           config.RepoPath,
+		masterPrivateKey:   mPrivKey,
+		masterPublicKey:    mPubKey,
+		mnemonic:           config.Mnemonic,
+		mnemonicPassphrase: config.MnemonicPassphrase,
+		watchOnly:          config.ExtendedPublicKey != "",
+		watchOnlyAccount:   watchOnlyAccount,
+		secrets:            secrets,
+		params:             config.Params,
+		creationDate:       config.CreationDate,
+		sigType:            config.SignatureType,
+		fPositives:         make(chan *peer.Peer),
+		stopChan:           make(chan int),
+		fpAccumulator:      make(map[int32]int32),
+		mutex:              new(sync.RWMutex),
+		paymentClient:      paymentprotocol.NewClient(config.Proxy),
+		proxy:              config.Proxy,
+	}
+
+	if w.sigType == DefaultSignatureType {
+		w.sigType = ECDSA
 	}
 
 	er := exchangerates.NewBitcoinCashPriceFetcher(config.Proxy)
 	w.exchangeRates = er
 	if !config.DisableExchangeRates {
 		go er.Run()
-		w.feeProvider.exchangeRates = er
 	}
 
-	w.keyManager, err = NewKeyManager(config.DB.Keys(), w.params, w.masterPrivateKey)
+	feeSource := config.FeeSource
+	if feeSource == nil {
+		if config.FeeAPI.String() != "" {
+			feeSource = NewHTTPFeeSource(config.FeeAPI.String(), config.Proxy)
+		} else if !config.DisableExchangeRates {
+			feeSource = NewFiatFeeSource(er)
+		}
+	}
+	w.feeProvider = NewFeeProvider(config.MaxFee, config.HighFee, config.MediumFee, config.LowFee, feeSource)
+
+	w.keyManager, err = newKeyManager(config.DB.Keys(), w.params, DefaultAccount, account)
+	if err != nil {
+		return nil, err
+	}
 
 	w.txstore, err = NewTxStore(w.params, config.DB, w.keyManager, config.AdditionalFilters...)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := w.loadAccounts(config.DB); err != nil {
+		return nil, err
+	}
+
 	w.blockchain, err = NewBlockchain(w.repoPath, w.creationDate, w.params)
 	if err != nil {
 		return nil, err
 	}
+	w.events = newEventHub(w.Balance)
+	w.txstore.events = w.events
 
 	minSync := 5
 	if config.TrustedPeer != nil {
@@ -136,6 +216,11 @@ func NewSPVWallet(config *Config) (*SPVWallet, error) {
 		walletCreationDate: w.creationDate,
 		minPeersForSync:    minSync,
 		params:             w.params,
+		syncMode:           config.SyncMode,
+		events:             w.events,
+		banPeer: func(p *peer.Peer) {
+			w.peerManager.BanPeer(p)
+		},
 	}
 
 	ws := NewWireService(wireConfig)
@@ -164,6 +249,10 @@ func NewSPVWallet(config *Config) (*SPVWallet, error) {
 		w.config.TrustedPeer = config.TrustedPeer
 	}
 
+	if config.SyncMode == CompactFilterSync {
+		w.config.RequiredServices = wire.SFNodeCF | wire.SFNodeNetwork | wire.SFNodeBitcoinCash
+	}
+
 	w.peerManager, err = NewPeerManager(w.config)
 	if err != nil {
 		return nil, err
@@ -172,19 +261,75 @@ func NewSPVWallet(config *Config) (*SPVWallet, error) {
 	return w, nil
 }
 
-func saveConfig(config *Config) error {
-	ds, err := db.Create(config.RepoPath)
+// ErrWatchOnly is returned by operations which need private keys when the
+// wallet was created from an extended public key.
+var ErrWatchOnly = errors.New("Wallet is watch-only")
+
+// parseWatchOnlyKey parses the account extended public key of a watch-only
+// wallet and returns it with its BIP44 account index. The key is saved in the
+// datastore, or checked against the wallet saved there if there is one.
+func parseWatchOnlyKey(ds secretStore, xpub string, params *chaincfg.Params) (*hd.ExtendedKey, uint32, error) {
+	account, err := hd.NewKeyFromString(xpub)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
-	mnem, err := ds.GetMnemonic()
+	if account.IsPrivate() {
+		return nil, 0, errors.New("Watch-only wallets require an extended public key")
+	}
+	if !account.IsForNet(params) {
+		return nil, 0, errors.New("Extended public key is for the wrong network")
+	}
+	// m / purpose' / coin_type' / account'
+	if account.Depth() != 3 {
+		return nil, 0, errors.New("Extended public key is not a BIP44 account key")
+	}
+	// The key was just parsed so the child number at bytes 9-13 is there
+	childNum := binary.BigEndian.Uint32(base58.Decode(xpub)[9:13])
+	if childNum < hd.HardenedKeyStart {
+		return nil, 0, errors.New("Extended public key is not a BIP44 account key")
+	}
+	_, stored, err := ds.GetPublicKeys()
 	if err != nil {
-		ds.SetConfigKV("archived_mnemonic_"+strconv.Itoa(rand.Int()), mnem)
+		// The account key stands in for the master public key
+		if err := ds.SetPublicKeys(account.String(), account.String()); err != nil {
+			return nil, 0, err
+		}
+	} else if stored != account.String() {
+		return nil, 0, errors.New("Extended public key does not match the wallet in the datastore")
+	}
+	return account, childNum - hd.HardenedKeyStart, nil
+}
+
+// ErrMnemonicPassphrase is returned when the wallet is started with its
+// mnemonic but a different Bip39 passphrase than it was created with.
+var ErrMnemonicPassphrase = errors.New("Mnemonic passphrase does not match the wallet")
+
+// saveConfig stores the mnemonic and the public keys derived from it. The
+// mnemonic passphrase is never saved.
+func saveConfig(ds secretStore, config *Config, masterPublicKey, account *hd.ExtendedKey) error {
+	mnem, err := ds.GetMnemonic()
+	if err == nil && mnem != "" && mnem != config.Mnemonic {
+		if err := ds.ArchiveMnemonic(mnem); err != nil {
+			return err
+		}
+	} else if err == nil && mnem == config.Mnemonic {
+		// The same mnemonic with another passphrase is a different wallet
+		storedMasterPublicKey, _, err := ds.GetPublicKeys()
+		if err == nil && storedMasterPublicKey != masterPublicKey.String() {
+			return ErrMnemonicPassphrase
+		}
 	}
 	err = ds.SetMnemonic(config.Mnemonic)
 	if err != nil {
 		return err
 	}
+	accountPub, err := account.Neuter()
+	if err != nil {
+		return err
+	}
+	if err := ds.SetPublicKeys(masterPublicKey.String(), accountPub.String()); err != nil {
+		return err
+	}
 	ds.SetCreationDate(config.CreationDate)
 	return nil
 }
@@ -225,10 +370,19 @@ func (w *SPVWallet) IsDust(amount int64) bool {
 	return txrules.IsDustAmount(bchutil.Amount(amount), 25, txrules.DefaultRelayFeePerKb)
 }
 
-func (w *SPVWallet) MasterPrivateKey() *hd.ExtendedKey {
-	return w.masterPrivateKey
+// MasterPrivateKey returns ErrWalletLocked while the wallet is locked and
+// ErrWatchOnly if the wallet is watch-only.
+func (w *SPVWallet) MasterPrivateKey() (*hd.ExtendedKey, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if err := w.privateKeyError(); err != nil {
+		return nil, err
+	}
+	return w.masterPrivateKey, nil
 }
 
+// MasterPublicKey returns the account extended public key if the wallet is
+// watch-only.
 func (w *SPVWallet) MasterPublicKey() *hd.ExtendedKey {
 	return w.masterPublicKey
 }
@@ -248,8 +402,15 @@ func (w *SPVWallet) ChildKey(keyBytes []byte, chaincode []byte, isPrivateKey boo
 	return hdKey.Child(0)
 }
 
-func (w *SPVWallet) Mnemonic() string {
-	return w.mnemonic
+// Mnemonic returns ErrWalletLocked while the wallet is locked and ErrWatchOnly
+// if the wallet is watch-only.
+func (w *SPVWallet) Mnemonic() (string, error) {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if err := w.privateKeyError(); err != nil {
+		return "", err
+	}
+	return w.mnemonic, nil
 }
 
 func (w *SPVWallet) ConnectedPeers() []*peer.Peer {
@@ -311,7 +472,7 @@ func (w *SPVWallet) AddressToScript(addr bchutil.Address) ([]byte, error) {
 }
 
 func (w *SPVWallet) HasKey(addr bchutil.Address) bool {
-	_, err := w.keyManager.GetKeyForScript(addr.ScriptAddress())
+	_, err := w.keyForScript(addr.ScriptAddress())
 	if err != nil {
 		return false
 	}
@@ -319,7 +480,10 @@ func (w *SPVWallet) HasKey(addr bchutil.Address) bool {
 }
 
 func (w *SPVWallet) GetKey(addr bchutil.Address) (*bchec.PrivateKey, error) {
-	key, err := w.keyManager.GetKeyForScript(addr.ScriptAddress())
+	if err := w.checkUnlocked(); err != nil {
+		return nil, err
+	}
+	key, err := w.keyForScript(addr.ScriptAddress())
 	if err != nil {
 		return nil, err
 	}
@@ -327,9 +491,8 @@ func (w *SPVWallet) GetKey(addr bchutil.Address) (*bchec.PrivateKey, error) {
 }
 
 func (w *SPVWallet) ListAddresses() []bchutil.Address {
-	keys := w.keyManager.GetKeys()
 	addrs := []bchutil.Address{}
-	for _, k := range keys {
+	for _, k := range w.allKeys() {
 		addr, err := k.Address(w.params)
 		if err != nil {
 			continue
@@ -343,20 +506,25 @@ func (w *SPVWallet) ListAddresses() []bchutil.Address {
 	return addrs
 }
 
-func (w *SPVWallet) ListKeys() []bchec.PrivateKey {
-	keys := w.keyManager.GetKeys()
+func (w *SPVWallet) ListKeys() ([]bchec.PrivateKey, error) {
+	if err := w.checkUnlocked(); err != nil {
+		return nil, err
+	}
 	list := []bchec.PrivateKey{}
-	for _, k := range keys {
+	for _, k := range w.allKeys() {
 		priv, err := k.ECPrivKey()
 		if err != nil {
 			continue
 		}
 		list = append(list, *priv)
 	}
-	return list
+	return list, nil
 }
 
 func (w *SPVWallet) ImportKey(privKey *bchec.PrivateKey, compress bool) error {
+	if err := w.checkUnlocked(); err != nil {
+		return err
+	}
 	pub := privKey.PubKey()
 	var pubKeyBytes []byte
 	if compress {
@@ -372,11 +540,28 @@ func (w *SPVWallet) ImportKey(privKey *bchec.PrivateKey, compress bool) error {
 	return w.keyManager.datastore.ImportKey(addr.ScriptAddress(), privKey)
 }
 
+// allKeys returns the keys of every account.
+func (w *SPVWallet) allKeys() []*hd.ExtendedKey {
+	var keys []*hd.ExtendedKey
+	for _, km := range w.txstore.keyManagers() {
+		keys = append(keys, km.GetKeys()...)
+	}
+	return keys
+}
+
+// Balance returns the confirmed and unconfirmed value of the wallet. Locked
+// outputs are reported by LockedBalance instead.
 func (w *SPVWallet) Balance() (confirmed, unconfirmed int64) {
 	utxos, _ := w.txstore.Utxos().GetAll()
+	return w.balance(utxos)
+}
+
+// balance sums the value of utxos which aren't watch-only or locked.
+func (w *SPVWallet) balance(utxos []wallet.Utxo) (confirmed, unconfirmed int64) {
 	stxos, _ := w.txstore.Stxos().GetAll()
+	locked := w.lockedUtxos()
 	for _, utxo := range utxos {
-		if !utxo.WatchOnly {
+		if !utxo.WatchOnly && !locked[utxo.Op] {
 			confs, _, err := w.GetConfirmations(utxo.Op.Hash)
 			if err != nil {
 				continue
@@ -398,11 +583,17 @@ func (w *SPVWallet) Balance() (confirmed, unconfirmed int64) {
 }
 
 func (w *SPVWallet) Transactions() ([]wallet.Txn, error) {
-	height, _ := w.ChainTip()
 	txns, err := w.txstore.Txns().GetAll(false)
 	if err != nil {
 		return txns, err
 	}
+	return w.setTxnStatus(txns), nil
+}
+
+// setTxnStatus fills in the confirmations, status and memos of txns.
+func (w *SPVWallet) setTxnStatus(txns []wallet.Txn) []wallet.Txn {
+	height, _ := w.ChainTip()
+	memos := w.txMemos()
 	for i, tx := range txns {
 		var confirmations int32
 		var status wallet.StatusCode
@@ -426,13 +617,21 @@ func (w *SPVWallet) Transactions() ([]wallet.Txn, error) {
 		}
 		tx.Confirmations = int64(confirmations)
 		tx.Status = status
+		tx.DataOutputs = txDataOutputs(tx.Bytes)
+		if txid, err := chainhash.NewHashFromStr(tx.Txid); err == nil {
+			tx.Memo = memos[*txid]
+		}
 		txns[i] = tx
 	}
-	return txns, nil
+	return txns
 }
 
 func (w *SPVWallet) GetTransaction(txid chainhash.Hash) (wallet.Txn, error) {
 	txn, err := w.txstore.Txns().Get(txid)
+	if err == nil {
+		txn.DataOutputs = txDataOutputs(txn.Bytes)
+		txn.Memo = w.txMemo(txid)
+	}
 	return txn, err
 }
 
@@ -474,12 +673,16 @@ func (w *SPVWallet) Params() *chaincfg.Params {
 }
 
 func (w *SPVWallet) AddTransactionListener(everyTx bool, callback func(wallet.TransactionCallback)) int {
+	w.txstore.cbMutex.Lock()
+	defer w.txstore.cbMutex.Unlock()
 	w.txstore.showEveryTx[len(w.txstore.listeners)] = everyTx
 	w.txstore.listeners = append(w.txstore.listeners, callback)
 	return len(w.txstore.listeners) - 1
 }
 
 func (w *SPVWallet) RemoveTransactionListener(cbId int) error {
+	w.txstore.cbMutex.Lock()
+	defer w.txstore.cbMutex.Unlock()
 	if _, ok := w.txstore.showEveryTx[cbId]; !ok {
 		return errors.New("invalid transaction listener id")
 	}
@@ -489,6 +692,8 @@ func (w *SPVWallet) RemoveTransactionListener(cbId int) error {
 }
 
 func (w *SPVWallet) AddBlockListener(tipOnly bool, callback func(wallet.BlockCallback)) int {
+	w.wireService.cbMutex.Lock()
+	defer w.wireService.cbMutex.Unlock()
 	id := len(w.wireService.listeners)
 	w.wireService.showTipOnly[id] = tipOnly
 	w.wireService.listeners = append(w.wireService.listeners, callback)
@@ -496,8 +701,10 @@ func (w *SPVWallet) AddBlockListener(tipOnly bool, callback func(wallet.BlockCal
 }
 
 func (w *SPVWallet) RemoveBlockListener(cbId int) error {
-	if ok := w.wireService.showTipOnly[cbId]; !ok {
-		return errors.New("invalid transaction listener id")
+	w.wireService.cbMutex.Lock()
+	defer w.wireService.cbMutex.Unlock()
+	if _, ok := w.wireService.showTipOnly[cbId]; !ok {
+		return errors.New("invalid block listener id")
 	}
 	w.wireService.listeners[cbId] = nil
 	delete(w.wireService.showTipOnly, cbId)
@@ -0,0 +1,128 @@
+package bitcoincash
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	db "github.com/BubbaJoe/spvwallet-cash/db"
+	"github.com/gcash/bchd/chaincfg"
+	hd "github.com/gcash/bchutil/hdkeychain"
+	b39 "github.com/tyler-smith/go-bip39"
+)
+
+func deriveTestKeys(t *testing.T, mnemonic, passphrase string) (master, account *hd.ExtendedKey) {
+	mPrivKey, err := hd.NewMaster(b39.NewSeed(mnemonic, passphrase), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err = mPrivKey.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err = bip44Account(mPrivKey, DefaultAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return master, account
+}
+
+func TestSaveConfig_MnemonicPassphrase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spvwallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ds, err := db.Create(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		Params:             &chaincfg.MainNetParams,
+		Mnemonic:           "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		MnemonicPassphrase: "TREZOR",
+	}
+	master, account := deriveTestKeys(t, config.Mnemonic, config.MnemonicPassphrase)
+	if err := saveConfig(ds, config, master, account); err != nil {
+		t.Fatal(err)
+	}
+
+	// The passphrase must not be written anywhere in the database
+	data, err := ioutil.ReadFile(filepath.Join(dir, "wallet.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), config.MnemonicPassphrase) {
+		t.Error("Mnemonic passphrase was saved to the database")
+	}
+
+	// Starting again with the same passphrase is fine
+	if err := saveConfig(ds, config, master, account); err != nil {
+		t.Error(err)
+	}
+
+	// but a different passphrase derives a different wallet
+	config.MnemonicPassphrase = ""
+	master, account = deriveTestKeys(t, config.Mnemonic, config.MnemonicPassphrase)
+	if err := saveConfig(ds, config, master, account); err != ErrMnemonicPassphrase {
+		t.Errorf("Expected ErrMnemonicPassphrase, got %v", err)
+	}
+}
+
+func Test_parseWatchOnlyKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spvwallet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ds, err := db.Create(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed := b39.NewSeed("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "")
+	mPrivKey, err := hd.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	neuter := func(key *hd.ExtendedKey, err error) *hd.ExtendedKey {
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := key.Neuter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pub
+	}
+	master := neuter(mPrivKey, nil)
+	account := neuter(bip44Account(mPrivKey, 3))
+	external := neuter(account.Child(0))
+
+	// Only BIP44 account keys are accepted
+	for _, key := range []*hd.ExtendedKey{master, external} {
+		if _, _, err := parseWatchOnlyKey(ds, key.String(), &chaincfg.MainNetParams); err == nil {
+			t.Errorf("Accepted a key of depth %d", key.Depth())
+		}
+	}
+
+	key, number, err := parseWatchOnlyKey(ds, account.String(), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.String() != account.String() || number != 3 {
+		t.Errorf("Returned the wrong key or account %d", number)
+	}
+	if _, stored, err := ds.GetPublicKeys(); err != nil || stored != account.String() {
+		t.Error("Extended public key was not saved to the datastore")
+	}
+
+	// Another key doesn't match the saved wallet
+	other := neuter(bip44Account(mPrivKey, 4))
+	if _, _, err := parseWatchOnlyKey(ds, other.String(), &chaincfg.MainNetParams); err == nil {
+		t.Error("Accepted a key which does not match the datastore")
+	}
+}
@@ -0,0 +1,473 @@
+package bitcoincash
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchd/chaincfg/chainhash"
+	"github.com/gcash/bchd/wire"
+)
+
+// ErrWebhooksNotSupported is returned when starting webhooks with a datastore
+// which can't queue them.
+var ErrWebhooksNotSupported = errors.New("Datastore does not support webhooks")
+
+// The events a webhook is sent for
+const (
+	// WebhookPaymentReceived is sent when a transaction paying the wallet or a
+	// watched address is first seen
+	WebhookPaymentReceived = "payment.received"
+
+	// WebhookPaymentConfirmed is sent when a payment reaches each of the
+	// configured numbers of confirmations
+	WebhookPaymentConfirmed = "payment.confirmed"
+)
+
+// The headers sent with each webhook. The signature is the HMAC-SHA256 of the
+// body keyed with the secret, see SignWebhook.
+const (
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	defaultWebhookRetryInterval = 30 * time.Second
+	defaultWebhookMaxAttempts   = 20
+	defaultWebhookTimeout       = 30 * time.Second
+
+	// The longest wait between two attempts to deliver a webhook
+	maxWebhookRetryInterval = time.Hour
+
+	// How often the queue is checked when no event is due sooner
+	webhookIdleInterval = time.Minute
+)
+
+// WebhookConfig configures the webhooks sent by a WebhookDispatcher.
+type WebhookConfig struct {
+	// The http or https URL each event is POSTed to
+	URL string
+
+	// The key the payloads are signed with
+	Secret string
+
+	// The numbers of confirmations at which a payment.confirmed event is
+	// sent, for example 1 and 6
+	Confirmations []uint32
+
+	// The wait before retrying a failed delivery, doubled after each attempt.
+	// Defaults to 30 seconds.
+	RetryInterval time.Duration
+
+	// The number of attempts after which an event is given up on. Defaults
+	// to 20.
+	MaxAttempts int
+
+	// The client the webhooks are sent with. Defaults to one with a 30 second
+	// timeout which connects through the wallet's proxy.
+	Client *http.Client
+}
+
+// WebhookPayload is the JSON body of a webhook.
+type WebhookPayload struct {
+	// Unique to the event. A receiver may see an event more than once if
+	// its response was lost.
+	ID    string `json:"id"`
+	Event string `json:"event"`
+
+	Txid   string `json:"txid"`
+	Height int32  `json:"height"`
+
+	// The outputs paying the wallet or a watched address and their sum. The
+	// wallet's change is left out.
+	Outputs []WebhookOutput `json:"outputs"`
+	Amount  int64           `json:"amount"`
+
+	// The threshold reached by a payment.confirmed event
+	Confirmations uint32 `json:"confirmations,omitempty"`
+
+	// When the event happened
+	Time time.Time `json:"time"`
+}
+
+// WebhookOutput is an output of a payment.
+type WebhookOutput struct {
+	Index     uint32 `json:"index"`
+	Address   string `json:"address"`
+	Value     int64  `json:"value"`
+	WatchOnly bool   `json:"watchOnly"`
+}
+
+// SignWebhook returns the signature of a webhook payload as it is sent in the
+// X-Webhook-Signature header, "sha256=" followed by the hex encoded
+// HMAC-SHA256 of the payload.
+func SignWebhook(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher POSTs the wallet's payments to a webhook. Events are
+// queued in the datastore before they are sent and failed deliveries are
+// retried with a growing delay, so nothing is lost when the receiver is down
+// or the wallet restarts. Events are delivered in the order they are due,
+// which may differ from the order they happened after a failure.
+type WebhookDispatcher struct {
+	w      *SPVWallet
+	config WebhookConfig
+	queue  wallet.Webhooks
+
+	txListener, blockListener int
+
+	// The payments which haven't reached the highest number of
+	// confirmations yet
+	mutex   sync.Mutex
+	pending map[chainhash.Hash]bool
+
+	ctx    context.Context
+	cancel func()
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+// StartWebhooks starts sending webhooks for the wallet's payments. Events
+// queued before a restart are sent again along with the confirmations reached
+// in the meantime.
+func (w *SPVWallet) StartWebhooks(config WebhookConfig) (*WebhookDispatcher, error) {
+	store, ok := w.txstore.Datastore.(wallet.WebhookDatastore)
+	if !ok {
+		return nil, ErrWebhooksNotSupported
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("Webhook URL must be an http or https URL")
+	}
+	if config.Secret == "" {
+		return nil, errors.New("A secret is needed to sign webhooks")
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = defaultWebhookRetryInterval
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if config.Client == nil {
+		dial := net.Dial
+		if w.proxy != nil {
+			dial = w.proxy.Dial
+		}
+		config.Client = &http.Client{Transport: &http.Transport{Dial: dial}, Timeout: defaultWebhookTimeout}
+	}
+	var confirmations []uint32
+	for _, n := range config.Confirmations {
+		if n > 0 {
+			confirmations = append(confirmations, n)
+		}
+	}
+	sort.Slice(confirmations, func(i, j int) bool { return confirmations[i] < confirmations[j] })
+	config.Confirmations = confirmations
+
+	d := &WebhookDispatcher{
+		w:       w,
+		config:  config,
+		queue:   store.Webhooks(),
+		pending: make(map[chainhash.Hash]bool),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	if err := d.loadPending(); err != nil {
+		return nil, err
+	}
+	d.checkConfirmations()
+
+	d.txListener = w.AddTransactionListener(false, d.transactionCallback)
+	d.blockListener = w.AddBlockListener(true, func(wallet.BlockCallback) {
+		go d.checkConfirmations()
+	})
+	go d.run()
+	return d, nil
+}
+
+// Stop stops sending webhooks. Events still in the queue are sent once the
+// dispatcher is started again.
+func (d *WebhookDispatcher) Stop() {
+	d.w.RemoveTransactionListener(d.txListener)
+	d.w.RemoveBlockListener(d.blockListener)
+	d.cancel()
+	<-d.done
+}
+
+func webhookID(txid, event string, confirmations uint32) string {
+	if event == WebhookPaymentConfirmed {
+		return txid + ":" + event + ":" + strconv.Itoa(int(confirmations))
+	}
+	return txid + ":" + event
+}
+
+// loadPending finds the payments which were received before a restart and
+// haven't reached every confirmation threshold.
+func (d *WebhookDispatcher) loadPending() error {
+	if len(d.config.Confirmations) == 0 {
+		return nil
+	}
+	highest := d.config.Confirmations[len(d.config.Confirmations)-1]
+	txns, err := d.w.txstore.Txns().GetAll(true)
+	if err != nil {
+		return err
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for _, txn := range txns {
+		received, err := d.queue.Has(webhookID(txn.Txid, WebhookPaymentReceived, 0))
+		if err != nil {
+			return err
+		}
+		if !received {
+			continue
+		}
+		confirmed, err := d.queue.Has(webhookID(txn.Txid, WebhookPaymentConfirmed, highest))
+		if err != nil {
+			return err
+		}
+		txid, err := chainhash.NewHashFromStr(txn.Txid)
+		if err == nil && !confirmed {
+			d.pending[*txid] = true
+		}
+	}
+	return nil
+}
+
+// transactionCallback queues a payment.received event for a transaction
+// paying the wallet or a watched address.
+func (d *WebhookDispatcher) transactionCallback(cb wallet.TransactionCallback) {
+	txid, err := chainhash.NewHashFromStr(cb.Txid)
+	if err != nil {
+		return
+	}
+	txn, err := d.w.txstore.Txns().Get(*txid)
+	if err != nil {
+		return
+	}
+	payload, ok := d.payment(txn)
+	if !ok {
+		return
+	}
+	payload.Event = WebhookPaymentReceived
+	if err := d.queueEvent(payload); err != nil {
+		log.Errorf("Error queueing webhook for %s: %s", cb.Txid, err)
+		return
+	}
+	if len(d.config.Confirmations) > 0 {
+		d.mutex.Lock()
+		d.pending[*txid] = true
+		d.mutex.Unlock()
+		d.checkConfirmations()
+	}
+}
+
+// checkConfirmations queues a payment.confirmed event for each threshold
+// reached by a pending payment.
+func (d *WebhookDispatcher) checkConfirmations() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if len(d.config.Confirmations) == 0 {
+		return
+	}
+	highest := d.config.Confirmations[len(d.config.Confirmations)-1]
+	tip, _ := d.w.ChainTip()
+	for txid := range d.pending {
+		txn, err := d.w.txstore.Txns().Get(txid)
+		if err != nil || txn.Height < 0 {
+			// Dead transactions will never confirm
+			delete(d.pending, txid)
+			continue
+		}
+		if txn.Height == 0 || uint32(txn.Height) > tip {
+			continue
+		}
+		confirmations := tip - uint32(txn.Height) + 1
+		payload, ok := d.payment(txn)
+		if !ok {
+			delete(d.pending, txid)
+			continue
+		}
+		payload.Event = WebhookPaymentConfirmed
+		failed := false
+		for _, n := range d.config.Confirmations {
+			if confirmations < n {
+				break
+			}
+			payload.Confirmations = n
+			if err := d.queueEvent(payload); err != nil {
+				log.Errorf("Error queueing webhook for %s: %s", txn.Txid, err)
+				failed = true
+				break
+			}
+		}
+		if confirmations >= highest && !failed {
+			delete(d.pending, txid)
+		}
+	}
+}
+
+// payment returns the payload of a transaction with the outputs it pays to
+// the wallet or a watched address. It returns false if it pays neither.
+// Outputs to the wallet count only if the transaction increases its balance
+// so that change isn't reported as a payment.
+func (d *WebhookDispatcher) payment(txn wallet.Txn) (WebhookPayload, bool) {
+	tx := wire.NewMsgTx(1)
+	if err := tx.BchDecode(bytes.NewReader(txn.Bytes), wire.ProtocolVersion, wire.BaseEncoding); err != nil {
+		return WebhookPayload{}, false
+	}
+	watched := make(map[string]bool)
+	if scripts, err := d.w.txstore.WatchedScripts().GetAll(); err == nil {
+		for _, script := range scripts {
+			watched[string(script)] = true
+		}
+	}
+	payload := WebhookPayload{Txid: txn.Txid, Height: txn.Height}
+	for i, out := range tx.TxOut {
+		addr, err := scriptToAddress(out.PkScript, d.w.params)
+		watchOnly := watched[string(out.PkScript)]
+		if !watchOnly && (txn.Value <= 0 || err != nil || !d.w.HasKey(addr)) {
+			continue
+		}
+		payload.Outputs = append(payload.Outputs, WebhookOutput{
+			Index:     uint32(i),
+			Address:   encodeAddress(addr),
+			Value:     out.Value,
+			WatchOnly: watchOnly,
+		})
+		payload.Amount += out.Value
+	}
+	return payload, len(payload.Outputs) > 0
+}
+
+// queueEvent saves an event in the queue, unless it was already queued, and
+// wakes up the delivery loop.
+func (d *WebhookDispatcher) queueEvent(payload WebhookPayload) error {
+	payload.ID = webhookID(payload.Txid, payload.Event, payload.Confirmations)
+	payload.Time = time.Now()
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	queued, err := d.queue.Put(wallet.WebhookEvent{ID: payload.ID, Payload: b, NextAttempt: time.Now()})
+	if err != nil || !queued {
+		return err
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// run delivers the queued events until the dispatcher is stopped.
+func (d *WebhookDispatcher) run() {
+	defer close(d.done)
+	for {
+		timer := time.NewTimer(d.deliverDue())
+		select {
+		case <-d.ctx.Done():
+			timer.Stop()
+			return
+		case <-d.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// deliverDue attempts to deliver each event which is due and returns the wait
+// until the next one is.
+func (d *WebhookDispatcher) deliverDue() time.Duration {
+	events, err := d.queue.GetPending()
+	if err != nil {
+		log.Errorf("Error loading webhooks: %s", err)
+		return d.config.RetryInterval
+	}
+	next := webhookIdleInterval
+	for _, event := range events {
+		if wait := time.Until(event.NextAttempt); wait > 0 {
+			if wait < next {
+				next = wait
+			}
+			break
+		}
+		if d.ctx.Err() != nil {
+			break
+		}
+		if err := d.deliver(event); err != nil {
+			event.Attempts++
+			if event.Attempts >= d.config.MaxAttempts {
+				log.Errorf("Giving up on webhook %s after %d attempts: %s", event.ID, event.Attempts, err)
+				event.Done = true
+			} else {
+				log.Warningf("Error sending webhook %s: %s", event.ID, err)
+				retry := d.retryInterval(event.Attempts)
+				event.NextAttempt = time.Now().Add(retry)
+				if retry < next {
+					next = retry
+				}
+			}
+		} else {
+			event.Done = true
+		}
+		if err := d.queue.Update(event); err != nil {
+			log.Errorf("Error updating webhook %s: %s", event.ID, err)
+		}
+	}
+	return next
+}
+
+// retryInterval returns the wait after a number of failed attempts.
+func (d *WebhookDispatcher) retryInterval(attempts int) time.Duration {
+	interval := d.config.RetryInterval
+	for i := 1; i < attempts && interval < maxWebhookRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxWebhookRetryInterval {
+		interval = maxWebhookRetryInterval
+	}
+	return interval
+}
+
+// deliver POSTs an event. It is delivered if the receiver answers with a 2xx
+// status.
+func (d *WebhookDispatcher) deliver(event wallet.WebhookEvent) error {
+	req, err := http.NewRequest("POST", d.config.URL, bytes.NewReader(event.Payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(d.ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, event.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(d.config.Secret, event.Payload))
+	resp, err := d.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Receiver returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package bitcoincash
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BubbaJoe/spvwallet-cash/wallet-interface"
+	"github.com/gcash/bchutil"
+)
+
+// webhookReceiver is a webhook endpoint which checks signatures and fails
+// while down is set.
+type webhookReceiver struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	down     bool
+	attempts int
+	payloads chan WebhookPayload
+}
+
+func (r *webhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		r.t.Error(err)
+	}
+	if sig := req.Header.Get(WebhookSignatureHeader); sig != SignWebhook("secret", body) {
+		r.t.Errorf("Invalid signature %s", sig)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.attempts++
+	if r.down {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		r.t.Error(err)
+	}
+	if req.Header.Get(WebhookIDHeader) != payload.ID {
+		r.t.Errorf("Header ID %s does not match %s", req.Header.Get(WebhookIDHeader), payload.ID)
+	}
+	r.payloads <- payload
+}
+
+func (r *webhookReceiver) setDown(down bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.down = down
+}
+
+func nextWebhook(t *testing.T, r *webhookReceiver) WebhookPayload {
+	select {
+	case p := <-r.payloads:
+		return p
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a webhook")
+	}
+	return WebhookPayload{}
+}
+
+func createWebhookWallet(t *testing.T) (*SPVWallet, func()) {
+	w, _, cleanup := createAccountsWallet(t)
+	w.wireService = &WireService{showTipOnly: make(map[int]bool), cbMutex: new(sync.Mutex)}
+	return w, cleanup
+}
+
+func TestSPVWallet_StartWebhooks(t *testing.T) {
+	w, cleanup := createWebhookWallet(t)
+	defer cleanup()
+	receiver := &webhookReceiver{t: t, down: true, payloads: make(chan WebhookPayload, 10)}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	config := WebhookConfig{
+		URL:           server.URL,
+		Secret:        "secret",
+		Confirmations: []uint32{3, 1},
+		RetryInterval: time.Millisecond * 10,
+	}
+	d, err := w.StartWebhooks(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Stop()
+
+	// The payment is retried until the receiver is up
+	addr := w.CurrentAddress(wallet.EXTERNAL)
+	tx := payTo(t, addr, 100000)
+	if _, err := w.txstore.Ingest(tx, 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 50)
+	receiver.setDown(false)
+	p := nextWebhook(t, receiver)
+	if p.Event != WebhookPaymentReceived || p.Txid != tx.TxHash().String() || p.Amount != 100000 {
+		t.Errorf("Unexpected payload %v", p)
+	}
+	if len(p.Outputs) != 1 || p.Outputs[0].Address != addr.String() || p.Outputs[0].WatchOnly {
+		t.Errorf("Unexpected outputs %v", p.Outputs)
+	}
+	receiver.mutex.Lock()
+	if receiver.attempts < 2 {
+		t.Error("Webhook was not retried")
+	}
+	receiver.mutex.Unlock()
+
+	// Mining it three blocks deep reaches both thresholds
+	tip, _ := w.ChainTip()
+	if _, err := w.txstore.Ingest(tx, int32(tip-2), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []uint32{1, 3} {
+		p := nextWebhook(t, receiver)
+		if p.Event != WebhookPaymentConfirmed || p.Confirmations != n || p.Height != int32(tip-2) || p.Amount != 100000 {
+			t.Errorf("Expected a confirmation at %d, got %v", n, p)
+		}
+	}
+	d.checkConfirmations()
+	select {
+	case p := <-receiver.payloads:
+		t.Errorf("Sent %s twice", p.ID)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
+
+func TestWebhookDispatcher_Restart(t *testing.T) {
+	w, cleanup := createWebhookWallet(t)
+	defer cleanup()
+	receiver := &webhookReceiver{t: t, down: true, payloads: make(chan WebhookPayload, 10)}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	config := WebhookConfig{
+		URL:           server.URL,
+		Secret:        "secret",
+		Confirmations: []uint32{1},
+		RetryInterval: time.Millisecond * 10,
+	}
+	d, err := w.StartWebhooks(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A payment to a watched address is queued while the receiver is down
+	watched, err := bchutil.NewAddressPubKeyHash(make([]byte, 20), w.params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := w.AddressToScript(watched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.txstore.WatchedScripts().Put(script); err != nil {
+		t.Fatal(err)
+	}
+	w.txstore.PopulateAdrs()
+	tx := payTo(t, watched, 5000)
+	if _, err := w.txstore.Ingest(tx, 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 50)
+	d.Stop()
+
+	// It is confirmed while the dispatcher is stopped
+	tip, _ := w.ChainTip()
+	if _, err := w.txstore.Ingest(tx, int32(tip), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 50)
+
+	receiver.setDown(false)
+	d, err = w.StartWebhooks(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Stop()
+	// The retried event may be due after the new one
+	payloads := make(map[string]WebhookPayload)
+	for i := 0; i < 2; i++ {
+		p := nextWebhook(t, receiver)
+		payloads[p.Event] = p
+	}
+	p := payloads[WebhookPaymentReceived]
+	if p.Amount != 5000 || len(p.Outputs) != 1 || !p.Outputs[0].WatchOnly {
+		t.Errorf("Unexpected payload %v", p)
+	}
+	if p := payloads[WebhookPaymentConfirmed]; p.Confirmations != 1 || p.Amount != 5000 {
+		t.Errorf("Expected a confirmation, got %v", p)
+	}
+}
+
+// countingDialer is a proxy which counts the connections made through it.
+type countingDialer struct {
+	mutex sync.Mutex
+	dials int
+}
+
+func (d *countingDialer) Dial(network, addr string) (net.Conn, error) {
+	d.mutex.Lock()
+	d.dials++
+	d.mutex.Unlock()
+	return net.Dial(network, addr)
+}
+
+func TestSPVWallet_StartWebhooksProxy(t *testing.T) {
+	w, cleanup := createWebhookWallet(t)
+	defer cleanup()
+	dialer := &countingDialer{}
+	w.proxy = dialer
+	receiver := &webhookReceiver{t: t, payloads: make(chan WebhookPayload, 10)}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	d, err := w.StartWebhooks(WebhookConfig{URL: server.URL, Secret: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Stop()
+	if _, err := w.txstore.Ingest(payTo(t, w.CurrentAddress(wallet.EXTERNAL), 100000), 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	nextWebhook(t, receiver)
+	dialer.mutex.Lock()
+	defer dialer.mutex.Unlock()
+	if dialer.dials == 0 {
+		t.Error("Webhook was not sent through the wallet's proxy")
+	}
+}
+
+func TestSPVWallet_StartWebhooksConfig(t *testing.T) {
+	w, cleanup := createWebhookWallet(t)
+	defer cleanup()
+	if _, err := w.StartWebhooks(WebhookConfig{URL: "ftp://example.com", Secret: "secret"}); err == nil {
+		t.Error("Accepted a URL which is not http")
+	}
+	if _, err := w.StartWebhooks(WebhookConfig{URL: "https://example.com"}); err == nil {
+		t.Error("Accepted webhooks without a secret")
+	}
+}
+
+func TestSPVWallet_StartWebhooksNotSupported(t *testing.T) {
+	w := MockWallet()
+	defer os.Remove("headers.bin")
+	if _, err := w.StartWebhooks(WebhookConfig{URL: "https://example.com", Secret: "secret"}); err != ErrWebhooksNotSupported {
+		t.Errorf("Expected ErrWebhooksNotSupported, got %v", err)
+	}
+}
+
+func TestWebhookDispatcher_retryInterval(t *testing.T) {
+	d := &WebhookDispatcher{config: WebhookConfig{RetryInterval: time.Second * 30}}
+	tests := []struct {
+		attempts int
+		interval time.Duration
+	}{
+		{1, time.Second * 30},
+		{2, time.Minute},
+		{5, time.Minute * 8},
+		{100, maxWebhookRetryInterval},
+	}
+	for _, test := range tests {
+		if interval := d.retryInterval(test.attempts); interval != test.interval {
+			t.Errorf("After %d attempts expected %s, got %s", test.attempts, test.interval, interval)
+		}
+	}
+}